@@ -4,6 +4,7 @@ import (
 	"github.com/flipped-aurora/gin-vue-admin/server/global"
 	"github.com/flipped-aurora/gin-vue-admin/server/model/cion"
 	cionReq "github.com/flipped-aurora/gin-vue-admin/server/model/cion/request"
+	cionRes "github.com/flipped-aurora/gin-vue-admin/server/model/cion/response"
 	"github.com/flipped-aurora/gin-vue-admin/server/model/common/response"
 	"github.com/flipped-aurora/gin-vue-admin/server/utils"
 	"github.com/gin-gonic/gin"
@@ -177,19 +178,48 @@ func (imageApi *ImageApi) GetImageList(c *gin.Context) {
 
 // GetImagePublic 不需要鉴权的image表接口
 // @Tags Image
-// @Summary 不需要鉴权的image表接口
+// @Summary 获取镜像库存概况（存在与已移除的数量、最近同步时间），不需要鉴权
 // @Accept application/json
 // @Produce application/json
-// @Success 200 {object} response.Response{data=object,msg=string} "获取成功"
+// @Success 200 {object} response.Response{data=cionRes.ImageInventorySummary,msg=string} "获取成功"
 // @Router /image/getImagePublic [get]
 func (imageApi *ImageApi) GetImagePublic(c *gin.Context) {
 	// 创建业务用Context
 	ctx := c.Request.Context()
 
-	// 此接口不需要鉴权
-	// 示例为返回了一个固定的消息接口，一般本接口用于C端服务，需要自己实现业务逻辑
-	imageService.GetImagePublic(ctx)
-	response.OkWithDetailed(gin.H{
-		"info": "不需要鉴权的image表接口信息",
-	}, "获取成功", c)
+	// 此接口不需要鉴权，只返回数量统计
+	var summary cionRes.ImageInventorySummary
+	summary, err := imageService.GetImagePublic(ctx)
+	if err != nil {
+		global.GVA_LOG.Error("获取失败!", zap.Error(err))
+		response.FailWithMessage("获取失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithDetailed(summary, "获取成功", c)
+}
+
+// SyncImages 同步Docker镜像到image表
+// @Tags Image
+// @Summary 立即将Docker中的镜像与image表对账，记录首次/最后发现时间并标记已移除的镜像
+// @Security ApiKeyAuth
+// @Accept application/json
+// @Produce application/json
+// @Success 200 {object} response.Response{data=cionRes.ImageSyncResult,msg=string} "同步成功"
+// @Router /image/syncImages [post]
+func (imageApi *ImageApi) SyncImages(c *gin.Context) {
+	// 创建业务用Context
+	ctx := c.Request.Context()
+
+	var result cionRes.ImageSyncResult
+	result, err := imageService.SyncImages(ctx)
+	if err != nil {
+		global.GVA_LOG.Error("同步失败!", zap.Error(err))
+		if err.Error() == "Docker client is not available" {
+			response.FailWithMessage("同步失败:Docker服务不可用", c)
+			return
+		}
+		response.FailWithMessage("同步失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithDetailed(result, "同步成功", c)
 }
@@ -0,0 +1,368 @@
+package docker
+
+import (
+	"strconv"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/response"
+	dockerReq "github.com/flipped-aurora/gin-vue-admin/server/model/docker/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type DockerAlertApi struct{}
+
+// GetAlertRuleList 获取告警规则列表
+// @Tags Docker告警
+// @Summary 获取告警规则列表
+// @Security ApiKeyAuth
+// @Produce application/json
+// @Success 200 {object} response.Response{data=[]docker.DockerAlertRule,msg=string} "获取成功"
+// @Router /docker/alerts/rules [get]
+func (d *DockerAlertApi) GetAlertRuleList(c *gin.Context) {
+	rules, err := dockerAlertService.GetAlertRuleList()
+	if err != nil {
+		global.GVA_LOG.Error("获取告警规则失败", zap.Error(err))
+		response.FailWithMessage("获取告警规则失败: "+err.Error(), c)
+		return
+	}
+
+	response.OkWithDetailed(rules, "获取成功", c)
+}
+
+// CreateAlertRule 创建告警规则
+// @Tags Docker告警
+// @Summary 创建告警规则
+// @Security ApiKeyAuth
+// @accept application/json
+// @Produce application/json
+// @Param data body dockerReq.AlertRuleRequest true "告警规则"
+// @Success 200 {object} response.Response{data=docker.DockerAlertRule,msg=string} "创建成功"
+// @Router /docker/alerts/rules [post]
+func (d *DockerAlertApi) CreateAlertRule(c *gin.Context) {
+	var req dockerReq.AlertRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	rule, err := dockerAlertService.CreateAlertRule(req)
+	if err != nil {
+		global.GVA_LOG.Error("创建告警规则失败", zap.Error(err))
+		response.FailWithMessage("创建告警规则失败: "+err.Error(), c)
+		return
+	}
+
+	response.OkWithDetailed(rule, "创建成功", c)
+}
+
+// UpdateAlertRule 更新告警规则
+// @Tags Docker告警
+// @Summary 更新告警规则，类型或匹配对象变化、停用时结束未恢复的事件
+// @Security ApiKeyAuth
+// @accept application/json
+// @Produce application/json
+// @Param data body dockerReq.AlertRuleRequest true "告警规则"
+// @Success 200 {object} response.Response{data=docker.DockerAlertRule,msg=string} "更新成功"
+// @Router /docker/alerts/rules [put]
+func (d *DockerAlertApi) UpdateAlertRule(c *gin.Context) {
+	var req dockerReq.AlertRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	if req.ID == 0 {
+		response.FailWithMessage("规则ID不能为空", c)
+		return
+	}
+
+	rule, err := dockerAlertService.UpdateAlertRule(req)
+	if err != nil {
+		global.GVA_LOG.Error("更新告警规则失败", zap.Uint("id", req.ID), zap.Error(err))
+		if err.Error() == "alert rule not found" {
+			response.FailWithMessage("告警规则不存在", c)
+			return
+		}
+		response.FailWithMessage("更新告警规则失败: "+err.Error(), c)
+		return
+	}
+
+	response.OkWithDetailed(rule, "更新成功", c)
+}
+
+// DeleteAlertRule 删除告警规则
+// @Tags Docker告警
+// @Summary 删除告警规则
+// @Security ApiKeyAuth
+// @Produce application/json
+// @Param id path int true "规则ID"
+// @Success 200 {object} response.Response{msg=string} "删除成功"
+// @Router /docker/alerts/rules/{id} [delete]
+func (d *DockerAlertApi) DeleteAlertRule(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.FailWithMessage("无效的规则ID", c)
+		return
+	}
+
+	if err := dockerAlertService.DeleteAlertRule(uint(id)); err != nil {
+		global.GVA_LOG.Error("删除告警规则失败", zap.Uint64("id", id), zap.Error(err))
+		if err.Error() == "alert rule not found" {
+			response.FailWithMessage("告警规则不存在", c)
+			return
+		}
+		response.FailWithMessage("删除告警规则失败: "+err.Error(), c)
+		return
+	}
+
+	response.OkWithMessage("删除成功", c)
+}
+
+// GetAlertChannelList 获取通知渠道列表
+// @Tags Docker告警
+// @Summary 获取通知渠道列表，不返回加签密钥
+// @Security ApiKeyAuth
+// @Produce application/json
+// @Success 200 {object} response.Response{data=[]docker.DockerAlertChannel,msg=string} "获取成功"
+// @Router /docker/alerts/channels [get]
+func (d *DockerAlertApi) GetAlertChannelList(c *gin.Context) {
+	channels, err := dockerAlertService.GetAlertChannelList()
+	if err != nil {
+		global.GVA_LOG.Error("获取通知渠道失败", zap.Error(err))
+		response.FailWithMessage("获取通知渠道失败: "+err.Error(), c)
+		return
+	}
+
+	response.OkWithDetailed(channels, "获取成功", c)
+}
+
+// CreateAlertChannel 创建通知渠道
+// @Tags Docker告警
+// @Summary 创建通知渠道 (email/dingtalk/feishu/slack/webhook)
+// @Security ApiKeyAuth
+// @accept application/json
+// @Produce application/json
+// @Param data body dockerReq.AlertChannelRequest true "通知渠道"
+// @Success 200 {object} response.Response{data=docker.DockerAlertChannel,msg=string} "创建成功"
+// @Router /docker/alerts/channels [post]
+func (d *DockerAlertApi) CreateAlertChannel(c *gin.Context) {
+	var req dockerReq.AlertChannelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	channel, err := dockerAlertService.CreateAlertChannel(req)
+	if err != nil {
+		global.GVA_LOG.Error("创建通知渠道失败", zap.Error(err))
+		response.FailWithMessage("创建通知渠道失败: "+err.Error(), c)
+		return
+	}
+
+	response.OkWithDetailed(channel, "创建成功", c)
+}
+
+// UpdateAlertChannel 更新通知渠道
+// @Tags Docker告警
+// @Summary 更新通知渠道，密钥为空时保持不变
+// @Security ApiKeyAuth
+// @accept application/json
+// @Produce application/json
+// @Param data body dockerReq.AlertChannelRequest true "通知渠道"
+// @Success 200 {object} response.Response{data=docker.DockerAlertChannel,msg=string} "更新成功"
+// @Router /docker/alerts/channels [put]
+func (d *DockerAlertApi) UpdateAlertChannel(c *gin.Context) {
+	var req dockerReq.AlertChannelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	if req.ID == 0 {
+		response.FailWithMessage("渠道ID不能为空", c)
+		return
+	}
+
+	channel, err := dockerAlertService.UpdateAlertChannel(req)
+	if err != nil {
+		global.GVA_LOG.Error("更新通知渠道失败", zap.Uint("id", req.ID), zap.Error(err))
+		if err.Error() == "alert channel not found" {
+			response.FailWithMessage("通知渠道不存在", c)
+			return
+		}
+		response.FailWithMessage("更新通知渠道失败: "+err.Error(), c)
+		return
+	}
+
+	response.OkWithDetailed(channel, "更新成功", c)
+}
+
+// DeleteAlertChannel 删除通知渠道
+// @Tags Docker告警
+// @Summary 删除通知渠道
+// @Security ApiKeyAuth
+// @Produce application/json
+// @Param id path int true "渠道ID"
+// @Success 200 {object} response.Response{msg=string} "删除成功"
+// @Router /docker/alerts/channels/{id} [delete]
+func (d *DockerAlertApi) DeleteAlertChannel(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.FailWithMessage("无效的渠道ID", c)
+		return
+	}
+
+	if err := dockerAlertService.DeleteAlertChannel(uint(id)); err != nil {
+		global.GVA_LOG.Error("删除通知渠道失败", zap.Uint64("id", id), zap.Error(err))
+		if err.Error() == "alert channel not found" {
+			response.FailWithMessage("通知渠道不存在", c)
+			return
+		}
+		response.FailWithMessage("删除通知渠道失败: "+err.Error(), c)
+		return
+	}
+
+	response.OkWithMessage("删除成功", c)
+}
+
+// TestAlertChannel 测试通知渠道
+// @Tags Docker告警
+// @Summary 通过通知渠道发送一条测试消息
+// @Security ApiKeyAuth
+// @Produce application/json
+// @Param id path int true "渠道ID"
+// @Success 200 {object} response.Response{msg=string} "发送成功"
+// @Router /docker/alerts/channels/{id}/test [post]
+func (d *DockerAlertApi) TestAlertChannel(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.FailWithMessage("无效的渠道ID", c)
+		return
+	}
+
+	if err := dockerAlertService.TestAlertChannel(uint(id)); err != nil {
+		global.GVA_LOG.Error("测试通知渠道失败", zap.Uint64("id", id), zap.Error(err))
+		if err.Error() == "alert channel not found" {
+			response.FailWithMessage("通知渠道不存在", c)
+			return
+		}
+		response.FailWithMessage("发送失败: "+err.Error(), c)
+		return
+	}
+
+	response.OkWithMessage("发送成功", c)
+}
+
+// GetAlertSilenceList 获取静默窗口列表
+// @Tags Docker告警
+// @Summary 获取未结束的静默窗口
+// @Security ApiKeyAuth
+// @Produce application/json
+// @Success 200 {object} response.Response{data=[]docker.DockerAlertSilence,msg=string} "获取成功"
+// @Router /docker/alerts/silences [get]
+func (d *DockerAlertApi) GetAlertSilenceList(c *gin.Context) {
+	silences, err := dockerAlertService.GetAlertSilenceList()
+	if err != nil {
+		global.GVA_LOG.Error("获取静默窗口失败", zap.Error(err))
+		response.FailWithMessage("获取静默窗口失败: "+err.Error(), c)
+		return
+	}
+
+	response.OkWithDetailed(silences, "获取成功", c)
+}
+
+// CreateAlertSilence 创建静默窗口
+// @Tags Docker告警
+// @Summary 创建静默窗口，窗口内告警照常记录但不发送通知
+// @Security ApiKeyAuth
+// @accept application/json
+// @Produce application/json
+// @Param data body dockerReq.AlertSilenceRequest true "静默窗口"
+// @Success 200 {object} response.Response{data=docker.DockerAlertSilence,msg=string} "创建成功"
+// @Router /docker/alerts/silences [post]
+func (d *DockerAlertApi) CreateAlertSilence(c *gin.Context) {
+	var req dockerReq.AlertSilenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	silence, err := dockerAlertService.CreateAlertSilence(req, utils.GetUserID(c))
+	if err != nil {
+		global.GVA_LOG.Error("创建静默窗口失败", zap.Error(err))
+		if err.Error() == "alert rule not found" {
+			response.FailWithMessage("告警规则不存在", c)
+			return
+		}
+		response.FailWithMessage("创建静默窗口失败: "+err.Error(), c)
+		return
+	}
+
+	response.OkWithDetailed(silence, "创建成功", c)
+}
+
+// DeleteAlertSilence 删除静默窗口
+// @Tags Docker告警
+// @Summary 删除静默窗口，提前结束静默
+// @Security ApiKeyAuth
+// @Produce application/json
+// @Param id path int true "静默窗口ID"
+// @Success 200 {object} response.Response{msg=string} "删除成功"
+// @Router /docker/alerts/silences/{id} [delete]
+func (d *DockerAlertApi) DeleteAlertSilence(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.FailWithMessage("无效的静默窗口ID", c)
+		return
+	}
+
+	if err := dockerAlertService.DeleteAlertSilence(uint(id)); err != nil {
+		global.GVA_LOG.Error("删除静默窗口失败", zap.Uint64("id", id), zap.Error(err))
+		if err.Error() == "alert silence not found" {
+			response.FailWithMessage("静默窗口不存在", c)
+			return
+		}
+		response.FailWithMessage("删除静默窗口失败: "+err.Error(), c)
+		return
+	}
+
+	response.OkWithMessage("删除成功", c)
+}
+
+// GetAlertEventList 获取告警事件列表
+// @Tags Docker告警
+// @Summary 分页获取告警事件，按时间倒序
+// @Security ApiKeyAuth
+// @Produce application/json
+// @Param data query dockerReq.AlertEventFilter false "查询参数"
+// @Success 200 {object} response.Response{data=response.PageResult,msg=string} "获取成功"
+// @Router /docker/alerts/events [get]
+func (d *DockerAlertApi) GetAlertEventList(c *gin.Context) {
+	var filter dockerReq.AlertEventFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	// 设置默认分页参数
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+	if filter.PageSize <= 0 || filter.PageSize > 100 {
+		filter.PageSize = 10
+	}
+
+	events, total, err := dockerAlertService.GetAlertEventList(filter)
+	if err != nil {
+		global.GVA_LOG.Error("获取告警事件失败", zap.Error(err))
+		response.FailWithMessage("获取告警事件失败: "+err.Error(), c)
+		return
+	}
+
+	response.OkWithDetailed(response.PageResult{
+		List:     events,
+		Total:    total,
+		Page:     filter.Page,
+		PageSize: filter.PageSize,
+	}, "获取成功", c)
+}
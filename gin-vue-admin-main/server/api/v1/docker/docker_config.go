@@ -1,13 +1,16 @@
 package docker
 
 import (
+	"mime/multipart"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/flipped-aurora/gin-vue-admin/server/global"
 	"github.com/flipped-aurora/gin-vue-admin/server/model/common/response"
 	dockerModel "github.com/flipped-aurora/gin-vue-admin/server/model/docker"
 	dockerService "github.com/flipped-aurora/gin-vue-admin/server/service/docker"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
@@ -311,4 +314,72 @@ func (api *DockerConfigApi) CheckDockerServiceHealth(c *gin.Context) {
 	}
 
 	response.OkWithMessage("Docker服务运行正常", c)
-}
\ No newline at end of file
+}
+
+// GetDockerTLSStatus 获取Docker TLS证书状态
+// @Tags Docker
+// @Summary 获取默认主机的TLS配置与证书有效期
+// @Security ApiKeyAuth
+// @Produce application/json
+// @Success 200 {object} response.Response{data=dockerRes.DockerTLSStatus,msg=string} "获取成功"
+// @Router /docker/config/tls [get]
+func (api *DockerConfigApi) GetDockerTLSStatus(c *gin.Context) {
+	service := dockerService.NewDockerConfigService()
+	response.OkWithDetailed(service.GetTLSStatus(), "获取成功", c)
+}
+
+// RotateDockerTLSCerts 上传并轮换Docker TLS证书
+// @Tags Docker
+// @Summary 上传 ca.pem、cert.pem、key.pem 中的一个或多个，未上传的沿用当前证书；校验通过且能连接守护进程后替换并重建客户端
+// @Security ApiKeyAuth
+// @accept multipart/form-data
+// @Produce application/json
+// @Param ca formData file false "CA证书 ca.pem"
+// @Param cert formData file false "客户端证书 cert.pem"
+// @Param key formData file false "客户端私钥 key.pem"
+// @Param tlsVerify formData bool false "是否校验服务端证书，默认沿用当前配置"
+// @Success 200 {object} response.Response{data=dockerRes.DockerTLSStatus,msg=string} "证书已更新"
+// @Router /docker/config/tls [post]
+func (api *DockerConfigApi) RotateDockerTLSCerts(c *gin.Context) {
+	files := make(map[string]*multipart.FileHeader)
+	for field, name := range map[string]string{"ca": utils.DockerCAFile, "cert": utils.DockerCertFile, "key": utils.DockerKeyFile} {
+		if header, err := c.FormFile(field); err == nil {
+			files[name] = header
+		}
+	}
+	if len(files) == 0 {
+		response.FailWithMessage("请至少上传一个证书文件", c)
+		return
+	}
+	tlsVerify := global.GVA_CONFIG.Docker.TLSVerify
+	if value := c.PostForm("tlsVerify"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			response.FailWithMessage("无效的 tlsVerify 参数", c)
+			return
+		}
+		tlsVerify = parsed
+	}
+
+	service := dockerService.NewDockerConfigService()
+	status, err := service.RotateTLSCertificates(files, tlsVerify)
+	if err != nil {
+		global.GVA_LOG.Error("更新Docker TLS证书失败", zap.Error(err))
+		msg := err.Error()
+		switch {
+		case msg == "tls certificates only apply to tcp endpoints":
+			response.FailWithMessage("仅 tcp 端点支持TLS证书", c)
+		case msg == "certificate rotation is already running":
+			response.FailWithMessage("证书正在更新，请稍后重试", c)
+		case strings.HasPrefix(msg, "invalid certificates"):
+			response.FailWithMessage("证书校验失败: "+strings.TrimPrefix(msg, "invalid certificates: "), c)
+		case strings.HasPrefix(msg, "docker daemon rejected the new certificates"):
+			response.FailWithMessage("使用新证书连接Docker失败，证书未替换: "+msg, c)
+		default:
+			response.FailWithMessage("更新Docker TLS证书失败: "+msg, c)
+		}
+		return
+	}
+
+	response.OkWithDetailed(status, "证书已更新", c)
+}
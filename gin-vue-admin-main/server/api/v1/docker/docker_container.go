@@ -1,6 +1,7 @@
 package docker
 
 import (
+	"context"
 	"strconv"
 
 	"github.com/flipped-aurora/gin-vue-admin/server/global"
@@ -39,7 +40,7 @@ func (d *DockerContainerApi) GetContainerList(c *gin.Context) {
 	}
 
 	// 调用服务层获取容器列表
-	containers, total, err := dockerContainerService.GetContainerList(pageInfo)
+	containers, total, err := onHost(c, dockerContainerService).GetContainerList(pageInfo)
 	if err != nil {
 		global.GVA_LOG.Error("获取容器列表失败", zap.Error(err))
 		response.FailWithMessage("获取容器列表失败: "+err.Error(), c)
@@ -76,7 +77,7 @@ func (d *DockerContainerApi) GetContainerDetail(c *gin.Context) {
 	}
 
 	// 调用服务层获取容器详细信息
-	containerDetail, err := dockerContainerService.GetContainerDetail(containerID)
+	containerDetail, err := onHost(c, dockerContainerService).GetContainerDetail(containerID)
 	if err != nil {
 		global.GVA_LOG.Error("获取容器详细信息失败", zap.String("containerID", containerID), zap.Error(err))
 		if err.Error() == "container not found" {
@@ -115,7 +116,7 @@ func (d *DockerContainerApi) GetContainerLogs(c *gin.Context) {
 	}
 
 	// 调用服务层获取容器日志
-	logs, err := dockerContainerService.GetContainerLogs(containerID, logOptions)
+	logs, err := onHost(c, dockerContainerService).GetContainerLogs(containerID, logOptions)
 	if err != nil {
 		global.GVA_LOG.Error("获取容器日志失败", zap.String("containerID", containerID), zap.Error(err))
 		if err.Error() == "container not found" {
@@ -129,6 +130,109 @@ func (d *DockerContainerApi) GetContainerLogs(c *gin.Context) {
 	response.OkWithDetailed(logs, "获取成功", c)
 }
 
+// StreamContainerLogs 实时跟踪容器日志
+// @Tags Docker
+// @Summary 实时跟踪Docker容器日志，支持WebSocket与SSE两种方式
+// @Description 携带 Upgrade: websocket 头时升级为WebSocket，每条消息为一行日志JSON；否则以SSE推送 log 事件，结束时推送 end 或 error 事件
+// @Security ApiKeyAuth
+// @Produce text/event-stream
+// @Param id path string true "容器ID"
+// @Param data query dockerReq.LogStreamOptions false "日志选项"
+// @Success 200 {object} dockerRes.ContainerLogLine "日志行"
+// @Router /docker/containers/{id}/logs/stream [get]
+func (d *DockerContainerApi) StreamContainerLogs(c *gin.Context) {
+	containerID := c.Param("id")
+	if containerID == "" {
+		response.FailWithMessage("容器ID不能为空", c)
+		return
+	}
+
+	var options dockerReq.LogStreamOptions
+	if err := c.ShouldBindQuery(&options); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	serveEventStream(c, "log", func(ctx context.Context, emit func(data interface{}) error) error {
+		err := onHost(c, dockerContainerService).StreamContainerLogs(ctx, containerID, options, func(line dockerRes.ContainerLogLine) error {
+			return emit(line)
+		})
+		if err != nil {
+			global.GVA_LOG.Warn("容器日志推送中断", zap.String("containerID", containerID), zap.Error(err))
+		}
+		return err
+	}, func(err error) {
+		if err.Error() == "container not found" {
+			response.FailWithMessage("容器不存在", c)
+			return
+		}
+		response.FailWithMessage("获取容器日志失败: "+err.Error(), c)
+	})
+}
+
+// CreateContainer 创建容器
+// @Tags Docker
+// @Summary 按完整配置创建Docker容器
+// @Security ApiKeyAuth
+// @accept application/json
+// @Produce application/json
+// @Param data body dockerReq.ContainerCreateRequest true "容器配置"
+// @Success 200 {object} response.Response{data=dockerRes.ContainerDetail,msg=string} "创建成功"
+// @Router /docker/containers [post]
+func (d *DockerContainerApi) CreateContainer(c *gin.Context) {
+	var req dockerReq.ContainerCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.FailWithMessage("参数错误: "+err.Error(), c)
+		return
+	}
+
+	detail, err := onHost(c, dockerContainerService).CreateContainer(req)
+	if err != nil {
+		global.GVA_LOG.Error("创建容器失败", zap.String("image", req.Image), zap.Error(err))
+		response.FailWithMessage("创建容器失败: "+err.Error(), c)
+		return
+	}
+
+	response.OkWithDetailed(detail, "创建成功", c)
+}
+
+// RecreateContainer 按修改重建容器
+// @Tags Docker
+// @Summary 修改环境变量、端口、挂载、重启策略或资源限制后重建容器，保留名称与网络别名
+// @Security ApiKeyAuth
+// @accept application/json
+// @Produce application/json
+// @Param id path string true "容器ID"
+// @Param data body dockerReq.ContainerRecreateRequest true "修改内容，未传的字段沿用原配置"
+// @Success 200 {object} response.Response{data=dockerRes.ContainerDetail,msg=string} "重建成功"
+// @Router /docker/containers/{id}/recreate [post]
+func (d *DockerContainerApi) RecreateContainer(c *gin.Context) {
+	containerID := c.Param("id")
+	if containerID == "" {
+		response.FailWithMessage("容器ID不能为空", c)
+		return
+	}
+
+	var req dockerReq.ContainerRecreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.FailWithMessage("参数错误: "+err.Error(), c)
+		return
+	}
+
+	detail, err := onHost(c, dockerContainerService).RecreateContainer(containerID, req)
+	if err != nil {
+		global.GVA_LOG.Error("重建容器失败", zap.String("containerID", containerID), zap.Error(err))
+		if err.Error() == "container not found" {
+			response.FailWithMessage("容器不存在", c)
+			return
+		}
+		response.FailWithMessage("重建容器失败: "+err.Error(), c)
+		return
+	}
+
+	response.OkWithDetailed(detail, "重建成功", c)
+}
+
 // StartContainer 启动容器
 // @Tags Docker
 // @Summary 启动Docker容器
@@ -146,7 +250,7 @@ func (d *DockerContainerApi) StartContainer(c *gin.Context) {
 	}
 
 	// 调用服务层启动容器
-	err := dockerContainerService.StartContainer(containerID)
+	err := onHost(c, dockerContainerService).StartContainer(containerID)
 	if err != nil {
 		global.GVA_LOG.Error("启动容器失败", zap.String("containerID", containerID), zap.Error(err))
 		if err.Error() == "container not found" {
@@ -186,7 +290,7 @@ func (d *DockerContainerApi) StopContainer(c *gin.Context) {
 	}
 
 	// 调用服务层停止容器
-	err := dockerContainerService.StopContainer(containerID, timeout)
+	err := onHost(c, dockerContainerService).StopContainer(containerID, timeout)
 	if err != nil {
 		global.GVA_LOG.Error("停止容器失败", zap.String("containerID", containerID), zap.Error(err))
 		if err.Error() == "container not found" {
@@ -226,7 +330,7 @@ func (d *DockerContainerApi) RestartContainer(c *gin.Context) {
 	}
 
 	// 调用服务层重启容器
-	err := dockerContainerService.RestartContainer(containerID, timeout)
+	err := onHost(c, dockerContainerService).RestartContainer(containerID, timeout)
 	if err != nil {
 		global.GVA_LOG.Error("重启容器失败", zap.String("containerID", containerID), zap.Error(err))
 		if err.Error() == "container not found" {
@@ -261,7 +365,7 @@ func (d *DockerContainerApi) RemoveContainer(c *gin.Context) {
 	force := c.Query("force") == "true"
 
 	// 调用服务层删除容器
-	err := dockerContainerService.RemoveContainer(containerID, force)
+	err := onHost(c, dockerContainerService).RemoveContainer(containerID, force)
 	if err != nil {
 		global.GVA_LOG.Error("删除容器失败", zap.String("containerID", containerID), zap.Error(err))
 		if err.Error() == "container not found" {
@@ -285,7 +389,7 @@ func (d *DockerContainerApi) RemoveContainer(c *gin.Context) {
 // @Router /docker/info [get]
 func (d *DockerContainerApi) GetDockerInfo(c *gin.Context) {
 	// 调用服务层获取Docker信息
-	info, err := dockerContainerService.GetDockerInfo()
+	info, err := onHost(c, dockerContainerService).GetDockerInfo()
 	if err != nil {
 		global.GVA_LOG.Error("获取Docker信息失败", zap.Error(err))
 		response.FailWithMessage("获取Docker信息失败: "+err.Error(), c)
@@ -305,7 +409,7 @@ func (d *DockerContainerApi) GetDockerInfo(c *gin.Context) {
 // @Router /docker/status [get]
 func (d *DockerContainerApi) CheckDockerStatus(c *gin.Context) {
 	// 调用服务层检查Docker状态
-	isAvailable := dockerContainerService.IsDockerAvailable()
+	isAvailable := onHost(c, dockerContainerService).IsDockerAvailable()
 
 	if isAvailable {
 		response.OkWithDetailed(true, "Docker守护进程运行正常", c)
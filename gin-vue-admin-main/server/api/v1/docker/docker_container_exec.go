@@ -0,0 +1,201 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/response"
+	dockerReq "github.com/flipped-aurora/gin-vue-admin/server/model/docker/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+	dockerService "github.com/flipped-aurora/gin-vue-admin/server/service/docker"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"golang.org/x/net/websocket"
+)
+
+// execMessage 终端WebSocket消息
+// 客户端发送 input/resize 文本消息；服务端以二进制消息推送终端输出，会话结束时发送 exit 文本消息
+type execMessage struct {
+	Type   string `json:"type"`             // 消息类型 (input/resize/exit)
+	Data   string `json:"data,omitempty"`   // 输入内容
+	Cols   uint   `json:"cols,omitempty"`   // 终端列数
+	Rows   uint   `json:"rows,omitempty"`   // 终端行数
+	Code   int    `json:"code,omitempty"`   // 退出码
+	Reason string `json:"reason,omitempty"` // 结束原因
+}
+
+// ExecContainer 打开容器交互式终端
+// @Tags Docker
+// @Summary 通过WebSocket打开容器终端
+// @Description 需以WebSocket方式连接。客户端发送 {"type":"input","data":"ls\r"} 输入、{"type":"resize","cols":80,"rows":24} 调整窗口；服务端以二进制消息返回终端输出，结束时发送 {"type":"exit"} 消息
+// @Security ApiKeyAuth
+// @Param id path string true "容器ID"
+// @Param data query dockerReq.ContainerExecOptions false "终端选项"
+// @Success 101 {string} string "切换协议"
+// @Router /docker/containers/{id}/exec [get]
+func (d *DockerContainerApi) ExecContainer(c *gin.Context) {
+	containerID := c.Param("id")
+	if containerID == "" {
+		response.FailWithMessage("容器ID不能为空", c)
+		return
+	}
+	if !isWebSocketRequest(c) {
+		response.FailWithMessage("请使用WebSocket连接终端", c)
+		return
+	}
+
+	var options dockerReq.ContainerExecOptions
+	if err := c.ShouldBindQuery(&options); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	// 先创建会话，失败时仍能以普通JSON响应返回错误
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	session, err := onHost(c, dockerContainerService).StartExecSession(ctx, containerID, options)
+	cancel()
+	if err != nil {
+		global.GVA_LOG.Error("打开容器终端失败", zap.String("containerID", containerID), zap.Error(err))
+		switch err.Error() {
+		case "container not found":
+			response.FailWithMessage("容器不存在", c)
+		case "container is not running":
+			response.FailWithMessage("容器未运行", c)
+		default:
+			response.FailWithMessage("打开容器终端失败: "+err.Error(), c)
+		}
+		return
+	}
+	defer session.Close()
+
+	record := createExecAuditRecord(c, session, options)
+	started := time.Now()
+
+	serveWebSocket(c, func(ws *websocket.Conn) {
+		defer ws.Close()
+		reason := bridgeExecSession(ws, session)
+		session.Close()
+
+		exitCode := session.ExitCode(context.Background())
+		_ = websocket.JSON.Send(ws, execMessage{Type: "exit", Code: exitCode, Reason: reason})
+
+		if record != nil {
+			err := global.GVA_DB.Model(record).Updates(map[string]interface{}{
+				"latency": time.Since(started),
+				"resp":    fmt.Sprintf("exit code %d, %s", exitCode, reason),
+			}).Error
+			if err != nil {
+				global.GVA_LOG.Error("更新终端审计记录失败", zap.Error(err))
+			}
+		}
+	})
+}
+
+// bridgeExecSession 在WebSocket与容器终端之间双向转发数据，返回会话结束原因
+func bridgeExecSession(ws *websocket.Conn, session *dockerService.ContainerExecSession) string {
+	var (
+		once     sync.Once
+		reason   string
+		done     = make(chan struct{})
+		activity = make(chan struct{}, 1)
+	)
+	finish := func(r string) {
+		once.Do(func() {
+			reason = r
+			close(done)
+		})
+	}
+	touch := func() {
+		select {
+		case activity <- struct{}{}:
+		default:
+		}
+	}
+
+	// 容器输出 -> 客户端
+	go func() {
+		buf := make([]byte, 32*1024)
+		for {
+			n, err := session.Conn.Reader.Read(buf)
+			if n > 0 {
+				touch()
+				if err := websocket.Message.Send(ws, buf[:n]); err != nil {
+					finish("client disconnected")
+					return
+				}
+			}
+			if err != nil {
+				finish("process exited")
+				return
+			}
+		}
+	}()
+
+	// 客户端输入 -> 容器
+	go func() {
+		for {
+			var msg execMessage
+			if err := websocket.JSON.Receive(ws, &msg); err != nil {
+				finish("client disconnected")
+				return
+			}
+			touch()
+			switch msg.Type {
+			case "input":
+				if _, err := session.Conn.Conn.Write([]byte(msg.Data)); err != nil {
+					finish("process exited")
+					return
+				}
+			case "resize":
+				if err := session.Resize(context.Background(), msg.Rows, msg.Cols); err != nil {
+					global.GVA_LOG.Warn("调整终端大小失败", zap.String("execID", session.ID), zap.Error(err))
+				}
+			}
+		}
+	}()
+
+	idle := time.NewTimer(session.IdleTimeout)
+	defer idle.Stop()
+	for {
+		select {
+		case <-done:
+			return reason
+		case <-activity:
+			idle.Reset(session.IdleTimeout)
+		case <-idle.C:
+			finish("idle timeout")
+			return reason
+		}
+	}
+}
+
+// createExecAuditRecord 在操作记录中登记谁在哪个容器上打开了终端
+func createExecAuditRecord(c *gin.Context, session *dockerService.ContainerExecSession, options dockerReq.ContainerExecOptions) *system.SysOperationRecord {
+	body, _ := json.Marshal(map[string]string{
+		"containerId": session.ContainerID,
+		"execId":      session.ID,
+		"shell":       options.Shell,
+		"user":        options.User,
+		"workDir":     options.WorkDir,
+	})
+	record := &system.SysOperationRecord{
+		Ip:     c.ClientIP(),
+		Method: c.Request.Method,
+		Path:   c.Request.URL.Path,
+		Status: http.StatusSwitchingProtocols,
+		Agent:  c.Request.UserAgent(),
+		Body:   string(body),
+		UserID: int(utils.GetUserID(c)),
+	}
+	if err := global.GVA_DB.Create(record).Error; err != nil {
+		global.GVA_LOG.Error("创建终端审计记录失败", zap.Error(err))
+		return nil
+	}
+	return record
+}
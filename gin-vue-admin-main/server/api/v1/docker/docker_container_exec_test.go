@@ -0,0 +1,145 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/client"
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	dockerReq "github.com/flipped-aurora/gin-vue-admin/server/model/docker/request"
+	dockerService "github.com/flipped-aurora/gin-vue-admin/server/service/docker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"golang.org/x/net/websocket"
+)
+
+// fakeExecDaemon 模拟守护进程的 exec 接口：终端进程回显输入，记录窗口大小调整
+type fakeExecDaemon struct {
+	server  *httptest.Server
+	resizes chan string
+	process chan net.Conn // 终端进程的连接，关闭即模拟进程退出
+}
+
+func newFakeExecDaemon(t *testing.T) *fakeExecDaemon {
+	d := &fakeExecDaemon{resizes: make(chan string, 10), process: make(chan net.Conn, 1)}
+	d.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/containers/web/json"):
+			fmt.Fprint(w, `{"Id":"abc","State":{"Running":true}}`)
+		case strings.HasSuffix(r.URL.Path, "/containers/abc/exec"):
+			fmt.Fprint(w, `{"Id":"exec1"}`)
+		case strings.HasSuffix(r.URL.Path, "/exec/exec1/resize"):
+			d.resizes <- r.URL.Query().Get("h") + "x" + r.URL.Query().Get("w")
+		case strings.HasSuffix(r.URL.Path, "/exec/exec1/json"):
+			fmt.Fprint(w, `{"Running":false,"ExitCode":0}`)
+		case strings.HasSuffix(r.URL.Path, "/exec/exec1/start"):
+			_, _ = io.ReadAll(r.Body)
+			conn, buf, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				return
+			}
+			fmt.Fprint(buf, "HTTP/1.1 101 UPGRADED\r\nContent-Type: application/vnd.docker.raw-stream\r\nConnection: Upgrade\r\nUpgrade: tcp\r\n\r\n")
+			_ = buf.Flush()
+			d.process <- conn
+			go func() {
+				_, _ = io.Copy(conn, buf)
+				conn.Close()
+			}()
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(d.server.Close)
+	return d
+}
+
+// startSession 通过模拟守护进程打开终端会话，并在 WebSocket 服务端运行转发
+func (d *fakeExecDaemon) startSession(t *testing.T, idleTimeout time.Duration) (*websocket.Conn, *dockerService.ContainerExecSession, <-chan string) {
+	oldLog := global.GVA_LOG
+	global.GVA_LOG = zap.NewNop()
+	t.Cleanup(func() { global.GVA_LOG = oldLog })
+
+	cli, err := client.NewClientWithOpts(client.WithHost("tcp://"+d.server.Listener.Addr().String()), client.WithVersion("1.41"))
+	require.NoError(t, err)
+	t.Cleanup(func() { cli.Close() })
+	svc := &dockerService.DockerContainerService{}
+	svc.UseHost(0, cli)
+	session, err := svc.StartExecSession(context.Background(), "web", dockerReq.ContainerExecOptions{})
+	require.NoError(t, err)
+	t.Cleanup(session.Close)
+	session.IdleTimeout = idleTimeout
+
+	reasons := make(chan string, 1)
+	wsServer := httptest.NewServer(websocket.Handler(func(ws *websocket.Conn) {
+		reasons <- bridgeExecSession(ws, session)
+	}))
+	t.Cleanup(wsServer.Close)
+	ws, err := websocket.Dial("ws://"+wsServer.Listener.Addr().String(), "", "http://localhost/")
+	require.NoError(t, err)
+	t.Cleanup(func() { ws.Close() })
+	return ws, session, reasons
+}
+
+func waitReason(t *testing.T, reasons <-chan string) string {
+	select {
+	case reason := <-reasons:
+		return reason
+	case <-time.After(5 * time.Second):
+		t.Fatal("exec bridge did not finish")
+		return ""
+	}
+}
+
+func TestBridgeExecSessionInputAndResize(t *testing.T) {
+	daemon := newFakeExecDaemon(t)
+	ws, _, reasons := daemon.startSession(t, time.Minute)
+
+	require.NoError(t, websocket.JSON.Send(ws, execMessage{Type: "resize", Cols: 120, Rows: 40}))
+	select {
+	case size := <-daemon.resizes:
+		assert.Equal(t, "40x120", size)
+	case <-time.After(5 * time.Second):
+		t.Fatal("resize was not forwarded")
+	}
+	// 行列为 0 的调整请求被忽略
+	require.NoError(t, websocket.JSON.Send(ws, execMessage{Type: "resize"}))
+
+	require.NoError(t, websocket.JSON.Send(ws, execMessage{Type: "input", Data: "ls\r"}))
+	var output []byte
+	require.NoError(t, websocket.Message.Receive(ws, &output))
+	assert.Equal(t, "ls\r", string(output))
+	assert.Empty(t, daemon.resizes)
+
+	ws.Close()
+	assert.Equal(t, "client disconnected", waitReason(t, reasons))
+}
+
+func TestBridgeExecSessionProcessExit(t *testing.T) {
+	daemon := newFakeExecDaemon(t)
+	_, _, reasons := daemon.startSession(t, time.Minute)
+
+	(<-daemon.process).Close()
+	assert.Equal(t, "process exited", waitReason(t, reasons))
+}
+
+func TestBridgeExecSessionIdleTimeout(t *testing.T) {
+	daemon := newFakeExecDaemon(t)
+	ws, _, reasons := daemon.startSession(t, 300*time.Millisecond)
+
+	// 输入与输出都会重置空闲计时
+	started := time.Now()
+	for i := 0; i < 3; i++ {
+		time.Sleep(150 * time.Millisecond)
+		require.NoError(t, websocket.JSON.Send(ws, execMessage{Type: "input", Data: "x"}))
+	}
+	assert.Equal(t, "idle timeout", waitReason(t, reasons))
+	assert.GreaterOrEqual(t, time.Since(started), 750*time.Millisecond)
+}
@@ -0,0 +1,77 @@
+package docker
+
+import (
+	"context"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/response"
+	dockerModel "github.com/flipped-aurora/gin-vue-admin/server/model/docker"
+	dockerReq "github.com/flipped-aurora/gin-vue-admin/server/model/docker/request"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type DockerEventApi struct{}
+
+// GetDockerEventList 获取Docker事件时间线
+// @Tags Docker
+// @Summary 分页获取所选主机记录的容器、镜像、网络、存储卷事件，按时间倒序
+// @Security ApiKeyAuth
+// @Produce application/json
+// @Param data query dockerReq.DockerEventFilter false "查询参数"
+// @Success 200 {object} response.Response{data=response.PageResult,msg=string} "获取成功"
+// @Router /docker/events [get]
+func (d *DockerEventApi) GetDockerEventList(c *gin.Context) {
+	var filter dockerReq.DockerEventFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	// 设置默认分页参数
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+	if filter.PageSize <= 0 || filter.PageSize > 100 {
+		filter.PageSize = 10
+	}
+
+	events, total, err := onHost(c, dockerEventService).GetEventList(filter)
+	if err != nil {
+		global.GVA_LOG.Error("获取Docker事件失败", zap.Error(err))
+		response.FailWithMessage("获取Docker事件失败: "+err.Error(), c)
+		return
+	}
+
+	response.OkWithDetailed(response.PageResult{
+		List:     events,
+		Total:    total,
+		Page:     filter.Page,
+		PageSize: filter.PageSize,
+	}, "获取成功", c)
+}
+
+// StreamDockerEvents 实时推送Docker事件
+// @Tags Docker
+// @Summary 实时推送所选主机新记录的Docker事件，支持WebSocket与SSE两种方式
+// @Description 携带 Upgrade: websocket 头时升级为WebSocket，每条消息为一个事件JSON；否则以SSE推送 event 事件
+// @Security ApiKeyAuth
+// @Produce text/event-stream
+// @Param data query dockerReq.DockerEventStreamOptions false "过滤条件"
+// @Success 200 {object} docker.DockerEvent "事件"
+// @Router /docker/events/live [get]
+func (d *DockerEventApi) StreamDockerEvents(c *gin.Context) {
+	var options dockerReq.DockerEventStreamOptions
+	if err := c.ShouldBindQuery(&options); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	serveEventStream(c, "event", func(ctx context.Context, emit func(data interface{}) error) error {
+		return onHost(c, dockerEventService).StreamEvents(ctx, options, func(event dockerModel.DockerEvent) error {
+			return emit(event)
+		})
+	}, func(err error) {
+		response.FailWithMessage("推送Docker事件失败: "+err.Error(), c)
+	})
+}
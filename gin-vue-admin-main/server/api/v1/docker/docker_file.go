@@ -0,0 +1,259 @@
+package docker
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/response"
+	dockerReq "github.com/flipped-aurora/gin-vue-admin/server/model/docker/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type DockerFileApi struct{}
+
+// ListFiles 列出容器或存储卷中的目录
+// @Tags Docker
+// @Summary 列出容器（/docker/containers/{id}/files）或存储卷（/docker/volumes/{name}/files）中的目录内容
+// @Security ApiKeyAuth
+// @Produce application/json
+// @Param id path string true "容器ID或存储卷名称"
+// @Param data query dockerReq.FilePathQuery false "目录路径"
+// @Success 200 {object} response.Response{data=[]dockerRes.FileEntry,msg=string} "获取成功"
+// @Router /docker/containers/{id}/files [get]
+func (d *DockerFileApi) ListFiles(c *gin.Context) {
+	var query dockerReq.FilePathQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	entries, err := onHost(c, dockerFileService).ListFiles(fileTarget(c), query.Path)
+	if err != nil {
+		failDockerFile(c, "列出目录失败", err)
+		return
+	}
+
+	response.OkWithDetailed(entries, "获取成功", c)
+}
+
+// StatFile 获取容器或存储卷中的文件信息
+// @Tags Docker
+// @Summary 获取容器或存储卷中文件或目录的大小、权限、修改时间与链接指向
+// @Security ApiKeyAuth
+// @Produce application/json
+// @Param id path string true "容器ID或存储卷名称"
+// @Param data query dockerReq.FilePathQuery true "文件路径"
+// @Success 200 {object} response.Response{data=dockerRes.FileEntry,msg=string} "获取成功"
+// @Router /docker/containers/{id}/files/stat [get]
+func (d *DockerFileApi) StatFile(c *gin.Context) {
+	var query dockerReq.FilePathQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	entry, err := onHost(c, dockerFileService).StatFile(fileTarget(c), query.Path)
+	if err != nil {
+		failDockerFile(c, "获取文件信息失败", err)
+		return
+	}
+
+	response.OkWithDetailed(entry, "获取成功", c)
+}
+
+// DownloadFile 下载容器或存储卷中的文件
+// @Tags Docker
+// @Summary 下载容器或存储卷中的普通文件，大小不超过 docker.file-max-size
+// @Security ApiKeyAuth
+// @Produce application/octet-stream
+// @Param id path string true "容器ID或存储卷名称"
+// @Param data query dockerReq.FilePathQuery true "文件路径"
+// @Success 200 {file} file "文件内容"
+// @Router /docker/containers/{id}/files/download [get]
+func (d *DockerFileApi) DownloadFile(c *gin.Context) {
+	var query dockerReq.FilePathQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	defer createFileTransferRecord(c, time.Now(), query.Path)
+
+	reader, entry, err := onHost(c, dockerFileService).OpenFile(c.Request.Context(), fileTarget(c), query.Path)
+	if err != nil {
+		failDockerFile(c, "下载文件失败", err)
+		return
+	}
+	defer reader.Close()
+
+	c.Header("Content-Type", "application/octet-stream")
+	c.Header("Content-Disposition", "attachment; filename*=UTF-8''"+url.PathEscape(entry.Name))
+	c.Status(http.StatusOK)
+	if _, err := io.Copy(c.Writer, reader); err != nil {
+		global.GVA_LOG.Error("传输文件失败", zap.String("path", entry.Path), zap.Error(err))
+	}
+}
+
+// UploadFile 上传文件到容器或存储卷
+// @Tags Docker
+// @Summary 上传文件到容器或存储卷中的目录，大小不超过 docker.file-max-size
+// @Security ApiKeyAuth
+// @accept multipart/form-data
+// @Produce application/json
+// @Param id path string true "容器ID或存储卷名称"
+// @Param file formData file true "文件"
+// @Param path formData string false "目标目录，默认为根目录"
+// @Param overwrite formData bool false "同名文件已存在时覆盖"
+// @Success 200 {object} response.Response{data=dockerRes.FileEntry,msg=string} "上传成功"
+// @Router /docker/containers/{id}/files/upload [post]
+func (d *DockerFileApi) UploadFile(c *gin.Context) {
+	var uploadReq dockerReq.FileUploadRequest
+	if err := c.ShouldBind(&uploadReq); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	header, err := c.FormFile("file")
+	if err != nil {
+		response.FailWithMessage("请选择要上传的文件", c)
+		return
+	}
+	defer createFileTransferRecord(c, time.Now(), path.Join("/", uploadReq.Path, header.Filename))
+	file, err := header.Open()
+	if err != nil {
+		response.FailWithMessage("读取上传文件失败: "+err.Error(), c)
+		return
+	}
+	defer file.Close()
+
+	entry, err := onHost(c, dockerFileService).UploadFile(fileTarget(c), uploadReq.Path, header.Filename, file, header.Size, uploadReq.Overwrite)
+	if err != nil {
+		failDockerFile(c, "上传文件失败", err)
+		return
+	}
+
+	response.OkWithDetailed(entry, "上传成功", c)
+}
+
+// MakeDir 在容器或存储卷中创建目录
+// @Tags Docker
+// @Summary 在容器或存储卷中创建目录，上级目录必须存在
+// @Security ApiKeyAuth
+// @accept application/json
+// @Produce application/json
+// @Param id path string true "容器ID或存储卷名称"
+// @Param data body dockerReq.FilePathRequest true "目录路径"
+// @Success 200 {object} response.Response{msg=string} "创建成功"
+// @Router /docker/containers/{id}/files/mkdir [post]
+func (d *DockerFileApi) MakeDir(c *gin.Context) {
+	var pathReq dockerReq.FilePathRequest
+	if err := c.ShouldBindJSON(&pathReq); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	if err := onHost(c, dockerFileService).MakeDir(fileTarget(c), pathReq.Path); err != nil {
+		failDockerFile(c, "创建目录失败", err)
+		return
+	}
+
+	response.OkWithMessage("创建成功", c)
+}
+
+// DeleteFile 删除容器或存储卷中的文件
+// @Tags Docker
+// @Summary 删除容器或存储卷中的文件或目录（递归），容器中的文件需在容器运行时删除
+// @Security ApiKeyAuth
+// @accept application/json
+// @Produce application/json
+// @Param id path string true "容器ID或存储卷名称"
+// @Param data body dockerReq.FilePathRequest true "文件路径"
+// @Success 200 {object} response.Response{msg=string} "删除成功"
+// @Router /docker/containers/{id}/files/delete [post]
+func (d *DockerFileApi) DeleteFile(c *gin.Context) {
+	var pathReq dockerReq.FilePathRequest
+	if err := c.ShouldBindJSON(&pathReq); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	if err := onHost(c, dockerFileService).DeleteFile(fileTarget(c), pathReq.Path); err != nil {
+		failDockerFile(c, "删除文件失败", err)
+		return
+	}
+
+	response.OkWithMessage("删除成功", c)
+}
+
+// fileTarget 容器路由使用 :id 参数，存储卷路由使用 :name 参数
+func fileTarget(c *gin.Context) dockerReq.FileTarget {
+	if id := c.Param("id"); id != "" {
+		return dockerReq.FileTarget{Container: id}
+	}
+	return dockerReq.FileTarget{Volume: c.Param("name")}
+}
+
+// createFileTransferRecord 下载与上传不经过操作记录中间件（中间件会把整个文件缓存在内存中），
+// 传输结束后在操作记录中登记目标与路径
+func createFileTransferRecord(c *gin.Context, start time.Time, filePath string) {
+	target := fileTarget(c)
+	body, _ := json.Marshal(map[string]string{
+		"container": target.Container,
+		"volume":    target.Volume,
+		"path":      filePath,
+	})
+	record := system.SysOperationRecord{
+		Ip:      c.ClientIP(),
+		Method:  c.Request.Method,
+		Path:    c.Request.URL.Path,
+		Status:  c.Writer.Status(),
+		Latency: time.Since(start),
+		Agent:   c.Request.UserAgent(),
+		Body:    string(body),
+		UserID:  int(utils.GetUserID(c)),
+	}
+	if err := global.GVA_DB.Create(&record).Error; err != nil {
+		global.GVA_LOG.Error("创建文件传输操作记录失败", zap.Error(err))
+	}
+}
+
+// failDockerFile 将服务层错误转换为中文提示
+func failDockerFile(c *gin.Context, prefix string, err error) {
+	global.GVA_LOG.Error(prefix, zap.Error(err))
+	msg := err.Error()
+	switch {
+	case msg == "container not found":
+		response.FailWithMessage("容器不存在", c)
+	case msg == "volume not found":
+		response.FailWithMessage("存储卷不存在", c)
+	case msg == "file not found":
+		response.FailWithMessage("文件或目录不存在", c)
+	case msg == "not a directory":
+		response.FailWithMessage("路径不是目录", c)
+	case msg == "path is a directory":
+		response.FailWithMessage("不能下载目录", c)
+	case msg == "path is a symbolic link":
+		response.FailWithMessage("路径是符号链接，请打开链接指向的文件", c)
+	case msg == "file already exists":
+		response.FailWithMessage("文件或目录已存在", c)
+	case msg == "invalid path", msg == "invalid file name", msg == "path escapes volume":
+		response.FailWithMessage("非法的路径或文件名", c)
+	case msg == "cannot delete root directory":
+		response.FailWithMessage("不能删除根目录", c)
+	case msg == "directory is too large to list":
+		response.FailWithMessage("目录内容过大，无法列出，请进入子目录查看", c)
+	case strings.HasPrefix(msg, "file exceeds size limit"):
+		response.FailWithMessage("文件超过大小上限: "+strings.TrimPrefix(msg, "file exceeds size limit of "), c)
+	case strings.Contains(msg, "container must be running to delete files"):
+		response.FailWithMessage("容器未运行，无法删除文件", c)
+	default:
+		response.FailWithMessage(prefix+": "+msg, c)
+	}
+}
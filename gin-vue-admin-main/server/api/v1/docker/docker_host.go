@@ -0,0 +1,203 @@
+package docker
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/client"
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/response"
+	dockerReq "github.com/flipped-aurora/gin-vue-admin/server/model/docker/request"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type DockerHostApi struct{}
+
+// onHost 复制服务并绑定 DockerHost 中间件选择的主机，未选择主机时使用默认主机
+func onHost[T any, PT interface {
+	*T
+	UseHost(hostID uint, cli *client.Client)
+}](c *gin.Context, svc T) PT {
+	bound := PT(&svc)
+	if value, ok := c.Get("dockerClient"); ok {
+		if cli, ok := value.(*client.Client); ok {
+			bound.UseHost(c.GetUint("dockerHostId"), cli)
+		}
+	}
+	return bound
+}
+
+// GetDockerHostList 获取Docker主机列表
+// @Tags Docker
+// @Summary 获取Docker主机列表，第一项为配置文件中的默认主机（ID为0）
+// @Security ApiKeyAuth
+// @Produce application/json
+// @Success 200 {object} response.Response{data=[]docker.DockerHost,msg=string} "获取成功"
+// @Router /docker/hosts [get]
+func (d *DockerHostApi) GetDockerHostList(c *gin.Context) {
+	hosts, err := dockerHostService.GetDockerHostList()
+	if err != nil {
+		global.GVA_LOG.Error("获取Docker主机列表失败", zap.Error(err))
+		response.FailWithMessage("获取Docker主机列表失败: "+err.Error(), c)
+		return
+	}
+
+	response.OkWithDetailed(hosts, "获取成功", c)
+}
+
+// CreateDockerHost 添加Docker主机
+// @Tags Docker
+// @Summary 添加Docker主机，支持 unix、tcp（可配置TLS证书目录）与 ssh 端点
+// @Security ApiKeyAuth
+// @accept application/json
+// @Produce application/json
+// @Param data body dockerReq.DockerHostRequest true "主机信息"
+// @Success 200 {object} response.Response{data=docker.DockerHost,msg=string} "添加成功"
+// @Router /docker/hosts [post]
+func (d *DockerHostApi) CreateDockerHost(c *gin.Context) {
+	var req dockerReq.DockerHostRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	host, err := dockerHostService.CreateDockerHost(req)
+	if err != nil {
+		failDockerHost(c, "添加Docker主机失败", err)
+		return
+	}
+
+	response.OkWithDetailed(host, "添加成功", c)
+}
+
+// UpdateDockerHost 更新Docker主机
+// @Tags Docker
+// @Summary 更新Docker主机，ssh 凭据为空时保持不变，修改后重新建立连接
+// @Security ApiKeyAuth
+// @accept application/json
+// @Produce application/json
+// @Param data body dockerReq.DockerHostRequest true "主机信息"
+// @Success 200 {object} response.Response{data=docker.DockerHost,msg=string} "更新成功"
+// @Router /docker/hosts [put]
+func (d *DockerHostApi) UpdateDockerHost(c *gin.Context) {
+	var req dockerReq.DockerHostRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	if req.ID == 0 {
+		response.FailWithMessage("主机ID不能为空，默认主机请在Docker配置中修改", c)
+		return
+	}
+
+	host, err := dockerHostService.UpdateDockerHost(req)
+	if err != nil {
+		failDockerHost(c, "更新Docker主机失败", err)
+		return
+	}
+
+	response.OkWithDetailed(host, "更新成功", c)
+}
+
+// DeleteDockerHost 删除Docker主机
+// @Tags Docker
+// @Summary 删除Docker主机并关闭其连接
+// @Security ApiKeyAuth
+// @Produce application/json
+// @Param id path int true "主机ID"
+// @Success 200 {object} response.Response{msg=string} "删除成功"
+// @Router /docker/hosts/{id} [delete]
+func (d *DockerHostApi) DeleteDockerHost(c *gin.Context) {
+	id, ok := parseDockerHostID(c)
+	if !ok {
+		return
+	}
+	if id == 0 {
+		response.FailWithMessage("默认主机不能删除", c)
+		return
+	}
+
+	if err := dockerHostService.DeleteDockerHost(id); err != nil {
+		failDockerHost(c, "删除Docker主机失败", err)
+		return
+	}
+
+	response.OkWithMessage("删除成功", c)
+}
+
+// TestDockerHost 测试Docker主机连接
+// @Tags Docker
+// @Summary 测试Docker主机连接并返回版本与资源概况，ID为0时测试默认主机
+// @Security ApiKeyAuth
+// @Produce application/json
+// @Param id path int true "主机ID"
+// @Success 200 {object} response.Response{data=dockerRes.DockerHostStatus,msg=string} "测试完成"
+// @Router /docker/hosts/{id}/test [post]
+func (d *DockerHostApi) TestDockerHost(c *gin.Context) {
+	id, ok := parseDockerHostID(c)
+	if !ok {
+		return
+	}
+
+	status, err := dockerHostService.TestDockerHost(id)
+	if err != nil {
+		failDockerHost(c, "测试Docker主机失败", err)
+		return
+	}
+
+	response.OkWithDetailed(status, "测试完成", c)
+}
+
+// GetConnectionStatus 获取默认主机连接状态
+// @Tags Docker
+// @Summary 获取默认主机的连接状态（connected/degraded/down）、最近错误、延迟与状态变化记录
+// @Security ApiKeyAuth
+// @Produce application/json
+// @Success 200 {object} response.Response{data=dockerRes.DockerConnectionStatus,msg=string} "获取成功"
+// @Router /docker/connection [get]
+func (d *DockerHostApi) GetConnectionStatus(c *gin.Context) {
+	response.OkWithDetailed(dockerConnectionService.GetConnectionStatus(), "获取成功", c)
+}
+
+// CheckConnection 立即检查默认主机连接
+// @Tags Docker
+// @Summary 立即检查默认主机连接，上次检查失败时先重建客户端
+// @Security ApiKeyAuth
+// @Produce application/json
+// @Success 200 {object} response.Response{data=dockerRes.DockerConnectionStatus,msg=string} "检查完成"
+// @Router /docker/connection/check [post]
+func (d *DockerHostApi) CheckConnection(c *gin.Context) {
+	response.OkWithDetailed(dockerConnectionService.CheckConnection(), "检查完成", c)
+}
+
+func parseDockerHostID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.FailWithMessage("无效的主机ID", c)
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// failDockerHost 将服务层错误转换为中文提示
+func failDockerHost(c *gin.Context, prefix string, err error) {
+	global.GVA_LOG.Error(prefix, zap.Error(err))
+	msg := err.Error()
+	switch {
+	case msg == "docker host not found":
+		response.FailWithMessage("Docker主机不存在", c)
+	case msg == "docker host name already exists":
+		response.FailWithMessage("主机名称已存在", c)
+	case msg == "invalid docker host name":
+		response.FailWithMessage("主机名称不能为纯数字或 local", c)
+	case msg == "ssh endpoint requires a private key or password":
+		response.FailWithMessage("ssh 端点需要填写私钥或密码", c)
+	case strings.HasPrefix(msg, "unsupported docker host scheme"):
+		response.FailWithMessage("不支持的端点类型，仅支持 unix、tcp、ssh", c)
+	case strings.HasPrefix(msg, "certificate directory not found"):
+		response.FailWithMessage("证书目录不存在", c)
+	default:
+		response.FailWithMessage(prefix+": "+msg, c)
+	}
+}
@@ -2,11 +2,13 @@ package docker
 
 import (
 	"io"
+	"net/http"
 
 	"github.com/flipped-aurora/gin-vue-admin/server/global"
 	"github.com/flipped-aurora/gin-vue-admin/server/model/common/response"
 	dockerReq "github.com/flipped-aurora/gin-vue-admin/server/model/docker/request"
 	dockerRes "github.com/flipped-aurora/gin-vue-admin/server/model/docker/response"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
@@ -39,7 +41,7 @@ func (d *DockerImageApi) GetImageList(c *gin.Context) {
 	}
 
 	// 调用服务层获取镜像列表
-	images, total, err := dockerImageService.GetImageList(pageInfo)
+	images, total, err := onHost(c, dockerImageService).GetImageList(pageInfo)
 	if err != nil {
 		global.GVA_LOG.Error("获取镜像列表失败", zap.Error(err))
 		response.FailWithMessage("获取镜像列表失败: "+err.Error(), c)
@@ -76,7 +78,7 @@ func (d *DockerImageApi) GetImageDetail(c *gin.Context) {
 	}
 
 	// 调用服务层获取镜像详细信息
-	imageDetail, err := dockerImageService.GetImageDetail(imageID)
+	imageDetail, err := onHost(c, dockerImageService).GetImageDetail(imageID)
 	if err != nil {
 		global.GVA_LOG.Error("获取镜像详细信息失败", zap.String("imageID", imageID), zap.Error(err))
 		if err.Error() == "image not found" {
@@ -108,7 +110,7 @@ func (d *DockerImageApi) PullImage(c *gin.Context) {
 	}
 
 	// 调用服务层拉取镜像
-	pullLog, err := dockerImageService.PullImage(pullReq)
+	pullLog, err := onHost(c, dockerImageService).PullImage(pullReq, utils.GetUserID(c))
 	if err != nil {
 		global.GVA_LOG.Error("拉取镜像失败", zap.String("image", pullReq.Image), zap.Error(err))
 		response.FailWithMessage("拉取镜像失败: "+err.Error(), c)
@@ -118,6 +120,34 @@ func (d *DockerImageApi) PullImage(c *gin.Context) {
 	response.OkWithDetailed(pullLog, "镜像拉取成功", c)
 }
 
+// PushImage 推送镜像
+// @Tags Docker
+// @Summary 推送Docker镜像到仓库
+// @Security ApiKeyAuth
+// @accept application/json
+// @Produce application/json
+// @Param data body dockerReq.ImagePushRequest true "推送镜像参数"
+// @Success 200 {object} response.Response{data=string,msg=string} "推送成功"
+// @Router /docker/images/push [post]
+func (d *DockerImageApi) PushImage(c *gin.Context) {
+	var pushReq dockerReq.ImagePushRequest
+	err := c.ShouldBindJSON(&pushReq)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	// 调用服务层推送镜像
+	pushLog, err := onHost(c, dockerImageService).PushImage(pushReq)
+	if err != nil {
+		global.GVA_LOG.Error("推送镜像失败", zap.String("image", pushReq.Image), zap.Error(err))
+		response.FailWithMessage("推送镜像失败: "+err.Error(), c)
+		return
+	}
+
+	response.OkWithDetailed(pushLog, "镜像推送成功", c)
+}
+
 // RemoveImage 删除镜像
 // @Tags Docker
 // @Summary 删除Docker镜像
@@ -139,7 +169,7 @@ func (d *DockerImageApi) RemoveImage(c *gin.Context) {
 	force := c.Query("force") == "true"
 
 	// 调用服务层删除镜像
-	err := dockerImageService.RemoveImage(imageID, force)
+	err := onHost(c, dockerImageService).RemoveImage(imageID, force)
 	if err != nil {
 		global.GVA_LOG.Error("删除镜像失败", zap.String("imageID", imageID), zap.Error(err))
 		if err.Error() == "image not found" {
@@ -171,7 +201,7 @@ func (d *DockerImageApi) TagImage(c *gin.Context) {
 	}
 
 	// 调用服务层给镜像打标签
-	err = dockerImageService.TagImage(tagReq)
+	err = onHost(c, dockerImageService).TagImage(tagReq)
 	if err != nil {
 		global.GVA_LOG.Error("镜像打标签失败",
 			zap.String("source", tagReq.SourceImage),
@@ -199,7 +229,7 @@ func (d *DockerImageApi) PruneImages(c *gin.Context) {
 	dangling := danglingStr == "true"
 
 	// 调用服务层清理镜像
-	deletedCount, spaceReclaimed, err := dockerImageService.PruneImages(dangling)
+	deletedCount, spaceReclaimed, err := onHost(c, dockerImageService).PruneImages(dangling)
 	if err != nil {
 		global.GVA_LOG.Error("清理镜像失败", zap.Error(err))
 		response.FailWithMessage("清理镜像失败: "+err.Error(), c)
@@ -217,7 +247,7 @@ func (d *DockerImageApi) PruneImages(c *gin.Context) {
 
 // BuildImage 构建镜像
 // @Tags Docker
-// @Summary 构建Docker镜像
+// @Summary 构建Docker镜像，构建上下文可以是Dockerfile内容、上传的压缩包、服务器目录或Git仓库
 // @Security ApiKeyAuth
 // @accept application/json
 // @Produce application/json
@@ -233,7 +263,7 @@ func (d *DockerImageApi) BuildImage(c *gin.Context) {
 	}
 
 	// 调用服务层构建镜像
-	buildLog, err := dockerImageService.BuildImage(buildReq)
+	buildLog, err := onHost(c, dockerImageService).BuildImage(buildReq)
 	if err != nil {
 		global.GVA_LOG.Error("构建镜像失败", 
 			zap.String("imageName", buildReq.ImageName), 
@@ -246,9 +276,36 @@ func (d *DockerImageApi) BuildImage(c *gin.Context) {
 	response.OkWithDetailed(buildLog, "镜像构建成功", c)
 }
 
+// UploadBuildContext 上传构建上下文
+// @Tags Docker
+// @Summary 上传构建上下文压缩包（tar/tar.gz/tgz/zip），构建时通过 uploadId 引用，24小时后自动清理
+// @Security ApiKeyAuth
+// @accept multipart/form-data
+// @Produce application/json
+// @Param file formData file true "构建上下文压缩包"
+// @Success 200 {object} response.Response{data=dockerRes.BuildContextUploadResponse,msg=string} "上传成功"
+// @Router /docker/images/build/context [post]
+func (d *DockerImageApi) UploadBuildContext(c *gin.Context) {
+	header, err := c.FormFile("file")
+	if err != nil {
+		response.FailWithMessage("请选择要上传的构建上下文文件", c)
+		return
+	}
+
+	result, err := onHost(c, dockerImageService).SaveBuildContext(header)
+	if err != nil {
+		global.GVA_LOG.Error("上传构建上下文失败", zap.String("file", header.Filename), zap.Error(err))
+		response.FailWithMessage("上传构建上下文失败: "+err.Error(), c)
+		return
+	}
+
+	response.OkWithDetailed(result, "上传成功", c)
+}
+
 // ExportImage 导出镜像
 // @Tags Docker
-// @Summary 导出Docker镜像
+// @Summary 导出Docker镜像，支持gzip压缩；target=oss时上传到对象存储并登记到文件管理
+// @Description 直接下载时以流式传输，不在服务端缓存整个镜像
 // @Security ApiKeyAuth
 // @accept application/json
 // @Produce application/octet-stream
@@ -263,8 +320,19 @@ func (d *DockerImageApi) ExportImage(c *gin.Context) {
 		return
 	}
 
-	// 调用服务层导出镜像
-	reader, err := dockerImageService.ExportImage(exportReq)
+	if exportReq.Target == "oss" {
+		file, err := onHost(c, dockerImageService).ExportImageToOSS(exportReq)
+		if err != nil {
+			global.GVA_LOG.Error("导出镜像失败", zap.Strings("images", exportReq.Images), zap.Error(err))
+			response.FailWithMessage("导出镜像失败: "+err.Error(), c)
+			return
+		}
+		response.OkWithDetailed(file, "镜像已导出到文件管理", c)
+		return
+	}
+
+	// 调用服务层导出镜像，客户端断开时停止导出
+	reader, fileName, err := onHost(c, dockerImageService).ExportImage(c.Request.Context(), exportReq)
 	if err != nil {
 		global.GVA_LOG.Error("导出镜像失败", zap.Strings("images", exportReq.Images), zap.Error(err))
 		response.FailWithMessage("导出镜像失败: "+err.Error(), c)
@@ -273,8 +341,13 @@ func (d *DockerImageApi) ExportImage(c *gin.Context) {
 	defer reader.Close()
 
 	// 设置响应头
-	c.Header("Content-Type", "application/octet-stream")
-	c.Header("Content-Disposition", "attachment; filename=images.tar")
+	contentType := "application/x-tar"
+	if exportReq.Compress {
+		contentType = "application/gzip"
+	}
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", "attachment; filename="+fileName)
+	c.Status(http.StatusOK)
 
 	// 将镜像数据流式传输给客户端
 	_, err = io.Copy(c.Writer, reader)
@@ -288,12 +361,13 @@ func (d *DockerImageApi) ExportImage(c *gin.Context) {
 
 // ImportImage 导入镜像
 // @Tags Docker
-// @Summary 导入Docker镜像
+// @Summary 导入Docker镜像，来源可以是服务器文件、URL或分片上传完成的文件
+// @Description 自动识别 docker save 镜像包（ImageLoad）与 rootfs 包（ImageImport），返回导入的镜像ID与标签
 // @Security ApiKeyAuth
 // @accept application/json
 // @Produce application/json
 // @Param data body dockerReq.ImageImportRequest true "导入镜像参数"
-// @Success 200 {object} response.Response{data=string,msg=string} "导入成功"
+// @Success 200 {object} response.Response{data=dockerRes.ImageImportResult,msg=string} "导入成功"
 // @Router /docker/images/import [post]
 func (d *DockerImageApi) ImportImage(c *gin.Context) {
 	var importReq dockerReq.ImageImportRequest
@@ -304,12 +378,90 @@ func (d *DockerImageApi) ImportImage(c *gin.Context) {
 	}
 
 	// 调用服务层导入镜像
-	importLog, err := dockerImageService.ImportImage(importReq)
+	result, err := onHost(c, dockerImageService).ImportImage(importReq)
 	if err != nil {
-		global.GVA_LOG.Error("导入镜像失败", zap.String("source", importReq.Source), zap.Error(err))
+		global.GVA_LOG.Error("导入镜像失败", zap.String("source", importReq.Source), zap.String("fileName", importReq.FileName), zap.Error(err))
 		response.FailWithMessage("导入镜像失败: "+err.Error(), c)
 		return
 	}
 
-	response.OkWithDetailed(importLog, "镜像导入成功", c)
+	response.OkWithDetailed(result, "镜像导入成功", c)
+}
+
+// ImportImageUpload 上传并导入镜像
+// @Tags Docker
+// @Summary 上传镜像包并导入，适用于较小的文件，大文件请使用分片上传
+// @Security ApiKeyAuth
+// @accept multipart/form-data
+// @Produce application/json
+// @Param file formData file true "镜像包（docker save 包或 rootfs 包，可gzip压缩）"
+// @Param data formData dockerReq.ImageImportRequest false "导入参数"
+// @Success 200 {object} response.Response{data=dockerRes.ImageImportResult,msg=string} "导入成功"
+// @Router /docker/images/import/upload [post]
+func (d *DockerImageApi) ImportImageUpload(c *gin.Context) {
+	header, err := c.FormFile("file")
+	if err != nil {
+		response.FailWithMessage("请选择要导入的镜像文件", c)
+		return
+	}
+	var importReq dockerReq.ImageImportRequest
+	if err := c.ShouldBind(&importReq); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	result, err := onHost(c, dockerImageService).ImportImageUpload(header, importReq)
+	if err != nil {
+		global.GVA_LOG.Error("导入镜像失败", zap.String("file", header.Filename), zap.Error(err))
+		response.FailWithMessage("导入镜像失败: "+err.Error(), c)
+		return
+	}
+
+	response.OkWithDetailed(result, "镜像导入成功", c)
+}
+
+// UploadImportChunk 分片上传镜像包
+// @Tags Docker
+// @Summary 分片上传镜像包，全部分片上传后以 sourceType=upload 调用导入接口
+// @Security ApiKeyAuth
+// @accept multipart/form-data
+// @Produce application/json
+// @Param file formData file true "分片内容"
+// @Param data formData dockerReq.ImageImportChunkRequest true "分片信息"
+// @Success 200 {object} response.Response{msg=string} "上传成功"
+// @Router /docker/images/import/chunk [post]
+func (d *DockerImageApi) UploadImportChunk(c *gin.Context) {
+	var chunkReq dockerReq.ImageImportChunkRequest
+	if err := c.ShouldBind(&chunkReq); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	header, err := c.FormFile("file")
+	if err != nil {
+		response.FailWithMessage("接收文件失败", c)
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		response.FailWithMessage("文件读取失败", c)
+		return
+	}
+	defer file.Close()
+	content, err := io.ReadAll(file)
+	if err != nil {
+		response.FailWithMessage("文件读取失败", c)
+		return
+	}
+
+	if err := onHost(c, dockerImageService).SaveImportChunk(chunkReq, content); err != nil {
+		global.GVA_LOG.Error("上传镜像分片失败", zap.String("fileName", chunkReq.FileName), zap.Int("chunk", chunkReq.ChunkNumber), zap.Error(err))
+		if err.Error() == "chunk md5 mismatch" {
+			response.FailWithMessage("检查md5失败", c)
+			return
+		}
+		response.FailWithMessage("上传镜像分片失败: "+err.Error(), c)
+		return
+	}
+
+	response.OkWithMessage("切片创建成功", c)
 }
@@ -0,0 +1,269 @@
+package docker
+
+import (
+	"strconv"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/response"
+	dockerReq "github.com/flipped-aurora/gin-vue-admin/server/model/docker/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type DockerImageInspectApi struct{}
+
+// InspectImage 提交镜像内容检查
+// @Tags Docker
+// @Summary 后台解包镜像层，清点系统软件包、语言依赖锁文件与 setuid/setgid 文件，并与离线漏洞库匹配
+// @Security ApiKeyAuth
+// @accept application/json
+// @Produce application/json
+// @Param data body dockerReq.ImageInspectRequest true "镜像ID或名称"
+// @Success 200 {object} response.Response{data=docker.DockerImageReport,msg=string} "提交成功"
+// @Router /docker/images/inspect [post]
+func (d *DockerImageInspectApi) InspectImage(c *gin.Context) {
+	var inspectReq dockerReq.ImageInspectRequest
+	if err := c.ShouldBindJSON(&inspectReq); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	report, err := onHost(c, dockerImageInspectService).InspectImage(inspectReq, utils.GetUserID(c))
+	if err != nil {
+		failImageReport(c, "提交镜像检查失败", err)
+		return
+	}
+
+	response.OkWithDetailed(report, "提交成功", c)
+}
+
+// GetImageReportList 获取镜像检查报告列表
+// @Tags Docker
+// @Summary 分页获取镜像检查报告，只含汇总信息
+// @Security ApiKeyAuth
+// @Produce application/json
+// @Param data query dockerReq.ImageReportFilter true "分页与过滤参数"
+// @Success 200 {object} response.Response{data=response.PageResult,msg=string} "获取成功"
+// @Router /docker/images/reports [get]
+func (d *DockerImageInspectApi) GetImageReportList(c *gin.Context) {
+	var filter dockerReq.ImageReportFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+	if filter.PageSize <= 0 {
+		filter.PageSize = 10
+	}
+
+	reports, total, err := onHost(c, dockerImageInspectService).GetImageReportList(filter)
+	if err != nil {
+		global.GVA_LOG.Error("获取镜像检查报告列表失败", zap.Error(err))
+		response.FailWithMessage("获取镜像检查报告列表失败: "+err.Error(), c)
+		return
+	}
+
+	response.OkWithDetailed(response.PageResult{
+		List:     reports,
+		Total:    total,
+		Page:     filter.Page,
+		PageSize: filter.PageSize,
+	}, "获取成功", c)
+}
+
+// GetImageReport 获取镜像检查报告详情
+// @Tags Docker
+// @Summary 获取镜像检查报告，包含软件包清单、漏洞匹配结果与 setuid/setgid 文件
+// @Security ApiKeyAuth
+// @Produce application/json
+// @Param id path int true "报告ID"
+// @Success 200 {object} response.Response{data=docker.DockerImageReport,msg=string} "获取成功"
+// @Router /docker/images/reports/{id} [get]
+func (d *DockerImageInspectApi) GetImageReport(c *gin.Context) {
+	id, ok := parseImageReportID(c)
+	if !ok {
+		return
+	}
+
+	report, err := onHost(c, dockerImageInspectService).GetImageReport(id)
+	if err != nil {
+		failImageReport(c, "获取镜像检查报告失败", err)
+		return
+	}
+
+	response.OkWithDetailed(report, "获取成功", c)
+}
+
+// CompareImageReports 对比镜像检查报告
+// @Tags Docker
+// @Summary 对比两份镜像检查报告的软件包、漏洞与 setuid/setgid 文件变化
+// @Security ApiKeyAuth
+// @Produce application/json
+// @Param data query dockerReq.ImageReportCompareRequest true "基准报告与对比报告ID"
+// @Success 200 {object} response.Response{data=dockerRes.ImageReportCompare,msg=string} "获取成功"
+// @Router /docker/images/reports/compare [get]
+func (d *DockerImageInspectApi) CompareImageReports(c *gin.Context) {
+	var compareReq dockerReq.ImageReportCompareRequest
+	if err := c.ShouldBindQuery(&compareReq); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	result, err := onHost(c, dockerImageInspectService).CompareImageReports(compareReq)
+	if err != nil {
+		failImageReport(c, "对比镜像检查报告失败", err)
+		return
+	}
+
+	response.OkWithDetailed(result, "获取成功", c)
+}
+
+// RematchImageReport 重新匹配漏洞
+// @Tags Docker
+// @Summary 使用当前离线漏洞库重新匹配报告中的软件包，无需重新解包镜像
+// @Security ApiKeyAuth
+// @Produce application/json
+// @Param id path int true "报告ID"
+// @Success 200 {object} response.Response{data=docker.DockerImageReport,msg=string} "匹配成功"
+// @Router /docker/images/reports/{id}/rematch [post]
+func (d *DockerImageInspectApi) RematchImageReport(c *gin.Context) {
+	id, ok := parseImageReportID(c)
+	if !ok {
+		return
+	}
+
+	report, err := onHost(c, dockerImageInspectService).RematchImageReport(id)
+	if err != nil {
+		failImageReport(c, "重新匹配漏洞失败", err)
+		return
+	}
+
+	response.OkWithDetailed(report, "匹配成功", c)
+}
+
+// DeleteImageReport 删除镜像检查报告
+// @Tags Docker
+// @Summary 删除镜像检查报告及其明细
+// @Security ApiKeyAuth
+// @Produce application/json
+// @Param id path int true "报告ID"
+// @Success 200 {object} response.Response{msg=string} "删除成功"
+// @Router /docker/images/reports/{id} [delete]
+func (d *DockerImageInspectApi) DeleteImageReport(c *gin.Context) {
+	id, ok := parseImageReportID(c)
+	if !ok {
+		return
+	}
+
+	if err := onHost(c, dockerImageInspectService).DeleteImageReport(id); err != nil {
+		failImageReport(c, "删除镜像检查报告失败", err)
+		return
+	}
+
+	response.OkWithMessage("删除成功", c)
+}
+
+// ImportVulnDB 导入离线漏洞库
+// @Tags Docker
+// @Summary 导入离线漏洞库文件，同名来源整体替换
+// @Description 支持 OSV 格式的 JSON（单条或数组）、osv.dev 按生态导出的 zip 包，以及简化格式 {"vulnerabilities":[{"id","ecosystem","distro","package","introduced","fixed","versions","severity","summary"}]}
+// @Security ApiKeyAuth
+// @accept multipart/form-data
+// @Produce application/json
+// @Param file formData file true "漏洞库文件（.json/.zip）"
+// @Param source formData string false "数据来源名称，为空时使用文件名"
+// @Success 200 {object} response.Response{data=dockerRes.VulnDBImportResult,msg=string} "导入成功"
+// @Router /docker/vulndb/import [post]
+func (d *DockerImageInspectApi) ImportVulnDB(c *gin.Context) {
+	var importReq dockerReq.VulnDBImportRequest
+	if err := c.ShouldBind(&importReq); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	header, err := c.FormFile("file")
+	if err != nil {
+		response.FailWithMessage("请上传漏洞库文件", c)
+		return
+	}
+
+	result, err := onHost(c, dockerImageInspectService).ImportVulnDB(header, importReq)
+	if err != nil {
+		failImageReport(c, "导入漏洞库失败", err)
+		return
+	}
+
+	response.OkWithDetailed(result, "导入成功", c)
+}
+
+// GetVulnDBStats 获取离线漏洞库统计
+// @Tags Docker
+// @Summary 按数据来源统计离线漏洞库条目
+// @Security ApiKeyAuth
+// @Produce application/json
+// @Success 200 {object} response.Response{data=[]dockerRes.VulnDBSourceStat,msg=string} "获取成功"
+// @Router /docker/vulndb [get]
+func (d *DockerImageInspectApi) GetVulnDBStats(c *gin.Context) {
+	stats, err := onHost(c, dockerImageInspectService).GetVulnDBStats()
+	if err != nil {
+		global.GVA_LOG.Error("获取漏洞库统计失败", zap.Error(err))
+		response.FailWithMessage("获取漏洞库统计失败: "+err.Error(), c)
+		return
+	}
+
+	response.OkWithDetailed(stats, "获取成功", c)
+}
+
+// DeleteVulnDBSource 删除离线漏洞库来源
+// @Tags Docker
+// @Summary 删除指定来源的离线漏洞库数据
+// @Security ApiKeyAuth
+// @Produce application/json
+// @Param source path string true "数据来源名称"
+// @Success 200 {object} response.Response{msg=string} "删除成功"
+// @Router /docker/vulndb/{source} [delete]
+func (d *DockerImageInspectApi) DeleteVulnDBSource(c *gin.Context) {
+	if err := onHost(c, dockerImageInspectService).DeleteVulnDBSource(c.Param("source")); err != nil {
+		failImageReport(c, "删除漏洞库失败", err)
+		return
+	}
+
+	response.OkWithMessage("删除成功", c)
+}
+
+// parseImageReportID 解析路径中的报告ID，无效时直接返回错误响应
+func parseImageReportID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.FailWithMessage("无效的报告ID", c)
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// failImageReport 将服务层错误转换为中文提示
+func failImageReport(c *gin.Context, prefix string, err error) {
+	global.GVA_LOG.Error(prefix, zap.Error(err))
+	switch err.Error() {
+	case "image report not found":
+		response.FailWithMessage("检查报告不存在", c)
+	case "image report is not completed":
+		response.FailWithMessage("检查尚未完成或已失败", c)
+	case "image inspection is already running":
+		response.FailWithMessage("该镜像正在检查中，请稍后查看报告", c)
+	case "image inspection is still running":
+		response.FailWithMessage("检查执行中，请等待完成后再删除", c)
+	case "vulnerability database file is too large":
+		response.FailWithMessage("漏洞库文件过大", c)
+	case "invalid vulnerability database source":
+		response.FailWithMessage("无效的数据来源名称", c)
+	case "no supported vulnerabilities found in file":
+		response.FailWithMessage("文件中没有可识别的漏洞数据", c)
+	case "vulnerability database source not found":
+		response.FailWithMessage("漏洞库来源不存在", c)
+	default:
+		response.FailWithMessage(prefix+": "+err.Error(), c)
+	}
+}
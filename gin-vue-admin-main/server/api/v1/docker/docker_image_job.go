@@ -0,0 +1,243 @@
+package docker
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/response"
+	dockerReq "github.com/flipped-aurora/gin-vue-admin/server/model/docker/request"
+	dockerRes "github.com/flipped-aurora/gin-vue-admin/server/model/docker/response"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type DockerImageJobApi struct{}
+
+// SubmitPullJob 提交后台拉取镜像任务
+// @Tags Docker
+// @Summary 提交后台拉取镜像任务，立即返回任务ID
+// @Security ApiKeyAuth
+// @accept application/json
+// @Produce application/json
+// @Param data body dockerReq.ImagePullRequest true "拉取镜像参数"
+// @Success 200 {object} response.Response{data=docker.DockerImageJob,msg=string} "提交成功"
+// @Router /docker/images/jobs/pull [post]
+func (d *DockerImageJobApi) SubmitPullJob(c *gin.Context) {
+	var pullReq dockerReq.ImagePullRequest
+	if err := c.ShouldBindJSON(&pullReq); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	job, err := onHost(c, dockerImageJobService).SubmitPullJob(pullReq, utils.GetUserID(c))
+	if err != nil {
+		global.GVA_LOG.Error("提交拉取任务失败", zap.String("image", pullReq.Image), zap.Error(err))
+		response.FailWithMessage("提交拉取任务失败: "+err.Error(), c)
+		return
+	}
+
+	response.OkWithDetailed(job, "提交成功", c)
+}
+
+// SubmitBuildJob 提交后台构建镜像任务
+// @Tags Docker
+// @Summary 提交后台构建镜像任务，立即返回任务ID
+// @Security ApiKeyAuth
+// @accept application/json
+// @Produce application/json
+// @Param data body dockerReq.ImageBuildRequest true "构建镜像参数"
+// @Success 200 {object} response.Response{data=docker.DockerImageJob,msg=string} "提交成功"
+// @Router /docker/images/jobs/build [post]
+func (d *DockerImageJobApi) SubmitBuildJob(c *gin.Context) {
+	var buildReq dockerReq.ImageBuildRequest
+	if err := c.ShouldBindJSON(&buildReq); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	job, err := onHost(c, dockerImageJobService).SubmitBuildJob(buildReq, utils.GetUserID(c))
+	if err != nil {
+		global.GVA_LOG.Error("提交构建任务失败", zap.String("imageName", buildReq.ImageName), zap.Error(err))
+		response.FailWithMessage("提交构建任务失败: "+err.Error(), c)
+		return
+	}
+
+	response.OkWithDetailed(job, "提交成功", c)
+}
+
+// GetImageJobList 获取镜像任务列表
+// @Tags Docker
+// @Summary 分页获取镜像拉取/构建任务列表，不含日志
+// @Security ApiKeyAuth
+// @Produce application/json
+// @Param data query dockerReq.ImageJobFilter true "分页与过滤参数"
+// @Success 200 {object} response.Response{data=response.PageResult,msg=string} "获取成功"
+// @Router /docker/images/jobs [get]
+func (d *DockerImageJobApi) GetImageJobList(c *gin.Context) {
+	var filter dockerReq.ImageJobFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+	if filter.PageSize <= 0 {
+		filter.PageSize = 10
+	}
+
+	jobs, total, err := onHost(c, dockerImageJobService).GetImageJobList(filter)
+	if err != nil {
+		global.GVA_LOG.Error("获取镜像任务列表失败", zap.Error(err))
+		response.FailWithMessage("获取镜像任务列表失败: "+err.Error(), c)
+		return
+	}
+
+	response.OkWithDetailed(response.PageResult{
+		List:     jobs,
+		Total:    total,
+		Page:     filter.Page,
+		PageSize: filter.PageSize,
+	}, "获取成功", c)
+}
+
+// GetImageJob 获取镜像任务进度
+// @Tags Docker
+// @Summary 获取镜像任务状态与进度，拉取任务包含各镜像层进度，构建任务包含当前步骤
+// @Security ApiKeyAuth
+// @Produce application/json
+// @Param id path int true "任务ID"
+// @Success 200 {object} response.Response{data=dockerRes.ImageJobProgress,msg=string} "获取成功"
+// @Router /docker/images/jobs/{id} [get]
+func (d *DockerImageJobApi) GetImageJob(c *gin.Context) {
+	id, ok := parseImageJobID(c)
+	if !ok {
+		return
+	}
+
+	progress, err := onHost(c, dockerImageJobService).GetImageJob(id)
+	if err != nil {
+		failImageJob(c, "获取镜像任务失败", err)
+		return
+	}
+
+	response.OkWithDetailed(progress, "获取成功", c)
+}
+
+// GetImageJobLog 获取镜像任务日志
+// @Tags Docker
+// @Summary 获取镜像任务日志，执行中的任务返回当前已产生的日志
+// @Security ApiKeyAuth
+// @Produce application/json
+// @Param id path int true "任务ID"
+// @Success 200 {object} response.Response{data=string,msg=string} "获取成功"
+// @Router /docker/images/jobs/{id}/log [get]
+func (d *DockerImageJobApi) GetImageJobLog(c *gin.Context) {
+	id, ok := parseImageJobID(c)
+	if !ok {
+		return
+	}
+
+	log, err := onHost(c, dockerImageJobService).GetImageJobLog(id)
+	if err != nil {
+		failImageJob(c, "获取镜像任务日志失败", err)
+		return
+	}
+
+	response.OkWithDetailed(log, "获取成功", c)
+}
+
+// StreamImageJob 实时推送镜像任务进度
+// @Tags Docker
+// @Summary 实时推送镜像任务进度直到任务结束，支持WebSocket与SSE两种方式
+// @Description 携带 Upgrade: websocket 头时升级为WebSocket，每条消息为一次进度JSON；否则以SSE推送 progress 事件
+// @Security ApiKeyAuth
+// @Produce text/event-stream
+// @Param id path int true "任务ID"
+// @Success 200 {object} dockerRes.ImageJobProgress "任务进度"
+// @Router /docker/images/jobs/{id}/stream [get]
+func (d *DockerImageJobApi) StreamImageJob(c *gin.Context) {
+	id, ok := parseImageJobID(c)
+	if !ok {
+		return
+	}
+
+	serveEventStream(c, "progress", func(ctx context.Context, emit func(data interface{}) error) error {
+		return onHost(c, dockerImageJobService).StreamImageJob(ctx, id, func(progress dockerRes.ImageJobProgress) error {
+			return emit(progress)
+		})
+	}, func(err error) {
+		failImageJob(c, "获取镜像任务失败", err)
+	})
+}
+
+// CancelImageJob 取消镜像任务
+// @Tags Docker
+// @Summary 取消排队中或执行中的镜像任务
+// @Security ApiKeyAuth
+// @Produce application/json
+// @Param id path int true "任务ID"
+// @Success 200 {object} response.Response{msg=string} "取消成功"
+// @Router /docker/images/jobs/{id}/cancel [post]
+func (d *DockerImageJobApi) CancelImageJob(c *gin.Context) {
+	id, ok := parseImageJobID(c)
+	if !ok {
+		return
+	}
+
+	if err := onHost(c, dockerImageJobService).CancelImageJob(id); err != nil {
+		failImageJob(c, "取消镜像任务失败", err)
+		return
+	}
+
+	response.OkWithMessage("取消成功", c)
+}
+
+// DeleteImageJob 删除镜像任务记录
+// @Tags Docker
+// @Summary 删除已结束的镜像任务及其日志
+// @Security ApiKeyAuth
+// @Produce application/json
+// @Param id path int true "任务ID"
+// @Success 200 {object} response.Response{msg=string} "删除成功"
+// @Router /docker/images/jobs/{id} [delete]
+func (d *DockerImageJobApi) DeleteImageJob(c *gin.Context) {
+	id, ok := parseImageJobID(c)
+	if !ok {
+		return
+	}
+
+	if err := onHost(c, dockerImageJobService).DeleteImageJob(id); err != nil {
+		failImageJob(c, "删除镜像任务失败", err)
+		return
+	}
+
+	response.OkWithMessage("删除成功", c)
+}
+
+// parseImageJobID 解析路径中的任务ID，无效时直接返回错误响应
+func parseImageJobID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.FailWithMessage("无效的任务ID", c)
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// failImageJob 将服务层错误转换为中文提示
+func failImageJob(c *gin.Context, prefix string, err error) {
+	global.GVA_LOG.Error(prefix, zap.Error(err))
+	switch err.Error() {
+	case "image job not found":
+		response.FailWithMessage("镜像任务不存在", c)
+	case "image job is not running":
+		response.FailWithMessage("任务已结束，无法取消", c)
+	case "image job is still running":
+		response.FailWithMessage("任务执行中，请先取消", c)
+	default:
+		response.FailWithMessage(prefix+": "+err.Error(), c)
+	}
+}
@@ -0,0 +1,244 @@
+package docker
+
+import (
+	"strconv"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/response"
+	dockerReq "github.com/flipped-aurora/gin-vue-admin/server/model/docker/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type DockerImageRetentionApi struct{}
+
+// GetRetentionPolicyList 获取镜像保留策略列表
+// @Tags Docker
+// @Summary 获取镜像保留策略列表
+// @Security ApiKeyAuth
+// @Produce application/json
+// @Success 200 {object} response.Response{data=[]docker.DockerImageRetentionPolicy,msg=string} "获取成功"
+// @Router /docker/images/retention/policies [get]
+func (d *DockerImageRetentionApi) GetRetentionPolicyList(c *gin.Context) {
+	policies, err := onHost(c, dockerImageRetentionService).GetRetentionPolicyList()
+	if err != nil {
+		global.GVA_LOG.Error("获取镜像保留策略失败", zap.Error(err))
+		response.FailWithMessage("获取镜像保留策略失败: "+err.Error(), c)
+		return
+	}
+
+	response.OkWithDetailed(policies, "获取成功", c)
+}
+
+// CreateRetentionPolicy 创建镜像保留策略
+// @Tags Docker
+// @Summary 创建镜像保留策略，启用后按执行周期定时清理
+// @Security ApiKeyAuth
+// @accept application/json
+// @Produce application/json
+// @Param data body dockerReq.ImageRetentionPolicyRequest true "保留策略"
+// @Success 200 {object} response.Response{data=docker.DockerImageRetentionPolicy,msg=string} "创建成功"
+// @Router /docker/images/retention/policies [post]
+func (d *DockerImageRetentionApi) CreateRetentionPolicy(c *gin.Context) {
+	var req dockerReq.ImageRetentionPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	policy, err := onHost(c, dockerImageRetentionService).CreateRetentionPolicy(req, utils.GetUserID(c))
+	if err != nil {
+		failImageRetention(c, "创建镜像保留策略失败", err)
+		return
+	}
+
+	response.OkWithDetailed(policy, "创建成功", c)
+}
+
+// UpdateRetentionPolicy 更新镜像保留策略
+// @Tags Docker
+// @Summary 更新镜像保留策略并重新注册定时任务
+// @Security ApiKeyAuth
+// @accept application/json
+// @Produce application/json
+// @Param data body dockerReq.ImageRetentionPolicyRequest true "保留策略"
+// @Success 200 {object} response.Response{data=docker.DockerImageRetentionPolicy,msg=string} "更新成功"
+// @Router /docker/images/retention/policies [put]
+func (d *DockerImageRetentionApi) UpdateRetentionPolicy(c *gin.Context) {
+	var req dockerReq.ImageRetentionPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	if req.ID == 0 {
+		response.FailWithMessage("策略ID不能为空", c)
+		return
+	}
+
+	policy, err := onHost(c, dockerImageRetentionService).UpdateRetentionPolicy(req)
+	if err != nil {
+		failImageRetention(c, "更新镜像保留策略失败", err)
+		return
+	}
+
+	response.OkWithDetailed(policy, "更新成功", c)
+}
+
+// DeleteRetentionPolicy 删除镜像保留策略
+// @Tags Docker
+// @Summary 删除镜像保留策略，执行记录保留
+// @Security ApiKeyAuth
+// @Produce application/json
+// @Param id path int true "策略ID"
+// @Success 200 {object} response.Response{msg=string} "删除成功"
+// @Router /docker/images/retention/policies/{id} [delete]
+func (d *DockerImageRetentionApi) DeleteRetentionPolicy(c *gin.Context) {
+	id, ok := parseRetentionPolicyID(c)
+	if !ok {
+		return
+	}
+
+	if err := onHost(c, dockerImageRetentionService).DeleteRetentionPolicy(id); err != nil {
+		failImageRetention(c, "删除镜像保留策略失败", err)
+		return
+	}
+
+	response.OkWithMessage("删除成功", c)
+}
+
+// PreviewRetentionPolicy 预览镜像保留策略
+// @Tags Docker
+// @Summary 预览已保存策略将删除的标签、因被容器使用而保留的标签，以及按磁盘占用估算可释放的空间，不删除任何镜像
+// @Security ApiKeyAuth
+// @Produce application/json
+// @Param id path int true "策略ID"
+// @Success 200 {object} response.Response{data=dockerRes.ImageRetentionPlan,msg=string} "获取成功"
+// @Router /docker/images/retention/policies/{id}/preview [get]
+func (d *DockerImageRetentionApi) PreviewRetentionPolicy(c *gin.Context) {
+	id, ok := parseRetentionPolicyID(c)
+	if !ok {
+		return
+	}
+
+	plan, err := onHost(c, dockerImageRetentionService).PreviewRetentionPolicy(id)
+	if err != nil {
+		failImageRetention(c, "预览镜像保留策略失败", err)
+		return
+	}
+
+	response.OkWithDetailed(plan, "获取成功", c)
+}
+
+// PreviewRetention 预览未保存的镜像保留策略
+// @Tags Docker
+// @Summary 按请求中的规则预览将删除的标签，用于保存策略前调整规则
+// @Security ApiKeyAuth
+// @accept application/json
+// @Produce application/json
+// @Param data body dockerReq.ImageRetentionPolicyRequest true "保留策略"
+// @Success 200 {object} response.Response{data=dockerRes.ImageRetentionPlan,msg=string} "获取成功"
+// @Router /docker/images/retention/preview [post]
+func (d *DockerImageRetentionApi) PreviewRetention(c *gin.Context) {
+	var req dockerReq.ImageRetentionPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	plan, err := onHost(c, dockerImageRetentionService).PreviewRetention(req)
+	if err != nil {
+		failImageRetention(c, "预览镜像保留策略失败", err)
+		return
+	}
+
+	response.OkWithDetailed(plan, "获取成功", c)
+}
+
+// RunRetentionPolicy 立即执行镜像保留策略
+// @Tags Docker
+// @Summary 立即执行镜像保留策略，返回执行记录
+// @Security ApiKeyAuth
+// @Produce application/json
+// @Param id path int true "策略ID"
+// @Success 200 {object} response.Response{data=docker.DockerImageRetentionRun,msg=string} "执行完成"
+// @Router /docker/images/retention/policies/{id}/run [post]
+func (d *DockerImageRetentionApi) RunRetentionPolicy(c *gin.Context) {
+	id, ok := parseRetentionPolicyID(c)
+	if !ok {
+		return
+	}
+
+	run, err := onHost(c, dockerImageRetentionService).RunRetentionPolicy(id, utils.GetUserID(c))
+	if err != nil {
+		failImageRetention(c, "执行镜像保留策略失败", err)
+		return
+	}
+
+	response.OkWithDetailed(run, "执行完成", c)
+}
+
+// GetRetentionRunList 获取镜像保留策略执行记录
+// @Tags Docker
+// @Summary 分页获取镜像保留策略执行记录
+// @Security ApiKeyAuth
+// @Produce application/json
+// @Param data query dockerReq.ImageRetentionRunFilter true "分页与过滤参数"
+// @Success 200 {object} response.Response{data=response.PageResult,msg=string} "获取成功"
+// @Router /docker/images/retention/runs [get]
+func (d *DockerImageRetentionApi) GetRetentionRunList(c *gin.Context) {
+	var filter dockerReq.ImageRetentionRunFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+	if filter.PageSize <= 0 {
+		filter.PageSize = 10
+	}
+
+	runs, total, err := onHost(c, dockerImageRetentionService).GetRetentionRunList(filter)
+	if err != nil {
+		global.GVA_LOG.Error("获取镜像保留策略执行记录失败", zap.Error(err))
+		response.FailWithMessage("获取镜像保留策略执行记录失败: "+err.Error(), c)
+		return
+	}
+
+	response.OkWithDetailed(response.PageResult{
+		List:     runs,
+		Total:    total,
+		Page:     filter.Page,
+		PageSize: filter.PageSize,
+	}, "获取成功", c)
+}
+
+// parseRetentionPolicyID 解析路径中的策略ID，无效时直接返回错误响应
+func parseRetentionPolicyID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.FailWithMessage("无效的策略ID", c)
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// failImageRetention 将服务层错误转换为中文提示
+func failImageRetention(c *gin.Context, prefix string, err error) {
+	global.GVA_LOG.Error(prefix, zap.Error(err))
+	switch err.Error() {
+	case "retention policy not found":
+		response.FailWithMessage("保留策略不存在", c)
+	case "image retention is already running":
+		response.FailWithMessage("已有保留策略正在执行，请稍后重试", c)
+	case "retention policy must set keep last or older than days":
+		response.FailWithMessage("保留数量与保留天数至少设置一项", c)
+	case "keep last and older than days cannot be negative":
+		response.FailWithMessage("保留数量与保留天数不能为负数", c)
+	case "schedule is required when the policy is enabled":
+		response.FailWithMessage("启用定时执行时必须填写执行周期", c)
+	default:
+		response.FailWithMessage(prefix+": "+err.Error(), c)
+	}
+}
@@ -0,0 +1,95 @@
+package docker
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/response"
+	dockerReq "github.com/flipped-aurora/gin-vue-admin/server/model/docker/request"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type DockerMetricsApi struct{}
+
+// GetHostMetrics 获取主机历史指标
+// @Tags Docker
+// @Summary 获取主机CPU、内存、负载与磁盘的历史指标
+// @Description 1h 返回原始采样，24h 返回5分钟聚合数据，7d 返回1小时聚合数据
+// @Security ApiKeyAuth
+// @Produce application/json
+// @Param data query dockerReq.MetricsQuery false "查询参数"
+// @Success 200 {object} response.Response{data=dockerRes.HostMetricsResult,msg=string} "获取成功"
+// @Router /docker/metrics/host [get]
+func (d *DockerMetricsApi) GetHostMetrics(c *gin.Context) {
+	var query dockerReq.MetricsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	result, err := dockerMetricsService.GetHostMetrics(query.Range)
+	if err != nil {
+		global.GVA_LOG.Error("获取主机历史指标失败", zap.Error(err))
+		response.FailWithMessage("获取主机历史指标失败: "+err.Error(), c)
+		return
+	}
+
+	response.OkWithDetailed(result, "获取成功", c)
+}
+
+// GetContainerMetricsSummary 获取容器资源使用汇总
+// @Tags Docker
+// @Summary 汇总时间范围内各容器的平均与峰值资源使用，用于容量规划
+// @Security ApiKeyAuth
+// @Produce application/json
+// @Param data query dockerReq.MetricsQuery false "查询参数"
+// @Success 200 {object} response.Response{data=[]dockerRes.ContainerMetricsSummary,msg=string} "获取成功"
+// @Router /docker/metrics/containers [get]
+func (d *DockerMetricsApi) GetContainerMetricsSummary(c *gin.Context) {
+	var query dockerReq.MetricsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	result, err := dockerMetricsService.GetContainerMetricsSummary(query.Range)
+	if err != nil {
+		global.GVA_LOG.Error("获取容器资源汇总失败", zap.Error(err))
+		response.FailWithMessage("获取容器资源汇总失败: "+err.Error(), c)
+		return
+	}
+
+	response.OkWithDetailed(result, "获取成功", c)
+}
+
+// GetContainerMetrics 获取容器历史指标
+// @Tags Docker
+// @Summary 获取指定容器的历史指标，支持容器ID、ID前缀或容器名称
+// @Description 1h 返回原始采样，24h 返回5分钟聚合数据，7d 返回1小时聚合数据；按名称查询时重建前后的容器分别返回序列
+// @Security ApiKeyAuth
+// @Produce application/json
+// @Param id path string true "容器ID或名称"
+// @Param data query dockerReq.MetricsQuery false "查询参数"
+// @Success 200 {object} response.Response{data=dockerRes.ContainerMetricsResult,msg=string} "获取成功"
+// @Router /docker/metrics/containers/{id} [get]
+func (d *DockerMetricsApi) GetContainerMetrics(c *gin.Context) {
+	containerID := c.Param("id")
+	if containerID == "" {
+		response.FailWithMessage("容器ID不能为空", c)
+		return
+	}
+
+	var query dockerReq.MetricsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	result, err := dockerMetricsService.GetContainerMetrics(containerID, query.Range)
+	if err != nil {
+		global.GVA_LOG.Error("获取容器历史指标失败", zap.String("containerID", containerID), zap.Error(err))
+		response.FailWithMessage("获取容器历史指标失败: "+err.Error(), c)
+		return
+	}
+
+	response.OkWithDetailed(result, "获取成功", c)
+}
@@ -39,7 +39,7 @@ func (d *DockerNetworkApi) GetNetworkList(c *gin.Context) {
 	}
 
 	// 调用服务层获取网络列表
-	networks, total, err := dockerNetworkService.GetNetworkList(pageInfo)
+	networks, total, err := onHost(c, dockerNetworkService).GetNetworkList(pageInfo)
 	if err != nil {
 		global.GVA_LOG.Error("获取网络列表失败", zap.Error(err))
 		response.FailWithMessage("获取网络列表失败: "+err.Error(), c)
@@ -69,7 +69,7 @@ func (d *DockerNetworkApi) GetNetworkDetail(c *gin.Context) {
 	}
 
 	// 调用服务层获取网络详细信息
-	networkDetail, err := dockerNetworkService.GetNetworkDetail(networkID)
+	networkDetail, err := onHost(c, dockerNetworkService).GetNetworkDetail(networkID)
 	if err != nil {
 		global.GVA_LOG.Error("获取网络详细信息失败", zap.String("networkID", networkID), zap.Error(err))
 		response.FailWithMessage("获取网络详细信息失败: "+err.Error(), c)
@@ -81,7 +81,7 @@ func (d *DockerNetworkApi) GetNetworkDetail(c *gin.Context) {
 
 // CreateNetwork 创建网络
 // @Tags Docker网络管理
-// @Summary 创建网络
+// @Summary 创建网络，支持多个IPv4/IPv6地址池、macvlan/ipvlan父接口与驱动参数，子网不能与已有网络重叠
 // @Security ApiKeyAuth
 // @accept application/json
 // @Produce application/json
@@ -102,10 +102,9 @@ func (d *DockerNetworkApi) CreateNetwork(c *gin.Context) {
 	}
 
 	// 调用服务层创建网络
-	networkID, err := dockerNetworkService.CreateNetwork(createReq)
+	networkID, err := onHost(c, dockerNetworkService).CreateNetwork(createReq)
 	if err != nil {
-		global.GVA_LOG.Error("创建网络失败", zap.String("name", createReq.Name), zap.Error(err))
-		response.FailWithMessage("创建网络失败: "+err.Error(), c)
+		failDockerNetwork(c, "创建网络失败", err)
 		return
 	}
 
@@ -131,7 +130,7 @@ func (d *DockerNetworkApi) RemoveNetwork(c *gin.Context) {
 	}
 
 	// 调用服务层删除网络
-	err := dockerNetworkService.RemoveNetwork(networkID)
+	err := onHost(c, dockerNetworkService).RemoveNetwork(networkID)
 	if err != nil {
 		global.GVA_LOG.Error("删除网络失败", zap.String("networkID", networkID), zap.Error(err))
 		response.FailWithMessage("删除网络失败: "+err.Error(), c)
@@ -151,7 +150,7 @@ func (d *DockerNetworkApi) RemoveNetwork(c *gin.Context) {
 // @Router /docker/networks/prune [post]
 func (d *DockerNetworkApi) PruneNetworks(c *gin.Context) {
 	// 调用服务层清理网络
-	deletedCount, spaceReclaimed, err := dockerNetworkService.PruneNetworks()
+	deletedCount, spaceReclaimed, err := onHost(c, dockerNetworkService).PruneNetworks()
 	if err != nil {
 		global.GVA_LOG.Error("清理网络失败", zap.Error(err))
 		response.FailWithMessage("清理网络失败: "+err.Error(), c)
@@ -0,0 +1,115 @@
+package docker
+
+import (
+	"strings"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/response"
+	dockerReq "github.com/flipped-aurora/gin-vue-admin/server/model/docker/request"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// ConnectContainer 将容器连接到网络
+// @Tags Docker网络管理
+// @Summary 将容器连接到网络，可指定静态IPv4/IPv6地址与网络内别名
+// @Security ApiKeyAuth
+// @accept application/json
+// @Produce application/json
+// @Param id path string true "网络ID或名称"
+// @Param data body dockerReq.NetworkConnectRequest true "连接参数"
+// @Success 200 {object} response.Response{msg=string} "连接成功"
+// @Router /docker/networks/{id}/connect [post]
+func (d *DockerNetworkApi) ConnectContainer(c *gin.Context) {
+	var connectReq dockerReq.NetworkConnectRequest
+	if err := c.ShouldBindJSON(&connectReq); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	if err := onHost(c, dockerNetworkService).ConnectContainer(c.Param("id"), connectReq); err != nil {
+		failDockerNetwork(c, "连接网络失败", err)
+		return
+	}
+
+	response.OkWithMessage("连接成功", c)
+}
+
+// DisconnectContainer 将容器从网络断开
+// @Tags Docker网络管理
+// @Summary 将容器从网络断开
+// @Security ApiKeyAuth
+// @accept application/json
+// @Produce application/json
+// @Param id path string true "网络ID或名称"
+// @Param data body dockerReq.NetworkDisconnectRequest true "断开参数"
+// @Success 200 {object} response.Response{msg=string} "断开成功"
+// @Router /docker/networks/{id}/disconnect [post]
+func (d *DockerNetworkApi) DisconnectContainer(c *gin.Context) {
+	var disconnectReq dockerReq.NetworkDisconnectRequest
+	if err := c.ShouldBindJSON(&disconnectReq); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	if err := onHost(c, dockerNetworkService).DisconnectContainer(c.Param("id"), disconnectReq); err != nil {
+		failDockerNetwork(c, "断开网络失败", err)
+		return
+	}
+
+	response.OkWithMessage("断开成功", c)
+}
+
+// failDockerNetwork 将服务层错误转换为中文提示
+func failDockerNetwork(c *gin.Context, prefix string, err error) {
+	global.GVA_LOG.Error(prefix, zap.Error(err))
+	msg := err.Error()
+	switch {
+	case msg == "network not found":
+		response.FailWithMessage("网络不存在", c)
+	case msg == "container not found":
+		response.FailWithMessage("容器不存在", c)
+	case msg == "container is already connected to network":
+		response.FailWithMessage("容器已连接到该网络", c)
+	case msg == "container is not connected to network":
+		response.FailWithMessage("容器未连接到该网络", c)
+	case strings.HasPrefix(msg, "invalid ip address: "):
+		response.FailWithMessage("IP地址格式错误或地址族不匹配: "+strings.TrimPrefix(msg, "invalid ip address: "), c)
+	case strings.HasPrefix(msg, "ip address is not in network subnet: "):
+		response.FailWithMessage("IP地址不在网络的子网内: "+strings.TrimPrefix(msg, "ip address is not in network subnet: "), c)
+	case msg == "subnet is required":
+		response.FailWithMessage("请填写子网", c)
+	case strings.HasPrefix(msg, "invalid subnet: "):
+		response.FailWithMessage("子网格式错误: "+strings.TrimPrefix(msg, "invalid subnet: "), c)
+	case strings.HasPrefix(msg, "ipv6 subnet requires enableIPv6: "):
+		response.FailWithMessage("配置IPv6子网需要启用IPv6: "+strings.TrimPrefix(msg, "ipv6 subnet requires enableIPv6: "), c)
+	case strings.HasPrefix(msg, "subnets overlap: "):
+		response.FailWithMessage("地址池子网重叠: "+strings.TrimPrefix(msg, "subnets overlap: "), c)
+	case strings.HasPrefix(msg, "gateway is not in subnet: "):
+		response.FailWithMessage("网关不在子网内: "+strings.TrimPrefix(msg, "gateway is not in subnet: "), c)
+	case strings.HasPrefix(msg, "ip range is not in subnet: "):
+		response.FailWithMessage("地址范围不在子网内: "+strings.TrimPrefix(msg, "ip range is not in subnet: "), c)
+	case strings.HasPrefix(msg, "aux address is not in subnet: "):
+		response.FailWithMessage("保留地址不在子网内: "+strings.TrimPrefix(msg, "aux address is not in subnet: "), c)
+	case strings.HasPrefix(msg, "subnet ") && strings.Contains(msg, " overlaps with network "):
+		response.FailWithMessage("子网与已有网络重叠: "+strings.TrimPrefix(msg, "subnet "), c)
+	case strings.HasPrefix(msg, "invalid mtu: "):
+		response.FailWithMessage("MTU取值范围为68-65535", c)
+	case msg == "bridge options require bridge driver":
+		response.FailWithMessage("网桥名称与容器间通信仅适用于bridge驱动", c)
+	case strings.HasPrefix(msg, "invalid bridge name: "):
+		response.FailWithMessage("网桥名称不合法（最多15个字符）: "+strings.TrimPrefix(msg, "invalid bridge name: "), c)
+	case msg == "parent interface requires macvlan or ipvlan driver":
+		response.FailWithMessage("父接口与模式仅适用于macvlan/ipvlan驱动", c)
+	case msg == "parent interface is required":
+		response.FailWithMessage("macvlan/ipvlan网络需要指定父接口", c)
+	case strings.HasPrefix(msg, "parent interface not found on host: "):
+		response.FailWithMessage("主机上不存在该网络接口: "+strings.TrimPrefix(msg, "parent interface not found on host: "), c)
+	case strings.HasPrefix(msg, "invalid vlan id: "):
+		response.FailWithMessage("VLAN ID取值范围为1-4094", c)
+	case strings.HasPrefix(msg, "invalid macvlan mode: "), strings.HasPrefix(msg, "invalid ipvlan mode: "):
+		response.FailWithMessage("不支持的网络模式: "+msg[strings.LastIndex(msg, " ")+1:], c)
+	default:
+		response.FailWithMessage(prefix+": "+msg, c)
+	}
+}
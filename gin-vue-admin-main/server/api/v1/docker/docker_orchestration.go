@@ -2,9 +2,13 @@ package docker
 
 import (
 	"strconv"
+	"strings"
 
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
 	"github.com/flipped-aurora/gin-vue-admin/server/model/common/response"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/docker/request"
 	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
 )
 
 // GetOrchestrationList 获取编排列表（支持分页、搜索、状态过滤）
@@ -23,7 +27,7 @@ func (d *DockerContainerApi) GetOrchestrationList(c *gin.Context) {
 	}
 
 	// 调用服务层获取编排列表
-	result, err := dockerContainerService.GetOrchestrationList(page, pageSize, search, statusFilter)
+	result, err := onHost(c, dockerContainerService).GetOrchestrationList(page, pageSize, search, statusFilter)
 	if err != nil {
 		response.FailWithMessage("获取编排列表失败: "+err.Error(), c)
 		return
@@ -41,7 +45,7 @@ func (d *DockerContainerApi) OrchestrationDetail(c *gin.Context) {
 	}
 
 	// 调用服务层获取编排详情
-	group, err := dockerContainerService.GetOrchestrationDetail(name)
+	group, err := onHost(c, dockerContainerService).GetOrchestrationDetail(name)
 	if err != nil {
 		if err.Error() == "orchestration not found" {
 			response.FailWithMessage("未找到该编排", c)
@@ -54,35 +58,87 @@ func (d *DockerContainerApi) OrchestrationDetail(c *gin.Context) {
 	response.OkWithDetailed(group, "获取成功", c)
 }
 
-// CreateOrchestration 创建编排（仅组装label，不实际创建容器）
-type CreateOrchestrationReq struct {
-	Name string `json:"name" binding:"required"`
-}
-
+// CreateOrchestration 创建编排
+// @Tags Docker
+// @Summary 根据Compose内容或服务配置创建并部署编排
+// @Security ApiKeyAuth
+// @accept application/json
+// @Produce application/json
+// @Param data body request.OrchestrationCreateRequest true "编排配置"
+// @Success 200 {object} response.Response{data=dockerRes.OrchestrationDeployResult,msg=string} "部署结果"
+// @Router /docker/orchestrations [post]
 func (d *DockerContainerApi) CreateOrchestration(c *gin.Context) {
-	var req CreateOrchestrationReq
+	var req request.OrchestrationCreateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		response.FailWithMessage("参数错误: "+err.Error(), c)
 		return
 	}
-	// 实际上，编排的创建应为创建一组带label的容器，这里仅返回成功
-	response.OkWithMessage("编排创建接口（请用容器创建接口并加label实现）", c)
-}
+	if strings.TrimSpace(req.ComposeContent) == "" && len(req.Services) == 0 {
+		response.FailWithMessage("Compose内容和服务配置不能同时为空", c)
+		return
+	}
+
+	result, err := onHost(c, dockerContainerService).CreateOrchestration(req)
+	if err != nil {
+		global.GVA_LOG.Error("创建编排失败", zap.String("name", req.Name), zap.Error(err))
+		if result != nil {
+			response.FailWithDetailed(result, "创建编排失败: "+err.Error(), c)
+			return
+		}
+		response.FailWithMessage("创建编排失败: "+err.Error(), c)
+		return
+	}
 
-// EditOrchestration 编辑编排（仅演示，实际应批量更新label）
-type EditOrchestrationReq struct {
-	Name    string `json:"name" binding:"required"`
-	NewName string `json:"newName" binding:"required"`
+	response.OkWithDetailed(result, "编排创建成功", c)
 }
 
+// EditOrchestration 编辑编排并重新部署
+// @Tags Docker
+// @Summary 更新编排配置，仅重建配置发生变化的服务
+// @Security ApiKeyAuth
+// @accept application/json
+// @Produce application/json
+// @Param name path string true "编排名称"
+// @Param data body request.OrchestrationUpdateRequest true "编排配置"
+// @Success 200 {object} response.Response{data=dockerRes.OrchestrationDeployResult,msg=string} "部署结果"
+// @Router /docker/orchestrations/{name} [put]
 func (d *DockerContainerApi) EditOrchestration(c *gin.Context) {
-	var req EditOrchestrationReq
+	name := c.Param("name")
+	if name == "" {
+		response.FailWithMessage("编排名称不能为空", c)
+		return
+	}
+
+	var req request.OrchestrationUpdateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		response.FailWithMessage("参数错误: "+err.Error(), c)
 		return
 	}
-	// 实际应批量更新所有容器的label
-	response.OkWithMessage("编排编辑接口（请用容器编辑接口并批量更新label实现）", c)
+	if req.Name != name {
+		response.FailWithMessage("编排名称不支持修改", c)
+		return
+	}
+	if strings.TrimSpace(req.ComposeContent) == "" && len(req.Services) == 0 {
+		response.FailWithMessage("Compose内容和服务配置不能同时为空", c)
+		return
+	}
+
+	result, err := onHost(c, dockerContainerService).UpdateOrchestration(name, req)
+	if err != nil {
+		if err.Error() == "orchestration not found" {
+			response.FailWithMessage("未找到该编排", c)
+			return
+		}
+		global.GVA_LOG.Error("更新编排失败", zap.String("name", name), zap.Error(err))
+		if result != nil {
+			response.FailWithDetailed(result, "更新编排失败: "+err.Error(), c)
+			return
+		}
+		response.FailWithMessage("更新编排失败: "+err.Error(), c)
+		return
+	}
+
+	response.OkWithDetailed(result, "编排更新成功", c)
 }
 
 // DeleteOrchestration 删除编排（批量删除该label下所有容器）
@@ -94,7 +150,7 @@ func (d *DockerContainerApi) DeleteOrchestration(c *gin.Context) {
 	}
 
 	// 调用服务层删除编排
-	failed, err := dockerContainerService.DeleteOrchestration(name)
+	failed, err := onHost(c, dockerContainerService).DeleteOrchestration(name)
 	if err != nil {
 		if len(failed) > 0 {
 			response.FailWithDetailed(failed, "部分容器删除失败: "+err.Error(), c)
@@ -116,7 +172,7 @@ func (d *DockerContainerApi) OrchestrationStatus(c *gin.Context) {
 	}
 
 	// 调用服务层获取编排状态
-	status, err := dockerContainerService.GetOrchestrationStatus(name)
+	status, err := onHost(c, dockerContainerService).GetOrchestrationStatus(name)
 	if err != nil {
 		if err.Error() == "orchestration not found" {
 			response.FailWithMessage("未找到该编排", c)
@@ -155,7 +211,7 @@ func (d *DockerContainerApi) BatchOperateOrchestration(c *gin.Context) {
 		}
 	}
 	force := c.Query("force") == "true"
-	successIDs, failed := dockerContainerService.BatchOperateByOrchestrationLabel(name, op, timeout, force)
+	successIDs, failed := onHost(c, dockerContainerService).BatchOperateByOrchestrationLabel(name, op, timeout, force)
 	resp := map[string]interface{}{
 		"successIDs": successIDs,
 		"failed":     failed,
@@ -19,7 +19,7 @@ type DockerOverviewApi struct{}
 // @Success 200 {object} response.Response{data=response.OverviewStats} "获取成功"
 // @Router /docker/overview [get]
 func (d *DockerOverviewApi) GetOverviewStats(c *gin.Context) {
-	stats, err := dockerOverviewService.GetOverviewStats()
+	stats, err := onHost(c, dockerOverviewService).GetOverviewStats()
 	if err != nil {
 		global.GVA_LOG.Error("获取Docker概览统计失败", zap.Error(err))
 		response.FailWithMessage("获取Docker概览统计失败: "+err.Error(), c)
@@ -39,7 +39,7 @@ func (d *DockerOverviewApi) GetOverviewStats(c *gin.Context) {
 // @Success 200 {object} response.Response{data=response.ConfigSummary} "获取成功"
 // @Router /docker/config/summary [get]
 func (d *DockerOverviewApi) GetConfigSummary(c *gin.Context) {
-	summary, err := dockerOverviewService.GetConfigSummary()
+	summary, err := onHost(c, dockerOverviewService).GetConfigSummary()
 	if err != nil {
 		global.GVA_LOG.Error("获取Docker配置摘要失败", zap.Error(err))
 		response.FailWithMessage("获取Docker配置摘要失败: "+err.Error(), c)
@@ -59,7 +59,7 @@ func (d *DockerOverviewApi) GetConfigSummary(c *gin.Context) {
 // @Success 200 {object} response.Response{data=response.DiskUsage} "获取成功"
 // @Router /docker/disk-usage [get]
 func (d *DockerOverviewApi) GetDockerDiskUsage(c *gin.Context) {
-	usage, err := dockerOverviewService.GetDockerDiskUsage()
+	usage, err := onHost(c, dockerOverviewService).GetDockerDiskUsage()
 	if err != nil {
 		global.GVA_LOG.Error("获取Docker磁盘使用情况失败", zap.Error(err))
 		response.FailWithMessage("获取Docker磁盘使用情况失败: "+err.Error(), c)
@@ -166,7 +166,7 @@ func (d *DockerRegistryApi) DeleteRegistry(c *gin.Context) {
 
 // TestRegistry 测试仓库连接
 // @Tags Docker仓库管理
-// @Summary 测试仓库连接
+// @Summary 使用保存的凭据访问仓库 /v2/ 接口，验证连接与认证
 // @Security ApiKeyAuth
 // @accept application/json
 // @Produce application/json
@@ -194,7 +194,7 @@ func (d *DockerRegistryApi) TestRegistry(c *gin.Context) {
 
 // SetDefaultRegistry 设置默认仓库
 // @Tags Docker仓库管理
-// @Summary 设置默认仓库
+// @Summary 设置默认仓库，拉取未指定仓库地址的镜像时从默认仓库拉取
 // @Security ApiKeyAuth
 // @accept application/json
 // @Produce application/json
@@ -220,3 +220,21 @@ func (d *DockerRegistryApi) SetDefaultRegistry(c *gin.Context) {
 	response.OkWithMessage("设置成功", c)
 }
 
+// ClearDefaultRegistry 取消默认仓库
+// @Tags Docker仓库管理
+// @Summary 取消默认仓库，恢复从Docker Hub拉取未指定仓库地址的镜像
+// @Security ApiKeyAuth
+// @accept application/json
+// @Produce application/json
+// @Success 200 {object} response.Response{msg=string} "取消成功"
+// @Router /docker/registries/default [delete]
+func (d *DockerRegistryApi) ClearDefaultRegistry(c *gin.Context) {
+	err := dockerRegistryService.ClearDefaultRegistry()
+	if err != nil {
+		global.GVA_LOG.Error("取消默认仓库失败", zap.Error(err))
+		response.FailWithMessage("取消默认仓库失败: "+err.Error(), c)
+		return
+	}
+
+	response.OkWithMessage("取消成功", c)
+}
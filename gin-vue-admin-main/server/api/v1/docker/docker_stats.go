@@ -0,0 +1,128 @@
+package docker
+
+import (
+	"context"
+	"time"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/response"
+	dockerReq "github.com/flipped-aurora/gin-vue-admin/server/model/docker/request"
+	dockerRes "github.com/flipped-aurora/gin-vue-admin/server/model/docker/response"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultStatsInterval = 5 // 默认推送间隔（秒）
+	minStatsInterval     = 2 // 最小推送间隔（秒）
+)
+
+type DockerStatsApi struct{}
+
+// GetAllContainerStats 获取所有运行中容器的资源使用情况
+// @Tags Docker
+// @Summary 获取所有运行中容器的CPU、内存、网络、磁盘IO快照
+// @Security ApiKeyAuth
+// @Produce application/json
+// @Success 200 {object} response.Response{data=[]dockerRes.ContainerResourceStats,msg=string} "获取成功"
+// @Router /docker/stats [get]
+func (d *DockerStatsApi) GetAllContainerStats(c *gin.Context) {
+	stats, err := onHost(c, dockerStatsService).GetAllContainerStats()
+	if err != nil {
+		global.GVA_LOG.Error("获取容器资源统计失败", zap.Error(err))
+		response.FailWithMessage("获取容器资源统计失败: "+err.Error(), c)
+		return
+	}
+
+	response.OkWithDetailed(stats, "获取成功", c)
+}
+
+// GetContainerStats 获取单个容器的资源使用情况
+// @Tags Docker
+// @Summary 获取指定容器的CPU、内存、网络、磁盘IO快照
+// @Security ApiKeyAuth
+// @Produce application/json
+// @Param id path string true "容器ID"
+// @Success 200 {object} response.Response{data=dockerRes.ContainerResourceStats,msg=string} "获取成功"
+// @Router /docker/containers/{id}/stats [get]
+func (d *DockerStatsApi) GetContainerStats(c *gin.Context) {
+	containerID := c.Param("id")
+	if containerID == "" {
+		response.FailWithMessage("容器ID不能为空", c)
+		return
+	}
+
+	stats, err := onHost(c, dockerStatsService).GetContainerStats(containerID)
+	if err != nil {
+		global.GVA_LOG.Error("获取容器资源统计失败", zap.String("containerID", containerID), zap.Error(err))
+		if err.Error() == "container not found" {
+			response.FailWithMessage("容器不存在", c)
+			return
+		}
+		response.FailWithMessage("获取容器资源统计失败: "+err.Error(), c)
+		return
+	}
+
+	response.OkWithDetailed(stats, "获取成功", c)
+}
+
+// StreamContainerStats 实时推送单个容器的资源使用情况
+// @Tags Docker
+// @Summary 实时推送指定容器的资源统计，支持WebSocket与SSE两种方式
+// @Description 携带 Upgrade: websocket 头时升级为WebSocket，每条消息为一次统计JSON；否则以SSE推送 stats 事件，约每秒一次
+// @Security ApiKeyAuth
+// @Produce text/event-stream
+// @Param id path string true "容器ID"
+// @Success 200 {object} dockerRes.ContainerResourceStats "资源统计"
+// @Router /docker/containers/{id}/stats/stream [get]
+func (d *DockerStatsApi) StreamContainerStats(c *gin.Context) {
+	containerID := c.Param("id")
+	if containerID == "" {
+		response.FailWithMessage("容器ID不能为空", c)
+		return
+	}
+
+	serveEventStream(c, "stats", func(ctx context.Context, emit func(data interface{}) error) error {
+		return onHost(c, dockerStatsService).StreamContainerStats(ctx, containerID, func(stats dockerRes.ContainerResourceStats) error {
+			return emit(stats)
+		})
+	}, func(err error) {
+		if err.Error() == "container not found" {
+			response.FailWithMessage("容器不存在", c)
+			return
+		}
+		response.FailWithMessage("获取容器资源统计失败: "+err.Error(), c)
+	})
+}
+
+// StreamAllContainerStats 实时推送所有运行中容器的资源使用情况
+// @Tags Docker
+// @Summary 按间隔推送所有运行中容器的资源统计，支持WebSocket与SSE两种方式
+// @Description 携带 Upgrade: websocket 头时升级为WebSocket，每条消息为一组统计JSON数组；否则以SSE推送 stats 事件
+// @Security ApiKeyAuth
+// @Produce text/event-stream
+// @Param data query dockerReq.StatsStreamOptions false "推送选项"
+// @Success 200 {array} dockerRes.ContainerResourceStats "资源统计"
+// @Router /docker/stats/stream [get]
+func (d *DockerStatsApi) StreamAllContainerStats(c *gin.Context) {
+	var options dockerReq.StatsStreamOptions
+	if err := c.ShouldBindQuery(&options); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	if options.Interval == 0 {
+		options.Interval = defaultStatsInterval
+	}
+	if options.Interval < minStatsInterval {
+		options.Interval = minStatsInterval
+	}
+	interval := time.Duration(options.Interval) * time.Second
+
+	serveEventStream(c, "stats", func(ctx context.Context, emit func(data interface{}) error) error {
+		return onHost(c, dockerStatsService).StreamAllContainerStats(ctx, interval, func(stats []dockerRes.ContainerResourceStats) error {
+			return emit(stats)
+		})
+	}, func(err error) {
+		response.FailWithMessage("获取容器资源统计失败: "+err.Error(), c)
+	})
+}
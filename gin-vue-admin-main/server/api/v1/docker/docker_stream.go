@@ -0,0 +1,146 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+)
+
+// sseHeartbeatInterval SSE 心跳间隔，防止代理因空闲断开连接
+const sseHeartbeatInterval = 15 * time.Second
+
+// streamProducer 事件生产函数，ctx 在客户端断开时取消
+type streamProducer func(ctx context.Context, emit func(data interface{}) error) error
+
+// serveEventStream 以 WebSocket 或 SSE 推送 produce 产生的事件
+// WebSocket 每条消息为一个事件的JSON；SSE 以 event 为事件名推送，正常结束推送 end，出错推送 error
+// produce 在推送任何事件前返回的错误交给 onError 以普通JSON响应输出
+func serveEventStream(c *gin.Context, event string, produce streamProducer, onError func(err error)) {
+	if isWebSocketRequest(c) {
+		serveWebSocket(c, func(ws *websocket.Conn) {
+			ctx, cancel := context.WithCancel(c.Request.Context())
+			defer cancel()
+			go watchWebSocketClose(ws, cancel)
+
+			err := produce(ctx, func(data interface{}) error {
+				return websocket.JSON.Send(ws, data)
+			})
+			if err != nil {
+				_ = websocket.JSON.Send(ws, gin.H{"error": err.Error()})
+			}
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+	events := make(chan interface{}, 64)
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- produce(ctx, func(data interface{}) error {
+			select {
+			case events <- data:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	}()
+
+	heartbeat := time.NewTicker(sseHeartbeatInterval)
+	defer heartbeat.Stop()
+	started := false
+	start := func() {
+		if !started {
+			writeSSEHeaders(c)
+			started = true
+		}
+	}
+	send := func(name string, data interface{}) {
+		start()
+		c.SSEvent(name, data)
+		c.Writer.Flush()
+	}
+
+	for {
+		select {
+		case data := <-events:
+			send(event, data)
+		case err := <-errCh:
+			// 结束前发送缓冲区中剩余的事件
+			for len(events) > 0 {
+				send(event, <-events)
+			}
+			if err == nil {
+				send("end", "")
+				return
+			}
+			if !started {
+				onError(err)
+				return
+			}
+			send("error", err.Error())
+			return
+		case <-heartbeat.C:
+			start()
+			_, _ = c.Writer.WriteString(": ping\n\n")
+			c.Writer.Flush()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// isWebSocketRequest 判断是否为 WebSocket 升级请求
+func isWebSocketRequest(c *gin.Context) bool {
+	return strings.EqualFold(c.GetHeader("Upgrade"), "websocket") &&
+		strings.Contains(strings.ToLower(c.GetHeader("Connection")), "upgrade")
+}
+
+// serveWebSocket 升级为 WebSocket 连接并交给 handler 处理
+// 浏览器请求必须同源，防止借助 cookie 中的 x-token 发起跨站连接
+func serveWebSocket(c *gin.Context, handler func(ws *websocket.Conn)) {
+	server := websocket.Server{
+		Handshake: func(config *websocket.Config, req *http.Request) error {
+			origin := req.Header.Get("Origin")
+			if origin == "" {
+				return nil
+			}
+			u, err := url.Parse(origin)
+			if err != nil || !strings.EqualFold(u.Host, req.Host) {
+				return fmt.Errorf("cross origin websocket request is not allowed")
+			}
+			config.Origin = u
+			return nil
+		},
+		Handler: handler,
+	}
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+// watchWebSocketClose 持续读取客户端消息，连接关闭时调用 onClose
+// 仅用于单向推送的连接，客户端发送的内容会被忽略
+func watchWebSocketClose(ws *websocket.Conn, onClose func()) {
+	var discard string
+	for {
+		if err := websocket.Message.Receive(ws, &discard); err != nil {
+			onClose()
+			return
+		}
+	}
+}
+
+// writeSSEHeaders 写入 SSE 响应头并立即刷新
+func writeSSEHeaders(c *gin.Context) {
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // 关闭 nginx 缓冲
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+}
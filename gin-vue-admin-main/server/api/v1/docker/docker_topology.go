@@ -0,0 +1,36 @@
+package docker
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/response"
+	dockerReq "github.com/flipped-aurora/gin-vue-admin/server/model/docker/request"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type DockerTopologyApi struct{}
+
+// GetTopology 获取Docker拓扑关系图
+// @Tags Docker
+// @Summary 获取容器、网络、存储卷与主机端口的关系图，并标记孤立与过度连接的容器
+// @Security ApiKeyAuth
+// @Produce application/json
+// @Param data query dockerReq.TopologyFilter false "过滤条件"
+// @Success 200 {object} response.Response{data=dockerRes.TopologyGraph,msg=string} "获取成功"
+// @Router /docker/topology [get]
+func (d *DockerTopologyApi) GetTopology(c *gin.Context) {
+	var filter dockerReq.TopologyFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	graph, err := onHost(c, dockerTopologyService).GetTopology(filter)
+	if err != nil {
+		global.GVA_LOG.Error("获取Docker拓扑图失败", zap.Error(err))
+		response.FailWithMessage("获取Docker拓扑图失败: "+err.Error(), c)
+		return
+	}
+
+	response.OkWithDetailed(graph, "获取成功", c)
+}
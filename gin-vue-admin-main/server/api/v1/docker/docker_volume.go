@@ -39,7 +39,7 @@ func (d *DockerVolumeApi) GetVolumeList(c *gin.Context) {
 	}
 
 	// 调用服务层获取存储卷列表
-	volumes, total, err := dockerVolumeService.GetVolumeList(pageInfo)
+	volumes, total, err := onHost(c, dockerVolumeService).GetVolumeList(pageInfo)
 	if err != nil {
 		global.GVA_LOG.Error("获取存储卷列表失败", zap.Error(err))
 		response.FailWithMessage("获取存储卷列表失败: "+err.Error(), c)
@@ -69,7 +69,7 @@ func (d *DockerVolumeApi) GetVolumeDetail(c *gin.Context) {
 	}
 
 	// 调用服务层获取存储卷详细信息
-	volumeDetail, err := dockerVolumeService.GetVolumeDetail(volumeName)
+	volumeDetail, err := onHost(c, dockerVolumeService).GetVolumeDetail(volumeName)
 	if err != nil {
 		global.GVA_LOG.Error("获取存储卷详细信息失败", zap.String("volumeName", volumeName), zap.Error(err))
 		response.FailWithMessage("获取存储卷详细信息失败: "+err.Error(), c)
@@ -102,7 +102,7 @@ func (d *DockerVolumeApi) CreateVolume(c *gin.Context) {
 	}
 
 	// 调用服务层创建存储卷
-	volumeName, err := dockerVolumeService.CreateVolume(createReq)
+	volumeName, err := onHost(c, dockerVolumeService).CreateVolume(createReq)
 	if err != nil {
 		global.GVA_LOG.Error("创建存储卷失败", zap.String("name", createReq.Name), zap.Error(err))
 		response.FailWithMessage("创建存储卷失败: "+err.Error(), c)
@@ -136,7 +136,7 @@ func (d *DockerVolumeApi) RemoveVolume(c *gin.Context) {
 	force := forceStr == "true"
 
 	// 调用服务层删除存储卷
-	err := dockerVolumeService.RemoveVolume(volumeName, force)
+	err := onHost(c, dockerVolumeService).RemoveVolume(volumeName, force)
 	if err != nil {
 		global.GVA_LOG.Error("删除存储卷失败", zap.String("volumeName", volumeName), zap.Error(err))
 		response.FailWithMessage("删除存储卷失败: "+err.Error(), c)
@@ -156,7 +156,7 @@ func (d *DockerVolumeApi) RemoveVolume(c *gin.Context) {
 // @Router /docker/volumes/prune [post]
 func (d *DockerVolumeApi) PruneVolumes(c *gin.Context) {
 	// 调用服务层清理存储卷
-	deletedCount, spaceReclaimed, err := dockerVolumeService.PruneVolumes()
+	deletedCount, spaceReclaimed, err := onHost(c, dockerVolumeService).PruneVolumes()
 	if err != nil {
 		global.GVA_LOG.Error("清理存储卷失败", zap.Error(err))
 		response.FailWithMessage("清理存储卷失败: "+err.Error(), c)
@@ -0,0 +1,195 @@
+package docker
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/response"
+	dockerReq "github.com/flipped-aurora/gin-vue-admin/server/model/docker/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// BackupVolume 备份存储卷
+// @Tags Docker存储卷管理
+// @Summary 将存储卷内容打包为 tar.gz 保存到本地备份目录或对象存储，可在备份期间停止挂载该存储卷的容器
+// @Security ApiKeyAuth
+// @accept application/json
+// @Produce application/json
+// @Param name path string true "存储卷名称"
+// @Param data body dockerReq.VolumeBackupRequest true "备份参数"
+// @Success 200 {object} response.Response{data=docker.DockerVolumeBackup,msg=string} "备份成功"
+// @Router /docker/volumes/{name}/backup [post]
+func (d *DockerVolumeApi) BackupVolume(c *gin.Context) {
+	volumeName := c.Param("name")
+	if volumeName == "" {
+		response.FailWithMessage("存储卷名称不能为空", c)
+		return
+	}
+	var backupReq dockerReq.VolumeBackupRequest
+	if err := c.ShouldBindJSON(&backupReq); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	backup, err := onHost(c, dockerVolumeService).BackupVolume(volumeName, backupReq, utils.GetUserID(c))
+	if err != nil {
+		failVolumeBackup(c, "备份存储卷失败", err)
+		return
+	}
+
+	response.OkWithDetailed(backup, "备份成功", c)
+}
+
+// GetVolumeBackupList 获取存储卷备份列表
+// @Tags Docker存储卷管理
+// @Summary 分页获取存储卷备份记录，按时间倒序
+// @Security ApiKeyAuth
+// @Produce application/json
+// @Param data query dockerReq.VolumeBackupFilter false "查询参数"
+// @Success 200 {object} response.Response{data=response.PageResult,msg=string} "获取成功"
+// @Router /docker/volume-backups [get]
+func (d *DockerVolumeApi) GetVolumeBackupList(c *gin.Context) {
+	var filter dockerReq.VolumeBackupFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	// 设置默认分页参数
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+	if filter.PageSize <= 0 || filter.PageSize > 100 {
+		filter.PageSize = 10
+	}
+
+	backups, total, err := dockerVolumeService.GetVolumeBackupList(filter)
+	if err != nil {
+		global.GVA_LOG.Error("获取存储卷备份列表失败", zap.Error(err))
+		response.FailWithMessage("获取存储卷备份列表失败: "+err.Error(), c)
+		return
+	}
+
+	response.OkWithDetailed(response.PageResult{
+		List:     backups,
+		Total:    total,
+		Page:     filter.Page,
+		PageSize: filter.PageSize,
+	}, "获取成功", c)
+}
+
+// RestoreVolume 从备份恢复存储卷
+// @Tags Docker存储卷管理
+// @Summary 从备份恢复到当前主机的存储卷，目标不存在时自动创建，已存在时需开启覆盖
+// @Security ApiKeyAuth
+// @accept application/json
+// @Produce application/json
+// @Param data body dockerReq.VolumeRestoreRequest true "恢复参数"
+// @Success 200 {object} response.Response{data=dockerRes.VolumeRestoreResult,msg=string} "恢复成功"
+// @Router /docker/volume-backups/restore [post]
+func (d *DockerVolumeApi) RestoreVolume(c *gin.Context) {
+	var restoreReq dockerReq.VolumeRestoreRequest
+	if err := c.ShouldBindJSON(&restoreReq); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	result, err := onHost(c, dockerVolumeService).RestoreVolume(restoreReq)
+	if err != nil {
+		failVolumeBackup(c, "恢复存储卷失败", err)
+		return
+	}
+
+	response.OkWithDetailed(result, "恢复成功", c)
+}
+
+// DownloadVolumeBackup 下载存储卷备份文件
+// @Tags Docker存储卷管理
+// @Summary 下载存储卷备份文件（tar.gz）
+// @Security ApiKeyAuth
+// @Produce application/gzip
+// @Param id path int true "备份ID"
+// @Success 200 {file} file "备份文件"
+// @Router /docker/volume-backups/{id}/download [get]
+func (d *DockerVolumeApi) DownloadVolumeBackup(c *gin.Context) {
+	id, ok := parseVolumeBackupID(c)
+	if !ok {
+		return
+	}
+
+	reader, fileName, err := dockerVolumeService.OpenVolumeBackup(c.Request.Context(), id)
+	if err != nil {
+		failVolumeBackup(c, "下载存储卷备份失败", err)
+		return
+	}
+	defer reader.Close()
+
+	c.Header("Content-Type", "application/gzip")
+	c.Header("Content-Disposition", "attachment; filename="+fileName)
+	c.Status(http.StatusOK)
+	if _, err := io.Copy(c.Writer, reader); err != nil {
+		global.GVA_LOG.Error("传输备份文件失败", zap.Uint("id", id), zap.Error(err))
+	}
+}
+
+// DeleteVolumeBackup 删除存储卷备份
+// @Tags Docker存储卷管理
+// @Summary 删除存储卷备份记录及备份文件
+// @Security ApiKeyAuth
+// @Produce application/json
+// @Param id path int true "备份ID"
+// @Success 200 {object} response.Response{msg=string} "删除成功"
+// @Router /docker/volume-backups/{id} [delete]
+func (d *DockerVolumeApi) DeleteVolumeBackup(c *gin.Context) {
+	id, ok := parseVolumeBackupID(c)
+	if !ok {
+		return
+	}
+
+	if err := dockerVolumeService.DeleteVolumeBackup(id); err != nil {
+		failVolumeBackup(c, "删除存储卷备份失败", err)
+		return
+	}
+
+	response.OkWithMessage("删除成功", c)
+}
+
+func parseVolumeBackupID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.FailWithMessage("无效的备份ID", c)
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// failVolumeBackup 将服务层错误转换为中文提示
+func failVolumeBackup(c *gin.Context, prefix string, err error) {
+	global.GVA_LOG.Error(prefix, zap.Error(err))
+	msg := err.Error()
+	switch {
+	case msg == "volume not found":
+		response.FailWithMessage("存储卷不存在", c)
+	case msg == "volume backup not found":
+		response.FailWithMessage("备份不存在", c)
+	case msg == "backup file not found":
+		response.FailWithMessage("备份文件不存在", c)
+	case msg == "backup checksum mismatch":
+		response.FailWithMessage("备份文件校验失败，文件可能已损坏或被修改", c)
+	case msg == "volume already exists":
+		response.FailWithMessage("目标存储卷已存在，如需覆盖请开启覆盖选项", c)
+	case msg == "volume mountpoint is not readable on this server":
+		response.FailWithMessage("当前服务器无法直接读取该存储卷的挂载点，请使用辅助容器方式", c)
+	case strings.HasPrefix(msg, "unsupported backup storage"):
+		response.FailWithMessage("不支持的存储位置，仅支持 local、oss", c)
+	case strings.HasPrefix(msg, "unsupported backup method"):
+		response.FailWithMessage("不支持的读取方式，仅支持 auto、container、mountpoint", c)
+	default:
+		response.FailWithMessage(prefix+": "+msg, c)
+	}
+}
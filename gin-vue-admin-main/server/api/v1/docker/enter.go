@@ -5,21 +5,42 @@ import "github.com/flipped-aurora/gin-vue-admin/server/service"
 type ApiGroup struct {
 	DockerContainerApi
 	DockerImageApi
+	DockerImageJobApi
+	DockerImageInspectApi
+	DockerImageRetentionApi
 	DockerNetworkApi
 	DockerVolumeApi
 	DockerRegistryApi
 	DockerConfigApi
 	DockerOverviewApi
 	DockerDiagnosticApi
+	DockerStatsApi
+	DockerMetricsApi
+	DockerAlertApi
+	DockerHostApi
+	DockerEventApi
+	DockerFileApi
+	DockerTopologyApi
 }
 
 var (
-	dockerContainerService  = service.ServiceGroupApp.DockerServiceGroup.DockerContainerService
-	dockerImageService      = service.ServiceGroupApp.DockerServiceGroup.DockerImageService
-	dockerNetworkService    = service.ServiceGroupApp.DockerServiceGroup.DockerNetworkService
-	dockerVolumeService     = service.ServiceGroupApp.DockerServiceGroup.DockerVolumeService
-	dockerRegistryService   = service.ServiceGroupApp.DockerServiceGroup.DockerRegistryService
-	dockerConfigService     = service.ServiceGroupApp.DockerServiceGroup.DockerConfigService
-	dockerOverviewService   = service.ServiceGroupApp.DockerServiceGroup.DockerOverviewService
-	dockerDiagnosticService = service.ServiceGroupApp.DockerServiceGroup.DockerDiagnosticService
+	dockerContainerService      = service.ServiceGroupApp.DockerServiceGroup.DockerContainerService
+	dockerImageService          = service.ServiceGroupApp.DockerServiceGroup.DockerImageService
+	dockerImageJobService       = service.ServiceGroupApp.DockerServiceGroup.DockerImageJobService
+	dockerImageInspectService   = service.ServiceGroupApp.DockerServiceGroup.DockerImageInspectService
+	dockerImageRetentionService = service.ServiceGroupApp.DockerServiceGroup.DockerImageRetentionService
+	dockerNetworkService        = service.ServiceGroupApp.DockerServiceGroup.DockerNetworkService
+	dockerVolumeService         = service.ServiceGroupApp.DockerServiceGroup.DockerVolumeService
+	dockerRegistryService       = service.ServiceGroupApp.DockerServiceGroup.DockerRegistryService
+	dockerConfigService         = service.ServiceGroupApp.DockerServiceGroup.DockerConfigService
+	dockerOverviewService       = service.ServiceGroupApp.DockerServiceGroup.DockerOverviewService
+	dockerDiagnosticService     = service.ServiceGroupApp.DockerServiceGroup.DockerDiagnosticService
+	dockerStatsService          = service.ServiceGroupApp.DockerServiceGroup.DockerStatsService
+	dockerMetricsService        = service.ServiceGroupApp.DockerServiceGroup.DockerMetricsService
+	dockerAlertService          = service.ServiceGroupApp.DockerServiceGroup.DockerAlertService
+	dockerHostService           = service.ServiceGroupApp.DockerServiceGroup.DockerHostService
+	dockerConnectionService     = service.ServiceGroupApp.DockerServiceGroup.DockerConnectionService
+	dockerEventService          = service.ServiceGroupApp.DockerServiceGroup.DockerEventService
+	dockerFileService           = service.ServiceGroupApp.DockerServiceGroup.DockerFileService
+	dockerTopologyService       = service.ServiceGroupApp.DockerServiceGroup.DockerTopologyService
 )
@@ -6,4 +6,27 @@ type Docker struct {
 	TLSVerify bool   `mapstructure:"tls-verify" json:"tlsVerify" yaml:"tls-verify"`
 	CertPath  string `mapstructure:"cert-path" json:"certPath" yaml:"cert-path"`
 	Timeout   int    `mapstructure:"timeout" json:"timeout" yaml:"timeout"`
-}
\ No newline at end of file
+	// 仓库凭据等敏感信息的加密密钥，为空时使用 jwt.signing-key；修改后已保存的凭据需要重新填写
+	CredentialKey string `mapstructure:"credential-key" json:"credentialKey" yaml:"credential-key"`
+	// 连接健康检查间隔（秒），默认10；连续失败时重建客户端
+	HealthInterval int `mapstructure:"health-interval" json:"healthInterval" yaml:"health-interval"`
+	// 守护进程事件保留天数，默认7
+	EventRetention int `mapstructure:"event-retention" json:"eventRetention" yaml:"event-retention"`
+	// 存储卷本地备份目录，默认 docker-backups
+	BackupPath string `mapstructure:"backup-path" json:"backupPath" yaml:"backup-path"`
+	// 存储卷备份与恢复使用的辅助容器镜像，需包含 sh 与 find，默认 busybox:latest
+	BackupImage string `mapstructure:"backup-image" json:"backupImage" yaml:"backup-image"`
+	// 文件浏览上传、下载的单个文件大小上限（MB），默认100
+	FileMaxSize int `mapstructure:"file-max-size" json:"fileMaxSize" yaml:"file-max-size"`
+	// 历史指标采集
+	Metrics DockerMetrics `mapstructure:"metrics" json:"metrics" yaml:"metrics"`
+}
+
+// DockerMetrics 主机与容器历史指标采集配置，数值为0时使用默认值
+type DockerMetrics struct {
+	Disable         bool `mapstructure:"disable" json:"disable" yaml:"disable"`                           // 关闭采集
+	Interval        int  `mapstructure:"interval" json:"interval" yaml:"interval"`                        // 采样间隔（秒），默认30
+	RawRetention    int  `mapstructure:"raw-retention" json:"rawRetention" yaml:"raw-retention"`          // 原始数据保留时长（小时），默认24
+	MinuteRetention int  `mapstructure:"minute-retention" json:"minuteRetention" yaml:"minute-retention"` // 5分钟聚合数据保留时长（小时），默认168
+	HourRetention   int  `mapstructure:"hour-retention" json:"hourRetention" yaml:"hour-retention"`       // 1小时聚合数据保留时长（小时），默认720
+}
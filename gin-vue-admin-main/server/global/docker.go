@@ -0,0 +1,43 @@
+package global
+
+import (
+	"sync/atomic"
+
+	"github.com/docker/docker/client"
+)
+
+// dockerClients 默认Docker主机的客户端：client 受 docker.timeout 限制，stream 不设置 HTTP 超时，
+// 用于跟随日志、统计、事件等长连接与大文件传输
+type dockerClients struct {
+	client *client.Client
+	stream *client.Client
+}
+
+// gvaDocker 配置文件中默认Docker主机的客户端；连接检查失败或配置变化时会被重建替换，
+// 请求与后台任务都在使用它，读写必须经过 GetDocker/GetDockerStream/SetDocker
+var gvaDocker atomic.Pointer[dockerClients]
+
+// GetDocker 获取默认Docker主机的客户端，未初始化时返回nil
+func GetDocker() *client.Client {
+	if clients := gvaDocker.Load(); clients != nil {
+		return clients.client
+	}
+	return nil
+}
+
+// GetDockerStream 获取默认Docker主机不设置 HTTP 超时的客户端，未初始化时返回nil；
+// 时长由调用方的 context 控制
+func GetDockerStream() *client.Client {
+	if clients := gvaDocker.Load(); clients != nil {
+		return clients.stream
+	}
+	return nil
+}
+
+// SetDocker 同时替换默认Docker主机的两个客户端，返回旧客户端，由调用方关闭
+func SetDocker(cli, stream *client.Client) (oldClient, oldStream *client.Client) {
+	if old := gvaDocker.Swap(&dockerClients{client: cli, stream: stream}); old != nil {
+		return old.client, old.stream
+	}
+	return nil, nil
+}
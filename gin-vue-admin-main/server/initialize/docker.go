@@ -6,36 +6,36 @@ import (
 
 	"github.com/docker/docker/client"
 	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils"
 	"go.uber.org/zap"
 )
 
 func Docker() {
-	dockerConfig := global.GVA_CONFIG.Docker
-	
-	// 设置默认值
-	if dockerConfig.Host == "" {
-		dockerConfig.Host = "unix:///var/run/docker.sock"
-	}
-	if dockerConfig.Version == "" {
-		dockerConfig.Version = "1.41"
+	opts, certs, err := utils.DefaultDockerClientOptions(global.GVA_CONFIG.Docker)
+	if err != nil {
+		global.GVA_LOG.Error("Docker client configuration is invalid", zap.Error(err))
+		return
 	}
-	if dockerConfig.Timeout == 0 {
-		dockerConfig.Timeout = 30
+	// 启动时检查 TLS 证书有效期
+	for _, cert := range certs {
+		remaining := time.Until(cert.Cert.NotAfter)
+		if remaining <= 0 {
+			global.GVA_LOG.Error("Docker TLS certificate has expired", zap.String("file", cert.File), zap.Time("notAfter", cert.Cert.NotAfter))
+		} else if remaining < 30*24*time.Hour {
+			global.GVA_LOG.Warn("Docker TLS certificate expires soon", zap.String("file", cert.File), zap.Time("notAfter", cert.Cert.NotAfter))
+		}
 	}
 
 	// 创建Docker客户端
-	cli, err := client.NewClientWithOpts(
-		client.WithHost(dockerConfig.Host),
-		client.WithVersion(dockerConfig.Version),
-		client.WithTimeout(time.Duration(dockerConfig.Timeout)*time.Second),
-	)
+	conn, err := utils.NewDockerClient(opts)
 	if err != nil {
 		global.GVA_LOG.Error("Docker client initialization failed", zap.Error(err))
 		return
 	}
+	cli := conn.Client
 
 	// 测试连接
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(dockerConfig.Timeout)*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
 	defer cancel()
 
 	_, err = cli.Ping(ctx)
@@ -46,23 +46,24 @@ func Docker() {
 		global.GVA_LOG.Info("Docker client connected successfully")
 	}
 
-	global.GVA_DOCKER = cli
+	global.SetDocker(cli, conn.Stream)
 }
 
 // GetDockerClient 获取Docker客户端，如果未初始化则返回nil
 func GetDockerClient() *client.Client {
-	return global.GVA_DOCKER
+	return global.GetDocker()
 }
 
 // IsDockerAvailable 检查Docker是否可用
 func IsDockerAvailable() bool {
-	if global.GVA_DOCKER == nil {
+	cli := global.GetDocker()
+	if cli == nil {
 		return false
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	_, err := global.GVA_DOCKER.Ping(ctx)
+	_, err := cli.Ping(ctx)
 	return err == nil
-}
\ No newline at end of file
+}
@@ -4,7 +4,6 @@ import (
 	"github.com/flipped-aurora/gin-vue-admin/server/global"
 	"github.com/flipped-aurora/gin-vue-admin/server/model/cion"
 	"github.com/flipped-aurora/gin-vue-admin/server/model/docker"
-	"gorm.io/gorm"
 )
 
 func bizModel() error {
@@ -14,31 +13,43 @@ func bizModel() error {
 		return err
 	}
 	
-	// Docker模型迁移 - 移除DockerRegistry，因为我们直接从1Panel读取
+	// Docker模型迁移，DockerRegistry 已在 RegisterTables 中迁移
 	err = db.AutoMigrate(
 		&docker.DockerOrchestration{},
 		&docker.DockerOrchestrationService{},
+		&docker.DockerMetric{},
+		&docker.DockerAlertRule{},
+		&docker.DockerAlertChannel{},
+		&docker.DockerAlertSilence{},
+		&docker.DockerAlertEvent{},
+		&docker.DockerImageJob{},
+		&docker.DockerImageReport{},
+		&docker.DockerImageReportPackage{},
+		&docker.DockerImageReportFinding{},
+		&docker.DockerImageReportFile{},
+		&docker.DockerVulnerability{},
+		&docker.DockerImageRetentionPolicy{},
+		&docker.DockerImageRetentionRun{},
+		&docker.DockerHost{},
+		&docker.DockerEvent{},
+		&docker.DockerVolumeBackup{},
 	)
 	if err != nil {
 		return err
 	}
-	
-	// 清理可能存在的docker_registries表数据
-	cleanupDockerRegistriesTable(db)
-	
-	return nil
-}
 
-// cleanupDockerRegistriesTable 清理docker_registries表
-func cleanupDockerRegistriesTable(db *gorm.DB) {
-	// 检查表是否存在
-	if db.Migrator().HasTable("docker_registries") {
-		// 删除表中的所有数据
-		db.Exec("DELETE FROM docker_registries")
-		global.GVA_LOG.Info("已清理docker_registries表中的数据")
-		
-		// 可选：完全删除表
-		// db.Migrator().DropTable("docker_registries")
-		// global.GVA_LOG.Info("已删除docker_registries表")
+	// 编排名称改为在同一主机内唯一，删除旧版本的全局名称唯一索引
+	if db.Migrator().HasIndex(&docker.DockerOrchestration{}, "idx_docker_orchestrations_name") {
+		if err = db.Migrator().DropIndex(&docker.DockerOrchestration{}, "idx_docker_orchestrations_name"); err != nil {
+			return err
+		}
 	}
+	// 事件改为按主机去重，删除旧版本不含主机的唯一索引
+	if db.Migrator().HasIndex(&docker.DockerEvent{}, "idx_docker_event_unique") {
+		if err = db.Migrator().DropIndex(&docker.DockerEvent{}, "idx_docker_event_unique"); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
@@ -76,6 +76,9 @@ func Routers() *gin.Engine {
 	PrivateGroup := Router.Group(global.GVA_CONFIG.System.RouterPrefix)
 
 	PrivateGroup.Use(middleware.JWTAuth()).Use(middleware.CasbinHandler())
+	// Docker资源路由按 hostId 参数或 X-Docker-Host 请求头选择主机
+	DockerHostGroup := PrivateGroup.Group("")
+	DockerHostGroup.Use(middleware.DockerHost())
 
 	// 注册自定义容器路由
 	router.RegisterContainerRouter(PrivateGroup)
@@ -109,13 +112,20 @@ func Routers() *gin.Engine {
 		systemRouter.InitAuthorityBtnRouterRouter(PrivateGroup)             // 按钮权限管理
 		systemRouter.InitSysExportTemplateRouter(PrivateGroup, PublicGroup) // 导出模板
 		systemRouter.InitSysParamsRouter(PrivateGroup, PublicGroup)         // 参数管理
-		dockerRouter.InitDockerContainerRouter(PrivateGroup)                // Docker容器管理路由
-		dockerRouter.InitDockerImageRouter(PrivateGroup)                    // Docker镜像管理路由
-		dockerRouter.InitDockerNetworkRouter(PrivateGroup)                  // Docker网络管理路由
-		dockerRouter.InitDockerVolumeRouter(PrivateGroup)                   // Docker存储卷管理路由
-		dockerRouter.InitDockerOrchestrationRouter(PrivateGroup)            // Docker编排管理路由
+		dockerRouter.InitDockerContainerRouter(DockerHostGroup)             // Docker容器管理路由
+		dockerRouter.InitDockerImageRouter(DockerHostGroup)                 // Docker镜像管理路由
+		dockerRouter.InitDockerNetworkRouter(DockerHostGroup)               // Docker网络管理路由
+		dockerRouter.InitDockerVolumeRouter(DockerHostGroup)                // Docker存储卷管理路由
+		dockerRouter.InitDockerOrchestrationRouter(DockerHostGroup)         // Docker编排管理路由
 		dockerRouter.InitDockerRegistryRouter(PrivateGroup)                 // Docker仓库管理路由
 		dockerRouter.InitDockerConfigRouter(PrivateGroup)                   // Docker配置管理路由
+		dockerRouter.InitDockerStatsRouter(DockerHostGroup)                 // Docker资源统计路由
+		dockerRouter.InitDockerFileRouter(DockerHostGroup)                  // 容器与存储卷文件浏览路由
+		dockerRouter.InitDockerTopologyRouter(DockerHostGroup)              // Docker拓扑关系图路由
+		dockerRouter.InitDockerMetricsRouter(DockerHostGroup)               // Docker历史指标路由
+		dockerRouter.InitDockerAlertRouter(DockerHostGroup)                 // Docker告警路由
+		dockerRouter.InitDockerHostRouter(PrivateGroup)                     // Docker主机管理路由
+		dockerRouter.InitDockerEventRouter(DockerHostGroup)                 // Docker事件时间线路由
 		// dockerRouter.InitDockerOverviewRouter(PrivateGroup)                 // Docker概览管理路由 (临时注释，使用公开路由测试)
 
 		systemRouter.InitDatabaseRouter(PublicGroup)                   // 数据库管理路由
@@ -1,7 +1,9 @@
 package initialize
 
 import (
+	"context"
 	"fmt"
+	"github.com/flipped-aurora/gin-vue-admin/server/service"
 	"github.com/flipped-aurora/gin-vue-admin/server/task"
 
 	"github.com/robfig/cron/v3"
@@ -24,6 +26,27 @@ func Timer() {
 			fmt.Println("add timer error:", err)
 		}
 
+		// Docker连接健康检查
+		dockerConnectionTimer()
+
+		// Docker事件记录与清理
+		dockerEventTimer()
+
+		// Docker历史指标采集、聚合与清理
+		dockerMetricsTimer()
+
+		// Docker告警规则评估
+		dockerAlertTimer()
+
+		// Docker镜像任务清理
+		dockerImageJobTimer()
+
+		// Docker镜像库存同步
+		dockerImageInventoryTimer()
+
+		// Docker镜像保留策略
+		dockerImageRetentionTimer()
+
 		// 其他定时任务定在这里 参考上方使用方法
 
 		//_, err := global.GVA_Timer.AddTaskByFunc("定时任务标识", "corn表达式", func() {
@@ -35,3 +58,143 @@ func Timer() {
 		//}
 	}()
 }
+
+// dockerConnectionTimer 注册Docker连接检查任务，守护进程恢复或端点配置变化后重建客户端
+func dockerConnectionTimer() {
+	const cronName = "DockerConnection"
+	global.GVA_Timer.Clear(cronName)
+
+	connectionService := service.ServiceGroupApp.DockerServiceGroup.DockerConnectionService
+	option := []cron.Option{
+		cron.WithSeconds(),
+		cron.WithChain(cron.SkipIfStillRunning(cron.DiscardLogger)),
+	}
+	_, err := global.GVA_Timer.AddTaskByFunc(cronName, fmt.Sprintf("@every %ds", int(connectionService.HealthInterval().Seconds())), func() {
+		connectionService.CheckConnection()
+	}, "检查Docker连接状态", option...)
+	if err != nil {
+		fmt.Println("add timer error:", err)
+	}
+}
+
+// dockerEventTimer 启动Docker事件记录并注册过期事件清理任务
+func dockerEventTimer() {
+	const cronName = "DockerEvent"
+	global.GVA_Timer.Clear(cronName)
+
+	eventService := service.ServiceGroupApp.DockerServiceGroup.DockerEventService
+	eventService.StartRecorder()
+	_, err := global.GVA_Timer.AddTaskByFunc(cronName, "@every 1h", func() {
+		if err := eventService.Cleanup(); err != nil {
+			fmt.Println("timer error:", err)
+		}
+	}, "清理过期的Docker事件", cron.WithSeconds())
+	if err != nil {
+		fmt.Println("add timer error:", err)
+	}
+}
+
+// dockerMetricsTimer 注册主机与容器历史指标任务，重载配置时先清除旧任务
+func dockerMetricsTimer() {
+	const cronName = "DockerMetrics"
+	global.GVA_Timer.Clear(cronName)
+
+	metricsService := service.ServiceGroupApp.DockerServiceGroup.DockerMetricsService
+	cfg := metricsService.MetricsConfig()
+	if cfg.Disable {
+		return
+	}
+
+	// 上一轮未结束时跳过本轮，避免容器较多时采集任务堆积
+	option := []cron.Option{
+		cron.WithSeconds(),
+		cron.WithChain(cron.SkipIfStillRunning(cron.DiscardLogger)),
+	}
+	_, err := global.GVA_Timer.AddTaskByFunc(cronName, fmt.Sprintf("@every %ds", cfg.Interval), func() {
+		if err := metricsService.Sample(); err != nil {
+			fmt.Println("timer error:", err)
+		}
+	}, "采集主机与容器资源指标", option...)
+	if err != nil {
+		fmt.Println("add timer error:", err)
+	}
+
+	_, err = global.GVA_Timer.AddTaskByFunc(cronName, "@every 5m", func() {
+		if err := metricsService.Rollup(); err != nil {
+			fmt.Println("timer error:", err)
+		}
+		if err := metricsService.Cleanup(); err != nil {
+			fmt.Println("timer error:", err)
+		}
+	}, "聚合并清理历史资源指标", option...)
+	if err != nil {
+		fmt.Println("add timer error:", err)
+	}
+}
+
+// dockerAlertTimer 注册告警规则评估任务，重载配置时先清除旧任务
+func dockerAlertTimer() {
+	const cronName = "DockerAlert"
+	global.GVA_Timer.Clear(cronName)
+
+	alertService := service.ServiceGroupApp.DockerServiceGroup.DockerAlertService
+	option := []cron.Option{
+		cron.WithSeconds(),
+		cron.WithChain(cron.SkipIfStillRunning(cron.DiscardLogger)),
+	}
+	_, err := global.GVA_Timer.AddTaskByFunc(cronName, "@every 30s", func() {
+		if err := alertService.EvaluateAlerts(); err != nil {
+			fmt.Println("timer error:", err)
+		}
+	}, "评估Docker告警规则", option...)
+	if err != nil {
+		fmt.Println("add timer error:", err)
+	}
+}
+
+// dockerImageJobTimer 注册镜像任务与镜像检查清理任务，启动时的首次清理在建表后由 DockerImageJobCleanup 执行
+func dockerImageJobTimer() {
+	const cronName = "DockerImageJob"
+	global.GVA_Timer.Clear(cronName)
+
+	_, err := global.GVA_Timer.AddTaskByFunc(cronName, "@every 1h", DockerImageJobCleanup, "清理过期的镜像任务、构建上下文与镜像检查临时文件", cron.WithSeconds())
+	if err != nil {
+		fmt.Println("add timer error:", err)
+	}
+}
+
+// DockerImageJobCleanup 清理过期的镜像任务与镜像检查，并标记重启前中断的任务，需在建表之后调用
+func DockerImageJobCleanup() {
+	dockerServiceGroup := service.ServiceGroupApp.DockerServiceGroup
+	if err := dockerServiceGroup.DockerImageJobService.Cleanup(); err != nil {
+		fmt.Println("timer error:", err)
+	}
+	if err := dockerServiceGroup.DockerImageInspectService.Cleanup(); err != nil {
+		fmt.Println("timer error:", err)
+	}
+}
+
+// dockerImageInventoryTimer 定期将Docker镜像同步到 image 表，Docker不可用时跳过
+func dockerImageInventoryTimer() {
+	const cronName = "DockerImageInventory"
+	global.GVA_Timer.Clear(cronName)
+
+	imageService := service.ServiceGroupApp.CionServiceGroup.ImageService
+	_, err := global.GVA_Timer.AddTaskByFunc(cronName, "@every 5m", func() {
+		if global.GetDocker() == nil {
+			return
+		}
+		if _, err := imageService.SyncImages(context.Background()); err != nil {
+			fmt.Println("timer error:", err)
+		}
+	}, "同步Docker镜像库存", cron.WithSeconds())
+	if err != nil {
+		fmt.Println("add timer error:", err)
+	}
+}
+
+// dockerImageRetentionTimer 按各保留策略的执行周期注册清理任务，策略增删改时由服务层重新注册
+func dockerImageRetentionTimer() {
+	retentionService := service.ServiceGroupApp.DockerServiceGroup.DockerImageRetentionService
+	retentionService.ScheduleRetentionPolicies()
+}
@@ -48,6 +48,7 @@ func initializeSystem() {
 	initialize.Docker()        // 初始化Docker客户端
 	initialize.SetupHandlers() // 注册全局函数
 	if global.GVA_DB != nil {
-		initialize.RegisterTables() // 初始化表
+		initialize.RegisterTables()        // 初始化表
+		initialize.DockerImageJobCleanup() // 标记重启前中断的镜像任务
 	}
 }
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/response"
+	dockerService "github.com/flipped-aurora/gin-vue-admin/server/service/docker"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// DockerHost 按 hostId 查询参数或 X-Docker-Host 请求头选择Docker主机（ID或名称），
+// 未指定时使用配置文件中的默认主机；选中的主机写入上下文供接口层绑定服务
+func DockerHost() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		selector := c.Query("hostId")
+		if selector == "" {
+			selector = c.GetHeader("X-Docker-Host")
+		}
+		if selector == "" {
+			c.Next()
+			return
+		}
+
+		hostID, cli, err := (&dockerService.DockerHostService{}).ResolveHost(selector)
+		if err != nil {
+			global.GVA_LOG.Warn("选择Docker主机失败", zap.String("host", selector), zap.Error(err))
+			response.FailWithMessage("Docker主机不可用: "+err.Error(), c)
+			c.Abort()
+			return
+		}
+		c.Set("dockerHostId", hostID)
+		c.Set("dockerClient", cli)
+		c.Next()
+	}
+}
+
+// DefaultDockerHostOnly 只支持默认主机的接口（历史指标、告警只采集默认主机）选择了其他主机时返回 400，
+// 避免忽略主机参数而返回默认主机的数据；需在 DockerHost 之后使用
+func DefaultDockerHostOnly() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetUint("dockerHostId") != 0 {
+			c.JSON(http.StatusBadRequest, response.Response{
+				Code: response.ERROR,
+				Data: map[string]interface{}{},
+				Msg:  "该功能只支持默认Docker主机",
+			})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
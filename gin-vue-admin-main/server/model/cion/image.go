@@ -9,19 +9,32 @@ import (
 // image表 结构体  Image
 type Image struct {
 	global.GVA_MODEL
-	ImageId      string    `json:"imageId" form:"imageId" gorm:"comment:镜像ID;column:image_id;size:64;"`             //镜像ID
-	Repository   string    `json:"repository" form:"repository" gorm:"comment:仓库名;column:repository;size:255;"`     //仓库名
-	Tag          string    `json:"tag" form:"tag" gorm:"comment:标签;column:tag;size:100;"`                           //标签
-	Size         string    `json:"size" form:"size" gorm:"comment:大小;column:size;size:50;"`                         //大小
-	CreatedTime  time.Time `json:"createdTime" form:"createdTime" gorm:"comment:创建时间;column:created_time;"`         //创建时间
-	Architecture string    `json:"architecture" form:"architecture" gorm:"comment:架构;column:architecture;size:50;"` //架构
-	Digest       string    `json:"digest" form:"digest" gorm:"comment:摘要;column:digest;size:255;"`                  //摘要
-	CreatedBy    int       `json:"createdBy" form:"createdBy" gorm:"comment:创建者;column:created_by;size:19;"`        //创建者
-	UpdatedBy    int       `json:"updatedBy" form:"updatedBy" gorm:"comment:更新者;column:updated_by;size:19;"`        //更新者
-	DeletedBy    int       `json:"deletedBy" form:"deletedBy" gorm:"comment:删除者;column:deleted_by;size:19;"`        //删除者
+	ImageId      string     `json:"imageId" form:"imageId" gorm:"comment:镜像ID;column:image_id;size:64;"`             //镜像ID
+	Repository   string     `json:"repository" form:"repository" gorm:"comment:仓库名;column:repository;size:255;"`     //仓库名
+	Tag          string     `json:"tag" form:"tag" gorm:"comment:标签;column:tag;size:100;"`                           //标签
+	Size         string     `json:"size" form:"size" gorm:"comment:大小;column:size;size:50;"`                         //大小
+	CreatedTime  time.Time  `json:"createdTime" form:"createdTime" gorm:"comment:创建时间;column:created_time;"`         //创建时间
+	Architecture string     `json:"architecture" form:"architecture" gorm:"comment:架构;column:architecture;size:50;"` //架构
+	Digest       string     `json:"digest" form:"digest" gorm:"comment:摘要;column:digest;size:255;"`                  //摘要
+	CreatedBy    int        `json:"createdBy" form:"createdBy" gorm:"comment:创建者;column:created_by;size:19;"`        //创建者
+	UpdatedBy    int        `json:"updatedBy" form:"updatedBy" gorm:"comment:更新者;column:updated_by;size:19;"`        //更新者
+	DeletedBy    int        `json:"deletedBy" form:"deletedBy" gorm:"comment:删除者;column:deleted_by;size:19;"`        //删除者
+	SizeBytes    int64      `json:"sizeBytes" form:"sizeBytes" gorm:"comment:大小（字节）;column:size_bytes;"`             //大小（字节）
+	Status       string     `json:"status" form:"status" gorm:"comment:状态;column:status;size:20;index;"`             //状态 present:存在于Docker removed:已从Docker移除
+	FirstSeenAt  *time.Time `json:"firstSeenAt" form:"firstSeenAt" gorm:"comment:首次发现时间;column:first_seen_at;"`      //首次发现时间
+	LastSeenAt   *time.Time `json:"lastSeenAt" form:"lastSeenAt" gorm:"comment:最后发现时间;column:last_seen_at;"`         //最后发现时间
+	RemovedAt    *time.Time `json:"removedAt" form:"removedAt" gorm:"comment:从Docker移除时间;column:removed_at;"`        //从Docker移除时间
+	PulledBy     int        `json:"pulledBy" form:"pulledBy" gorm:"comment:拉取者;column:pulled_by;size:19;"`           //拉取者
+	PulledAt     *time.Time `json:"pulledAt" form:"pulledAt" gorm:"comment:拉取时间;column:pulled_at;"`                  //拉取时间
 
 }
 
+// 镜像库存状态
+const (
+	ImageStatusPresent = "present" // 存在于Docker
+	ImageStatusRemoved = "removed" // 已从Docker移除
+)
+
 // TableName image表 Image自定义表名 image
 func (Image) TableName() string {
 	return "image"
@@ -8,5 +8,9 @@ import (
 
 type ImageSearch struct{
     CreatedAtRange []time.Time `json:"createdAtRange" form:"createdAtRange[]"`
+    ImageId        string      `json:"imageId" form:"imageId"`       // 镜像ID（前缀匹配）
+    Repository     string      `json:"repository" form:"repository"` // 仓库名（模糊匹配）
+    Tag            string      `json:"tag" form:"tag"`               // 标签
+    Status         string      `json:"status" form:"status"`         // 状态 present/removed
     request.PageInfo
 }
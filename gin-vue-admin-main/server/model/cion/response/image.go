@@ -0,0 +1,20 @@
+package response
+
+import "time"
+
+// ImageSyncResult 镜像库存同步结果
+type ImageSyncResult struct {
+	Total    int       `json:"total"`    // Docker中当前的镜像标签数
+	Added    int       `json:"added"`    // 新发现的记录数
+	Updated  int       `json:"updated"`  // 仍存在的记录数
+	Restored int       `json:"restored"` // 移除后重新出现的记录数
+	Removed  int       `json:"removed"`  // 本次标记为已移除的记录数
+	SyncedAt time.Time `json:"syncedAt"` // 同步时间
+}
+
+// ImageInventorySummary 镜像库存概况
+type ImageInventorySummary struct {
+	Present      int64      `json:"present"`      // 存在于Docker的记录数
+	Removed      int64      `json:"removed"`      // 已从Docker移除的记录数
+	LastSyncedAt *time.Time `json:"lastSyncedAt"` // 最近一次同步时间，服务启动后尚未同步时为空
+}
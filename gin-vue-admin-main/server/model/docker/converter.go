@@ -104,6 +104,9 @@ func ConvertToContainerDetail(dockerContainer types.ContainerJSON) response.Cont
 		Entrypoint:   dockerContainer.Config.Entrypoint,
 		Labels:       dockerContainer.Config.Labels,
 		ExposedPorts: convertExposedPorts(dockerContainer.Config.ExposedPorts),
+		Healthcheck:  convertHealthConfig(dockerContainer.Config.Healthcheck),
+		Tty:          dockerContainer.Config.Tty,
+		OpenStdin:    dockerContainer.Config.OpenStdin,
 	}
 
 	// 转换主机配置
@@ -185,6 +188,30 @@ func convertHostConfig(hostConfig *container.HostConfig) response.HostConfig {
 		UsernsMode:      string(hostConfig.UsernsMode),
 		Sysctls:         hostConfig.Sysctls,
 		Runtime:         hostConfig.Runtime,
+		NanoCPUs:          hostConfig.NanoCPUs,
+		CPUShares:         hostConfig.CPUShares,
+		Memory:            hostConfig.Memory,
+		MemoryReservation: hostConfig.MemoryReservation,
+	}
+}
+
+// convertHealthConfig 转换健康检查配置
+func convertHealthConfig(health *container.HealthConfig) *response.HealthConfig {
+	if health == nil {
+		return nil
+	}
+	formatDuration := func(d time.Duration) string {
+		if d == 0 {
+			return ""
+		}
+		return d.String()
+	}
+	return &response.HealthConfig{
+		Test:        health.Test,
+		Interval:    formatDuration(health.Interval),
+		Timeout:     formatDuration(health.Timeout),
+		StartPeriod: formatDuration(health.StartPeriod),
+		Retries:     health.Retries,
 	}
 }
 
@@ -261,6 +288,7 @@ func convertMounts(mounts []types.MountPoint) []response.Mount {
 	
 	for _, mount := range mounts {
 		responseMount := response.Mount{
+			Name:        mount.Name,
 			Target:      mount.Destination,
 			Source:      mount.Source,
 			Type:        string(mount.Type),
@@ -0,0 +1,121 @@
+package docker
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 告警规则类型
+const (
+	AlertRuleContainerExited     = "container_exited"     // 容器异常退出（退出码非0或dead）
+	AlertRuleContainerUnhealthy  = "container_unhealthy"  // 容器健康检查失败
+	AlertRuleContainerRestarting = "container_restarting" // 容器反复重启
+	AlertRuleContainerCPU        = "container_cpu"        // 容器CPU使用率过高
+	AlertRuleContainerMemory     = "container_memory"     // 容器内存使用率过高
+	AlertRuleHostCPU             = "host_cpu"             // 主机CPU使用率过高
+	AlertRuleHostMemory          = "host_memory"          // 主机内存使用率过高
+	AlertRuleDiskUsage           = "disk_usage"           // 磁盘使用率过高
+	AlertRuleDaemonUnreachable   = "daemon_unreachable"   // Docker守护进程不可达
+)
+
+// 告警通知渠道类型
+const (
+	AlertChannelEmail    = "email"    // 邮件，使用系统邮件配置发送
+	AlertChannelDingTalk = "dingtalk" // 钉钉机器人
+	AlertChannelFeishu   = "feishu"   // 飞书机器人
+	AlertChannelSlack    = "slack"    // Slack Incoming Webhook
+	AlertChannelWebhook  = "webhook"  // 通用Webhook，POST告警JSON
+)
+
+// 告警事件状态
+const (
+	AlertStatusPending  = "pending"  // 条件成立但未达到持续时长
+	AlertStatusFiring   = "firing"   // 告警中
+	AlertStatusResolved = "resolved" // 已恢复
+)
+
+// DockerAlertRule 告警规则
+type DockerAlertRule struct {
+	ID             uint           `json:"id" gorm:"primarykey"`                                                   // 主键ID
+	CreatedAt      time.Time      `json:"createdAt"`                                                              // 创建时间
+	UpdatedAt      time.Time      `json:"updatedAt"`                                                              // 更新时间
+	DeletedAt      gorm.DeletedAt `json:"-" gorm:"index"`                                                         // 删除时间
+	Name           string         `json:"name" gorm:"column:name;type:varchar(100);not null"`                     // 规则名称
+	Type           string         `json:"type" gorm:"column:type;type:varchar(50);not null"`                      // 规则类型
+	Target         string         `json:"target" gorm:"column:target;type:varchar(255)"`                          // 匹配对象：容器名称（支持通配符）或ID前缀，磁盘挂载点；为空匹配全部
+	Threshold      float64        `json:"threshold" gorm:"column:threshold"`                                      // 阈值：使用率（%），重启规则为重启次数
+	Duration       int            `json:"duration" gorm:"column:duration"`                                        // 持续时长（分钟），条件持续成立才告警；重启规则为统计窗口
+	RepeatInterval int            `json:"repeatInterval" gorm:"column:repeat_interval"`                           // 重复通知间隔（分钟），0为只通知一次
+	Severity       string         `json:"severity" gorm:"column:severity;type:varchar(20);not null"`              // 级别 (info/warning/critical)
+	Enabled        bool           `json:"enabled" gorm:"column:enabled"`                                          // 是否启用
+	ChannelIDs     []uint         `json:"channelIds" gorm:"column:channel_ids;type:varchar(500);serializer:json"` // 通知渠道ID
+	Description    string         `json:"description" gorm:"column:description;type:text"`                        // 描述
+}
+
+// TableName 设置表名
+func (DockerAlertRule) TableName() string {
+	return "docker_alert_rules"
+}
+
+// DockerAlertChannel 告警通知渠道
+type DockerAlertChannel struct {
+	ID        uint           `json:"id" gorm:"primarykey"`                                    // 主键ID
+	CreatedAt time.Time      `json:"createdAt"`                                               // 创建时间
+	UpdatedAt time.Time      `json:"updatedAt"`                                               // 更新时间
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`                                          // 删除时间
+	Name      string         `json:"name" gorm:"column:name;type:varchar(100);not null"`      // 渠道名称
+	Type      string         `json:"type" gorm:"column:type;type:varchar(20);not null"`       // 渠道类型 (email/dingtalk/feishu/slack/webhook)
+	Target    string         `json:"target" gorm:"column:target;type:varchar(1000);not null"` // 邮件为收件人（逗号分隔），其余为Webhook地址
+	Secret    string         `json:"secret,omitempty" gorm:"column:secret;type:varchar(255)"` // 钉钉/飞书加签密钥，查询时不返回
+	Enabled   bool           `json:"enabled" gorm:"column:enabled"`                           // 是否启用
+}
+
+// TableName 设置表名
+func (DockerAlertChannel) TableName() string {
+	return "docker_alert_channels"
+}
+
+// DockerAlertSilence 告警静默窗口，窗口内告警状态照常跟踪但不发送通知
+type DockerAlertSilence struct {
+	ID        uint      `json:"id" gorm:"primarykey"`                            // 主键ID
+	CreatedAt time.Time `json:"createdAt"`                                       // 创建时间
+	RuleID    uint      `json:"ruleId" gorm:"column:rule_id;index"`              // 规则ID，0为所有规则
+	Target    string    `json:"target" gorm:"column:target;type:varchar(255)"`   // 匹配对象，规则同告警规则的Target，为空匹配全部
+	StartsAt  time.Time `json:"startsAt" gorm:"column:starts_at;not null"`       // 开始时间
+	EndsAt    time.Time `json:"endsAt" gorm:"column:ends_at;not null;index"`     // 结束时间
+	Comment   string    `json:"comment" gorm:"column:comment;type:varchar(500)"` // 备注
+	CreatedBy uint      `json:"createdBy" gorm:"column:created_by"`              // 创建人
+}
+
+// TableName 设置表名
+func (DockerAlertSilence) TableName() string {
+	return "docker_alert_silences"
+}
+
+// DockerAlertEvent 告警事件，同一规则与对象同时只有一条未恢复的事件
+type DockerAlertEvent struct {
+	ID             uint       `json:"id" gorm:"primarykey"`                                        // 主键ID
+	CreatedAt      time.Time  `json:"createdAt"`                                                   // 创建时间
+	UpdatedAt      time.Time  `json:"updatedAt"`                                                   // 更新时间
+	RuleID         uint       `json:"ruleId" gorm:"column:rule_id;not null;index"`                 // 规则ID
+	RuleName       string     `json:"ruleName" gorm:"column:rule_name;type:varchar(100)"`          // 规则名称
+	RuleType       string     `json:"ruleType" gorm:"column:rule_type;type:varchar(50)"`           // 规则类型
+	Severity       string     `json:"severity" gorm:"column:severity;type:varchar(20)"`            // 级别
+	ObjectID       string     `json:"objectId" gorm:"column:object_id;type:varchar(255);not null"` // 对象标识：容器ID、挂载点、host或daemon
+	ObjectName     string     `json:"objectName" gorm:"column:object_name;type:varchar(255)"`      // 对象名称
+	Status         string     `json:"status" gorm:"column:status;type:varchar(20);not null;index"` // 状态 (pending/firing/resolved)
+	Value          float64    `json:"value" gorm:"column:value"`                                   // 最近一次评估的值
+	Message        string     `json:"message" gorm:"column:message;type:text"`                     // 告警详情
+	StartsAt       time.Time  `json:"startsAt" gorm:"column:starts_at"`                            // 条件开始成立的时间
+	FiredAt        *time.Time `json:"firedAt" gorm:"column:fired_at"`                              // 开始告警时间
+	ResolvedAt     *time.Time `json:"resolvedAt" gorm:"column:resolved_at"`                        // 恢复时间
+	LastNotifiedAt *time.Time `json:"lastNotifiedAt" gorm:"column:last_notified_at"`               // 最近一次通知时间
+	NotifyCount    int        `json:"notifyCount" gorm:"column:notify_count"`                      // 已通知次数
+	Silenced       bool       `json:"silenced" gorm:"column:silenced"`                             // 当前是否处于静默
+}
+
+// TableName 设置表名
+func (DockerAlertEvent) TableName() string {
+	return "docker_alert_events"
+}
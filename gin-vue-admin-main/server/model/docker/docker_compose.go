@@ -0,0 +1,393 @@
+package docker
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ComposeProject Docker Compose文件结构（仅包含面板支持的字段）
+type ComposeProject struct {
+	Version  string                     `yaml:"version,omitempty"`  // 版本（已废弃，仅作兼容）
+	Services map[string]*ComposeService `yaml:"services"`           // 服务
+	Networks map[string]*ComposeNetwork `yaml:"networks,omitempty"` // 网络
+	Volumes  map[string]*ComposeVolume  `yaml:"volumes,omitempty"`  // 存储卷
+}
+
+// ComposeService Compose服务定义
+type ComposeService struct {
+	Image         string                 `yaml:"image"`                    // 镜像
+	ContainerName string                 `yaml:"container_name,omitempty"` // 容器名称
+	Command       ComposeStringList      `yaml:"command,omitempty"`        // 启动命令
+	Entrypoint    ComposeStringList      `yaml:"entrypoint,omitempty"`     // 入口点
+	Environment   ComposeMapping         `yaml:"environment,omitempty"`    // 环境变量
+	Labels        ComposeMapping         `yaml:"labels,omitempty"`         // 标签
+	Ports         []ComposePort          `yaml:"ports,omitempty"`          // 端口映射
+	Volumes       []ComposeServiceVolume `yaml:"volumes,omitempty"`        // 卷挂载
+	Networks      ComposeServiceNetworks `yaml:"networks,omitempty"`       // 加入的网络
+	NetworkMode   string                 `yaml:"network_mode,omitempty"`   // 网络模式
+	DependsOn     ComposeDependsOn       `yaml:"depends_on,omitempty"`     // 依赖服务
+	Restart       string                 `yaml:"restart,omitempty"`        // 重启策略
+	WorkingDir    string                 `yaml:"working_dir,omitempty"`    // 工作目录
+	User          string                 `yaml:"user,omitempty"`           // 运行用户
+	Hostname      string                 `yaml:"hostname,omitempty"`       // 主机名
+	Privileged    bool                   `yaml:"privileged,omitempty"`     // 特权模式
+	Healthcheck   *ComposeHealthcheck    `yaml:"healthcheck,omitempty"`    // 健康检查
+}
+
+// ComposeNetwork Compose顶层网络定义
+type ComposeNetwork struct {
+	Name       string            `yaml:"name,omitempty"`        // 实际网络名称
+	Driver     string            `yaml:"driver,omitempty"`      // 网络驱动
+	DriverOpts map[string]string `yaml:"driver_opts,omitempty"` // 驱动选项
+	External   bool              `yaml:"external,omitempty"`    // 是否为外部网络
+	Internal   bool              `yaml:"internal,omitempty"`    // 是否为内部网络
+	Attachable bool              `yaml:"attachable,omitempty"`  // 是否可附加
+	Labels     ComposeMapping    `yaml:"labels,omitempty"`      // 标签
+}
+
+// ComposeVolume Compose顶层存储卷定义
+type ComposeVolume struct {
+	Name       string            `yaml:"name,omitempty"`        // 实际存储卷名称
+	Driver     string            `yaml:"driver,omitempty"`      // 存储卷驱动
+	DriverOpts map[string]string `yaml:"driver_opts,omitempty"` // 驱动选项
+	External   bool              `yaml:"external,omitempty"`    // 是否为外部存储卷
+	Labels     ComposeMapping    `yaml:"labels,omitempty"`      // 标签
+}
+
+// ComposeServiceNetwork 服务在某个网络中的配置
+type ComposeServiceNetwork struct {
+	Aliases     []string `yaml:"aliases,omitempty"`      // 网络别名
+	IPv4Address string   `yaml:"ipv4_address,omitempty"` // 固定IPv4地址
+	IPv6Address string   `yaml:"ipv6_address,omitempty"` // 固定IPv6地址
+}
+
+// ComposeHealthcheck 健康检查配置
+type ComposeHealthcheck struct {
+	Test        ComposeStringList `yaml:"test,omitempty"`         // 检查命令
+	Interval    string            `yaml:"interval,omitempty"`     // 检查间隔
+	Timeout     string            `yaml:"timeout,omitempty"`      // 超时时间
+	Retries     int               `yaml:"retries,omitempty"`      // 重试次数
+	StartPeriod string            `yaml:"start_period,omitempty"` // 启动等待时间
+	Disable     bool              `yaml:"disable,omitempty"`      // 禁用健康检查
+}
+
+// ComposeStringList 兼容字符串与字符串数组两种写法
+type ComposeStringList []string
+
+// UnmarshalYAML 解析字符串或字符串数组
+func (l *ComposeStringList) UnmarshalYAML(value *yaml.Node) error {
+	switch value.Kind {
+	case yaml.ScalarNode:
+		if value.Value == "" {
+			*l = nil
+			return nil
+		}
+		words, err := splitShellWords(value.Value)
+		if err != nil {
+			return fmt.Errorf("line %d: %v", value.Line, err)
+		}
+		*l = words
+		return nil
+	case yaml.SequenceNode:
+		var list []string
+		if err := value.Decode(&list); err != nil {
+			return err
+		}
+		*l = list
+		return nil
+	}
+	return fmt.Errorf("line %d: expected string or list", value.Line)
+}
+
+// ComposeMapping 兼容 map 与 "KEY=VALUE" 列表两种写法
+type ComposeMapping map[string]string
+
+// UnmarshalYAML 解析映射或 KEY=VALUE 列表
+func (m *ComposeMapping) UnmarshalYAML(value *yaml.Node) error {
+	result := make(map[string]string)
+	switch value.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(value.Content); i += 2 {
+			key := value.Content[i].Value
+			val := value.Content[i+1]
+			if val.Tag == "!!null" {
+				result[key] = ""
+				continue
+			}
+			if val.Kind != yaml.ScalarNode {
+				return fmt.Errorf("line %d: value of %s must be a scalar", val.Line, key)
+			}
+			result[key] = val.Value
+		}
+	case yaml.SequenceNode:
+		var list []string
+		if err := value.Decode(&list); err != nil {
+			return err
+		}
+		for _, item := range list {
+			key, val, _ := strings.Cut(item, "=")
+			result[key] = val
+		}
+	default:
+		return fmt.Errorf("line %d: expected mapping or list", value.Line)
+	}
+	*m = result
+	return nil
+}
+
+// ToList 转换为按键排序的 KEY=VALUE 列表
+func (m ComposeMapping) ToList() []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	list := make([]string, 0, len(keys))
+	for _, key := range keys {
+		list = append(list, key+"="+m[key])
+	}
+	return list
+}
+
+// ComposeDependsOn 兼容列表与带条件的映射两种写法
+type ComposeDependsOn []string
+
+// UnmarshalYAML 解析依赖服务
+func (d *ComposeDependsOn) UnmarshalYAML(value *yaml.Node) error {
+	switch value.Kind {
+	case yaml.SequenceNode:
+		var list []string
+		if err := value.Decode(&list); err != nil {
+			return err
+		}
+		*d = list
+		return nil
+	case yaml.MappingNode:
+		list := make([]string, 0, len(value.Content)/2)
+		for i := 0; i < len(value.Content); i += 2 {
+			list = append(list, value.Content[i].Value)
+		}
+		sort.Strings(list)
+		*d = list
+		return nil
+	}
+	return fmt.Errorf("line %d: depends_on must be a list or mapping", value.Line)
+}
+
+// ComposeServiceNetworks 服务网络，兼容列表与映射两种写法
+type ComposeServiceNetworks map[string]*ComposeServiceNetwork
+
+// UnmarshalYAML 解析服务网络
+func (n *ComposeServiceNetworks) UnmarshalYAML(value *yaml.Node) error {
+	result := make(map[string]*ComposeServiceNetwork)
+	switch value.Kind {
+	case yaml.SequenceNode:
+		var list []string
+		if err := value.Decode(&list); err != nil {
+			return err
+		}
+		for _, name := range list {
+			result[name] = nil
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(value.Content); i += 2 {
+			name := value.Content[i].Value
+			if value.Content[i+1].Tag == "!!null" {
+				result[name] = nil
+				continue
+			}
+			var cfg ComposeServiceNetwork
+			if err := value.Content[i+1].Decode(&cfg); err != nil {
+				return err
+			}
+			result[name] = &cfg
+		}
+	default:
+		return fmt.Errorf("line %d: networks must be a list or mapping", value.Line)
+	}
+	*n = result
+	return nil
+}
+
+// Names 返回排序后的网络名称
+func (n ComposeServiceNetworks) Names() []string {
+	names := make([]string, 0, len(n))
+	for name := range n {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ComposePort 端口映射，统一保存为短语法 [ip:]host:container[/protocol]
+type ComposePort string
+
+// UnmarshalYAML 解析端口短语法或长语法
+func (p *ComposePort) UnmarshalYAML(value *yaml.Node) error {
+	switch value.Kind {
+	case yaml.ScalarNode:
+		*p = ComposePort(value.Value)
+		return nil
+	case yaml.MappingNode:
+		var long struct {
+			Target    int    `yaml:"target"`
+			Published string `yaml:"published"`
+			Protocol  string `yaml:"protocol"`
+			HostIP    string `yaml:"host_ip"`
+		}
+		if err := value.Decode(&long); err != nil {
+			return err
+		}
+		if long.Target == 0 {
+			return fmt.Errorf("line %d: port target is required", value.Line)
+		}
+		spec := strconv.Itoa(long.Target)
+		if long.Published != "" {
+			spec = long.Published + ":" + spec
+			if long.HostIP != "" {
+				spec = long.HostIP + ":" + spec
+			}
+		}
+		if long.Protocol != "" {
+			spec += "/" + long.Protocol
+		}
+		*p = ComposePort(spec)
+		return nil
+	}
+	return fmt.Errorf("line %d: invalid port definition", value.Line)
+}
+
+// ComposeServiceVolume 服务卷挂载
+type ComposeServiceVolume struct {
+	Type     string // 挂载类型 (bind/volume)
+	Source   string // 源路径或存储卷名称，匿名卷为空
+	Target   string // 容器内路径
+	ReadOnly bool   // 是否只读
+}
+
+// UnmarshalYAML 解析卷挂载短语法或长语法
+func (v *ComposeServiceVolume) UnmarshalYAML(value *yaml.Node) error {
+	switch value.Kind {
+	case yaml.ScalarNode:
+		parsed, err := ParseComposeVolume(value.Value)
+		if err != nil {
+			return fmt.Errorf("line %d: %v", value.Line, err)
+		}
+		*v = parsed
+		return nil
+	case yaml.MappingNode:
+		var long struct {
+			Type     string `yaml:"type"`
+			Source   string `yaml:"source"`
+			Target   string `yaml:"target"`
+			ReadOnly bool   `yaml:"read_only"`
+		}
+		if err := value.Decode(&long); err != nil {
+			return err
+		}
+		if long.Target == "" {
+			return fmt.Errorf("line %d: volume target is required", value.Line)
+		}
+		if long.Type == "" {
+			long.Type = "volume"
+		}
+		if long.Type != "bind" && long.Type != "volume" {
+			return fmt.Errorf("line %d: unsupported volume type %q", value.Line, long.Type)
+		}
+		*v = ComposeServiceVolume{Type: long.Type, Source: long.Source, Target: long.Target, ReadOnly: long.ReadOnly}
+		return nil
+	}
+	return fmt.Errorf("line %d: invalid volume definition", value.Line)
+}
+
+// MarshalYAML 以短语法输出卷挂载
+func (v ComposeServiceVolume) MarshalYAML() (interface{}, error) {
+	spec := v.Target
+	if v.Source != "" {
+		spec = v.Source + ":" + v.Target
+	}
+	if v.ReadOnly {
+		spec += ":ro"
+	}
+	return spec, nil
+}
+
+// ParseComposeVolume 解析卷挂载短语法 [source:]target[:mode]
+func ParseComposeVolume(spec string) (ComposeServiceVolume, error) {
+	parts := strings.Split(spec, ":")
+	var volume ComposeServiceVolume
+	switch len(parts) {
+	case 1:
+		volume.Target = parts[0]
+	case 2:
+		if strings.HasPrefix(parts[1], "/") {
+			volume.Source, volume.Target = parts[0], parts[1]
+		} else {
+			volume.Target = parts[0]
+			volume.ReadOnly = parts[1] == "ro"
+		}
+	case 3:
+		volume.Source, volume.Target = parts[0], parts[1]
+		volume.ReadOnly = strings.Contains(","+parts[2]+",", ",ro,")
+	default:
+		return volume, fmt.Errorf("invalid volume spec %q", spec)
+	}
+	if volume.Target == "" || !strings.HasPrefix(volume.Target, "/") {
+		return volume, fmt.Errorf("volume target must be an absolute path in %q", spec)
+	}
+	volume.Type = "volume"
+	if strings.HasPrefix(volume.Source, "/") || strings.HasPrefix(volume.Source, ".") || strings.HasPrefix(volume.Source, "~") {
+		volume.Type = "bind"
+	}
+	return volume, nil
+}
+
+// splitShellWords 按shell规则拆分命令字符串，支持单双引号与反斜杠转义
+func splitShellWords(input string) ([]string, error) {
+	var (
+		words   []string
+		current strings.Builder
+		inWord  bool
+		quote   rune
+		escaped bool
+	)
+	for _, r := range input {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+			inWord = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inWord {
+				words = append(words, current.String())
+				current.Reset()
+				inWord = false
+			}
+		default:
+			current.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 || escaped {
+		return nil, fmt.Errorf("unterminated quote or escape in %q", input)
+	}
+	if inWord {
+		words = append(words, current.String())
+	}
+	return words, nil
+}
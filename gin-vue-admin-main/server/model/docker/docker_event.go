@@ -0,0 +1,21 @@
+package docker
+
+import "time"
+
+// DockerEvent 守护进程事件，记录各主机上容器、镜像、网络与存储卷的变化，按保留天数清理
+type DockerEvent struct {
+	ID         uint              `json:"id" gorm:"primarykey"`                                                                                         // 主键ID
+	HostID     uint              `json:"hostId" gorm:"column:host_id;not null;default:0;uniqueIndex:idx_docker_event_host_unique,priority:1"`          // Docker主机ID，0为默认主机
+	Time       time.Time         `json:"time" gorm:"column:time;not null;index"`                                                                       // 事件时间
+	TimeNano   int64             `json:"-" gorm:"column:time_nano;not null;uniqueIndex:idx_docker_event_host_unique,priority:2"`                       // 事件时间（纳秒），与主机、类型、动作、对象共同去重
+	Type       string            `json:"type" gorm:"column:type;type:varchar(20);not null;index;uniqueIndex:idx_docker_event_host_unique,priority:3"`  // 对象类型 (container/image/network/volume)
+	Action     string            `json:"action" gorm:"column:action;type:varchar(100);not null;uniqueIndex:idx_docker_event_host_unique,priority:4"`   // 动作，如 create、die、oom、health_status: unhealthy、pull、destroy
+	ObjectID   string            `json:"objectId" gorm:"column:object_id;type:varchar(255);index;uniqueIndex:idx_docker_event_host_unique,priority:5"` // 对象ID，镜像为镜像名称，存储卷为卷名
+	ObjectName string            `json:"objectName" gorm:"column:object_name;type:varchar(255);index"`                                                 // 对象名称
+	Attributes map[string]string `json:"attributes" gorm:"column:attributes;type:text;serializer:json"`                                                // 事件属性，如 exitCode、image、container
+}
+
+// TableName 设置表名
+func (DockerEvent) TableName() string {
+	return "docker_events"
+}
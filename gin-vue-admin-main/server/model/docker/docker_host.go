@@ -0,0 +1,38 @@
+package docker
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DefaultDockerHostName 配置文件中 docker.host 对应的默认主机名称，主机ID为0
+const DefaultDockerHostName = "local"
+
+// DockerHost 受管的Docker主机，接口通过 hostId 参数或 X-Docker-Host 请求头选择，未指定时使用配置文件中的默认主机
+type DockerHost struct {
+	ID          uint           `json:"id" gorm:"primarykey"`                                           // 主键ID
+	CreatedAt   time.Time      `json:"createdAt"`                                                      // 创建时间
+	UpdatedAt   time.Time      `json:"updatedAt"`                                                      // 更新时间
+	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`                                                 // 删除时间
+	Name        string         `json:"name" gorm:"column:name;type:varchar(100);not null;uniqueIndex"` // 主机名称
+	Endpoint    string         `json:"endpoint" gorm:"column:endpoint;type:varchar(500);not null"`     // 端点：unix:///var/run/docker.sock、tcp://host:2376、ssh://user@host:22
+	Version     string         `json:"version" gorm:"column:version;type:varchar(20)"`                 // API版本，为空时自动协商
+	Timeout     int            `json:"timeout" gorm:"column:timeout"`                                  // 请求超时（秒），0使用默认值30
+	TLSVerify   bool           `json:"tlsVerify" gorm:"column:tls_verify"`                             // tcp 端点是否校验服务端证书
+	CertPath    string         `json:"certPath" gorm:"column:cert_path;type:varchar(500)"`             // tcp 端点的证书目录，包含 ca.pem、cert.pem、key.pem
+	SSHKey      string         `json:"-" gorm:"column:ssh_key;type:text"`                              // ssh 私钥（AES加密存储）
+	SSHPassword string         `json:"-" gorm:"column:ssh_password;type:varchar(500)"`                 // ssh 密码（AES加密存储）
+	SSHHostKey  string         `json:"sshHostKey" gorm:"column:ssh_host_key;type:varchar(100)"`        // ssh 主机公钥指纹，首次连接时记录
+	Enabled     bool           `json:"enabled" gorm:"column:enabled"`                                  // 是否启用
+	Description string         `json:"description" gorm:"column:description;type:varchar(500)"`        // 描述
+	LastCheckAt *time.Time     `json:"lastCheckAt" gorm:"column:last_check_at"`                        // 最近一次连接测试时间
+	LastStatus  string         `json:"lastStatus" gorm:"column:last_status;type:varchar(500)"`         // 最近一次连接测试结果
+	HasSSHKey   bool           `json:"hasSshKey" gorm:"-"`                                             // 是否已保存 ssh 私钥
+	HasPassword bool           `json:"hasPassword" gorm:"-"`                                           // 是否已保存 ssh 密码
+}
+
+// TableName 设置表名
+func (DockerHost) TableName() string {
+	return "docker_hosts"
+}
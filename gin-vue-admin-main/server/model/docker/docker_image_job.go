@@ -0,0 +1,47 @@
+package docker
+
+import "time"
+
+// 镜像任务类型
+const (
+	ImageJobTypePull  = "pull"  // 拉取镜像
+	ImageJobTypeBuild = "build" // 构建镜像
+)
+
+// 镜像任务状态
+const (
+	ImageJobStatusPending   = "pending"   // 排队中
+	ImageJobStatusRunning   = "running"   // 执行中
+	ImageJobStatusSuccess   = "success"   // 已完成
+	ImageJobStatusFailed    = "failed"    // 失败
+	ImageJobStatusCancelled = "cancelled" // 已取消
+)
+
+// DockerImageJob 后台镜像拉取/构建任务，执行结束后保留日志供查看
+type DockerImageJob struct {
+	ID         uint       `json:"id" gorm:"primarykey"`                               // 主键ID
+	CreatedAt  time.Time  `json:"createdAt"`                                          // 提交时间
+	UpdatedAt  time.Time  `json:"updatedAt"`                                          // 更新时间
+	Type       string     `json:"type" gorm:"column:type;type:varchar(20);index"`     // 任务类型 (pull/build)
+	Image      string     `json:"image" gorm:"column:image;type:varchar(500)"`        // 镜像名称
+	Status     string     `json:"status" gorm:"column:status;type:varchar(20);index"` // 任务状态
+	Progress   float64    `json:"progress" gorm:"column:progress"`                    // 进度（%）
+	Message    string     `json:"message" gorm:"column:message;type:varchar(500)"`    // 当前阶段
+	Error      string     `json:"error" gorm:"column:error;type:text"`                // 失败原因
+	ImageID    string     `json:"imageId" gorm:"column:image_id;type:varchar(100)"`   // 完成后的镜像ID
+	Log        string     `json:"log,omitempty" gorm:"column:log;type:text"`          // 任务日志，超长时保留末尾部分
+	HostID     uint       `json:"hostId" gorm:"column:host_id"`                       // 执行任务的Docker主机ID，0为默认主机
+	CreatedBy  uint       `json:"createdBy" gorm:"column:created_by"`                 // 提交人ID
+	StartedAt  *time.Time `json:"startedAt" gorm:"column:started_at"`                 // 开始执行时间
+	FinishedAt *time.Time `json:"finishedAt" gorm:"column:finished_at;index"`         // 结束时间
+}
+
+// TableName 设置表名
+func (DockerImageJob) TableName() string {
+	return "docker_image_jobs"
+}
+
+// Finished 任务是否已结束
+func (j DockerImageJob) Finished() bool {
+	return j.Status == ImageJobStatusSuccess || j.Status == ImageJobStatusFailed || j.Status == ImageJobStatusCancelled
+}
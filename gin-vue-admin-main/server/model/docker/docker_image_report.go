@@ -0,0 +1,140 @@
+package docker
+
+import "time"
+
+// 镜像检查报告状态
+const (
+	ImageReportStatusRunning = "running" // 检查中
+	ImageReportStatusSuccess = "success" // 已完成
+	ImageReportStatusFailed  = "failed"  // 失败
+)
+
+// 软件包类型，同时作为漏洞库的生态标识
+const (
+	PackageTypeDeb      = "deb"      // Debian/Ubuntu dpkg
+	PackageTypeApk      = "apk"      // Alpine apk
+	PackageTypeRpm      = "rpm"      // RHEL/CentOS/Fedora rpm
+	PackageTypeNpm      = "npm"      // Node.js
+	PackageTypePypi     = "pypi"     // Python
+	PackageTypeGo       = "go"       // Go modules
+	PackageTypeCargo    = "cargo"    // Rust
+	PackageTypeGem      = "gem"      // Ruby
+	PackageTypeComposer = "composer" // PHP
+)
+
+// 漏洞严重程度
+const (
+	SeverityCritical = "critical"
+	SeverityHigh     = "high"
+	SeverityMedium   = "medium"
+	SeverityLow      = "low"
+	SeverityUnknown  = "unknown"
+)
+
+// DockerImageReport 镜像内容检查报告，保存软件包清单、漏洞匹配结果与特权文件，供后续对比
+type DockerImageReport struct {
+	ID            uint                       `json:"id" gorm:"primarykey"`                                      // 主键ID
+	CreatedAt     time.Time                  `json:"createdAt"`                                                 // 检查时间
+	UpdatedAt     time.Time                  `json:"updatedAt"`                                                 // 更新时间
+	ImageID       string                     `json:"imageId" gorm:"column:image_id;type:varchar(100);index"`    // 镜像ID
+	ImageRef      string                     `json:"imageRef" gorm:"column:image_ref;type:varchar(500)"`        // 检查时使用的镜像名称
+	Status        string                     `json:"status" gorm:"column:status;type:varchar(20)"`              // 检查状态
+	Error         string                     `json:"error" gorm:"column:error;type:text"`                       // 失败原因
+	OS            string                     `json:"os" gorm:"column:os;type:varchar(200)"`                     // 操作系统（os-release）
+	OSID          string                     `json:"osId" gorm:"column:os_id;type:varchar(50)"`                 // 操作系统标识，如 debian/alpine
+	OSVersion     string                     `json:"osVersion" gorm:"column:os_version;type:varchar(50)"`       // 操作系统版本号
+	LayerCount    int                        `json:"layerCount" gorm:"column:layer_count"`                      // 镜像层数
+	Size          int64                      `json:"size" gorm:"column:size"`                                   // 镜像大小
+	PackageCount  int                        `json:"packageCount" gorm:"column:package_count"`                  // 软件包数量
+	VulnCount     int                        `json:"vulnCount" gorm:"column:vuln_count"`                        // 漏洞数量
+	CriticalCount int                        `json:"criticalCount" gorm:"column:critical_count"`                // 严重漏洞数量
+	HighCount     int                        `json:"highCount" gorm:"column:high_count"`                        // 高危漏洞数量
+	MediumCount   int                        `json:"mediumCount" gorm:"column:medium_count"`                    // 中危漏洞数量
+	LowCount      int                        `json:"lowCount" gorm:"column:low_count"`                          // 低危漏洞数量
+	SetuidCount   int                        `json:"setuidCount" gorm:"column:setuid_count"`                    // setuid/setgid 文件数量
+	Warnings      []string                   `json:"warnings" gorm:"column:warnings;type:text;serializer:json"` // 检查过程中的提示，如不支持的软件包数据库
+	MatchedAt     *time.Time                 `json:"matchedAt" gorm:"column:matched_at"`                        // 最近一次漏洞匹配时间
+	HostID        uint                       `json:"hostId" gorm:"column:host_id"`                              // 镜像所在的Docker主机ID，0为默认主机
+	CreatedBy     uint                       `json:"createdBy" gorm:"column:created_by"`                        // 发起人ID
+	Packages      []DockerImageReportPackage `json:"packages,omitempty" gorm:"foreignKey:ReportID"`             // 软件包清单
+	Findings      []DockerImageReportFinding `json:"findings,omitempty" gorm:"foreignKey:ReportID"`             // 漏洞匹配结果
+	Files         []DockerImageReportFile    `json:"files,omitempty" gorm:"foreignKey:ReportID"`                // setuid/setgid 文件
+}
+
+// TableName 设置表名
+func (DockerImageReport) TableName() string {
+	return "docker_image_reports"
+}
+
+// DockerImageReportPackage 报告中的软件包
+type DockerImageReportPackage struct {
+	ID       uint   `json:"id" gorm:"primarykey"`                            // 主键ID
+	ReportID uint   `json:"reportId" gorm:"column:report_id;index"`          // 报告ID
+	Type     string `json:"type" gorm:"column:type;type:varchar(20)"`        // 软件包类型
+	Name     string `json:"name" gorm:"column:name;type:varchar(255)"`       // 包名
+	Version  string `json:"version" gorm:"column:version;type:varchar(255)"` // 版本
+	Source   string `json:"source" gorm:"column:source;type:varchar(255)"`   // 源码包名，系统包用于匹配发行版公告
+	Path     string `json:"path" gorm:"column:path;type:varchar(1000)"`      // 来源文件，如 var/lib/dpkg/status 或锁文件路径
+}
+
+// TableName 设置表名
+func (DockerImageReportPackage) TableName() string {
+	return "docker_image_report_packages"
+}
+
+// DockerImageReportFinding 报告中的漏洞匹配结果
+type DockerImageReportFinding struct {
+	ID           uint   `json:"id" gorm:"primarykey"`                                       // 主键ID
+	ReportID     uint   `json:"reportId" gorm:"column:report_id;index"`                     // 报告ID
+	VulnID       string `json:"vulnId" gorm:"column:vuln_id;type:varchar(100)"`             // 漏洞编号
+	PackageType  string `json:"packageType" gorm:"column:package_type;type:varchar(20)"`    // 软件包类型
+	Package      string `json:"package" gorm:"column:package;type:varchar(255)"`            // 包名
+	Version      string `json:"version" gorm:"column:version;type:varchar(255)"`            // 已安装版本
+	FixedVersion string `json:"fixedVersion" gorm:"column:fixed_version;type:varchar(255)"` // 修复版本，为空表示暂无修复
+	Severity     string `json:"severity" gorm:"column:severity;type:varchar(20)"`           // 严重程度
+	Summary      string `json:"summary" gorm:"column:summary;type:varchar(1000)"`           // 漏洞摘要
+	Path         string `json:"path" gorm:"column:path;type:varchar(1000)"`                 // 软件包来源文件
+}
+
+// TableName 设置表名
+func (DockerImageReportFinding) TableName() string {
+	return "docker_image_report_findings"
+}
+
+// DockerImageReportFile 报告中设置了 setuid/setgid 位的文件
+type DockerImageReportFile struct {
+	ID       uint   `json:"id" gorm:"primarykey"`                       // 主键ID
+	ReportID uint   `json:"reportId" gorm:"column:report_id;index"`     // 报告ID
+	Path     string `json:"path" gorm:"column:path;type:varchar(1000)"` // 文件路径
+	Mode     string `json:"mode" gorm:"column:mode;type:varchar(20)"`   // 权限，如 -rwsr-xr-x
+	Uid      int    `json:"uid" gorm:"column:uid"`                      // 属主
+	Gid      int    `json:"gid" gorm:"column:gid"`                      // 属组
+	Size     int64  `json:"size" gorm:"column:size"`                    // 文件大小
+}
+
+// TableName 设置表名
+func (DockerImageReportFile) TableName() string {
+	return "docker_image_report_files"
+}
+
+// DockerVulnerability 离线漏洞库条目，每条记录对应一个漏洞在一个软件包上的一个受影响版本区间
+type DockerVulnerability struct {
+	ID           uint      `json:"id" gorm:"primarykey"`                                                         // 主键ID
+	CreatedAt    time.Time `json:"createdAt"`                                                                    // 导入时间
+	Source       string    `json:"source" gorm:"column:source;type:varchar(100);index"`                          // 数据来源名称，重新导入同名来源时整体替换
+	VulnID       string    `json:"vulnId" gorm:"column:vuln_id;type:varchar(100)"`                               // 漏洞编号
+	Ecosystem    string    `json:"ecosystem" gorm:"column:ecosystem;type:varchar(20);index:idx_docker_vuln_pkg"` // 生态，与软件包类型一致
+	Distro       string    `json:"distro" gorm:"column:distro;type:varchar(50)"`                                 // 发行版及版本，如 debian:12、alpine:3.18，仅系统包使用，为空表示不限
+	Package      string    `json:"package" gorm:"column:package;type:varchar(255);index:idx_docker_vuln_pkg"`    // 包名
+	Introduced   string    `json:"introduced" gorm:"column:introduced;type:varchar(255)"`                        // 引入版本（含），为空表示最早版本
+	Fixed        string    `json:"fixed" gorm:"column:fixed;type:varchar(255)"`                                  // 修复版本（不含），为空表示尚未修复
+	LastAffected string    `json:"lastAffected" gorm:"column:last_affected;type:varchar(255)"`                   // 最后受影响版本（含），与修复版本二选一
+	Versions     []string  `json:"versions" gorm:"column:versions;type:text;serializer:json"`                    // 明确列出的受影响版本
+	Severity     string    `json:"severity" gorm:"column:severity;type:varchar(20)"`                             // 严重程度
+	Summary      string    `json:"summary" gorm:"column:summary;type:varchar(1000)"`                             // 漏洞摘要
+}
+
+// TableName 设置表名
+func (DockerVulnerability) TableName() string {
+	return "docker_vulnerabilities"
+}
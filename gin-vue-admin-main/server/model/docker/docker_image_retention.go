@@ -0,0 +1,69 @@
+package docker
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 镜像保留策略执行方式
+const (
+	RetentionTriggerManual   = "manual"   // 手动执行
+	RetentionTriggerSchedule = "schedule" // 定时执行
+)
+
+// 镜像保留策略执行结果
+const (
+	RetentionRunSuccess = "success" // 全部删除成功
+	RetentionRunPartial = "partial" // 部分标签删除失败
+	RetentionRunFailed  = "failed"  // 执行失败
+)
+
+// DockerImageRetentionPolicy 镜像保留策略，按仓库保留最近的标签，其余超过保留期的标签定时清理；
+// 被容器（含已停止容器）使用的镜像始终保留
+type DockerImageRetentionPolicy struct {
+	ID            uint           `json:"id" gorm:"primarykey"`                                           // 主键ID
+	CreatedAt     time.Time      `json:"createdAt"`                                                      // 创建时间
+	UpdatedAt     time.Time      `json:"updatedAt"`                                                      // 更新时间
+	DeletedAt     gorm.DeletedAt `json:"-" gorm:"index"`                                                 // 删除时间
+	Name          string         `json:"name" gorm:"column:name;type:varchar(100);not null"`             // 策略名称
+	Repository    string         `json:"repository" gorm:"column:repository;type:varchar(255);not null"` // 仓库名称，支持通配符，如 registry.local/app/*
+	KeepLast      int            `json:"keepLast" gorm:"column:keep_last"`                               // 每个仓库保留最近创建的标签数，0为不按数量保留
+	KeepPattern   string         `json:"keepPattern" gorm:"column:keep_pattern;type:varchar(255)"`       // 标签匹配该正则时始终保留，如 ^v\d+\.\d+\.\d+$
+	OlderThanDays int            `json:"olderThanDays" gorm:"column:older_than_days"`                    // 只删除创建超过该天数的镜像，0为不限
+	Schedule      string         `json:"schedule" gorm:"column:schedule;type:varchar(100)"`              // 执行周期（cron表达式，分 时 日 月 周），为空时只能手动执行
+	Enabled       bool           `json:"enabled" gorm:"column:enabled"`                                  // 是否启用定时执行
+	Description   string         `json:"description" gorm:"column:description;type:varchar(500)"`        // 描述
+	LastRunAt     *time.Time     `json:"lastRunAt" gorm:"column:last_run_at"`                            // 最近一次执行时间
+	LastRunStatus string         `json:"lastRunStatus" gorm:"column:last_run_status;type:varchar(20)"`   // 最近一次执行结果
+	HostID        uint           `json:"hostId" gorm:"column:host_id"`                                   // 策略所在的Docker主机ID，创建时确定，0为默认主机
+	CreatedBy     uint           `json:"createdBy" gorm:"column:created_by"`                             // 创建人ID
+}
+
+// TableName 设置表名
+func (DockerImageRetentionPolicy) TableName() string {
+	return "docker_image_retention_policies"
+}
+
+// DockerImageRetentionRun 镜像保留策略执行记录
+type DockerImageRetentionRun struct {
+	ID             uint      `json:"id" gorm:"primarykey"`                                    // 主键ID
+	CreatedAt      time.Time `json:"createdAt"`                                               // 开始时间
+	PolicyID       uint      `json:"policyId" gorm:"column:policy_id;index"`                  // 策略ID
+	PolicyName     string    `json:"policyName" gorm:"column:policy_name;type:varchar(100)"`  // 执行时的策略名称
+	Trigger        string    `json:"trigger" gorm:"column:trigger_type;type:varchar(20)"`     // 执行方式 (manual/schedule)
+	Status         string    `json:"status" gorm:"column:status;type:varchar(20)"`            // 执行结果 (success/partial/failed)
+	RemovedTags    int       `json:"removedTags" gorm:"column:removed_tags"`                  // 删除的标签数
+	RemovedImages  int       `json:"removedImages" gorm:"column:removed_images"`              // 随之删除的镜像数
+	ProtectedTags  int       `json:"protectedTags" gorm:"column:protected_tags"`              // 因被容器使用而保留的标签数
+	ReclaimedBytes int64     `json:"reclaimedBytes" gorm:"column:reclaimed_bytes"`            // 按磁盘占用估算的释放空间（字节）
+	Removed        []string  `json:"removed" gorm:"column:removed;type:text;serializer:json"` // 删除的标签
+	Errors         []string  `json:"errors" gorm:"column:errors;type:text;serializer:json"`   // 删除失败的标签及原因
+	Duration       int64     `json:"duration" gorm:"column:duration"`                         // 耗时（毫秒）
+	CreatedBy      uint      `json:"createdBy" gorm:"column:created_by"`                      // 手动执行人ID，定时执行为0
+}
+
+// TableName 设置表名
+func (DockerImageRetentionRun) TableName() string {
+	return "docker_image_retention_runs"
+}
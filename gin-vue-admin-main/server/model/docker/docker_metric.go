@@ -0,0 +1,50 @@
+package docker
+
+import "time"
+
+// 指标对象类型
+const (
+	MetricTargetHost      = "host"      // 主机
+	MetricTargetDisk      = "disk"      // 磁盘挂载点
+	MetricTargetContainer = "container" // 容器
+)
+
+// 指标数据精度（秒），原始采样为0
+const (
+	MetricResolutionRaw    = 0
+	MetricResolutionMinute = 300
+	MetricResolutionHour   = 3600
+)
+
+// DockerMetric 主机与容器资源使用的时序数据
+// 原始采样按采集间隔写入，之后逐级聚合为5分钟、1小时精度；聚合数据取区间平均值，累计计数取区间末值
+type DockerMetric struct {
+	ID               uint      `json:"id" gorm:"primarykey"`                                                                                    // 主键ID
+	SampledAt        time.Time `json:"sampledAt" gorm:"column:sampled_at;not null;index:idx_docker_metric_query,priority:4"`                    // 采样时间，聚合数据为区间起点
+	Resolution       int       `json:"resolution" gorm:"column:resolution;not null;index:idx_docker_metric_query,priority:1"`                   // 精度（秒），0为原始数据
+	TargetType       string    `json:"targetType" gorm:"column:target_type;type:varchar(20);not null;index:idx_docker_metric_query,priority:2"` // 对象类型 (host/disk/container)
+	TargetID         string    `json:"targetId" gorm:"column:target_id;type:varchar(255);not null;index:idx_docker_metric_query,priority:3"`    // 对象标识：主机为host，磁盘为挂载点，容器为容器ID
+	TargetName       string    `json:"targetName" gorm:"column:target_name;type:varchar(255);index"`                                            // 对象名称，容器为容器名
+	CPUPercent       float64   `json:"cpuPercent" gorm:"column:cpu_percent"`                                                                    // CPU使用率（%）
+	CPUPercentMax    float64   `json:"cpuPercentMax" gorm:"column:cpu_percent_max"`                                                             // 区间内CPU使用率峰值（%）
+	MemoryUsage      uint64    `json:"memoryUsage" gorm:"column:memory_usage"`                                                                  // 内存使用量（字节）
+	MemoryTotal      uint64    `json:"memoryTotal" gorm:"column:memory_total"`                                                                  // 内存总量或限制（字节）
+	MemoryPercent    float64   `json:"memoryPercent" gorm:"column:memory_percent"`                                                              // 内存使用率（%）
+	MemoryPercentMax float64   `json:"memoryPercentMax" gorm:"column:memory_percent_max"`                                                       // 区间内内存使用率峰值（%）
+	DiskUsed         uint64    `json:"diskUsed" gorm:"column:disk_used"`                                                                        // 磁盘已用（字节）
+	DiskTotal        uint64    `json:"diskTotal" gorm:"column:disk_total"`                                                                      // 磁盘总量（字节）
+	DiskPercent      float64   `json:"diskPercent" gorm:"column:disk_percent"`                                                                  // 磁盘使用率（%）
+	Load1            float64   `json:"load1" gorm:"column:load1"`                                                                               // 1分钟平均负载
+	Load5            float64   `json:"load5" gorm:"column:load5"`                                                                               // 5分钟平均负载
+	Load15           float64   `json:"load15" gorm:"column:load15"`                                                                             // 15分钟平均负载
+	NetworkRx        uint64    `json:"networkRx" gorm:"column:network_rx"`                                                                      // 网络累计接收（字节）
+	NetworkTx        uint64    `json:"networkTx" gorm:"column:network_tx"`                                                                      // 网络累计发送（字节）
+	BlockRead        uint64    `json:"blockRead" gorm:"column:block_read"`                                                                      // 块设备累计读取（字节）
+	BlockWrite       uint64    `json:"blockWrite" gorm:"column:block_write"`                                                                    // 块设备累计写入（字节）
+	PIDs             uint64    `json:"pids" gorm:"column:pids"`                                                                                 // 进程数
+}
+
+// TableName 设置表名
+func (DockerMetric) TableName() string {
+	return "docker_metrics"
+}
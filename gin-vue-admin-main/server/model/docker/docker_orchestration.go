@@ -11,7 +11,8 @@ type DockerOrchestration struct {
 	CreatedAt        time.Time      `json:"createdAt"`                                                               // 创建时间
 	UpdatedAt        time.Time      `json:"updatedAt"`                                                               // 更新时间
 	DeletedAt        gorm.DeletedAt `json:"-" gorm:"index"`                                                          // 删除时间
-	Name             string         `json:"name" gorm:"column:name;type:varchar(100);not null;uniqueIndex"`         // 编排名称
+	HostID           uint           `json:"hostId" gorm:"column:host_id;not null;default:0;uniqueIndex:idx_docker_orchestration_host_name,priority:1"` // 所在Docker主机ID，0为默认主机
+	Name             string         `json:"name" gorm:"column:name;type:varchar(100);not null;uniqueIndex:idx_docker_orchestration_host_name,priority:2"` // 编排名称，同一主机内唯一
 	Description      string         `json:"description" gorm:"column:description;type:text"`                        // 描述
 	ComposeContent   string         `json:"composeContent" gorm:"column:compose_content;type:longtext"`             // Docker Compose内容
 	Status           string         `json:"status" gorm:"column:status;type:varchar(20);not null;default:'stopped'"` // 状态 (running/stopped/error)
@@ -16,7 +16,7 @@ type DockerRegistry struct {
 	Protocol    string         `json:"protocol" gorm:"column:protocol;type:varchar(10);not null;default:'https'"` // 协议
 	Status      string         `json:"status" gorm:"column:status;type:varchar(20);not null;default:'active'"` // 状态
 	Username    string         `json:"username" gorm:"column:username;type:varchar(100)"`       // 用户名
-	Password    string         `json:"password" gorm:"column:password;type:varchar(200)"`       // 密码（加密存储）
+	Password    string         `json:"-" gorm:"column:password;type:varchar(500)"`              // 密码（AES加密存储）
 	Description string         `json:"description" gorm:"column:description;type:text"`         // 描述
 	IsDefault   bool           `json:"isDefault" gorm:"column:is_default;default:false"`        // 是否为默认仓库
 	LastTestTime *time.Time    `json:"lastTestTime" gorm:"column:last_test_time"`               // 最后测试时间
@@ -0,0 +1,43 @@
+package docker
+
+import "time"
+
+// 存储卷备份的存储位置
+const (
+	VolumeBackupStorageLocal = "local" // 保存在服务器的 docker.backup-path 目录
+	VolumeBackupStorageOSS   = "oss"   // 上传到 system.oss-type 配置的对象存储
+)
+
+// 存储卷备份的读取方式
+const (
+	VolumeBackupMethodContainer  = "container"  // 通过临时辅助容器挂载存储卷读取
+	VolumeBackupMethodMountpoint = "mountpoint" // 直接读取本机的存储卷挂载点
+)
+
+// DockerVolumeBackup 存储卷备份记录，备份文件为 gzip 压缩的 tar 包，包内路径相对于存储卷根目录
+type DockerVolumeBackup struct {
+	ID         uint              `json:"id" gorm:"primarykey"`                                         // 主键ID
+	CreatedAt  time.Time         `json:"createdAt"`                                                    // 备份时间
+	HostID     uint              `json:"hostId" gorm:"column:host_id"`                                 // 来源Docker主机ID，0为默认主机
+	VolumeName string            `json:"volumeName" gorm:"column:volume_name;type:varchar(255);index"` // 存储卷名称
+	Driver     string            `json:"driver" gorm:"column:driver;type:varchar(100)"`                // 存储卷驱动，恢复时重建存储卷使用
+	Labels     map[string]string `json:"labels" gorm:"column:labels;type:text;serializer:json"`        // 存储卷标签，恢复时重建存储卷使用
+	Method     string            `json:"method" gorm:"column:method;type:varchar(20)"`                 // 读取方式 (container/mountpoint)
+	Storage    string            `json:"storage" gorm:"column:storage;type:varchar(20)"`               // 存储位置 (local/oss)
+	OssType    string            `json:"ossType" gorm:"column:oss_type;type:varchar(50)"`              // 备份时使用的对象存储类型
+	FileName   string            `json:"fileName" gorm:"column:file_name;type:varchar(255)"`           // 备份文件名
+	Path       string            `json:"-" gorm:"column:path;type:varchar(500)"`                       // 本地备份文件路径
+	Url        string            `json:"url" gorm:"column:url;type:varchar(500)"`                      // 对象存储访问地址
+	Key        string            `json:"-" gorm:"column:key;type:varchar(255)"`                        // 对象存储文件key
+	Size       int64             `json:"size" gorm:"column:size"`                                      // 备份文件大小（字节）
+	Checksum   string            `json:"checksum" gorm:"column:checksum;type:varchar(64)"`             // 备份文件的 SHA-256
+	Quiesced   []string          `json:"quiesced" gorm:"column:quiesced;type:text;serializer:json"`    // 备份期间停止的容器
+	Duration   int64             `json:"duration" gorm:"column:duration"`                              // 耗时（毫秒）
+	Remark     string            `json:"remark" gorm:"column:remark;type:varchar(255)"`                // 备注
+	CreatedBy  uint              `json:"createdBy" gorm:"column:created_by"`                           // 创建人ID
+}
+
+// TableName 设置表名
+func (DockerVolumeBackup) TableName() string {
+	return "docker_volume_backups"
+}
@@ -0,0 +1,46 @@
+package request
+
+import "time"
+
+// AlertRuleRequest 创建/更新告警规则请求
+type AlertRuleRequest struct {
+	ID             uint    `json:"id"`                      // 规则ID，更新时必填
+	Name           string  `json:"name" binding:"required"` // 规则名称
+	Type           string  `json:"type" binding:"required"` // 规则类型
+	Target         string  `json:"target"`                  // 匹配对象，为空匹配全部
+	Threshold      float64 `json:"threshold"`               // 阈值：使用率（%），重启规则为重启次数（默认3）
+	Duration       int     `json:"duration"`                // 持续时长（分钟）；重启规则为统计窗口（默认10）
+	RepeatInterval int     `json:"repeatInterval"`          // 重复通知间隔（分钟），0为只通知一次
+	Severity       string  `json:"severity"`                // 级别 (info/warning/critical)，默认warning
+	Enabled        bool    `json:"enabled"`                 // 是否启用
+	ChannelIDs     []uint  `json:"channelIds"`              // 通知渠道ID
+	Description    string  `json:"description"`             // 描述
+}
+
+// AlertChannelRequest 创建/更新告警通知渠道请求
+type AlertChannelRequest struct {
+	ID      uint   `json:"id"`                        // 渠道ID，更新时必填
+	Name    string `json:"name" binding:"required"`   // 渠道名称
+	Type    string `json:"type" binding:"required"`   // 渠道类型 (email/dingtalk/feishu/slack/webhook)
+	Target  string `json:"target" binding:"required"` // 邮件为收件人（逗号分隔），其余为Webhook地址
+	Secret  string `json:"secret"`                    // 钉钉/飞书加签密钥，更新时为空则保持不变
+	Enabled bool   `json:"enabled"`                   // 是否启用
+}
+
+// AlertSilenceRequest 创建静默窗口请求
+type AlertSilenceRequest struct {
+	RuleID   uint      `json:"ruleId"`                    // 规则ID，0为所有规则
+	Target   string    `json:"target"`                    // 匹配对象，为空匹配全部
+	StartsAt time.Time `json:"startsAt"`                  // 开始时间，为空则立即开始
+	EndsAt   time.Time `json:"endsAt" binding:"required"` // 结束时间
+	Comment  string    `json:"comment"`                   // 备注
+}
+
+// AlertEventFilter 告警事件查询
+type AlertEventFilter struct {
+	Page     int    `form:"page" json:"page"`         // 页码
+	PageSize int    `form:"pageSize" json:"pageSize"` // 每页大小
+	Status   string `form:"status" json:"status"`     // 状态过滤 (pending/firing/resolved)
+	RuleID   uint   `form:"ruleId" json:"ruleId"`     // 规则ID过滤
+	Object   string `form:"object" json:"object"`     // 对象名称或ID过滤
+}
@@ -13,5 +13,22 @@ type ContainerFilter struct {
 type LogOptions struct {
 	Tail   string `json:"tail" form:"tail"`     // 显示最后N行日志
 	Since  string `json:"since" form:"since"`   // 显示指定时间之后的日志
-	Follow bool   `json:"follow" form:"follow"` // 是否跟踪日志流
-}
\ No newline at end of file
+	Follow bool   `json:"follow" form:"follow"` // 已废弃，跟踪日志请使用 logs/stream 接口
+}
+// LogStreamOptions 容器实时日志选项
+type LogStreamOptions struct {
+	Tail       string `json:"tail" form:"tail"`             // 先输出最后N行日志，默认100，all为全部
+	Since      string `json:"since" form:"since"`           // 起始时间（RFC3339、Unix时间戳或10m这类相对时间）
+	Until      string `json:"until" form:"until"`           // 截止时间，到达后结束推送
+	Timestamps bool   `json:"timestamps" form:"timestamps"` // 是否附带时间戳
+}
+
+// ContainerExecOptions 容器终端会话选项
+type ContainerExecOptions struct {
+	Shell       string `json:"shell" form:"shell"`             // 使用的shell，默认/bin/sh
+	User        string `json:"user" form:"user"`               // 执行用户，默认容器用户
+	WorkDir     string `json:"workDir" form:"workDir"`         // 工作目录，默认容器工作目录
+	Cols        uint   `json:"cols" form:"cols"`               // 终端列数
+	Rows        uint   `json:"rows" form:"rows"`               // 终端行数
+	IdleTimeout int    `json:"idleTimeout" form:"idleTimeout"` // 空闲超时（秒），默认600，最大3600
+}
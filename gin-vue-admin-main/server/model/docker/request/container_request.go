@@ -0,0 +1,67 @@
+package request
+
+// ContainerCreateRequest 创建容器请求
+type ContainerCreateRequest struct {
+	Name              string                 `json:"name"`                     // 容器名称，为空则由Docker生成
+	Image             string                 `json:"image" binding:"required"` // 镜像
+	Cmd               []string               `json:"cmd"`                      // 启动命令
+	Entrypoint        []string               `json:"entrypoint"`               // 入口点
+	Env               []string               `json:"env"`                      // 环境变量 (KEY=VALUE)
+	WorkingDir        string                 `json:"workingDir"`               // 工作目录
+	User              string                 `json:"user"`                     // 运行用户
+	Hostname          string                 `json:"hostname"`                 // 主机名
+	Ports             []PortMapping          `json:"ports"`                    // 端口映射，主机端口为0时随机分配
+	Mounts            []VolumeMount          `json:"mounts"`                   // 挂载 (bind时HostPath为主机路径，volume时为卷名)
+	NetworkMode       string                 `json:"networkMode"`              // 网络模式 (bridge/host/none/container:xxx)，与Networks二选一
+	Networks          []ContainerNetworkSpec `json:"networks"`                 // 加入的网络，第一个为创建时的主网络
+	Labels            map[string]string      `json:"labels"`                   // 标签
+	RestartPolicy     string                 `json:"restartPolicy"`            // 重启策略 (no/always/unless-stopped/on-failure[:N])
+	CPUs              float64                `json:"cpus"`                     // CPU限制（核数，如0.5）
+	CPUShares         int64                  `json:"cpuShares"`                // CPU权重
+	Memory            int64                  `json:"memory"`                   // 内存限制（字节）
+	MemoryReservation int64                  `json:"memoryReservation"`        // 内存软限制（字节）
+	Privileged        bool                   `json:"privileged"`               // 特权模式
+	Tty               bool                   `json:"tty"`                      // 分配伪终端
+	OpenStdin         bool                   `json:"openStdin"`                // 保持标准输入打开
+	Healthcheck       *ContainerHealthcheck  `json:"healthcheck"`              // 健康检查
+	PullImage         bool                   `json:"pullImage"`                // 创建前强制拉取镜像
+	Start             bool                   `json:"start"`                    // 创建后立即启动
+}
+
+// ContainerNetworkSpec 容器网络配置
+type ContainerNetworkSpec struct {
+	Name        string   `json:"name" binding:"required"` // 网络名称
+	Aliases     []string `json:"aliases"`                 // 网络别名
+	IPv4Address string   `json:"ipv4Address"`             // 固定IPv4地址
+	IPv6Address string   `json:"ipv6Address"`             // 固定IPv6地址
+}
+
+// ContainerHealthcheck 容器健康检查
+type ContainerHealthcheck struct {
+	Test        []string `json:"test"`        // 检查命令，如 ["CMD","curl","-f","http://localhost"]，首项非CMD/CMD-SHELL/NONE时按shell命令执行
+	Interval    string   `json:"interval"`    // 检查间隔，如30s
+	Timeout     string   `json:"timeout"`     // 超时时间
+	StartPeriod string   `json:"startPeriod"` // 启动等待时间
+	Retries     int      `json:"retries"`     // 重试次数
+	Disable     bool     `json:"disable"`     // 禁用镜像自带的健康检查
+}
+
+// ContainerRecreateRequest 按修改重建容器请求
+// 切片与映射字段为 null（未传）时沿用原容器配置，传空数组则清空
+type ContainerRecreateRequest struct {
+	Image             string                `json:"image"`             // 新镜像，为空则沿用
+	PullImage         bool                  `json:"pullImage"`         // 重建前拉取最新镜像
+	Cmd               []string              `json:"cmd"`               // 启动命令
+	Entrypoint        []string              `json:"entrypoint"`        // 入口点
+	Env               []string              `json:"env"`               // 环境变量
+	Ports             []PortMapping         `json:"ports"`             // 端口映射
+	Mounts            []VolumeMount         `json:"mounts"`            // 挂载
+	Labels            map[string]string     `json:"labels"`            // 标签
+	RestartPolicy     string                `json:"restartPolicy"`     // 重启策略，为空则沿用
+	CPUs              *float64              `json:"cpus"`              // CPU限制（核数），0为不限制
+	CPUShares         *int64                `json:"cpuShares"`         // CPU权重
+	Memory            *int64                `json:"memory"`            // 内存限制（字节），0为不限制
+	MemoryReservation *int64                `json:"memoryReservation"` // 内存软限制（字节）
+	Privileged        *bool                 `json:"privileged"`        // 特权模式
+	Healthcheck       *ContainerHealthcheck `json:"healthcheck"`       // 健康检查
+}
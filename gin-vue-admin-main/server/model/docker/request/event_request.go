@@ -0,0 +1,20 @@
+package request
+
+import "time"
+
+// DockerEventFilter 事件时间线查询
+type DockerEventFilter struct {
+	Page      int       `form:"page" json:"page"`                                                   // 页码
+	PageSize  int       `form:"pageSize" json:"pageSize"`                                           // 每页大小
+	Type      string    `form:"type" json:"type"`                                                   // 对象类型过滤 (container/image/network/volume)
+	Action    string    `form:"action" json:"action"`                                               // 动作过滤，按前缀匹配，如 health_status
+	Object    string    `form:"object" json:"object"`                                               // 对象名称或ID（前缀）过滤
+	StartTime time.Time `form:"startTime" json:"startTime" time_format:"2006-01-02T15:04:05Z07:00"` // 开始时间
+	EndTime   time.Time `form:"endTime" json:"endTime" time_format:"2006-01-02T15:04:05Z07:00"`     // 结束时间
+}
+
+// DockerEventStreamOptions 实时事件推送过滤
+type DockerEventStreamOptions struct {
+	Type   string `form:"type" json:"type"`     // 对象类型过滤
+	Object string `form:"object" json:"object"` // 对象名称或ID（前缀）过滤
+}
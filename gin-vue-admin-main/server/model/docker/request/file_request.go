@@ -0,0 +1,23 @@
+package request
+
+// FileTarget 文件浏览的对象，由路由参数填充：容器ID或存储卷名称
+type FileTarget struct {
+	Container string `json:"-"` // 容器ID或名称
+	Volume    string `json:"-"` // 存储卷名称
+}
+
+// FilePathQuery 按路径查询文件
+type FilePathQuery struct {
+	Path string `form:"path" json:"path"` // 文件或目录路径，默认为根目录
+}
+
+// FilePathRequest 创建目录、删除文件请求
+type FilePathRequest struct {
+	Path string `json:"path" binding:"required"` // 文件或目录路径
+}
+
+// FileUploadRequest 上传文件参数，与文件一同以 multipart/form-data 提交
+type FileUploadRequest struct {
+	Path      string `form:"path" json:"path"`           // 目标目录，默认为根目录
+	Overwrite bool   `form:"overwrite" json:"overwrite"` // 同名文件已存在时覆盖
+}
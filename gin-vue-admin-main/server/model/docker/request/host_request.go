@@ -0,0 +1,17 @@
+package request
+
+// DockerHostRequest 创建/更新Docker主机请求
+type DockerHostRequest struct {
+	ID           uint   `json:"id"`                          // 主机ID，更新时必填
+	Name         string `json:"name" binding:"required"`     // 主机名称，不能为纯数字或 local
+	Endpoint     string `json:"endpoint" binding:"required"` // 端点：unix:///var/run/docker.sock、tcp://host:2376、ssh://user@host:22[/path/docker.sock]
+	Version      string `json:"version"`                     // API版本，为空时自动协商
+	Timeout      int    `json:"timeout"`                     // 请求超时（秒），0使用默认值30
+	TLSVerify    bool   `json:"tlsVerify"`                   // tcp 端点是否校验服务端证书
+	CertPath     string `json:"certPath"`                    // tcp 端点的证书目录，包含 ca.pem、cert.pem、key.pem
+	SSHKey       string `json:"sshKey"`                      // ssh 私钥（PEM），更新时为空则保持不变
+	SSHPassword  string `json:"sshPassword"`                 // ssh 密码，更新时为空则保持不变
+	ResetHostKey bool   `json:"resetHostKey"`                // 清除已记录的 ssh 主机指纹，远端重装后使用
+	Enabled      bool   `json:"enabled"`                     // 是否启用
+	Description  string `json:"description"`                 // 描述
+}
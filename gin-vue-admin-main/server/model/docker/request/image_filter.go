@@ -17,13 +17,30 @@ type ImagePullRequest struct {
 	Tag   string `json:"tag"`                      // 标签（可选，如果image中没有包含）
 }
 
+// ImagePushRequest 推送镜像请求
+type ImagePushRequest struct {
+	Image string `json:"image" binding:"required"` // 镜像名称，如 harbor.example.com/library/nginx:latest
+	Tag   string `json:"tag"`                      // 标签（可选，如果image中没有包含）
+}
+
 // ImageBuildRequest 构建镜像请求
 type ImageBuildRequest struct {
-	Dockerfile string            `json:"dockerfile" binding:"required"` // Dockerfile内容
-	ImageName  string            `json:"imageName" binding:"required"`  // 镜像名称
-	Tag        string            `json:"tag"`                           // 标签，默认latest
-	BuildArgs  map[string]string `json:"buildArgs"`                     // 构建参数
-	Context    string            `json:"context"`                       // 构建上下文路径
+	Dockerfile     string            `json:"dockerfile"`                   // Dockerfile内容；上下文中已有Dockerfile时可为空，填写时覆盖上下文中的Dockerfile
+	ImageName      string            `json:"imageName" binding:"required"` // 镜像名称
+	Tag            string            `json:"tag"`                          // 标签，默认latest
+	BuildArgs      map[string]string `json:"buildArgs"`                    // 构建参数
+	ContextType    string            `json:"contextType"`                  // 构建上下文来源 (dockerfile/upload/directory/git)，默认dockerfile
+	Context        string            `json:"context"`                      // 服务器上的构建上下文目录（directory）
+	UploadID       string            `json:"uploadId"`                     // 已上传的构建上下文ID（upload）
+	GitURL         string            `json:"gitUrl"`                       // Git仓库地址（git）
+	GitRef         string            `json:"gitRef"`                       // 分支、标签或提交，默认为仓库默认分支（git）
+	GitSubdir      string            `json:"gitSubdir"`                    // 仓库内作为构建上下文的子目录（git）
+	DockerfilePath string            `json:"dockerfilePath"`               // 上下文中的Dockerfile路径，默认Dockerfile
+	Target         string            `json:"target"`                       // 多阶段构建的目标阶段
+	Platform       string            `json:"platform"`                     // 目标平台，如 linux/amd64
+	Labels         map[string]string `json:"labels"`                       // 镜像标签（LABEL）
+	NoCache        bool              `json:"noCache"`                      // 不使用构建缓存
+	Pull           bool              `json:"pull"`                         // 总是尝试拉取基础镜像的新版本
 }
 
 // ImageTagRequest 镜像标签请求
@@ -34,11 +51,31 @@ type ImageTagRequest struct {
 
 // ImageExportRequest 导出镜像请求
 type ImageExportRequest struct {
-	Images []string `json:"images" binding:"required"` // 要导出的镜像列表
+	Images   []string `json:"images" binding:"required"` // 要导出的镜像列表
+	Compress bool     `json:"compress"`                  // 是否gzip压缩
+	Target   string   `json:"target"`                    // 导出目标 (download/oss)，默认download直接下载；oss上传到配置的对象存储并登记到文件管理
+	FileName string   `json:"fileName"`                  // 文件名（不含扩展名），默认根据镜像名生成
+	ClassId  int      `json:"classId"`                   // 上传到对象存储时的附件分类ID
 }
 
 // ImageImportRequest 导入镜像请求
 type ImageImportRequest struct {
-	Source string `json:"source" binding:"required"` // 导入源（文件路径或URL）
-	Tag    string `json:"tag"`                       // 标签
-}
\ No newline at end of file
+	SourceType string   `json:"sourceType" form:"sourceType"` // 导入来源 (path/url/upload)，为空时根据 source 推断
+	Source     string   `json:"source" form:"source"`         // 服务器文件路径或URL（path/url）
+	FileMd5    string   `json:"fileMd5" form:"fileMd5"`       // 分片上传文件的MD5（upload）
+	FileName   string   `json:"fileName" form:"fileName"`     // 分片上传的文件名（upload）
+	Format     string   `json:"format" form:"format"`         // 包格式 (auto/save/rootfs)，默认auto自动识别
+	Tag        string   `json:"tag" form:"tag"`               // 镜像名称与标签，如 myapp:1.0；rootfs包必填，save包仅导入一个镜像时追加此标签
+	Changes    []string `json:"changes" form:"changes"`       // rootfs导入时应用的Dockerfile指令，如 CMD ["/bin/sh"]
+	Message    string   `json:"message" form:"message"`       // rootfs导入时的提交信息
+	Platform   string   `json:"platform" form:"platform"`     // rootfs导入时的平台，如 linux/amd64
+}
+
+// ImageImportChunkRequest 分片上传导入包请求，与文件上传的断点续传参数一致
+type ImageImportChunkRequest struct {
+	FileMd5     string `form:"fileMd5" binding:"required"`  // 完整文件MD5
+	FileName    string `form:"fileName" binding:"required"` // 文件名
+	ChunkMd5    string `form:"chunkMd5" binding:"required"` // 当前分片MD5
+	ChunkNumber int    `form:"chunkNumber"`                 // 分片序号，从0开始
+	ChunkTotal  int    `form:"chunkTotal"`                  // 分片总数
+}
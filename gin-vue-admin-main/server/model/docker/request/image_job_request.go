@@ -0,0 +1,11 @@
+package request
+
+import "github.com/flipped-aurora/gin-vue-admin/server/model/common/request"
+
+// ImageJobFilter 镜像任务列表过滤请求
+type ImageJobFilter struct {
+	request.PageInfo
+	Type   string `json:"type" form:"type"`     // 任务类型 (pull/build)
+	Status string `json:"status" form:"status"` // 任务状态
+	Image  string `json:"image" form:"image"`   // 镜像名称（模糊匹配）
+}
@@ -0,0 +1,26 @@
+package request
+
+import "github.com/flipped-aurora/gin-vue-admin/server/model/common/request"
+
+// ImageInspectRequest 镜像内容检查请求
+type ImageInspectRequest struct {
+	Image string `json:"image" binding:"required"` // 镜像ID或名称
+}
+
+// ImageReportFilter 镜像检查报告列表过滤请求
+type ImageReportFilter struct {
+	request.PageInfo
+	Image  string `json:"image" form:"image"`   // 镜像ID或名称（模糊匹配）
+	Status string `json:"status" form:"status"` // 检查状态
+}
+
+// ImageReportCompareRequest 镜像检查报告对比请求
+type ImageReportCompareRequest struct {
+	BaseID   uint `json:"baseId" form:"baseId" binding:"required"`     // 基准报告ID
+	TargetID uint `json:"targetId" form:"targetId" binding:"required"` // 对比报告ID
+}
+
+// VulnDBImportRequest 离线漏洞库导入请求，文件通过 multipart 的 file 字段上传
+type VulnDBImportRequest struct {
+	Source string `json:"source" form:"source"` // 数据来源名称，为空时使用文件名
+}
@@ -0,0 +1,22 @@
+package request
+
+import "github.com/flipped-aurora/gin-vue-admin/server/model/common/request"
+
+// ImageRetentionPolicyRequest 创建/更新镜像保留策略请求，保留数量与保留天数至少设置一项
+type ImageRetentionPolicyRequest struct {
+	ID            uint   `json:"id"`                            // 策略ID，更新时必填
+	Name          string `json:"name" binding:"required"`       // 策略名称
+	Repository    string `json:"repository" binding:"required"` // 仓库名称，支持通配符
+	KeepLast      int    `json:"keepLast"`                      // 每个仓库保留最近创建的标签数
+	KeepPattern   string `json:"keepPattern"`                   // 标签匹配该正则时始终保留
+	OlderThanDays int    `json:"olderThanDays"`                 // 只删除创建超过该天数的镜像
+	Schedule      string `json:"schedule"`                      // 执行周期（cron表达式，分 时 日 月 周）
+	Enabled       bool   `json:"enabled"`                       // 是否启用定时执行
+	Description   string `json:"description"`                   // 描述
+}
+
+// ImageRetentionRunFilter 镜像保留策略执行记录查询
+type ImageRetentionRunFilter struct {
+	request.PageInfo
+	PolicyID uint `json:"policyId" form:"policyId"` // 策略ID过滤
+}
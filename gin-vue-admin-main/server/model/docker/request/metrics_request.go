@@ -0,0 +1,6 @@
+package request
+
+// MetricsQuery 历史指标查询参数
+type MetricsQuery struct {
+	Range string `json:"range" form:"range"` // 时间范围 (1h/24h/7d)，默认1h
+}
@@ -10,12 +10,41 @@ type NetworkFilter struct {
 
 // NetworkCreateRequest 创建网络请求
 type NetworkCreateRequest struct {
-	Name       string            `json:"name" binding:"required"`       // 网络名称
-	Driver     string            `json:"driver"`                        // 网络驱动，默认为bridge
-	Subnet     string            `json:"subnet"`                        // 子网
-	Gateway    string            `json:"gateway"`                       // 网关
-	EnableIPv6 bool              `json:"enableIPv6"`                    // 是否启用IPv6
-	Internal   bool              `json:"internal"`                      // 是否为内部网络
-	Attachable bool              `json:"attachable"`                    // 是否可附加
-	Labels     map[string]string `json:"labels"`                        // 标签
-}
\ No newline at end of file
+	Name       string              `json:"name" binding:"required"` // 网络名称
+	Driver     string              `json:"driver"`                  // 网络驱动，默认为bridge
+	Subnet     string              `json:"subnet"`                  // 子网
+	Gateway    string              `json:"gateway"`                 // 网关
+	EnableIPv6 bool                `json:"enableIPv6"`              // 是否启用IPv6
+	Internal   bool                `json:"internal"`                // 是否为内部网络
+	Attachable bool                `json:"attachable"`              // 是否可附加
+	Labels     map[string]string   `json:"labels"`                  // 标签
+	IPAM       []NetworkIPAMConfig `json:"ipam"`                    // 地址池，可同时配置多个IPv4与IPv6子网；Subnet/Gateway 作为第一个地址池的简写
+	Parent     string              `json:"parent"`                  // macvlan/ipvlan 的父接口，如 eth0 或 VLAN 子接口 eth0.100
+	Mode       string              `json:"mode"`                    // macvlan 模式 (bridge/vepa/private/passthru) 或 ipvlan 模式 (l2/l3/l3s)
+	MTU        int                 `json:"mtu"`                     // MTU
+	BridgeName string              `json:"bridgeName"`              // bridge 驱动的主机网桥名称
+	ICC        *bool               `json:"icc"`                     // bridge 驱动是否允许容器间通信，默认允许
+	Options    map[string]string   `json:"options"`                 // 其他驱动参数，与上述字段冲突时以上述字段为准
+}
+
+// NetworkIPAMConfig 网络地址池
+type NetworkIPAMConfig struct {
+	Subnet       string            `json:"subnet"`       // 子网
+	Gateway      string            `json:"gateway"`      // 网关
+	IPRange      string            `json:"ipRange"`      // 分配给容器的地址范围，需在子网内
+	AuxAddresses map[string]string `json:"auxAddresses"` // 保留地址，主机名到IP，不分配给容器
+}
+
+// NetworkConnectRequest 将容器连接到网络请求
+type NetworkConnectRequest struct {
+	Container   string   `json:"container" binding:"required"` // 容器ID或名称
+	IPv4Address string   `json:"ipv4Address"`                  // 静态IPv4地址，需在网络的子网内
+	IPv6Address string   `json:"ipv6Address"`                  // 静态IPv6地址，需在网络的子网内
+	Aliases     []string `json:"aliases"`                      // 网络内的别名
+}
+
+// NetworkDisconnectRequest 将容器从网络断开请求
+type NetworkDisconnectRequest struct {
+	Container string `json:"container" binding:"required"` // 容器ID或名称
+	Force     bool   `json:"force"`                        // 强制断开
+}
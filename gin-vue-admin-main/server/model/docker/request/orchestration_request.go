@@ -25,6 +25,7 @@ type ServiceConfig struct {
 
 // PortMapping 端口映射
 type PortMapping struct {
+	HostIP        string `json:"hostIp"`        // 绑定的主机IP，为空则绑定所有地址
 	HostPort      int    `json:"hostPort"`      // 主机端口
 	ContainerPort int    `json:"containerPort"` // 容器端口
 	Protocol      string `json:"protocol"`      // 协议 (tcp/udp)
@@ -45,18 +46,18 @@ type OrchestrationCreateRequest struct {
 	ComposeContent string          `json:"composeContent"`             // Docker Compose内容
 	Services       []ServiceConfig `json:"services"`                   // 服务配置列表
 	WorkingDir     string          `json:"workingDir"`                 // 工作目录
-	EnvFile        string          `json:"envFile"`                    // 环境变量文件路径
+	EnvFile        string          `json:"envFile"`                    // 环境变量文件路径，须为工作目录下的相对路径
 }
 
 // OrchestrationUpdateRequest 更新编排请求
 type OrchestrationUpdateRequest struct {
-	ID             uint            `json:"id" binding:"required"`      // 编排ID
+	ID             uint            `json:"id"`                         // 编排ID
 	Name           string          `json:"name" binding:"required"`    // 编排名称
 	Description    string          `json:"description"`                // 描述
 	ComposeContent string          `json:"composeContent"`             // Docker Compose内容
 	Services       []ServiceConfig `json:"services"`                   // 服务配置列表
 	WorkingDir     string          `json:"workingDir"`                 // 工作目录
-	EnvFile        string          `json:"envFile"`                    // 环境变量文件路径
+	EnvFile        string          `json:"envFile"`                    // 环境变量文件路径，须为工作目录下的相对路径
 }
 
 // OrchestrationOperationRequest 编排操作请求
@@ -0,0 +1,6 @@
+package request
+
+// StatsStreamOptions 资源统计推送选项
+type StatsStreamOptions struct {
+	Interval int `json:"interval" form:"interval"` // 全部容器统计的推送间隔（秒），默认5，最小2
+}
@@ -0,0 +1,7 @@
+package request
+
+// TopologyFilter 拓扑图查询
+type TopologyFilter struct {
+	Orchestration string `form:"orchestration" json:"orchestration"` // 按编排名称过滤，只保留该编排的容器及其关联的网络、存储卷与端口
+	MaxNetworks   int    `form:"maxNetworks" json:"maxNetworks"`     // 容器连接的网络数超过该值时视为过度连接，默认3
+}
@@ -0,0 +1,24 @@
+package request
+
+// VolumeBackupRequest 备份存储卷请求
+type VolumeBackupRequest struct {
+	Storage string `json:"storage"` // 存储位置：local（默认）或 oss
+	Method  string `json:"method"`  // 读取方式：auto（默认）、container 或 mountpoint；auto 在本机可读挂载点时直接读取
+	Quiesce bool   `json:"quiesce"` // 备份期间停止挂载该存储卷的运行中容器，完成后重新启动
+	Remark  string `json:"remark"`  // 备注
+}
+
+// VolumeBackupFilter 备份列表查询
+type VolumeBackupFilter struct {
+	Page       int    `form:"page" json:"page"`             // 页码
+	PageSize   int    `form:"pageSize" json:"pageSize"`     // 每页大小
+	VolumeName string `form:"volumeName" json:"volumeName"` // 存储卷名称
+}
+
+// VolumeRestoreRequest 从备份恢复存储卷请求
+type VolumeRestoreRequest struct {
+	BackupID  uint   `json:"backupId" binding:"required"` // 备份ID
+	Volume    string `json:"volume"`                      // 目标存储卷，默认为备份来源的存储卷；不存在时按备份记录的驱动与标签创建
+	Overwrite bool   `json:"overwrite"`                   // 目标存储卷已存在时清空后恢复，未开启时拒绝恢复到已存在的存储卷
+	Quiesce   bool   `json:"quiesce"`                     // 恢复期间停止挂载目标存储卷的运行中容器，完成后重新启动
+}
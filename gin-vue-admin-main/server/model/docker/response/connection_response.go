@@ -0,0 +1,27 @@
+package response
+
+import "time"
+
+// DockerConnectionEvent 默认Docker主机连接状态变化
+type DockerConnectionEvent struct {
+	Time        time.Time `json:"time"`            // 发生时间
+	From        string    `json:"from"`            // 变化前状态
+	To          string    `json:"to"`              // 变化后状态
+	Reconnected bool      `json:"reconnected"`     // 是否重建了客户端
+	Error       string    `json:"error,omitempty"` // 导致状态变化的错误
+}
+
+// DockerConnectionStatus 默认Docker主机连接状态
+type DockerConnectionStatus struct {
+	State         string                  `json:"state"`               // 连接状态 (connected/degraded/down)
+	Host          string                  `json:"host"`                // Docker端点
+	ServerVersion string                  `json:"serverVersion"`       // 守护进程版本
+	APIVersion    string                  `json:"apiVersion"`          // 协商后的API版本
+	Latency       int64                   `json:"latency"`             // 最近一次 ping 耗时（毫秒）
+	LastError     string                  `json:"lastError,omitempty"` // 最近一次失败原因
+	Failures      int                     `json:"failures"`            // 连续失败次数
+	Reconnects    int                     `json:"reconnects"`          // 启动以来重建客户端的次数
+	LastCheckAt   *time.Time              `json:"lastCheckAt"`         // 最近一次检查时间
+	LastChangeAt  *time.Time              `json:"lastChangeAt"`        // 最近一次状态变化时间
+	Events        []DockerConnectionEvent `json:"events"`              // 最近的状态变化，按时间倒序
+}
@@ -45,6 +45,18 @@ type ContainerConfig struct {
 	Entrypoint   []string          `json:"entrypoint"`   // 入口点
 	Labels       map[string]string `json:"labels"`       // 标签
 	ExposedPorts map[string]struct{} `json:"exposedPorts"` // 暴露端口
+	Healthcheck  *HealthConfig     `json:"healthcheck,omitempty"` // 健康检查
+	Tty          bool              `json:"tty"`          // 是否分配伪终端
+	OpenStdin    bool              `json:"openStdin"`    // 是否保持标准输入打开
+}
+
+// HealthConfig 健康检查配置
+type HealthConfig struct {
+	Test        []string `json:"test"`        // 检查命令
+	Interval    string   `json:"interval"`    // 检查间隔
+	Timeout     string   `json:"timeout"`     // 超时时间
+	StartPeriod string   `json:"startPeriod"` // 启动等待时间
+	Retries     int      `json:"retries"`     // 重试次数
 }
 
 // HostConfig 主机配置
@@ -79,6 +91,10 @@ type HostConfig struct {
 	ShmSize         int64             `json:"shmSize"`         // 共享内存大小
 	Sysctls         map[string]string `json:"sysctls"`         // 系统控制参数
 	Runtime         string            `json:"runtime"`         // 运行时
+	NanoCPUs          int64           `json:"nanoCpus"`          // CPU限制（1e9为1核）
+	CPUShares         int64           `json:"cpuShares"`         // CPU权重
+	Memory            int64           `json:"memory"`            // 内存限制（字节）
+	MemoryReservation int64           `json:"memoryReservation"` // 内存软限制（字节）
 }
 
 // LogConfig 日志配置
@@ -146,6 +162,7 @@ type EndpointIPAMConfig struct {
 
 // Mount 挂载点
 type Mount struct {
+	Name          string      `json:"name,omitempty"` // 存储卷名称（仅volume类型）
 	Target        string      `json:"target"`        // 目标路径
 	Source        string      `json:"source"`        // 源路径
 	Type          string      `json:"type"`          // 挂载类型
@@ -178,4 +195,10 @@ type VolumeDriverConfig struct {
 type TmpfsOptions struct {
 	SizeBytes int64 `json:"sizeBytes"` // 大小（字节）
 	Mode      int   `json:"mode"`      // 模式
-}
\ No newline at end of file
+}
+// ContainerLogLine 实时日志行
+type ContainerLogLine struct {
+	Stream    string `json:"stream"`              // 输出流 (stdout/stderr)
+	Timestamp string `json:"timestamp,omitempty"` // 时间戳，仅在请求时间戳时返回
+	Line      string `json:"line"`                // 日志内容
+}
@@ -0,0 +1,20 @@
+package response
+
+import "time"
+
+// ContainerResourceStats 容器资源使用情况
+type ContainerResourceStats struct {
+	ID            string    `json:"id"`            // 容器ID
+	Name          string    `json:"name"`          // 容器名称
+	CPUPercent    float64   `json:"cpuPercent"`    // CPU使用率（%，多核可超过100）
+	OnlineCPUs    uint32    `json:"onlineCpus"`    // 可用CPU核数
+	MemoryUsage   uint64    `json:"memoryUsage"`   // 内存使用量（字节，不含页缓存）
+	MemoryLimit   uint64    `json:"memoryLimit"`   // 内存限制（字节）
+	MemoryPercent float64   `json:"memoryPercent"` // 内存使用率（%）
+	NetworkRx     uint64    `json:"networkRx"`     // 网络接收（字节）
+	NetworkTx     uint64    `json:"networkTx"`     // 网络发送（字节）
+	BlockRead     uint64    `json:"blockRead"`     // 块设备读取（字节）
+	BlockWrite    uint64    `json:"blockWrite"`    // 块设备写入（字节）
+	PIDs          uint64    `json:"pids"`          // 进程数
+	Read          time.Time `json:"read"`          // 采样时间
+}
@@ -0,0 +1,15 @@
+package response
+
+import "time"
+
+// FileEntry 容器或存储卷中的文件信息
+type FileEntry struct {
+	Name       string    `json:"name"`                 // 文件名
+	Path       string    `json:"path"`                 // 完整路径
+	Size       int64     `json:"size"`                 // 大小（字节）
+	Mode       string    `json:"mode"`                 // 权限，如 drwxr-xr-x
+	IsDir      bool      `json:"isDir"`                // 是否为目录
+	IsLink     bool      `json:"isLink"`               // 是否为符号链接
+	LinkTarget string    `json:"linkTarget,omitempty"` // 符号链接指向
+	ModTime    time.Time `json:"modTime"`              // 修改时间
+}
@@ -0,0 +1,15 @@
+package response
+
+// DockerHostStatus Docker主机连接测试结果
+type DockerHostStatus struct {
+	HostID          uint   `json:"hostId"`          // 主机ID，0为默认主机
+	Reachable       bool   `json:"reachable"`       // 是否可连接
+	Version         string `json:"version"`         // Docker版本
+	Latency         int64  `json:"latency"`         // 连接耗时（毫秒）
+	Name            string `json:"name"`            // 主机名
+	OperatingSystem string `json:"operatingSystem"` // 操作系统
+	Architecture    string `json:"architecture"`    // 架构
+	Containers      int    `json:"containers"`      // 容器数
+	Images          int    `json:"images"`          // 镜像数
+	Error           string `json:"error,omitempty"` // 连接失败原因
+}
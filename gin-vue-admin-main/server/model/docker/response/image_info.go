@@ -69,4 +69,23 @@ type ImageDetailResponse struct {
 type ImageBuildResponse struct {
 	response.Response
 	Data string `json:"data"` // 构建日志
-}
\ No newline at end of file
+}
+// BuildContextUploadResponse 上传构建上下文响应
+type BuildContextUploadResponse struct {
+	UploadID string `json:"uploadId"` // 上传ID，构建时通过 uploadId 引用
+	FileName string `json:"fileName"` // 原始文件名
+	Size     int64  `json:"size"`     // 文件大小（字节）
+}
+
+// ImportedImage 导入后得到的镜像
+type ImportedImage struct {
+	ID   string   `json:"id"`   // 镜像ID
+	Tags []string `json:"tags"` // 镜像标签
+}
+
+// ImageImportResult 导入镜像结果
+type ImageImportResult struct {
+	Format string          `json:"format"` // 识别出的包格式 (save/rootfs)
+	Images []ImportedImage `json:"images"` // 导入的镜像
+	Log    string          `json:"log"`    // 导入日志
+}
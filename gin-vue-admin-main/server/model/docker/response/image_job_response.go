@@ -0,0 +1,29 @@
+package response
+
+import "time"
+
+// ImageLayerProgress 拉取镜像时单个镜像层的进度
+type ImageLayerProgress struct {
+	ID      string  `json:"id"`      // 镜像层ID
+	Status  string  `json:"status"`  // 当前状态，如 Downloading、Extracting、Pull complete
+	Current int64   `json:"current"` // 当前阶段已处理字节数
+	Total   int64   `json:"total"`   // 当前阶段总字节数
+	Percent float64 `json:"percent"` // 该层整体进度（%），下载与解压各占一半
+}
+
+// ImageJobProgress 镜像任务实时进度
+type ImageJobProgress struct {
+	ID         uint                 `json:"id"`                   // 任务ID
+	Type       string               `json:"type"`                 // 任务类型 (pull/build)
+	Image      string               `json:"image"`                // 镜像名称
+	Status     string               `json:"status"`               // 任务状态
+	Progress   float64              `json:"progress"`             // 整体进度（%）
+	Message    string               `json:"message"`              // 当前阶段
+	Error      string               `json:"error,omitempty"`      // 失败原因
+	ImageID    string               `json:"imageId,omitempty"`    // 完成后的镜像ID
+	Layers     []ImageLayerProgress `json:"layers,omitempty"`     // 各镜像层进度（拉取任务）
+	Step       int                  `json:"step,omitempty"`       // 当前构建步骤（构建任务）
+	TotalSteps int                  `json:"totalSteps,omitempty"` // 构建总步骤数（构建任务）
+	StartedAt  *time.Time           `json:"startedAt"`            // 开始执行时间
+	FinishedAt *time.Time           `json:"finishedAt"`           // 结束时间
+}
@@ -0,0 +1,54 @@
+package response
+
+import "time"
+
+// ReportPackageChange 两次检查之间的软件包变化
+type ReportPackageChange struct {
+	Type          string `json:"type"`          // 软件包类型
+	Name          string `json:"name"`          // 包名
+	Path          string `json:"path"`          // 来源文件
+	BaseVersion   string `json:"baseVersion"`   // 基准报告中的版本，新增时为空
+	TargetVersion string `json:"targetVersion"` // 对比报告中的版本，移除时为空
+}
+
+// ReportFindingChange 两次检查之间的漏洞变化
+type ReportFindingChange struct {
+	VulnID       string `json:"vulnId"`       // 漏洞编号
+	PackageType  string `json:"packageType"`  // 软件包类型
+	Package      string `json:"package"`      // 包名
+	Version      string `json:"version"`      // 所在报告中的版本
+	FixedVersion string `json:"fixedVersion"` // 修复版本
+	Severity     string `json:"severity"`     // 严重程度
+	Summary      string `json:"summary"`      // 漏洞摘要
+}
+
+// ImageReportCompare 两份镜像检查报告的对比结果
+type ImageReportCompare struct {
+	BaseID          uint                  `json:"baseId"`          // 基准报告ID
+	TargetID        uint                  `json:"targetId"`        // 对比报告ID
+	BaseImage       string                `json:"baseImage"`       // 基准镜像
+	TargetImage     string                `json:"targetImage"`     // 对比镜像
+	AddedPackages   []ReportPackageChange `json:"addedPackages"`   // 新增软件包
+	RemovedPackages []ReportPackageChange `json:"removedPackages"` // 移除的软件包
+	ChangedPackages []ReportPackageChange `json:"changedPackages"` // 版本变化的软件包
+	NewFindings     []ReportFindingChange `json:"newFindings"`     // 新出现的漏洞
+	FixedFindings   []ReportFindingChange `json:"fixedFindings"`   // 已消除的漏洞
+	AddedSetuid     []string              `json:"addedSetuid"`     // 新增的 setuid/setgid 文件
+	RemovedSetuid   []string              `json:"removedSetuid"`   // 移除的 setuid/setgid 文件
+}
+
+// VulnDBSourceStat 离线漏洞库各数据来源统计
+type VulnDBSourceStat struct {
+	Source     string    `json:"source"`     // 数据来源名称
+	Ecosystems []string  `json:"ecosystems"` // 包含的生态
+	Count      int64     `json:"count"`      // 条目数
+	ImportedAt time.Time `json:"importedAt"` // 导入时间
+}
+
+// VulnDBImportResult 离线漏洞库导入结果
+type VulnDBImportResult struct {
+	Source          string `json:"source"`          // 数据来源名称
+	Vulnerabilities int    `json:"vulnerabilities"` // 导入的漏洞数
+	Entries         int    `json:"entries"`         // 生成的匹配条目数
+	Skipped         int    `json:"skipped"`         // 因生态不支持或格式错误跳过的漏洞数
+}
@@ -0,0 +1,29 @@
+package response
+
+import "time"
+
+// ImageRetentionCandidate 保留策略匹配到的镜像标签
+type ImageRetentionCandidate struct {
+	Repository   string    `json:"repository"`           // 仓库名称
+	Tag          string    `json:"tag"`                  // 标签
+	ImageID      string    `json:"imageId"`              // 镜像ID
+	Created      time.Time `json:"created"`              // 镜像创建时间
+	AgeDays      int       `json:"ageDays"`              // 已创建天数
+	Rank         int       `json:"rank"`                 // 在仓库内按创建时间的排名，1为最新
+	Size         int64     `json:"size"`                 // 镜像大小（字节）
+	ImageRemoved bool      `json:"imageRemoved"`         // 删除后镜像不再有标签，镜像本身随之删除
+	Containers   []string  `json:"containers,omitempty"` // 使用该镜像的容器（被保护时）
+}
+
+// ImageRetentionPlan 保留策略预览结果，列出将被删除与因被使用而保留的标签
+type ImageRetentionPlan struct {
+	PolicyID         uint                      `json:"policyId"`         // 策略ID，未保存的策略为0
+	PolicyName       string                    `json:"policyName"`       // 策略名称
+	Matched          int                       `json:"matched"`          // 匹配仓库的标签数
+	Kept             int                       `json:"kept"`             // 按规则保留的标签数
+	Candidates       []ImageRetentionCandidate `json:"candidates"`       // 将被删除的标签
+	Protected        []ImageRetentionCandidate `json:"protected"`        // 符合删除条件但被容器使用而保留的标签
+	ReclaimableBytes int64                     `json:"reclaimableBytes"` // 按磁盘占用估算可释放的空间（字节），只计入随之删除且不与其他镜像共享的部分
+	ReclaimableSize  string                    `json:"reclaimableSize"`  // 可释放空间（格式化）
+	GeneratedAt      time.Time                 `json:"generatedAt"`      // 生成时间
+}
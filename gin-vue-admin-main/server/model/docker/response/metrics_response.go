@@ -0,0 +1,63 @@
+package response
+
+import "time"
+
+// MetricPoint 指标数据点，聚合数据的时间为区间起点
+type MetricPoint struct {
+	Time             time.Time `json:"time"`             // 时间
+	CPUPercent       float64   `json:"cpuPercent"`       // CPU使用率（%）
+	CPUPercentMax    float64   `json:"cpuPercentMax"`    // 区间内CPU使用率峰值（%）
+	MemoryUsage      uint64    `json:"memoryUsage"`      // 内存使用量（字节）
+	MemoryTotal      uint64    `json:"memoryTotal"`      // 内存总量或限制（字节）
+	MemoryPercent    float64   `json:"memoryPercent"`    // 内存使用率（%）
+	MemoryPercentMax float64   `json:"memoryPercentMax"` // 区间内内存使用率峰值（%）
+	DiskUsed         uint64    `json:"diskUsed"`         // 磁盘已用（字节）
+	DiskTotal        uint64    `json:"diskTotal"`        // 磁盘总量（字节）
+	DiskPercent      float64   `json:"diskPercent"`      // 磁盘使用率（%）
+	Load1            float64   `json:"load1"`            // 1分钟平均负载
+	Load5            float64   `json:"load5"`            // 5分钟平均负载
+	Load15           float64   `json:"load15"`           // 15分钟平均负载
+	NetworkRx        uint64    `json:"networkRx"`        // 网络累计接收（字节）
+	NetworkTx        uint64    `json:"networkTx"`        // 网络累计发送（字节）
+	BlockRead        uint64    `json:"blockRead"`        // 块设备累计读取（字节）
+	BlockWrite       uint64    `json:"blockWrite"`       // 块设备累计写入（字节）
+	PIDs             uint64    `json:"pids"`             // 进程数
+}
+
+// MetricSeries 单个对象的指标序列
+type MetricSeries struct {
+	TargetType string        `json:"targetType"` // 对象类型 (host/disk/container)
+	TargetID   string        `json:"targetId"`   // 对象标识
+	TargetName string        `json:"targetName"` // 对象名称
+	Points     []MetricPoint `json:"points"`     // 按时间升序的数据点
+}
+
+// HostMetricsResult 主机历史指标
+type HostMetricsResult struct {
+	Range      string         `json:"range"`      // 时间范围
+	Resolution int            `json:"resolution"` // 数据精度（秒），0为原始采样
+	Start      time.Time      `json:"start"`      // 起始时间
+	End        time.Time      `json:"end"`        // 结束时间
+	Host       MetricSeries   `json:"host"`       // 主机CPU、内存、负载
+	Disks      []MetricSeries `json:"disks"`      // 各挂载点磁盘使用
+}
+
+// ContainerMetricsResult 容器历史指标
+type ContainerMetricsResult struct {
+	Range      string         `json:"range"`      // 时间范围
+	Resolution int            `json:"resolution"` // 数据精度（秒），0为原始采样
+	Start      time.Time      `json:"start"`      // 起始时间
+	End        time.Time      `json:"end"`        // 结束时间
+	Series     []MetricSeries `json:"series"`     // 匹配的容器序列，容器重建后ID变化时按ID分别返回
+}
+
+// ContainerMetricsSummary 容器在时间范围内的资源使用汇总，用于容量规划
+type ContainerMetricsSummary struct {
+	TargetID         string    `json:"targetId"`         // 容器ID
+	TargetName       string    `json:"targetName"`       // 容器名称
+	CPUPercentAvg    float64   `json:"cpuPercentAvg"`    // 平均CPU使用率（%）
+	CPUPercentMax    float64   `json:"cpuPercentMax"`    // CPU使用率峰值（%）
+	MemoryUsageAvg   float64   `json:"memoryUsageAvg"`   // 平均内存使用量（字节）
+	MemoryPercentMax float64   `json:"memoryPercentMax"` // 内存使用率峰值（%）
+	LastSampledAt    time.Time `json:"lastSampledAt"`    // 最近一次数据时间
+}
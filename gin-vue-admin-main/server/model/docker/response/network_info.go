@@ -26,17 +26,23 @@ type IPAMConfig struct {
 	Gateway string `json:"gateway"` // 网关
 }
 
-// NetworkContainer 网络中的容器信息
+// NetworkContainer 网络中的容器信息，包括已连接但未运行的容器
 type NetworkContainer struct {
-	Name        string `json:"name"`        // 容器名称
-	EndpointID  string `json:"endpointId"`  // 端点ID
-	MacAddress  string `json:"macAddress"`  // MAC地址
-	IPv4Address string `json:"ipv4Address"` // IPv4地址
-	IPv6Address string `json:"ipv6Address"` // IPv6地址
+	Name          string   `json:"name"`          // 容器名称
+	EndpointID    string   `json:"endpointId"`    // 端点ID
+	MacAddress    string   `json:"macAddress"`    // MAC地址
+	IPv4Address   string   `json:"ipv4Address"`   // IPv4地址
+	IPv6Address   string   `json:"ipv6Address"`   // IPv6地址
+	StaticIPv4    string   `json:"staticIPv4"`    // 连接时指定的静态IPv4地址
+	StaticIPv6    string   `json:"staticIPv6"`    // 连接时指定的静态IPv6地址
+	Aliases       []string `json:"aliases"`       // 网络内的别名
+	State         string   `json:"state"`         // 容器状态
+	Orchestration string   `json:"orchestration"` // 所属编排
+	Service       string   `json:"service"`       // 编排中的服务名
 }
 
 // NetworkListResponse 网络列表响应
 type NetworkListResponse struct {
 	List  []NetworkInfo `json:"list"`  // 网络列表
 	Total int64         `json:"total"` // 总数
-}
\ No newline at end of file
+}
@@ -119,4 +119,15 @@ type ServiceStatusInfo struct {
 	ContainerID   string `json:"containerId"`   // 容器ID
 	Status        string `json:"status"`        // 服务状态
 	Health        string `json:"health"`        // 健康状态
-}
\ No newline at end of file
+}
+// OrchestrationDeployResult 编排部署结果
+type OrchestrationDeployResult struct {
+	Name      string   `json:"name"`      // 编排名称
+	Created   []string `json:"created"`   // 新建的服务
+	Recreated []string `json:"recreated"` // 配置变化而重建的服务
+	Unchanged []string `json:"unchanged"` // 未变化的服务
+	Removed   []string `json:"removed"`   // 已移除的服务
+	Down      []string `json:"down"`      // 部署中断时旧容器已删除、新容器未能创建的服务
+	Networks  []string `json:"networks"`  // 新建的网络
+	Volumes   []string `json:"volumes"`   // 新建的存储卷
+}
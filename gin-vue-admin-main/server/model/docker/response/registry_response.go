@@ -10,6 +10,8 @@ type RegistryInfo struct {
 	Protocol    string    `json:"protocol"`    // 协议 (http/https)
 	Status      string    `json:"status"`      // 状态 (active/inactive)
 	Username    string    `json:"username"`    // 用户名
+	HasPassword bool      `json:"hasPassword"` // 是否已保存密码
+	IsDefault   bool      `json:"isDefault"`   // 是否为默认仓库
 	Description string    `json:"description"` // 描述
 	CreatedAt   time.Time `json:"createdAt"`   // 创建时间
 	UpdatedAt   time.Time `json:"updatedAt"`   // 更新时间
@@ -18,7 +20,6 @@ type RegistryInfo struct {
 // RegistryDetail 仓库详细信息
 type RegistryDetail struct {
 	RegistryInfo
-	IsDefault    bool       `json:"isDefault"`    // 是否为默认仓库
 	LastTestTime *time.Time `json:"lastTestTime"` // 最后测试时间
 	TestResult   string     `json:"testResult"`   // 测试结果
 }
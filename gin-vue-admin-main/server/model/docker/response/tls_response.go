@@ -0,0 +1,25 @@
+package response
+
+import "time"
+
+// DockerCertificateInfo 证书目录中一个证书的有效期
+type DockerCertificateInfo struct {
+	File         string    `json:"file"`         // 文件名：ca.pem 或 cert.pem
+	Subject      string    `json:"subject"`      // 证书主体
+	Issuer       string    `json:"issuer"`       // 签发者
+	NotBefore    time.Time `json:"notBefore"`    // 生效时间
+	NotAfter     time.Time `json:"notAfter"`     // 到期时间
+	DaysLeft     int       `json:"daysLeft"`     // 剩余天数，已过期时为负数
+	Expired      bool      `json:"expired"`      // 是否已过期
+	ExpiringSoon bool      `json:"expiringSoon"` // 是否将在30天内过期
+}
+
+// DockerTLSStatus 默认Docker主机的 TLS 配置与证书状态
+type DockerTLSStatus struct {
+	Host         string                  `json:"host"`            // Docker端点
+	Enabled      bool                    `json:"enabled"`         // 是否使用 TLS 连接，仅 tcp 端点配置证书目录时启用
+	TLSVerify    bool                    `json:"tlsVerify"`       // 是否校验服务端证书
+	CertPath     string                  `json:"certPath"`        // 证书目录
+	Certificates []DockerCertificateInfo `json:"certificates"`    // 证书有效期
+	Error        string                  `json:"error,omitempty"` // 证书校验失败原因
+}
@@ -0,0 +1,27 @@
+package response
+
+// TopologyNode 拓扑图节点
+type TopologyNode struct {
+	ID            string            `json:"id"`                      // 节点ID，格式为 类型:标识
+	Type          string            `json:"type"`                    // 节点类型 (container/network/volume/port/orchestration)
+	Name          string            `json:"name"`                    // 显示名称
+	State         string            `json:"state,omitempty"`         // 容器状态
+	Orchestration string            `json:"orchestration,omitempty"` // 容器所属编排
+	Meta          map[string]string `json:"meta,omitempty"`          // 附加信息，如镜像、网络驱动、存储卷驱动
+}
+
+// TopologyEdge 拓扑图的边
+type TopologyEdge struct {
+	Source string `json:"source"`          // 起点节点ID
+	Target string `json:"target"`          // 终点节点ID
+	Type   string `json:"type"`            // 关系类型 (attachment/mount/port/member)
+	Label  string `json:"label,omitempty"` // 说明，如IP地址、挂载路径、端口映射
+}
+
+// TopologyGraph 容器、网络、存储卷与主机端口的关系图
+type TopologyGraph struct {
+	Nodes         []TopologyNode `json:"nodes"`         // 节点
+	Edges         []TopologyEdge `json:"edges"`         // 边
+	Isolated      []string       `json:"isolated"`      // 未连接任何网络（或仅使用 none 网络）的容器节点ID
+	OverConnected []string       `json:"overConnected"` // 连接网络数超过阈值的容器节点ID
+}
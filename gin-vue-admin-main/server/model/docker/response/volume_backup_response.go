@@ -0,0 +1,9 @@
+package response
+
+// VolumeRestoreResult 存储卷恢复结果
+type VolumeRestoreResult struct {
+	Volume   string   `json:"volume"`   // 恢复到的存储卷
+	Created  bool     `json:"created"`  // 存储卷是否为本次新建
+	Quiesced []string `json:"quiesced"` // 恢复期间停止并重新启动的容器
+	Duration int64    `json:"duration"` // 耗时（毫秒）
+}
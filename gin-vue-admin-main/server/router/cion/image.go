@@ -17,6 +17,7 @@ func (s *ImageRouter) InitImageRouter(Router *gin.RouterGroup,PublicRouter *gin.
 		imageRouter.DELETE("deleteImage", imageApi.DeleteImage) // 删除image表
 		imageRouter.DELETE("deleteImageByIds", imageApi.DeleteImageByIds) // 批量删除image表
 		imageRouter.PUT("updateImage", imageApi.UpdateImage)    // 更新image表
+		imageRouter.POST("syncImages", imageApi.SyncImages)     // 同步Docker镜像到image表
 	}
 	{
 		imageRouterWithoutRecord.GET("findImage", imageApi.FindImage)        // 根据ID获取image表
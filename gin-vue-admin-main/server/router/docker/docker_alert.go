@@ -0,0 +1,40 @@
+package docker
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/api/v1/docker"
+	"github.com/flipped-aurora/gin-vue-admin/server/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+var dockerAlertApi = docker.DockerAlertApi{}
+
+type DockerAlertRouter struct{}
+
+// InitDockerAlertRouter 初始化Docker告警路由，告警规则只评估默认主机的指标
+func (d *DockerAlertRouter) InitDockerAlertRouter(Router *gin.RouterGroup) {
+	// 带操作记录的路由组 - 用于需要记录操作日志的API
+	alertRouter := Router.Group("docker/alerts").Use(middleware.DefaultDockerHostOnly(), middleware.OperationRecord())
+	// 不带操作记录的路由组 - 用于查询类API
+	alertRouterWithoutRecord := Router.Group("docker/alerts").Use(middleware.DefaultDockerHostOnly())
+
+	// 需要记录操作的路由
+	{
+		alertRouter.POST("rules", dockerAlertApi.CreateAlertRule)              // 创建告警规则
+		alertRouter.PUT("rules", dockerAlertApi.UpdateAlertRule)               // 更新告警规则
+		alertRouter.DELETE("rules/:id", dockerAlertApi.DeleteAlertRule)        // 删除告警规则
+		alertRouter.POST("channels", dockerAlertApi.CreateAlertChannel)        // 创建通知渠道
+		alertRouter.PUT("channels", dockerAlertApi.UpdateAlertChannel)         // 更新通知渠道
+		alertRouter.DELETE("channels/:id", dockerAlertApi.DeleteAlertChannel)  // 删除通知渠道
+		alertRouter.POST("channels/:id/test", dockerAlertApi.TestAlertChannel) // 测试通知渠道
+		alertRouter.POST("silences", dockerAlertApi.CreateAlertSilence)        // 创建静默窗口
+		alertRouter.DELETE("silences/:id", dockerAlertApi.DeleteAlertSilence)  // 删除静默窗口
+	}
+
+	// 不需要记录操作的路由（查询类）
+	{
+		alertRouterWithoutRecord.GET("rules", dockerAlertApi.GetAlertRuleList)       // 获取告警规则列表
+		alertRouterWithoutRecord.GET("channels", dockerAlertApi.GetAlertChannelList) // 获取通知渠道列表
+		alertRouterWithoutRecord.GET("silences", dockerAlertApi.GetAlertSilenceList) // 获取静默窗口列表
+		alertRouterWithoutRecord.GET("events", dockerAlertApi.GetAlertEventList)     // 获取告警事件列表
+	}
+}
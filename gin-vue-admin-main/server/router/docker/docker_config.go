@@ -28,6 +28,7 @@ func (d *DockerConfigRouter) InitDockerConfigRouter(Router *gin.RouterGroup) {
 		dockerRouter.POST("service/restart", dockerConfigApi.RestartDockerService)     // 重启Docker服务
 		dockerRouter.POST("service/start", dockerConfigApi.StartDockerService)         // 启动Docker服务
 		dockerRouter.POST("service/stop", dockerConfigApi.StopDockerService)           // 停止Docker服务
+		dockerRouter.POST("config/tls", dockerConfigApi.RotateDockerTLSCerts)          // 上传并轮换TLS证书
 	}
 
 	// 不需要记录操作的路由（查询类）
@@ -37,5 +38,6 @@ func (d *DockerConfigRouter) InitDockerConfigRouter(Router *gin.RouterGroup) {
 		dockerRouterWithoutRecord.GET("config/backups", dockerConfigApi.GetBackupList)            // 获取备份列表
 		dockerRouterWithoutRecord.GET("service/status", dockerConfigApi.GetDockerServiceStatus)   // 获取Docker服务状态
 		dockerRouterWithoutRecord.GET("service/health", dockerConfigApi.CheckDockerServiceHealth) // 检查Docker服务健康状态
+		dockerRouterWithoutRecord.GET("config/tls", dockerConfigApi.GetDockerTLSStatus)           // 获取TLS证书状态
 	}
 }
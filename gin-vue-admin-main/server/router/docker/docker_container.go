@@ -17,19 +17,23 @@ func (d *DockerContainerRouter) InitDockerContainerRouter(Router *gin.RouterGrou
 
 	// 需要记录操作的路由（容器操作）
 	{
-		dockerRouter.POST("containers/:id/start", dockerContainerApi.StartContainer)     // 启动容器
-		dockerRouter.POST("containers/:id/stop", dockerContainerApi.StopContainer)       // 停止容器
-		dockerRouter.POST("containers/:id/restart", dockerContainerApi.RestartContainer) // 重启容器
-		dockerRouter.DELETE("containers/:id", dockerContainerApi.RemoveContainer)        // 删除容器
+		dockerRouter.POST("containers", dockerContainerApi.CreateContainer)                // 创建容器
+		dockerRouter.POST("containers/:id/recreate", dockerContainerApi.RecreateContainer) // 按修改重建容器
+		dockerRouter.POST("containers/:id/start", dockerContainerApi.StartContainer)       // 启动容器
+		dockerRouter.POST("containers/:id/stop", dockerContainerApi.StopContainer)         // 停止容器
+		dockerRouter.POST("containers/:id/restart", dockerContainerApi.RestartContainer)   // 重启容器
+		dockerRouter.DELETE("containers/:id", dockerContainerApi.RemoveContainer)          // 删除容器
 		// 编排批量操作路由已迁移到docker_orchestration.go
 	}
 
 	// 不需要记录操作的路由（查询类）
 	{
-		dockerRouterWithoutRecord.GET("containers", dockerContainerApi.GetContainerList)          // 获取容器列表
-		dockerRouterWithoutRecord.GET("containers/:id", dockerContainerApi.GetContainerDetail)    // 获取容器详情
-		dockerRouterWithoutRecord.GET("containers/:id/logs", dockerContainerApi.GetContainerLogs) // 获取容器日志
-		dockerRouterWithoutRecord.GET("info", dockerContainerApi.GetDockerInfo)                   // 获取Docker信息
-		dockerRouterWithoutRecord.GET("status", dockerContainerApi.CheckDockerStatus)             // 检查Docker状态
+		dockerRouterWithoutRecord.GET("containers", dockerContainerApi.GetContainerList)                    // 获取容器列表
+		dockerRouterWithoutRecord.GET("containers/:id", dockerContainerApi.GetContainerDetail)              // 获取容器详情
+		dockerRouterWithoutRecord.GET("containers/:id/logs", dockerContainerApi.GetContainerLogs)           // 获取容器日志
+		dockerRouterWithoutRecord.GET("containers/:id/logs/stream", dockerContainerApi.StreamContainerLogs) // 实时跟踪容器日志
+		dockerRouterWithoutRecord.GET("containers/:id/exec", dockerContainerApi.ExecContainer)              // 容器终端（WebSocket，自行记录审计日志）
+		dockerRouterWithoutRecord.GET("info", dockerContainerApi.GetDockerInfo)                             // 获取Docker信息
+		dockerRouterWithoutRecord.GET("status", dockerContainerApi.CheckDockerStatus)                       // 检查Docker状态
 	}
 }
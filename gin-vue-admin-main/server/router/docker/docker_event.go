@@ -0,0 +1,22 @@
+package docker
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/api/v1/docker"
+	"github.com/gin-gonic/gin"
+)
+
+var dockerEventApi = docker.DockerEventApi{}
+
+type DockerEventRouter struct{}
+
+// InitDockerEventRouter 初始化Docker事件路由
+func (d *DockerEventRouter) InitDockerEventRouter(Router *gin.RouterGroup) {
+	// 不带操作记录的路由组 - 用于查询类API
+	eventRouterWithoutRecord := Router.Group("docker/events")
+
+	// 不需要记录操作的路由（查询类）
+	{
+		eventRouterWithoutRecord.GET("", dockerEventApi.GetDockerEventList)     // 获取事件时间线
+		eventRouterWithoutRecord.GET("live", dockerEventApi.StreamDockerEvents) // 实时推送事件
+	}
+}
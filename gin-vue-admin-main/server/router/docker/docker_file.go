@@ -0,0 +1,43 @@
+package docker
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/api/v1/docker"
+	"github.com/flipped-aurora/gin-vue-admin/server/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+var dockerFileApi = docker.DockerFileApi{}
+
+type DockerFileRouter struct{}
+
+// InitDockerFileRouter 初始化容器与存储卷文件浏览路由，下载、上传、创建目录与删除记录操作日志
+func (d *DockerFileRouter) InitDockerFileRouter(Router *gin.RouterGroup) {
+	// 带操作记录的路由组 - 用于需要记录操作日志的API
+	fileRouter := Router.Group("docker").Use(middleware.OperationRecord())
+	// 不带操作记录的路由组 - 用于查询类API
+	fileRouterWithoutRecord := Router.Group("docker")
+
+	// 需要记录操作的路由
+	{
+		fileRouter.POST("containers/:id/files/mkdir", dockerFileApi.MakeDir)     // 在容器中创建目录
+		fileRouter.POST("containers/:id/files/delete", dockerFileApi.DeleteFile) // 删除容器文件
+		fileRouter.POST("volumes/:name/files/mkdir", dockerFileApi.MakeDir)      // 在存储卷中创建目录
+		fileRouter.POST("volumes/:name/files/delete", dockerFileApi.DeleteFile)  // 删除存储卷文件
+	}
+
+	// 传输文件的路由，由接口自行登记操作记录
+	{
+		fileRouterWithoutRecord.GET("containers/:id/files/download", dockerFileApi.DownloadFile) // 下载容器文件
+		fileRouterWithoutRecord.POST("containers/:id/files/upload", dockerFileApi.UploadFile)    // 上传文件到容器
+		fileRouterWithoutRecord.GET("volumes/:name/files/download", dockerFileApi.DownloadFile)  // 下载存储卷文件
+		fileRouterWithoutRecord.POST("volumes/:name/files/upload", dockerFileApi.UploadFile)     // 上传文件到存储卷
+	}
+
+	// 不需要记录操作的路由（查询类）
+	{
+		fileRouterWithoutRecord.GET("containers/:id/files", dockerFileApi.ListFiles)     // 列出容器目录
+		fileRouterWithoutRecord.GET("containers/:id/files/stat", dockerFileApi.StatFile) // 获取容器文件信息
+		fileRouterWithoutRecord.GET("volumes/:name/files", dockerFileApi.ListFiles)      // 列出存储卷目录
+		fileRouterWithoutRecord.GET("volumes/:name/files/stat", dockerFileApi.StatFile)  // 获取存储卷文件信息
+	}
+}
@@ -0,0 +1,36 @@
+package docker
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileTransferRoutesAreRecordedByHandler(t *testing.T) {
+	engine := newRecordTestRouter(t, (&DockerFileRouter{}).InitDockerFileRouter)
+
+	assert.Len(t, serveRecords(t, engine, jsonRequest(http.MethodPost, "/docker/containers/web/files/mkdir")), 1)
+
+	// 下载与上传只有接口登记的一条记录，内容为目标与路径而不是文件
+	records := serveRecords(t, engine, httptest.NewRequest(http.MethodGet, "/docker/containers/web/files/download?path=/etc/hosts", nil))
+	require.Len(t, records, 1)
+	assert.JSONEq(t, `{"container":"web","volume":"","path":"/etc/hosts"}`, records[0].Body)
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	require.NoError(t, form.WriteField("path", "/data"))
+	part, err := form.CreateFormFile("file", "app.conf")
+	require.NoError(t, err)
+	_, _ = part.Write([]byte("listen 80;"))
+	require.NoError(t, form.Close())
+	req := httptest.NewRequest(http.MethodPost, "/docker/volumes/conf/files/upload", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	records = serveRecords(t, engine, req)
+	require.Len(t, records, 1)
+	assert.JSONEq(t, `{"container":"","volume":"conf","path":"/data/app.conf"}`, records[0].Body)
+}
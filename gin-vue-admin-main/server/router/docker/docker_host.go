@@ -0,0 +1,35 @@
+package docker
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/api/v1/docker"
+	"github.com/flipped-aurora/gin-vue-admin/server/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+var dockerHostApi = docker.DockerHostApi{}
+
+type DockerHostRouter struct{}
+
+// InitDockerHostRouter 初始化Docker主机管理路由
+func (d *DockerHostRouter) InitDockerHostRouter(Router *gin.RouterGroup) {
+	// 带操作记录的路由组 - 用于需要记录操作日志的API
+	hostRouter := Router.Group("docker/hosts").Use(middleware.OperationRecord())
+	// 不带操作记录的路由组 - 用于查询类API
+	hostRouterWithoutRecord := Router.Group("docker/hosts")
+	connectionRouter := Router.Group("docker/connection")
+
+	// 需要记录操作的路由
+	{
+		hostRouter.POST("", dockerHostApi.CreateDockerHost)       // 添加Docker主机
+		hostRouter.PUT("", dockerHostApi.UpdateDockerHost)        // 更新Docker主机
+		hostRouter.DELETE(":id", dockerHostApi.DeleteDockerHost)  // 删除Docker主机
+		hostRouter.POST(":id/test", dockerHostApi.TestDockerHost) // 测试Docker主机连接
+	}
+
+	// 不需要记录操作的路由（查询类）
+	{
+		hostRouterWithoutRecord.GET("", dockerHostApi.GetDockerHostList) // 获取Docker主机列表
+		connectionRouter.GET("", dockerHostApi.GetConnectionStatus)      // 获取默认主机连接状态
+		connectionRouter.POST("check", dockerHostApi.CheckConnection)    // 立即检查默认主机连接
+	}
+}
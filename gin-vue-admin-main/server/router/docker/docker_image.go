@@ -9,6 +9,12 @@ import (
 // 在文件顶部添加dockerImageApi变量定义
 var dockerImageApi = docker.DockerImageApi{}
 
+var dockerImageJobApi = docker.DockerImageJobApi{}
+
+var dockerImageInspectApi = docker.DockerImageInspectApi{}
+
+var dockerImageRetentionApi = docker.DockerImageRetentionApi{}
+
 type DockerImageRouter struct{}
 
 // InitDockerImageRouter 初始化Docker镜像路由
@@ -22,17 +28,56 @@ func (d *DockerImageRouter) InitDockerImageRouter(Router *gin.RouterGroup) {
 	// 需要记录操作的路由（镜像操作）
 	{
 		dockerRouter.POST("images/pull", dockerImageApi.PullImage)     // 拉取镜像
+		dockerRouter.POST("images/push", dockerImageApi.PushImage)     // 推送镜像
 		dockerRouter.DELETE("images/:id", dockerImageApi.RemoveImage)  // 删除镜像
 		dockerRouter.POST("images/tag", dockerImageApi.TagImage)       // 给镜像打标签
 		dockerRouter.POST("images/prune", dockerImageApi.PruneImages)  // 清理未使用的镜像
 		dockerRouter.POST("images/build", dockerImageApi.BuildImage)   // 构建镜像
-		dockerRouter.POST("images/export", dockerImageApi.ExportImage) // 导出镜像
 		dockerRouter.POST("images/import", dockerImageApi.ImportImage) // 导入镜像
+
+		dockerRouter.POST("images/jobs/pull", dockerImageJobApi.SubmitPullJob)        // 提交后台拉取任务
+		dockerRouter.POST("images/jobs/build", dockerImageJobApi.SubmitBuildJob)      // 提交后台构建任务
+		dockerRouter.POST("images/jobs/:id/cancel", dockerImageJobApi.CancelImageJob) // 取消任务
+		dockerRouter.DELETE("images/jobs/:id", dockerImageJobApi.DeleteImageJob)      // 删除任务记录
+
+		dockerRouter.POST("images/inspect", dockerImageInspectApi.InspectImage)                   // 提交镜像内容检查
+		dockerRouter.POST("images/reports/:id/rematch", dockerImageInspectApi.RematchImageReport) // 重新匹配漏洞
+		dockerRouter.DELETE("images/reports/:id", dockerImageInspectApi.DeleteImageReport)        // 删除检查报告
+		dockerRouter.POST("vulndb/import", dockerImageInspectApi.ImportVulnDB)                    // 导入离线漏洞库
+		dockerRouter.DELETE("vulndb/:source", dockerImageInspectApi.DeleteVulnDBSource)           // 删除漏洞库来源
+
+		dockerRouter.POST("images/retention/policies", dockerImageRetentionApi.CreateRetentionPolicy)       // 创建镜像保留策略
+		dockerRouter.PUT("images/retention/policies", dockerImageRetentionApi.UpdateRetentionPolicy)        // 更新镜像保留策略
+		dockerRouter.DELETE("images/retention/policies/:id", dockerImageRetentionApi.DeleteRetentionPolicy) // 删除镜像保留策略
+		dockerRouter.POST("images/retention/policies/:id/run", dockerImageRetentionApi.RunRetentionPolicy)  // 立即执行镜像保留策略
+	}
+
+	// 以流式传输镜像包的路由不经过操作记录，该中间件会把整个请求体与响应缓存在内存中
+	{
+		dockerRouterWithoutRecord.POST("images/build/context", dockerImageApi.UploadBuildContext) // 上传构建上下文
+		dockerRouterWithoutRecord.POST("images/export", dockerImageApi.ExportImage)               // 导出镜像
+		dockerRouterWithoutRecord.POST("images/import/upload", dockerImageApi.ImportImageUpload)  // 上传并导入镜像
+		dockerRouterWithoutRecord.POST("images/import/chunk", dockerImageApi.UploadImportChunk)   // 分片上传镜像包
 	}
 
 	// 不需要记录操作的路由（查询类）
 	{
 		dockerRouterWithoutRecord.GET("images", dockerImageApi.GetImageList)       // 获取镜像列表
 		dockerRouterWithoutRecord.GET("images/:id", dockerImageApi.GetImageDetail) // 获取镜像详情
+
+		dockerRouterWithoutRecord.GET("images/jobs", dockerImageJobApi.GetImageJobList)           // 获取任务列表
+		dockerRouterWithoutRecord.GET("images/jobs/:id", dockerImageJobApi.GetImageJob)           // 获取任务进度
+		dockerRouterWithoutRecord.GET("images/jobs/:id/log", dockerImageJobApi.GetImageJobLog)    // 获取任务日志
+		dockerRouterWithoutRecord.GET("images/jobs/:id/stream", dockerImageJobApi.StreamImageJob) // 实时推送任务进度
+
+		dockerRouterWithoutRecord.GET("images/reports", dockerImageInspectApi.GetImageReportList)          // 获取检查报告列表
+		dockerRouterWithoutRecord.GET("images/reports/compare", dockerImageInspectApi.CompareImageReports) // 对比检查报告
+		dockerRouterWithoutRecord.GET("images/reports/:id", dockerImageInspectApi.GetImageReport)          // 获取检查报告详情
+		dockerRouterWithoutRecord.GET("vulndb", dockerImageInspectApi.GetVulnDBStats)                      // 获取漏洞库统计
+
+		dockerRouterWithoutRecord.GET("images/retention/policies", dockerImageRetentionApi.GetRetentionPolicyList)             // 获取镜像保留策略列表
+		dockerRouterWithoutRecord.GET("images/retention/policies/:id/preview", dockerImageRetentionApi.PreviewRetentionPolicy) // 预览镜像保留策略
+		dockerRouterWithoutRecord.POST("images/retention/preview", dockerImageRetentionApi.PreviewRetention)                   // 预览未保存的保留策略
+		dockerRouterWithoutRecord.GET("images/retention/runs", dockerImageRetentionApi.GetRetentionRunList)                    // 获取保留策略执行记录
 	}
 }
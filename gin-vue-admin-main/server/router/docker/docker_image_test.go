@@ -0,0 +1,75 @@
+package docker
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/client"
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// newRecordTestRouter 返回只注册了待测路由的引擎：操作记录写入临时数据库，
+// Docker 请求都发往返回错误的模拟守护进程，接口在调用 Docker 失败后即返回
+func newRecordTestRouter(t *testing.T, register func(*gin.RouterGroup)) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	oldLog, oldDB := global.GVA_LOG, global.GVA_DB
+	global.GVA_LOG = zap.NewNop()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "record.db")), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&system.SysOperationRecord{}))
+	global.GVA_DB = db
+
+	daemon := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(`{"message":"unavailable"}`))
+	}))
+	cli, err := client.NewClientWithOpts(client.WithHost("tcp://"+daemon.Listener.Addr().String()), client.WithVersion("1.41"))
+	require.NoError(t, err)
+	oldCli, oldStream := global.SetDocker(cli, cli)
+	t.Cleanup(func() {
+		global.SetDocker(oldCli, oldStream)
+		cli.Close()
+		daemon.Close()
+		global.GVA_LOG, global.GVA_DB = oldLog, oldDB
+	})
+
+	engine := gin.New()
+	register(engine.Group(""))
+	return engine
+}
+
+// serveRecords 发送请求，返回该路径生成的操作记录
+func serveRecords(t *testing.T, engine *gin.Engine, req *http.Request) []system.SysOperationRecord {
+	engine.ServeHTTP(httptest.NewRecorder(), req)
+
+	var records []system.SysOperationRecord
+	require.NoError(t, global.GVA_DB.Where("path = ?", req.URL.Path).Find(&records).Error)
+	return records
+}
+
+func jsonRequest(method, target string) *http.Request {
+	req := httptest.NewRequest(method, target, strings.NewReader("{}"))
+	req.Header.Set("Content-Type", "application/json")
+	return req
+}
+
+func TestImageStreamingRoutesAreNotRecorded(t *testing.T) {
+	engine := newRecordTestRouter(t, (&DockerImageRouter{}).InitDockerImageRouter)
+
+	assert.Len(t, serveRecords(t, engine, jsonRequest(http.MethodPost, "/docker/images/tag")), 1)
+	assert.Empty(t, serveRecords(t, engine, jsonRequest(http.MethodPost, "/docker/images/build/context")))
+	assert.Empty(t, serveRecords(t, engine, jsonRequest(http.MethodPost, "/docker/images/export")))
+	assert.Empty(t, serveRecords(t, engine, jsonRequest(http.MethodPost, "/docker/images/import/upload")))
+	assert.Empty(t, serveRecords(t, engine, jsonRequest(http.MethodPost, "/docker/images/import/chunk")))
+}
@@ -0,0 +1,22 @@
+package docker
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/api/v1/docker"
+	"github.com/flipped-aurora/gin-vue-admin/server/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+var dockerMetricsApi = docker.DockerMetricsApi{}
+
+type DockerMetricsRouter struct{}
+
+// InitDockerMetricsRouter 初始化Docker历史指标路由，只采集默认主机的指标
+func (d *DockerMetricsRouter) InitDockerMetricsRouter(Router *gin.RouterGroup) {
+	// 历史指标均为查询类，不记录操作日志
+	dockerRouterWithoutRecord := Router.Group("docker").Use(middleware.DefaultDockerHostOnly())
+	{
+		dockerRouterWithoutRecord.GET("metrics/host", dockerMetricsApi.GetHostMetrics)                   // 获取主机历史指标
+		dockerRouterWithoutRecord.GET("metrics/containers", dockerMetricsApi.GetContainerMetricsSummary) // 获取容器资源使用汇总
+		dockerRouterWithoutRecord.GET("metrics/containers/:id", dockerMetricsApi.GetContainerMetrics)    // 获取容器历史指标
+	}
+}
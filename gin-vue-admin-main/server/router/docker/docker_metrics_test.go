@@ -0,0 +1,33 @@
+package docker
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultHostOnlyRoutesRejectOtherHosts(t *testing.T) {
+	engine := newRecordTestRouter(t, func(group *gin.RouterGroup) {
+		// 代替 DockerHost 中间件，直接使用 hostId 作为选中的主机
+		group.Use(func(c *gin.Context) {
+			id, _ := strconv.Atoi(c.Query("hostId"))
+			c.Set("dockerHostId", uint(id))
+		})
+		(&DockerMetricsRouter{}).InitDockerMetricsRouter(group)
+		(&DockerAlertRouter{}).InitDockerAlertRouter(group)
+	})
+
+	for _, path := range []string{"/docker/metrics/host", "/docker/alerts/rules"} {
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path+"?hostId=2", nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code, path)
+
+		w = httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path+"?hostId=0", nil))
+		assert.Equal(t, http.StatusOK, w.Code, path)
+	}
+}
@@ -11,7 +11,7 @@ type DockerNetworkRouter struct{}
 // InitDockerNetworkRouter 初始化Docker网络路由
 func (d *DockerNetworkRouter) InitDockerNetworkRouter(Router *gin.RouterGroup) {
 	dockerNetworkApi := api.DockerNetworkApi{}
-	
+
 	// 带操作记录的路由组 - 用于需要记录操作日志的API
 	networkRouter := Router.Group("docker").Use(middleware.OperationRecord())
 	// 不带操作记录的路由组 - 用于查询类API
@@ -19,9 +19,11 @@ func (d *DockerNetworkRouter) InitDockerNetworkRouter(Router *gin.RouterGroup) {
 
 	// 需要记录操作的路由（网络操作）
 	{
-		networkRouter.POST("networks", dockerNetworkApi.CreateNetwork)           // 创建网络
-		networkRouter.DELETE("networks/:id", dockerNetworkApi.RemoveNetwork)    // 删除网络
-		networkRouter.POST("networks/prune", dockerNetworkApi.PruneNetworks)    // 清理未使用的网络
+		networkRouter.POST("networks", dockerNetworkApi.CreateNetwork)                      // 创建网络
+		networkRouter.DELETE("networks/:id", dockerNetworkApi.RemoveNetwork)                // 删除网络
+		networkRouter.POST("networks/prune", dockerNetworkApi.PruneNetworks)                // 清理未使用的网络
+		networkRouter.POST("networks/:id/connect", dockerNetworkApi.ConnectContainer)       // 将容器连接到网络
+		networkRouter.POST("networks/:id/disconnect", dockerNetworkApi.DisconnectContainer) // 将容器从网络断开
 	}
 
 	// 不需要记录操作的路由（查询类）
@@ -24,6 +24,7 @@ func (d *DockerRegistryRouter) InitDockerRegistryRouter(Router *gin.RouterGroup)
 		registryRouter.DELETE("registries/:id", dockerRegistryApi.DeleteRegistry)             // 删除仓库
 		registryRouter.POST("registries/:id/test", dockerRegistryApi.TestRegistry)            // 测试仓库连接
 		registryRouter.POST("registries/:id/default", dockerRegistryApi.SetDefaultRegistry)   // 设置默认仓库
+		registryRouter.DELETE("registries/default", dockerRegistryApi.ClearDefaultRegistry)   // 取消默认仓库
 	}
 
 	// 不需要记录操作的路由（查询类）
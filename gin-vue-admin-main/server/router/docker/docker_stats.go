@@ -0,0 +1,22 @@
+package docker
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/api/v1/docker"
+	"github.com/gin-gonic/gin"
+)
+
+var dockerStatsApi = docker.DockerStatsApi{}
+
+type DockerStatsRouter struct{}
+
+// InitDockerStatsRouter 初始化Docker资源统计路由
+func (d *DockerStatsRouter) InitDockerStatsRouter(Router *gin.RouterGroup) {
+	// 资源统计均为查询类，不记录操作日志
+	dockerRouterWithoutRecord := Router.Group("docker")
+	{
+		dockerRouterWithoutRecord.GET("stats", dockerStatsApi.GetAllContainerStats)                       // 获取所有容器资源统计
+		dockerRouterWithoutRecord.GET("stats/stream", dockerStatsApi.StreamAllContainerStats)             // 实时推送所有容器资源统计
+		dockerRouterWithoutRecord.GET("containers/:id/stats", dockerStatsApi.GetContainerStats)           // 获取容器资源统计
+		dockerRouterWithoutRecord.GET("containers/:id/stats/stream", dockerStatsApi.StreamContainerStats) // 实时推送容器资源统计
+	}
+}
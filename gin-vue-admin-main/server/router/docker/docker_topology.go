@@ -0,0 +1,21 @@
+package docker
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/api/v1/docker"
+	"github.com/gin-gonic/gin"
+)
+
+var dockerTopologyApi = docker.DockerTopologyApi{}
+
+type DockerTopologyRouter struct{}
+
+// InitDockerTopologyRouter 初始化Docker拓扑关系图路由
+func (d *DockerTopologyRouter) InitDockerTopologyRouter(Router *gin.RouterGroup) {
+	// 不带操作记录的路由组 - 用于查询类API
+	topologyRouterWithoutRecord := Router.Group("docker")
+
+	// 不需要记录操作的路由（查询类）
+	{
+		topologyRouterWithoutRecord.GET("topology", dockerTopologyApi.GetTopology) // 获取拓扑关系图
+	}
+}
@@ -11,7 +11,7 @@ type DockerVolumeRouter struct{}
 // InitDockerVolumeRouter 初始化Docker存储卷路由
 func (d *DockerVolumeRouter) InitDockerVolumeRouter(Router *gin.RouterGroup) {
 	dockerVolumeApi := api.DockerVolumeApi{}
-	
+
 	// 带操作记录的路由组 - 用于需要记录操作日志的API
 	volumeRouter := Router.Group("docker").Use(middleware.OperationRecord())
 	// 不带操作记录的路由组 - 用于查询类API
@@ -19,14 +19,19 @@ func (d *DockerVolumeRouter) InitDockerVolumeRouter(Router *gin.RouterGroup) {
 
 	// 需要记录操作的路由（存储卷操作）
 	{
-		volumeRouter.POST("volumes", dockerVolumeApi.CreateVolume)           // 创建存储卷
-		volumeRouter.DELETE("volumes/:name", dockerVolumeApi.RemoveVolume)   // 删除存储卷
-		volumeRouter.POST("volumes/prune", dockerVolumeApi.PruneVolumes)     // 清理未使用的存储卷
+		volumeRouter.POST("volumes", dockerVolumeApi.CreateVolume)                    // 创建存储卷
+		volumeRouter.DELETE("volumes/:name", dockerVolumeApi.RemoveVolume)            // 删除存储卷
+		volumeRouter.POST("volumes/prune", dockerVolumeApi.PruneVolumes)              // 清理未使用的存储卷
+		volumeRouter.POST("volumes/:name/backup", dockerVolumeApi.BackupVolume)       // 备份存储卷
+		volumeRouter.POST("volume-backups/restore", dockerVolumeApi.RestoreVolume)    // 从备份恢复存储卷
+		volumeRouter.DELETE("volume-backups/:id", dockerVolumeApi.DeleteVolumeBackup) // 删除存储卷备份
 	}
 
 	// 不需要记录操作的路由（查询类）
 	{
-		volumeRouterWithoutRecord.GET("volumes", dockerVolumeApi.GetVolumeList)       // 获取存储卷列表
-		volumeRouterWithoutRecord.GET("volumes/:name", dockerVolumeApi.GetVolumeDetail) // 获取存储卷详情
+		volumeRouterWithoutRecord.GET("volumes", dockerVolumeApi.GetVolumeList)                            // 获取存储卷列表
+		volumeRouterWithoutRecord.GET("volumes/:name", dockerVolumeApi.GetVolumeDetail)                    // 获取存储卷详情
+		volumeRouterWithoutRecord.GET("volume-backups", dockerVolumeApi.GetVolumeBackupList)               // 获取存储卷备份列表
+		volumeRouterWithoutRecord.GET("volume-backups/:id/download", dockerVolumeApi.DownloadVolumeBackup) // 下载存储卷备份
 	}
-}
\ No newline at end of file
+}
@@ -15,6 +15,13 @@ type RouterGroup struct {
 	DockerConfigRouter
 	DockerOverviewRouter
 	DockerDiagnosticRouter
+	DockerStatsRouter
+	DockerMetricsRouter
+	DockerAlertRouter
+	DockerHostRouter
+	DockerEventRouter
+	DockerFileRouter
+	DockerTopologyRouter
 }
 
 // 适配 initialize/router.go 的调用，转发到 DockerRouter 的实现
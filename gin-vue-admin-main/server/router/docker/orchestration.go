@@ -2,6 +2,7 @@ package docker
 
 import (
 	api "github.com/flipped-aurora/gin-vue-admin/server/api/v1/docker"
+	"github.com/flipped-aurora/gin-vue-admin/server/middleware"
 	"github.com/gin-gonic/gin"
 )
 
@@ -17,4 +18,20 @@ func (d *DockerRouter) InitDockerOrchestrationRouter(Router *gin.RouterGroup) {
 	{
 		orchestrationRouter.GET("/list", dockerApi.GetOrchestrationList)
 	}
+
+	// 带操作记录的路由组 - 部署、删除等变更操作
+	orchestrationsRouter := Router.Group("docker").Use(middleware.OperationRecord())
+	// 不带操作记录的路由组 - 查询类操作
+	orchestrationsRouterWithoutRecord := Router.Group("docker")
+	{
+		orchestrationsRouter.POST("orchestrations", dockerApi.CreateOrchestration)                 // 创建并部署编排
+		orchestrationsRouter.PUT("orchestrations/:name", dockerApi.EditOrchestration)              // 更新并重新部署编排
+		orchestrationsRouter.DELETE("orchestrations/:name", dockerApi.DeleteOrchestration)         // 删除编排
+		orchestrationsRouter.POST("orchestrations/:name/:op", dockerApi.BatchOperateOrchestration) // 批量操作编排容器
+	}
+	{
+		orchestrationsRouterWithoutRecord.GET("orchestrations", dockerApi.GetOrchestrationList)             // 获取编排列表
+		orchestrationsRouterWithoutRecord.GET("orchestrations/:name", dockerApi.OrchestrationDetail)        // 获取编排详情
+		orchestrationsRouterWithoutRecord.GET("orchestrations/:name/status", dockerApi.OrchestrationStatus) // 获取编排状态
+	}
 }
@@ -3,9 +3,11 @@ package cion
 
 import (
 	"context"
+	"strings"
 	"github.com/flipped-aurora/gin-vue-admin/server/global"
 	"github.com/flipped-aurora/gin-vue-admin/server/model/cion"
     cionReq "github.com/flipped-aurora/gin-vue-admin/server/model/cion/request"
+    cionRes "github.com/flipped-aurora/gin-vue-admin/server/model/cion/response"
     "gorm.io/gorm"
 )
 
@@ -72,6 +74,18 @@ func (imageService *ImageService)GetImageInfoList(ctx context.Context, info cion
     if len(info.CreatedAtRange) == 2 {
      db = db.Where("created_at BETWEEN ? AND ?", info.CreatedAtRange[0], info.CreatedAtRange[1])
     }
+    if info.ImageId != "" {
+        db = db.Where("image_id LIKE ?", strings.TrimPrefix(info.ImageId, "sha256:")+"%")
+    }
+    if info.Repository != "" {
+        db = db.Where("repository LIKE ?", "%"+info.Repository+"%")
+    }
+    if info.Tag != "" {
+        db = db.Where("tag = ?", info.Tag)
+    }
+    if info.Status != "" {
+        db = db.Where("status = ?", info.Status)
+    }
     
 	err = db.Count(&total).Error
 	if err!=nil {
@@ -82,10 +96,11 @@ func (imageService *ImageService)GetImageInfoList(ctx context.Context, info cion
        db = db.Limit(limit).Offset(offset)
     }
 
-	err = db.Find(&images).Error
+	err = db.Order("last_seen_at desc, id desc").Find(&images).Error
 	return  images, total, err
 }
-func (imageService *ImageService)GetImagePublic(ctx context.Context) {
-    // 此方法为获取数据源定义的数据
-    // 请自行实现
+
+// GetImagePublic 获取镜像库存概况，只返回数量统计，不暴露镜像明细
+func (imageService *ImageService)GetImagePublic(ctx context.Context) (cionRes.ImageInventorySummary, error) {
+    return imageService.GetImageInventorySummary(ctx)
 }
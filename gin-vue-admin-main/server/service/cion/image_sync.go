@@ -0,0 +1,303 @@
+package cion
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/cion"
+	cionRes "github.com/flipped-aurora/gin-vue-admin/server/model/cion/response"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// imageSyncTimeout 单次同步的超时时间
+const imageSyncTimeout = 2 * time.Minute
+
+// untaggedName 悬空镜像的仓库名与标签
+const untaggedName = "<none>"
+
+// imageSyncState 同步互斥与最近一次同步时间
+var imageSyncState struct {
+	sync.Mutex
+	lastSyncedAt *time.Time
+}
+
+// imageObservation 一次同步中观察到的镜像标签
+type imageObservation struct {
+	imageID    string
+	repository string
+	tag        string
+	size       int64
+	created    time.Time
+	digest     string
+}
+
+// key 记录的唯一标识：同一镜像的每个标签一条记录，标签指向新镜像时旧记录标记为移除
+func (o imageObservation) key() string {
+	return o.imageID + "|" + o.repository + "|" + o.tag
+}
+
+// SyncImages 将Docker中的镜像与 image 表对账：新镜像写入首次发现时间，仍存在的更新最后发现时间，
+// 不再存在的标记为已移除，保留跨清理操作的库存历史
+func (imageService *ImageService) SyncImages(ctx context.Context) (result cionRes.ImageSyncResult, err error) {
+	cli := global.GetDocker()
+	if cli == nil {
+		return result, fmt.Errorf("Docker client is not available")
+	}
+	imageSyncState.Lock()
+	defer imageSyncState.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, imageSyncTimeout)
+	defer cancel()
+
+	summaries, err := cli.ImageList(ctx, types.ImageListOptions{})
+	if err != nil {
+		return result, fmt.Errorf("failed to get image list: %v", err)
+	}
+
+	observed := make(map[string]imageObservation)
+	ids := make([]string, 0, len(summaries))
+	for _, summary := range summaries {
+		id := shortImageID(summary.ID)
+		ids = append(ids, id)
+		refs := summary.RepoTags
+		if len(refs) == 0 {
+			refs = []string{untaggedName + ":" + untaggedName}
+		}
+		for _, ref := range refs {
+			repository, tag := splitImageRef(ref)
+			obs := imageObservation{
+				imageID:    id,
+				repository: repository,
+				tag:        tag,
+				size:       summary.Size,
+				created:    time.Unix(summary.Created, 0),
+				digest:     repoDigest(repository, summary.RepoDigests),
+			}
+			observed[obs.key()] = obs
+		}
+	}
+
+	var rows []cion.Image
+	if err = global.GVA_DB.Where("status = ? OR image_id IN ?", cion.ImageStatusPresent, ids).Order("id").Find(&rows).Error; err != nil {
+		return result, err
+	}
+	existing := make(map[string]cion.Image, len(rows))
+	for _, row := range rows {
+		existing[row.ImageId+"|"+row.Repository+"|"+row.Tag] = row
+	}
+
+	now := time.Now()
+	architectures := make(map[string]string)
+	err = global.GVA_DB.Transaction(func(tx *gorm.DB) error {
+		var unchanged []uint
+		for key, obs := range observed {
+			row, ok := existing[key]
+			if !ok {
+				record := cion.Image{
+					ImageId:      obs.imageID,
+					Repository:   obs.repository,
+					Tag:          obs.tag,
+					Size:         formatImageSize(obs.size),
+					SizeBytes:    obs.size,
+					CreatedTime:  obs.created,
+					Architecture: imageArchitecture(ctx, obs.imageID, architectures),
+					Digest:       obs.digest,
+					Status:       cion.ImageStatusPresent,
+					FirstSeenAt:  &now,
+					LastSeenAt:   &now,
+				}
+				if err := tx.Create(&record).Error; err != nil {
+					return err
+				}
+				result.Added++
+				continue
+			}
+
+			if row.Status == cion.ImageStatusPresent && row.SizeBytes == obs.size && row.Digest == obs.digest && row.Architecture != "" {
+				unchanged = append(unchanged, row.ID)
+				result.Updated++
+				continue
+			}
+			updates := map[string]interface{}{
+				"size":         formatImageSize(obs.size),
+				"size_bytes":   obs.size,
+				"digest":       obs.digest,
+				"created_time": obs.created,
+				"status":       cion.ImageStatusPresent,
+				"last_seen_at": now,
+				"removed_at":   nil,
+			}
+			if row.FirstSeenAt == nil {
+				updates["first_seen_at"] = now
+			}
+			if row.Architecture == "" {
+				updates["architecture"] = imageArchitecture(ctx, obs.imageID, architectures)
+			}
+			if err := tx.Model(&cion.Image{}).Where("id = ?", row.ID).Updates(updates).Error; err != nil {
+				return err
+			}
+			if row.Status == cion.ImageStatusRemoved {
+				result.Restored++
+			} else {
+				result.Updated++
+			}
+		}
+
+		if len(unchanged) > 0 {
+			if err := tx.Model(&cion.Image{}).Where("id IN ?", unchanged).Update("last_seen_at", now).Error; err != nil {
+				return err
+			}
+		}
+
+		var removed []uint
+		for key, row := range existing {
+			if _, ok := observed[key]; !ok && row.Status == cion.ImageStatusPresent {
+				removed = append(removed, row.ID)
+			}
+		}
+		if len(removed) > 0 {
+			if err := tx.Model(&cion.Image{}).Where("id IN ?", removed).Updates(map[string]interface{}{
+				"status":     cion.ImageStatusRemoved,
+				"removed_at": now,
+			}).Error; err != nil {
+				return err
+			}
+		}
+		result.Removed = len(removed)
+		return nil
+	})
+	if err != nil {
+		global.GVA_LOG.Error("Failed to sync image inventory", zap.Error(err))
+		return result, err
+	}
+
+	result.Total = len(observed)
+	result.SyncedAt = now
+	imageSyncState.lastSyncedAt = &now
+	if result.Added > 0 || result.Removed > 0 || result.Restored > 0 {
+		global.GVA_LOG.Info("Image inventory synced", zap.Int("total", result.Total), zap.Int("added", result.Added),
+			zap.Int("restored", result.Restored), zap.Int("removed", result.Removed))
+	}
+	return result, nil
+}
+
+// RecordImagePull 记录镜像的拉取者：先同步库存，再将拉取的标签记为该用户拉取
+func (imageService *ImageService) RecordImagePull(ctx context.Context, ref string, userID uint) error {
+	if _, err := imageService.SyncImages(ctx); err != nil {
+		return err
+	}
+	inspect, _, err := global.GetDocker().ImageInspectWithRaw(ctx, ref)
+	if err != nil {
+		return fmt.Errorf("image not found: %s", ref)
+	}
+
+	var rows []cion.Image
+	if err := global.GVA_DB.Where("image_id = ? AND status = ?", shortImageID(inspect.ID), cion.ImageStatusPresent).Find(&rows).Error; err != nil {
+		return err
+	}
+	// 优先只标记拉取的标签，按摘要拉取等找不到对应标签时标记该镜像的全部标签
+	var ids, all []uint
+	for _, row := range rows {
+		all = append(all, row.ID)
+		if familiarImageRef(row.Repository+":"+row.Tag) == familiarImageRef(ref) {
+			ids = append(ids, row.ID)
+		}
+	}
+	if len(ids) == 0 {
+		ids = all
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	return global.GVA_DB.Model(&cion.Image{}).Where("id IN ?", ids).Updates(map[string]interface{}{
+		"pulled_by": userID,
+		"pulled_at": time.Now(),
+	}).Error
+}
+
+// GetImageInventorySummary 获取镜像库存概况
+func (imageService *ImageService) GetImageInventorySummary(ctx context.Context) (summary cionRes.ImageInventorySummary, err error) {
+	err = global.GVA_DB.Model(&cion.Image{}).Where("status = ?", cion.ImageStatusPresent).Count(&summary.Present).Error
+	if err != nil {
+		return
+	}
+	err = global.GVA_DB.Model(&cion.Image{}).Where("status = ?", cion.ImageStatusRemoved).Count(&summary.Removed).Error
+	imageSyncState.Lock()
+	summary.LastSyncedAt = imageSyncState.lastSyncedAt
+	imageSyncState.Unlock()
+	return
+}
+
+// imageArchitecture 查询镜像架构，同一次同步中按镜像ID缓存
+func imageArchitecture(ctx context.Context, imageID string, cache map[string]string) string {
+	if arch, ok := cache[imageID]; ok {
+		return arch
+	}
+	arch := ""
+	if inspect, _, err := global.GetDocker().ImageInspectWithRaw(ctx, "sha256:"+imageID); err == nil {
+		arch = inspect.Architecture
+		if inspect.Variant != "" {
+			arch += "/" + inspect.Variant
+		}
+	}
+	cache[imageID] = arch
+	return arch
+}
+
+// shortImageID 去掉镜像ID的 sha256: 前缀，与 image_id 列长度一致
+func shortImageID(id string) string {
+	return strings.TrimPrefix(id, "sha256:")
+}
+
+// splitImageRef 拆分仓库名与标签，仓库地址中的端口不视为标签
+func splitImageRef(ref string) (string, string) {
+	if i := strings.LastIndex(ref, ":"); i > strings.LastIndex(ref, "/") {
+		return ref[:i], ref[i+1:]
+	}
+	return ref, "latest"
+}
+
+// repoDigest 取与仓库名对应的摘要
+func repoDigest(repository string, repoDigests []string) string {
+	for _, digest := range repoDigests {
+		if name, sum, ok := strings.Cut(digest, "@"); ok && name == repository {
+			return sum
+		}
+	}
+	return ""
+}
+
+// familiarImageRef 将镜像引用转换为 Docker 显示的简写形式，如 docker.io/library/nginx -> nginx:latest
+func familiarImageRef(ref string) string {
+	for _, prefix := range []string{"docker.io/", "index.docker.io/", "registry-1.docker.io/"} {
+		ref = strings.TrimPrefix(ref, prefix)
+	}
+	ref = strings.TrimPrefix(ref, "library/")
+	if strings.Contains(ref, "@") {
+		return ref
+	}
+	repository, tag := splitImageRef(ref)
+	return repository + ":" + tag
+}
+
+// formatImageSize 格式化镜像大小
+func formatImageSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	value := float64(size)
+	for _, suffix := range []string{"KB", "MB", "GB"} {
+		value /= unit
+		if value < unit {
+			return fmt.Sprintf("%.2f %s", value, suffix)
+		}
+	}
+	return fmt.Sprintf("%.2f TB", value/unit)
+}
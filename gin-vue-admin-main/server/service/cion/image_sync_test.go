@@ -0,0 +1,38 @@
+package cion
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitImageRef(t *testing.T) {
+	cases := map[string][2]string{
+		"nginx:1.25":                   {"nginx", "1.25"},
+		"registry.local:5000/app":      {"registry.local:5000/app", "latest"},
+		"registry.local:5000/app:v1.2": {"registry.local:5000/app", "v1.2"},
+		"<none>:<none>":                {"<none>", "<none>"},
+	}
+	for ref, want := range cases {
+		repository, tag := splitImageRef(ref)
+		assert.Equal(t, want[0], repository, ref)
+		assert.Equal(t, want[1], tag, ref)
+	}
+}
+
+func TestFamiliarImageRef(t *testing.T) {
+	assert.Equal(t, "nginx:latest", familiarImageRef("docker.io/library/nginx"))
+	assert.Equal(t, "nginx:latest", familiarImageRef("nginx:latest"))
+	assert.Equal(t, "bitnami/redis:7.2", familiarImageRef("docker.io/bitnami/redis:7.2"))
+	assert.Equal(t, "registry.local:5000/app:latest", familiarImageRef("registry.local:5000/app"))
+}
+
+func TestRepoDigestAndSize(t *testing.T) {
+	digests := []string{"other@sha256:aaa", "nginx@sha256:bbb"}
+	assert.Equal(t, "sha256:bbb", repoDigest("nginx", digests))
+	assert.Equal(t, "", repoDigest("redis", digests))
+
+	assert.Equal(t, "512 B", formatImageSize(512))
+	assert.Equal(t, "1.50 KB", formatImageSize(1536))
+	assert.Equal(t, "187.00 MB", formatImageSize(187*1024*1024))
+}
@@ -0,0 +1,382 @@
+package docker
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	dockerModel "github.com/flipped-aurora/gin-vue-admin/server/model/docker"
+	dockerReq "github.com/flipped-aurora/gin-vue-admin/server/model/docker/request"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultAlertRestartThreshold = 3  // 重启规则默认次数
+	defaultAlertRestartWindow    = 10 // 重启规则默认统计窗口（分钟）
+)
+
+var alertRuleTypes = map[string]bool{
+	dockerModel.AlertRuleContainerExited:     true,
+	dockerModel.AlertRuleContainerUnhealthy:  true,
+	dockerModel.AlertRuleContainerRestarting: true,
+	dockerModel.AlertRuleContainerCPU:        true,
+	dockerModel.AlertRuleContainerMemory:     true,
+	dockerModel.AlertRuleHostCPU:             true,
+	dockerModel.AlertRuleHostMemory:          true,
+	dockerModel.AlertRuleDiskUsage:           true,
+	dockerModel.AlertRuleDaemonUnreachable:   true,
+}
+
+var alertChannelTypes = map[string]bool{
+	dockerModel.AlertChannelEmail:    true,
+	dockerModel.AlertChannelDingTalk: true,
+	dockerModel.AlertChannelFeishu:   true,
+	dockerModel.AlertChannelSlack:    true,
+	dockerModel.AlertChannelWebhook:  true,
+}
+
+var alertSeverities = map[string]bool{"info": true, "warning": true, "critical": true}
+
+type DockerAlertService struct{}
+
+// GetAlertRuleList 获取告警规则列表
+func (d *DockerAlertService) GetAlertRuleList() ([]dockerModel.DockerAlertRule, error) {
+	var rules []dockerModel.DockerAlertRule
+	if err := global.GVA_DB.Order("id asc").Find(&rules).Error; err != nil {
+		return nil, fmt.Errorf("failed to query alert rules: %v", err)
+	}
+	return rules, nil
+}
+
+// CreateAlertRule 创建告警规则
+func (d *DockerAlertService) CreateAlertRule(req dockerReq.AlertRuleRequest) (*dockerModel.DockerAlertRule, error) {
+	rule := dockerModel.DockerAlertRule{}
+	if err := applyAlertRuleRequest(&rule, req); err != nil {
+		return nil, err
+	}
+	if err := global.GVA_DB.Create(&rule).Error; err != nil {
+		return nil, fmt.Errorf("failed to create alert rule: %v", err)
+	}
+	return &rule, nil
+}
+
+// UpdateAlertRule 更新告警规则，类型或匹配对象变化时结束该规则下未恢复的事件
+func (d *DockerAlertService) UpdateAlertRule(req dockerReq.AlertRuleRequest) (*dockerModel.DockerAlertRule, error) {
+	var rule dockerModel.DockerAlertRule
+	if err := global.GVA_DB.First(&rule, req.ID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("alert rule not found")
+		}
+		return nil, fmt.Errorf("failed to query alert rule: %v", err)
+	}
+
+	oldType, oldTarget := rule.Type, rule.Target
+	if err := applyAlertRuleRequest(&rule, req); err != nil {
+		return nil, err
+	}
+
+	err := global.GVA_DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&rule).Error; err != nil {
+			return err
+		}
+		if oldType != rule.Type || oldTarget != rule.Target || !rule.Enabled {
+			return resolveRuleEvents(tx, rule.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update alert rule: %v", err)
+	}
+	return &rule, nil
+}
+
+// DeleteAlertRule 删除告警规则并结束其未恢复的事件
+func (d *DockerAlertService) DeleteAlertRule(id uint) error {
+	err := global.GVA_DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&dockerModel.DockerAlertRule{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return resolveRuleEvents(tx, id)
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("alert rule not found")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete alert rule: %v", err)
+	}
+	return nil
+}
+
+// GetAlertChannelList 获取通知渠道列表，不返回加签密钥
+func (d *DockerAlertService) GetAlertChannelList() ([]dockerModel.DockerAlertChannel, error) {
+	var channels []dockerModel.DockerAlertChannel
+	if err := global.GVA_DB.Order("id asc").Find(&channels).Error; err != nil {
+		return nil, fmt.Errorf("failed to query alert channels: %v", err)
+	}
+	for i := range channels {
+		channels[i].Secret = ""
+	}
+	return channels, nil
+}
+
+// CreateAlertChannel 创建通知渠道
+func (d *DockerAlertService) CreateAlertChannel(req dockerReq.AlertChannelRequest) (*dockerModel.DockerAlertChannel, error) {
+	if err := validateAlertChannel(req); err != nil {
+		return nil, err
+	}
+	channel := dockerModel.DockerAlertChannel{
+		Name:    req.Name,
+		Type:    req.Type,
+		Target:  strings.TrimSpace(req.Target),
+		Secret:  req.Secret,
+		Enabled: req.Enabled,
+	}
+	if err := global.GVA_DB.Create(&channel).Error; err != nil {
+		return nil, fmt.Errorf("failed to create alert channel: %v", err)
+	}
+	channel.Secret = ""
+	return &channel, nil
+}
+
+// UpdateAlertChannel 更新通知渠道，密钥为空时保持不变
+func (d *DockerAlertService) UpdateAlertChannel(req dockerReq.AlertChannelRequest) (*dockerModel.DockerAlertChannel, error) {
+	if err := validateAlertChannel(req); err != nil {
+		return nil, err
+	}
+	var channel dockerModel.DockerAlertChannel
+	if err := global.GVA_DB.First(&channel, req.ID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("alert channel not found")
+		}
+		return nil, fmt.Errorf("failed to query alert channel: %v", err)
+	}
+
+	channel.Name = req.Name
+	channel.Type = req.Type
+	channel.Target = strings.TrimSpace(req.Target)
+	channel.Enabled = req.Enabled
+	if req.Secret != "" {
+		channel.Secret = req.Secret
+	}
+	if err := global.GVA_DB.Save(&channel).Error; err != nil {
+		return nil, fmt.Errorf("failed to update alert channel: %v", err)
+	}
+	channel.Secret = ""
+	return &channel, nil
+}
+
+// DeleteAlertChannel 删除通知渠道，已引用该渠道的规则在发送时忽略
+func (d *DockerAlertService) DeleteAlertChannel(id uint) error {
+	result := global.GVA_DB.Delete(&dockerModel.DockerAlertChannel{}, id)
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete alert channel: %v", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("alert channel not found")
+	}
+	return nil
+}
+
+// TestAlertChannel 通过渠道发送一条测试消息
+func (d *DockerAlertService) TestAlertChannel(id uint) error {
+	var channel dockerModel.DockerAlertChannel
+	if err := global.GVA_DB.First(&channel, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("alert channel not found")
+		}
+		return fmt.Errorf("failed to query alert channel: %v", err)
+	}
+
+	now := time.Now()
+	return sendAlertNotification(channel, alertNotification{
+		Status:     dockerModel.AlertStatusFiring,
+		RuleName:   "测试通知",
+		Severity:   "info",
+		ObjectName: channel.Name,
+		Message:    "这是一条测试消息，收到说明通知渠道配置正确",
+		StartsAt:   now,
+	})
+}
+
+// GetAlertSilenceList 获取未结束的静默窗口
+func (d *DockerAlertService) GetAlertSilenceList() ([]dockerModel.DockerAlertSilence, error) {
+	var silences []dockerModel.DockerAlertSilence
+	if err := global.GVA_DB.Where("ends_at > ?", time.Now()).Order("starts_at asc").Find(&silences).Error; err != nil {
+		return nil, fmt.Errorf("failed to query alert silences: %v", err)
+	}
+	return silences, nil
+}
+
+// CreateAlertSilence 创建静默窗口
+func (d *DockerAlertService) CreateAlertSilence(req dockerReq.AlertSilenceRequest, userID uint) (*dockerModel.DockerAlertSilence, error) {
+	if req.StartsAt.IsZero() {
+		req.StartsAt = time.Now()
+	}
+	if !req.EndsAt.After(req.StartsAt) {
+		return nil, fmt.Errorf("silence end time must be after start time")
+	}
+	if req.Target != "" {
+		if _, err := path.Match(req.Target, ""); err != nil {
+			return nil, fmt.Errorf("invalid target pattern: %v", err)
+		}
+	}
+	if req.RuleID != 0 {
+		var count int64
+		if err := global.GVA_DB.Model(&dockerModel.DockerAlertRule{}).Where("id = ?", req.RuleID).Count(&count).Error; err != nil {
+			return nil, fmt.Errorf("failed to query alert rule: %v", err)
+		}
+		if count == 0 {
+			return nil, fmt.Errorf("alert rule not found")
+		}
+	}
+
+	silence := dockerModel.DockerAlertSilence{
+		RuleID:    req.RuleID,
+		Target:    req.Target,
+		StartsAt:  req.StartsAt,
+		EndsAt:    req.EndsAt,
+		Comment:   req.Comment,
+		CreatedBy: userID,
+	}
+	if err := global.GVA_DB.Create(&silence).Error; err != nil {
+		return nil, fmt.Errorf("failed to create alert silence: %v", err)
+	}
+	return &silence, nil
+}
+
+// DeleteAlertSilence 删除静默窗口，提前结束静默
+func (d *DockerAlertService) DeleteAlertSilence(id uint) error {
+	result := global.GVA_DB.Delete(&dockerModel.DockerAlertSilence{}, id)
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete alert silence: %v", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("alert silence not found")
+	}
+	return nil
+}
+
+// GetAlertEventList 分页查询告警事件，按时间倒序
+func (d *DockerAlertService) GetAlertEventList(filter dockerReq.AlertEventFilter) ([]dockerModel.DockerAlertEvent, int64, error) {
+	db := global.GVA_DB.Model(&dockerModel.DockerAlertEvent{})
+	if filter.Status != "" {
+		db = db.Where("status = ?", filter.Status)
+	}
+	if filter.RuleID != 0 {
+		db = db.Where("rule_id = ?", filter.RuleID)
+	}
+	if filter.Object != "" {
+		db = db.Where("object_name LIKE ? OR object_id LIKE ?", "%"+filter.Object+"%", filter.Object+"%")
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count alert events: %v", err)
+	}
+
+	var events []dockerModel.DockerAlertEvent
+	offset := (filter.Page - 1) * filter.PageSize
+	if err := db.Order("id desc").Offset(offset).Limit(filter.PageSize).Find(&events).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to query alert events: %v", err)
+	}
+	return events, total, nil
+}
+
+// applyAlertRuleRequest 校验请求并写入规则，未填写的项使用默认值
+func applyAlertRuleRequest(rule *dockerModel.DockerAlertRule, req dockerReq.AlertRuleRequest) error {
+	if !alertRuleTypes[req.Type] {
+		return fmt.Errorf("unsupported alert rule type: %s", req.Type)
+	}
+	if req.Severity == "" {
+		req.Severity = "warning"
+	}
+	if !alertSeverities[req.Severity] {
+		return fmt.Errorf("unsupported severity: %s", req.Severity)
+	}
+	if req.Duration < 0 || req.RepeatInterval < 0 {
+		return fmt.Errorf("duration and repeat interval cannot be negative")
+	}
+	if req.Target != "" {
+		if _, err := path.Match(req.Target, ""); err != nil {
+			return fmt.Errorf("invalid target pattern: %v", err)
+		}
+	}
+
+	switch req.Type {
+	case dockerModel.AlertRuleContainerCPU, dockerModel.AlertRuleHostCPU:
+		// 容器CPU使用率按核数累加，可超过100
+		if req.Threshold <= 0 {
+			return fmt.Errorf("threshold must be greater than 0")
+		}
+	case dockerModel.AlertRuleContainerMemory, dockerModel.AlertRuleHostMemory, dockerModel.AlertRuleDiskUsage:
+		if req.Threshold <= 0 || req.Threshold > 100 {
+			return fmt.Errorf("threshold must be between 0 and 100")
+		}
+	case dockerModel.AlertRuleContainerRestarting:
+		if req.Threshold <= 0 {
+			req.Threshold = defaultAlertRestartThreshold
+		}
+		if req.Duration <= 0 {
+			req.Duration = defaultAlertRestartWindow
+		}
+	}
+
+	if len(req.ChannelIDs) > 0 {
+		var count int64
+		if err := global.GVA_DB.Model(&dockerModel.DockerAlertChannel{}).Where("id IN ?", req.ChannelIDs).Count(&count).Error; err != nil {
+			return fmt.Errorf("failed to query alert channels: %v", err)
+		}
+		if int(count) != len(req.ChannelIDs) {
+			return fmt.Errorf("alert channel not found")
+		}
+	}
+
+	rule.Name = req.Name
+	rule.Type = req.Type
+	rule.Target = req.Target
+	rule.Threshold = req.Threshold
+	rule.Duration = req.Duration
+	rule.RepeatInterval = req.RepeatInterval
+	rule.Severity = req.Severity
+	rule.Enabled = req.Enabled
+	rule.ChannelIDs = req.ChannelIDs
+	rule.Description = req.Description
+	return nil
+}
+
+// validateAlertChannel 校验通知渠道配置
+func validateAlertChannel(req dockerReq.AlertChannelRequest) error {
+	if !alertChannelTypes[req.Type] {
+		return fmt.Errorf("unsupported alert channel type: %s", req.Type)
+	}
+	target := strings.TrimSpace(req.Target)
+	if req.Type == dockerModel.AlertChannelEmail {
+		for _, addr := range strings.Split(target, ",") {
+			if !strings.Contains(strings.TrimSpace(addr), "@") {
+				return fmt.Errorf("invalid email address: %s", addr)
+			}
+		}
+		return nil
+	}
+	u, err := url.Parse(target)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid webhook url: %s", target)
+	}
+	return nil
+}
+
+// resolveRuleEvents 静默结束规则下未恢复的事件，不发送恢复通知
+func resolveRuleEvents(tx *gorm.DB, ruleID uint) error {
+	now := time.Now()
+	return tx.Model(&dockerModel.DockerAlertEvent{}).
+		Where("rule_id = ? AND status IN ?", ruleID, []string{dockerModel.AlertStatusPending, dockerModel.AlertStatusFiring}).
+		Updates(map[string]interface{}{"status": dockerModel.AlertStatusResolved, "resolved_at": now}).Error
+}
@@ -0,0 +1,574 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	dockerModel "github.com/flipped-aurora/gin-vue-admin/server/model/docker"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/docker/response"
+	"github.com/shirou/gopsutil/v3/cpu"
+	"github.com/shirou/gopsutil/v3/disk"
+	"github.com/shirou/gopsutil/v3/mem"
+	"go.uber.org/zap"
+)
+
+const (
+	// alertEvaluateTimeout 单轮评估的超时时间
+	alertEvaluateTimeout = 2 * time.Minute
+	// restartHistoryRetention 容器重启次数采样的保留时长，需覆盖重启规则的统计窗口
+	restartHistoryRetention = 24 * time.Hour
+)
+
+var exitCodePattern = regexp.MustCompile(`^Exited \((-?\d+)\)`)
+
+// restartSample 某一时刻容器的累计重启次数
+type restartSample struct {
+	at    time.Time
+	count int
+}
+
+// restartHistory 各容器的重启次数采样，用于判断统计窗口内的重启次数
+var restartHistory = struct {
+	sync.Mutex
+	samples map[string][]restartSample
+}{samples: make(map[string][]restartSample)}
+
+// alertObservation 规则在某个对象上条件成立的评估结果
+type alertObservation struct {
+	ObjectID   string
+	ObjectName string
+	Value      float64
+	Message    string
+}
+
+// alertSnapshot 一轮评估中按需采集、在多条规则间复用的数据
+type alertSnapshot struct {
+	ctx context.Context
+	now time.Time
+
+	daemon *ClientStatusResult
+
+	containersLoaded bool
+	containers       []types.Container
+	containersErr    error
+
+	statsLoaded bool
+	stats       map[string]response.ContainerResourceStats
+	statsErr    error
+
+	hostCPU    *float64
+	hostMemory *mem.VirtualMemoryStat
+
+	restartCounts map[string]int
+}
+
+// EvaluateAlerts 评估所有启用的告警规则，更新事件状态并发送通知
+func (d *DockerAlertService) EvaluateAlerts() error {
+	if global.GVA_DB == nil {
+		return nil
+	}
+
+	var rules []dockerModel.DockerAlertRule
+	if err := global.GVA_DB.Where("enabled = ?", true).Find(&rules).Error; err != nil {
+		return fmt.Errorf("failed to query alert rules: %v", err)
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	var silences []dockerModel.DockerAlertSilence
+	if err := global.GVA_DB.Where("starts_at <= ? AND ends_at > ?", now, now).Find(&silences).Error; err != nil {
+		return fmt.Errorf("failed to query alert silences: %v", err)
+	}
+	var channels []dockerModel.DockerAlertChannel
+	if err := global.GVA_DB.Where("enabled = ?", true).Find(&channels).Error; err != nil {
+		return fmt.Errorf("failed to query alert channels: %v", err)
+	}
+	channelMap := make(map[uint]dockerModel.DockerAlertChannel, len(channels))
+	for _, channel := range channels {
+		channelMap[channel.ID] = channel
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), alertEvaluateTimeout)
+	defer cancel()
+	snapshot := &alertSnapshot{ctx: ctx, now: now, restartCounts: make(map[string]int)}
+
+	for _, rule := range rules {
+		observations, err := snapshot.evaluate(rule)
+		if err != nil {
+			// 数据不可用时保持原状态，避免误报恢复
+			global.GVA_LOG.Warn("Skip alert rule evaluation", zap.Uint("ruleID", rule.ID), zap.String("rule", rule.Name), zap.Error(err))
+			continue
+		}
+		if err := applyAlertObservations(rule, observations, silences, channelMap, now); err != nil {
+			global.GVA_LOG.Error("Failed to update alert events", zap.Uint("ruleID", rule.ID), zap.Error(err))
+		}
+	}
+
+	pruneRestartHistory(now)
+	return nil
+}
+
+// applyAlertObservations 根据评估结果推进事件状态：
+// 条件成立时创建 pending 事件，持续达到时长后转为 firing 并通知；条件不再成立时 firing 转为 resolved 并通知，pending 直接删除
+func applyAlertObservations(rule dockerModel.DockerAlertRule, observations []alertObservation, silences []dockerModel.DockerAlertSilence, channels map[uint]dockerModel.DockerAlertChannel, now time.Time) error {
+	var active []dockerModel.DockerAlertEvent
+	err := global.GVA_DB.Where("rule_id = ? AND status IN ?", rule.ID, []string{dockerModel.AlertStatusPending, dockerModel.AlertStatusFiring}).Find(&active).Error
+	if err != nil {
+		return err
+	}
+	activeByObject := make(map[string]*dockerModel.DockerAlertEvent, len(active))
+	for i := range active {
+		activeByObject[active[i].ObjectID] = &active[i]
+	}
+
+	seen := make(map[string]bool, len(observations))
+	for _, obs := range observations {
+		seen[obs.ObjectID] = true
+		event, ok := activeByObject[obs.ObjectID]
+		if !ok {
+			event = &dockerModel.DockerAlertEvent{
+				RuleID:   rule.ID,
+				RuleType: rule.Type,
+				ObjectID: obs.ObjectID,
+				Status:   dockerModel.AlertStatusPending,
+				StartsAt: now,
+			}
+		}
+		event.RuleName = rule.Name
+		event.Severity = rule.Severity
+		event.ObjectName = obs.ObjectName
+		event.Value = obs.Value
+		event.Message = obs.Message
+
+		if event.Status == dockerModel.AlertStatusPending && alertDurationReached(rule, event, now) {
+			event.Status = dockerModel.AlertStatusFiring
+			event.FiredAt = &now
+		}
+		if event.Status == dockerModel.AlertStatusFiring {
+			event.Silenced = isAlertSilenced(silences, rule.ID, event.ObjectID, event.ObjectName)
+			if !event.Silenced && alertNotifyDue(rule, event, now) {
+				notifyAlertChannels(rule, event, channels)
+				event.LastNotifiedAt = &now
+				event.NotifyCount++
+			}
+		}
+		if err := global.GVA_DB.Save(event).Error; err != nil {
+			return err
+		}
+	}
+
+	for i := range active {
+		event := &active[i]
+		if seen[event.ObjectID] {
+			continue
+		}
+		if event.Status == dockerModel.AlertStatusPending {
+			if err := global.GVA_DB.Delete(event).Error; err != nil {
+				return err
+			}
+			continue
+		}
+
+		event.Status = dockerModel.AlertStatusResolved
+		event.ResolvedAt = &now
+		// 只有发出过告警通知的事件才发送恢复通知
+		if event.NotifyCount > 0 && !isAlertSilenced(silences, rule.ID, event.ObjectID, event.ObjectName) {
+			notifyAlertChannels(rule, event, channels)
+		}
+		if err := global.GVA_DB.Save(event).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// alertDurationReached 条件是否已持续达到规则要求的时长，重启规则的时长为统计窗口，成立即告警
+func alertDurationReached(rule dockerModel.DockerAlertRule, event *dockerModel.DockerAlertEvent, now time.Time) bool {
+	if rule.Type == dockerModel.AlertRuleContainerRestarting || rule.Duration <= 0 {
+		return true
+	}
+	return now.Sub(event.StartsAt) >= time.Duration(rule.Duration)*time.Minute
+}
+
+// alertNotifyDue 是否需要发送告警通知：首次通知（含静默结束后补发），或到达重复通知间隔
+func alertNotifyDue(rule dockerModel.DockerAlertRule, event *dockerModel.DockerAlertEvent, now time.Time) bool {
+	if event.NotifyCount == 0 || event.LastNotifiedAt == nil {
+		return true
+	}
+	if rule.RepeatInterval <= 0 {
+		return false
+	}
+	return now.Sub(*event.LastNotifiedAt) >= time.Duration(rule.RepeatInterval)*time.Minute
+}
+
+// isAlertSilenced 判断对象是否处于生效中的静默窗口
+func isAlertSilenced(silences []dockerModel.DockerAlertSilence, ruleID uint, objectID, objectName string) bool {
+	for _, silence := range silences {
+		if silence.RuleID != 0 && silence.RuleID != ruleID {
+			continue
+		}
+		if matchAlertTarget(silence.Target, objectID, objectName) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchAlertTarget 匹配对象：为空匹配全部，支持名称通配符、名称全等与ID前缀
+func matchAlertTarget(pattern, objectID, objectName string) bool {
+	if pattern == "" {
+		return true
+	}
+	pattern = strings.TrimPrefix(pattern, "/")
+	if pattern == objectName || pattern == objectID {
+		return true
+	}
+	if matched, err := path.Match(pattern, objectName); err == nil && matched {
+		return true
+	}
+	return len(pattern) >= 12 && strings.HasPrefix(objectID, pattern)
+}
+
+// notifyAlertChannels 向规则配置的所有启用渠道发送通知，单个渠道失败不影响其他渠道
+func notifyAlertChannels(rule dockerModel.DockerAlertRule, event *dockerModel.DockerAlertEvent, channels map[uint]dockerModel.DockerAlertChannel) {
+	n := alertNotification{
+		Status:     event.Status,
+		RuleID:     rule.ID,
+		RuleName:   rule.Name,
+		RuleType:   rule.Type,
+		Severity:   rule.Severity,
+		ObjectID:   event.ObjectID,
+		ObjectName: event.ObjectName,
+		Value:      event.Value,
+		Message:    event.Message,
+		StartsAt:   event.StartsAt,
+		ResolvedAt: event.ResolvedAt,
+	}
+	for _, id := range rule.ChannelIDs {
+		channel, ok := channels[id]
+		if !ok {
+			continue
+		}
+		if err := sendAlertNotification(channel, n); err != nil {
+			global.GVA_LOG.Error("Failed to send alert notification",
+				zap.Uint("ruleID", rule.ID), zap.Uint("channelID", channel.ID), zap.String("channel", channel.Name), zap.Error(err))
+		}
+	}
+}
+
+// evaluate 评估规则，返回条件成立的对象
+func (s *alertSnapshot) evaluate(rule dockerModel.DockerAlertRule) ([]alertObservation, error) {
+	switch rule.Type {
+	case dockerModel.AlertRuleDaemonUnreachable:
+		return s.evaluateDaemon(), nil
+	case dockerModel.AlertRuleContainerExited, dockerModel.AlertRuleContainerUnhealthy:
+		return s.evaluateContainerState(rule)
+	case dockerModel.AlertRuleContainerRestarting:
+		return s.evaluateContainerRestarts(rule)
+	case dockerModel.AlertRuleContainerCPU, dockerModel.AlertRuleContainerMemory:
+		return s.evaluateContainerUsage(rule)
+	case dockerModel.AlertRuleHostCPU, dockerModel.AlertRuleHostMemory:
+		return s.evaluateHostUsage(rule)
+	case dockerModel.AlertRuleDiskUsage:
+		return s.evaluateDiskUsage(rule)
+	default:
+		return nil, fmt.Errorf("unsupported alert rule type: %s", rule.Type)
+	}
+}
+
+// evaluateDaemon 通过诊断服务检查Docker守护进程是否可达
+func (s *alertSnapshot) evaluateDaemon() []alertObservation {
+	if s.daemon == nil {
+		s.daemon = (&DockerDiagnosticService{}).CheckClientStatus()
+	}
+	if s.daemon.IsConnected {
+		return nil
+	}
+	name := global.GVA_CONFIG.Docker.Host
+	if name == "" {
+		name = "Docker"
+	}
+	return []alertObservation{{
+		ObjectID:   "daemon",
+		ObjectName: name,
+		Message:    "Docker守护进程不可达: " + s.daemon.Error,
+	}}
+}
+
+// evaluateContainerState 检查容器异常退出或健康检查失败
+func (s *alertSnapshot) evaluateContainerState(rule dockerModel.DockerAlertRule) ([]alertObservation, error) {
+	containers, err := s.listContainers()
+	if err != nil {
+		return nil, err
+	}
+
+	var observations []alertObservation
+	for _, c := range containers {
+		name := alertContainerName(c)
+		if !matchAlertTarget(rule.Target, c.ID, name) {
+			continue
+		}
+
+		if rule.Type == dockerModel.AlertRuleContainerUnhealthy {
+			if strings.Contains(c.Status, "(unhealthy)") {
+				observations = append(observations, alertObservation{
+					ObjectID: c.ID, ObjectName: name, Message: "容器健康检查失败: " + c.Status,
+				})
+			}
+			continue
+		}
+
+		if c.State == "dead" {
+			observations = append(observations, alertObservation{
+				ObjectID: c.ID, ObjectName: name, Message: "容器处于dead状态",
+			})
+			continue
+		}
+		if c.State != "exited" {
+			continue
+		}
+		match := exitCodePattern.FindStringSubmatch(c.Status)
+		if match == nil {
+			continue
+		}
+		code, _ := strconv.Atoi(match[1])
+		if code == 0 {
+			continue
+		}
+		observations = append(observations, alertObservation{
+			ObjectID: c.ID, ObjectName: name, Value: float64(code), Message: fmt.Sprintf("容器异常退出，退出码 %d", code),
+		})
+	}
+	return observations, nil
+}
+
+// evaluateContainerRestarts 检查统计窗口内的重启次数
+func (s *alertSnapshot) evaluateContainerRestarts(rule dockerModel.DockerAlertRule) ([]alertObservation, error) {
+	containers, err := s.listContainers()
+	if err != nil {
+		return nil, err
+	}
+
+	window := time.Duration(rule.Duration) * time.Minute
+	var observations []alertObservation
+	for _, c := range containers {
+		name := alertContainerName(c)
+		if !matchAlertTarget(rule.Target, c.ID, name) {
+			continue
+		}
+		if c.State != "running" && c.State != "restarting" {
+			continue
+		}
+		count, err := s.restartCount(c.ID)
+		if err != nil {
+			continue
+		}
+
+		restarts := restartsWithin(c.ID, count, s.now.Add(-window))
+		if float64(restarts) >= rule.Threshold {
+			observations = append(observations, alertObservation{
+				ObjectID:   c.ID,
+				ObjectName: name,
+				Value:      float64(restarts),
+				Message:    fmt.Sprintf("最近%d分钟内重启%d次，当前状态 %s", rule.Duration, restarts, c.State),
+			})
+		}
+	}
+	return observations, nil
+}
+
+// evaluateContainerUsage 检查容器CPU或内存使用率
+func (s *alertSnapshot) evaluateContainerUsage(rule dockerModel.DockerAlertRule) ([]alertObservation, error) {
+	stats, err := s.containerStats()
+	if err != nil {
+		return nil, err
+	}
+
+	var observations []alertObservation
+	for _, stat := range stats {
+		if !matchAlertTarget(rule.Target, stat.ID, stat.Name) {
+			continue
+		}
+		value, label := stat.CPUPercent, "CPU"
+		if rule.Type == dockerModel.AlertRuleContainerMemory {
+			value, label = stat.MemoryPercent, "内存"
+		}
+		if value > rule.Threshold {
+			observations = append(observations, alertObservation{
+				ObjectID:   stat.ID,
+				ObjectName: stat.Name,
+				Value:      value,
+				Message:    fmt.Sprintf("%s使用率 %.1f%%，超过阈值 %.1f%%", label, value, rule.Threshold),
+			})
+		}
+	}
+	return observations, nil
+}
+
+// evaluateHostUsage 检查主机CPU或内存使用率
+func (s *alertSnapshot) evaluateHostUsage(rule dockerModel.DockerAlertRule) ([]alertObservation, error) {
+	var value float64
+	label := "CPU"
+	if rule.Type == dockerModel.AlertRuleHostCPU {
+		if s.hostCPU == nil {
+			percents, err := cpu.Percent(time.Second, false)
+			if err != nil {
+				return nil, err
+			}
+			if len(percents) == 0 {
+				return nil, fmt.Errorf("no cpu usage available")
+			}
+			s.hostCPU = &percents[0]
+		}
+		value = *s.hostCPU
+	} else {
+		if s.hostMemory == nil {
+			vm, err := mem.VirtualMemory()
+			if err != nil {
+				return nil, err
+			}
+			s.hostMemory = vm
+		}
+		value, label = s.hostMemory.UsedPercent, "内存"
+	}
+
+	if value <= rule.Threshold {
+		return nil, nil
+	}
+	return []alertObservation{{
+		ObjectID:   dockerModel.MetricTargetHost,
+		ObjectName: dockerModel.MetricTargetHost,
+		Value:      value,
+		Message:    fmt.Sprintf("主机%s使用率 %.1f%%，超过阈值 %.1f%%", label, value, rule.Threshold),
+	}}, nil
+}
+
+// evaluateDiskUsage 检查磁盘使用率，Target 为空时检查配置中的所有挂载点
+func (s *alertSnapshot) evaluateDiskUsage(rule dockerModel.DockerAlertRule) ([]alertObservation, error) {
+	mountPoints := []string{rule.Target}
+	if rule.Target == "" {
+		mountPoints = mountPoints[:0]
+		for _, item := range global.GVA_CONFIG.DiskList {
+			mountPoints = append(mountPoints, item.MountPoint)
+		}
+	}
+
+	var observations []alertObservation
+	for _, mountPoint := range mountPoints {
+		usage, err := disk.Usage(mountPoint)
+		if err != nil {
+			return nil, err
+		}
+		if usage.UsedPercent > rule.Threshold {
+			observations = append(observations, alertObservation{
+				ObjectID:   mountPoint,
+				ObjectName: mountPoint,
+				Value:      usage.UsedPercent,
+				Message:    fmt.Sprintf("磁盘使用率 %.1f%%，超过阈值 %.1f%%", usage.UsedPercent, rule.Threshold),
+			})
+		}
+	}
+	return observations, nil
+}
+
+// listContainers 获取所有容器（含已停止），守护进程不可达时返回错误
+func (s *alertSnapshot) listContainers() ([]types.Container, error) {
+	if !s.containersLoaded {
+		s.containersLoaded = true
+		if !dockerAvailable() {
+			s.containersErr = fmt.Errorf("Docker client is not available")
+		} else {
+			s.containers, s.containersErr = global.GetDocker().ContainerList(s.ctx, types.ContainerListOptions{All: true})
+		}
+	}
+	return s.containers, s.containersErr
+}
+
+// containerStats 获取运行中容器的资源使用情况
+func (s *alertSnapshot) containerStats() (map[string]response.ContainerResourceStats, error) {
+	if !s.statsLoaded {
+		s.statsLoaded = true
+		if !dockerAvailable() {
+			s.statsErr = fmt.Errorf("Docker client is not available")
+		} else {
+			var stats []response.ContainerResourceStats
+			stats, s.statsErr = collectAllContainerStats(s.ctx, global.GetDocker())
+			s.stats = make(map[string]response.ContainerResourceStats, len(stats))
+			for _, stat := range stats {
+				s.stats[stat.ID] = stat
+			}
+		}
+	}
+	return s.stats, s.statsErr
+}
+
+// restartCount 获取容器累计重启次数并记录采样，同一轮评估只查询一次
+func (s *alertSnapshot) restartCount(containerID string) (int, error) {
+	if count, ok := s.restartCounts[containerID]; ok {
+		return count, nil
+	}
+	info, err := global.GetDocker().ContainerInspect(s.ctx, containerID)
+	if err != nil {
+		return 0, err
+	}
+	s.restartCounts[containerID] = info.RestartCount
+
+	restartHistory.Lock()
+	restartHistory.samples[containerID] = append(restartHistory.samples[containerID], restartSample{at: s.now, count: info.RestartCount})
+	restartHistory.Unlock()
+	return info.RestartCount, nil
+}
+
+// restartsWithin 计算 since 之后的重启次数
+func restartsWithin(containerID string, current int, since time.Time) int {
+	restartHistory.Lock()
+	defer restartHistory.Unlock()
+	for _, sample := range restartHistory.samples[containerID] {
+		if !sample.at.Before(since) {
+			if current > sample.count {
+				return current - sample.count
+			}
+			return 0
+		}
+	}
+	return 0
+}
+
+// pruneRestartHistory 删除过期的重启次数采样
+func pruneRestartHistory(now time.Time) {
+	restartHistory.Lock()
+	defer restartHistory.Unlock()
+	cutoff := now.Add(-restartHistoryRetention)
+	for id, samples := range restartHistory.samples {
+		i := 0
+		for i < len(samples) && samples[i].at.Before(cutoff) {
+			i++
+		}
+		if i == len(samples) {
+			delete(restartHistory.samples, id)
+			continue
+		}
+		restartHistory.samples[id] = samples[i:]
+	}
+}
+
+// alertContainerName 取容器的主名称
+func alertContainerName(c types.Container) string {
+	if len(c.Names) > 0 {
+		return strings.TrimPrefix(c.Names[0], "/")
+	}
+	if len(c.ID) > 12 {
+		return c.ID[:12]
+	}
+	return c.ID
+}
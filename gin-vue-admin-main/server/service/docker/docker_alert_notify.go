@@ -0,0 +1,170 @@
+package docker
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	dockerModel "github.com/flipped-aurora/gin-vue-admin/server/model/docker"
+	emailUtils "github.com/flipped-aurora/gin-vue-admin/server/plugin/email/utils"
+)
+
+// alertWebhookTimeout Webhook请求超时时间
+const alertWebhookTimeout = 10 * time.Second
+
+var alertHTTPClient = &http.Client{Timeout: alertWebhookTimeout}
+
+// alertNotification 一次告警或恢复通知的内容，通用Webhook直接以此结构POST
+type alertNotification struct {
+	Status     string     `json:"status"` // firing/resolved
+	RuleID     uint       `json:"ruleId"`
+	RuleName   string     `json:"ruleName"`
+	RuleType   string     `json:"ruleType"`
+	Severity   string     `json:"severity"`
+	ObjectID   string     `json:"objectId"`
+	ObjectName string     `json:"objectName"`
+	Value      float64    `json:"value"`
+	Message    string     `json:"message"`
+	StartsAt   time.Time  `json:"startsAt"`
+	ResolvedAt *time.Time `json:"resolvedAt,omitempty"`
+}
+
+// title 通知标题
+func (n alertNotification) title() string {
+	prefix := "[告警]"
+	if n.Status == dockerModel.AlertStatusResolved {
+		prefix = "[恢复]"
+	}
+	return fmt.Sprintf("%s %s - %s", prefix, n.RuleName, n.ObjectName)
+}
+
+// lines 通知正文，每项一行
+func (n alertNotification) lines() []string {
+	lines := []string{
+		"规则: " + n.RuleName,
+		"级别: " + n.Severity,
+		"对象: " + n.ObjectName,
+		"详情: " + n.Message,
+		"开始时间: " + n.StartsAt.Format("2006-01-02 15:04:05"),
+	}
+	if n.ResolvedAt != nil {
+		lines = append(lines, "恢复时间: "+n.ResolvedAt.Format("2006-01-02 15:04:05"))
+	}
+	return lines
+}
+
+// sendAlertNotification 通过指定渠道发送通知
+func sendAlertNotification(channel dockerModel.DockerAlertChannel, n alertNotification) error {
+	switch channel.Type {
+	case dockerModel.AlertChannelEmail:
+		lines := n.lines()
+		for i := range lines {
+			lines[i] = html.EscapeString(lines[i])
+		}
+		return emailUtils.Email(channel.Target, n.title(), strings.Join(lines, "<br>"))
+	case dockerModel.AlertChannelDingTalk:
+		return sendDingTalkNotification(channel, n)
+	case dockerModel.AlertChannelFeishu:
+		return sendFeishuNotification(channel, n)
+	case dockerModel.AlertChannelSlack:
+		return postAlertWebhook(channel.Target, map[string]interface{}{
+			"text": "*" + n.title() + "*\n" + strings.Join(n.lines(), "\n"),
+		})
+	case dockerModel.AlertChannelWebhook:
+		return postAlertWebhook(channel.Target, n)
+	default:
+		return fmt.Errorf("unsupported alert channel type: %s", channel.Type)
+	}
+}
+
+// sendDingTalkNotification 发送钉钉机器人markdown消息，配置密钥时按加签方式签名
+func sendDingTalkNotification(channel dockerModel.DockerAlertChannel, n alertNotification) error {
+	target := channel.Target
+	if channel.Secret != "" {
+		timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
+		sign := alertHmacSign(channel.Secret, timestamp+"\n"+channel.Secret)
+		separator := "&"
+		if !strings.Contains(target, "?") {
+			separator = "?"
+		}
+		target += separator + "timestamp=" + timestamp + "&sign=" + url.QueryEscape(sign)
+	}
+
+	return postAlertWebhook(target, map[string]interface{}{
+		"msgtype": "markdown",
+		"markdown": map[string]string{
+			"title": n.title(),
+			"text":  "### " + n.title() + "\n\n- " + strings.Join(n.lines(), "\n- "),
+		},
+	})
+}
+
+// sendFeishuNotification 发送飞书机器人文本消息，配置密钥时按签名校验方式签名
+func sendFeishuNotification(channel dockerModel.DockerAlertChannel, n alertNotification) error {
+	body := map[string]interface{}{
+		"msg_type": "text",
+		"content": map[string]string{
+			"text": n.title() + "\n" + strings.Join(n.lines(), "\n"),
+		},
+	}
+	if channel.Secret != "" {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		// 飞书以 timestamp+"\n"+secret 作为密钥对空串签名
+		body["timestamp"] = timestamp
+		body["sign"] = alertHmacSign(timestamp+"\n"+channel.Secret, "")
+	}
+	return postAlertWebhook(channel.Target, body)
+}
+
+// alertHmacSign 计算 HmacSHA256 并以 base64 编码
+func alertHmacSign(key, content string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(content))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// postAlertWebhook POST JSON到Webhook地址
+// 钉钉与飞书在HTTP 200时通过 errcode/code 返回业务错误，一并检查
+func postAlertWebhook(target string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	resp, err := alertHTTPClient.Post(target, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to send webhook: %v", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	var result struct {
+		ErrCode *int   `json:"errcode"`
+		ErrMsg  string `json:"errmsg"`
+		Code    *int   `json:"code"`
+		Msg     string `json:"msg"`
+	}
+	if json.Unmarshal(respBody, &result) == nil {
+		if result.ErrCode != nil && *result.ErrCode != 0 {
+			return fmt.Errorf("webhook returned error %d: %s", *result.ErrCode, result.ErrMsg)
+		}
+		if result.Code != nil && *result.Code != 0 {
+			return fmt.Errorf("webhook returned error %d: %s", *result.Code, result.Msg)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package docker
+
+import (
+	"testing"
+	"time"
+
+	dockerModel "github.com/flipped-aurora/gin-vue-admin/server/model/docker"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchAlertTarget(t *testing.T) {
+	id := "3f4e5d6c7b8a9f0e1d2c3b4a5f6e7d8c9b0a1f2e3d4c5b6a7f8e9d0c1b2a3f4e"
+	assert.True(t, matchAlertTarget("", id, "web"))
+	assert.True(t, matchAlertTarget("web", id, "web"))
+	assert.True(t, matchAlertTarget("/web", id, "web"))
+	assert.True(t, matchAlertTarget("web-*", id, "web-1"))
+	assert.True(t, matchAlertTarget(id[:12], id, "web"))
+	assert.False(t, matchAlertTarget(id[:6], id, "web"))
+	assert.False(t, matchAlertTarget("db*", id, "web"))
+}
+
+func TestAlertNotifyDue(t *testing.T) {
+	now := time.Now()
+	last := now.Add(-10 * time.Minute)
+	event := &dockerModel.DockerAlertEvent{}
+	rule := dockerModel.DockerAlertRule{}
+
+	assert.True(t, alertNotifyDue(rule, event, now))
+
+	event.NotifyCount, event.LastNotifiedAt = 1, &last
+	assert.False(t, alertNotifyDue(rule, event, now))
+
+	rule.RepeatInterval = 15
+	assert.False(t, alertNotifyDue(rule, event, now))
+	rule.RepeatInterval = 5
+	assert.True(t, alertNotifyDue(rule, event, now))
+}
@@ -0,0 +1,574 @@
+package docker
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/go-connections/nat"
+	dockerModel "github.com/flipped-aurora/gin-vue-admin/server/model/docker"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/docker/request"
+	"gopkg.in/yaml.v3"
+)
+
+// Compose 兼容标签，保证面板创建的容器能被 docker compose 及编排列表识别
+const (
+	composeProjectLabel    = "com.docker.compose.project"
+	composeServiceLabel    = "com.docker.compose.service"
+	composeNetworkLabel    = "com.docker.compose.network"
+	composeVolumeLabel     = "com.docker.compose.volume"
+	composeNumberLabel     = "com.docker.compose.container-number"
+	composeOneoffLabel     = "com.docker.compose.oneoff"
+	composeConfigHashLabel = "com.docker.compose.config-hash"
+	composeWorkingDirLabel = "com.docker.compose.project.working_dir"
+	composeDefaultNetwork  = "default"
+)
+
+var (
+	composeProjectNameRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+	composeVariableRegexp    = regexp.MustCompile(`\$\$|\$\{([^}]+)\}|\$([A-Za-z_][A-Za-z0-9_]*)`)
+)
+
+// containerCreateSpec 容器创建参数
+type containerCreateSpec struct {
+	Name          string                               // 容器名称
+	Hash          string                               // 编排服务配置哈希，非编排容器为空
+	Config        *container.Config                    // 容器配置
+	HostConfig    *container.HostConfig                // 主机配置
+	Networking    *network.NetworkingConfig            // 创建时加入的网络
+	ExtraNetworks map[string]*network.EndpointSettings // 创建后追加连接的网络
+}
+
+// validateComposeProjectName 校验编排名称是否符合 compose 项目名规则
+func validateComposeProjectName(name string) error {
+	if !composeProjectNameRegexp.MatchString(name) {
+		return fmt.Errorf("invalid orchestration name %q: only lowercase letters, digits, '_' and '-' are allowed", name)
+	}
+	return nil
+}
+
+// loadComposeProject 解析 compose 内容，支持从环境变量文件插值
+func loadComposeProject(content, workingDir, envFile string) (*dockerModel.ComposeProject, error) {
+	env := map[string]string{}
+	if envFile != "" {
+		path, err := resolveComposeEnvFile(workingDir, envFile)
+		if err != nil {
+			return nil, err
+		}
+		if env, err = readComposeEnvFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	// 先解析再对标量值插值，变量值中的 ": "、"#" 或换行不会改变文档结构
+	var root yaml.Node
+	if err := yaml.Unmarshal([]byte(content), &root); err != nil {
+		return nil, fmt.Errorf("invalid compose content: %v", err)
+	}
+	if err := interpolateComposeNode(&root, env); err != nil {
+		return nil, err
+	}
+
+	var project dockerModel.ComposeProject
+	if err := root.Decode(&project); err != nil {
+		return nil, fmt.Errorf("invalid compose content: %v", err)
+	}
+	if len(project.Services) == 0 {
+		return nil, fmt.Errorf("compose content defines no services")
+	}
+	for name, svc := range project.Services {
+		if svc == nil || svc.Image == "" {
+			return nil, fmt.Errorf("service %s: image is required (build is not supported)", name)
+		}
+	}
+	return &project, nil
+}
+
+// resolveComposeEnvFile 解析环境变量文件路径，须为编排工作目录下的相对路径，解析符号链接后仍须位于工作目录内
+func resolveComposeEnvFile(workingDir, envFile string) (string, error) {
+	if workingDir == "" {
+		return "", fmt.Errorf("env file %s requires a working directory", envFile)
+	}
+	cleaned, err := cleanRelativePath(envFile)
+	if err != nil {
+		return "", fmt.Errorf("env file must be a relative path inside the working directory: %s", envFile)
+	}
+	resolvedBase, err := filepath.EvalSymlinks(workingDir)
+	if err != nil {
+		return "", fmt.Errorf("working directory not found: %s", workingDir)
+	}
+	resolved, err := filepath.EvalSymlinks(filepath.Join(workingDir, cleaned))
+	if err != nil {
+		return "", fmt.Errorf("failed to read env file: %v", err)
+	}
+	rel, err := filepath.Rel(resolvedBase, resolved)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("env file must be a relative path inside the working directory: %s", envFile)
+	}
+	return resolved, nil
+}
+
+// readComposeEnvFile 读取 KEY=VALUE 格式的环境变量文件
+func readComposeEnvFile(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open env file: %v", err)
+	}
+	defer file.Close()
+
+	env := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		env[strings.TrimSpace(key)] = value
+	}
+	return env, scanner.Err()
+}
+
+// interpolateComposeNode 对解析后的文档逐个标量值插值，映射的键不插值
+func interpolateComposeNode(node *yaml.Node, env map[string]string) error {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		value, err := interpolateComposeEnv(node.Value, env)
+		if err != nil {
+			return err
+		}
+		if value != node.Value {
+			node.Value = value
+			// 未加引号且未显式指定类型的值按插值结果重新推断类型，如 replicas: ${REPLICAS} 解析为整数
+			if node.Style&(yaml.TaggedStyle|yaml.DoubleQuotedStyle|yaml.SingleQuotedStyle|yaml.LiteralStyle|yaml.FoldedStyle) == 0 {
+				node.Tag = ""
+			}
+		}
+	case yaml.MappingNode:
+		for i := 1; i < len(node.Content); i += 2 {
+			if err := interpolateComposeNode(node.Content[i], env); err != nil {
+				return err
+			}
+		}
+	default:
+		for _, child := range node.Content {
+			if err := interpolateComposeNode(child, env); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// interpolateComposeEnv 替换 $VAR、${VAR}、${VAR:-default}、${VAR-default}、${VAR:?err}，$$ 转义为 $
+func interpolateComposeEnv(content string, env map[string]string) (string, error) {
+	var firstErr error
+	result := composeVariableRegexp.ReplaceAllStringFunc(content, func(match string) string {
+		if match == "$$" {
+			return "$"
+		}
+		expr := strings.TrimPrefix(match, "$")
+		if strings.HasPrefix(expr, "{") {
+			expr = expr[1 : len(expr)-1]
+		} else {
+			return env[expr]
+		}
+
+		for _, sep := range []string{":-", ":?", "-", "?"} {
+			name, arg, ok := strings.Cut(expr, sep)
+			if !ok {
+				continue
+			}
+			value, set := env[name]
+			empty := !set || (strings.HasPrefix(sep, ":") && value == "")
+			if !empty {
+				return value
+			}
+			if strings.HasSuffix(sep, "?") {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("required variable %s is missing: %s", name, arg)
+				}
+				return ""
+			}
+			return arg
+		}
+		return env[expr]
+	})
+	return result, firstErr
+}
+
+// buildComposeFromServices 将结构化服务配置转换为 compose 项目
+func buildComposeFromServices(services []request.ServiceConfig) (*dockerModel.ComposeProject, error) {
+	project := &dockerModel.ComposeProject{Services: make(map[string]*dockerModel.ComposeService)}
+	for _, cfg := range services {
+		if _, exists := project.Services[cfg.ServiceName]; exists {
+			return nil, fmt.Errorf("duplicate service name %s", cfg.ServiceName)
+		}
+		svc := &dockerModel.ComposeService{
+			Image:         cfg.Image,
+			ContainerName: cfg.ContainerName,
+			Command:       cfg.Command,
+			Environment:   cfg.Environment,
+			Restart:       cfg.RestartPolicy,
+			NetworkMode:   cfg.NetworkMode,
+			DependsOn:     cfg.DependsOn,
+		}
+		for _, port := range cfg.Ports {
+			if port.ContainerPort <= 0 {
+				return nil, fmt.Errorf("service %s: container port is required", cfg.ServiceName)
+			}
+			svc.Ports = append(svc.Ports, dockerModel.ComposePort(portMappingSpec(port)))
+		}
+		for _, vol := range cfg.Volumes {
+			if vol.Type == "tmpfs" {
+				return nil, fmt.Errorf("service %s: tmpfs mounts are not supported", cfg.ServiceName)
+			}
+			spec := vol.ContainerPath
+			if vol.HostPath != "" {
+				spec = vol.HostPath + ":" + vol.ContainerPath
+			}
+			volume, err := dockerModel.ParseComposeVolume(spec)
+			if err != nil {
+				return nil, fmt.Errorf("service %s: %v", cfg.ServiceName, err)
+			}
+			if vol.Type != "" {
+				volume.Type = vol.Type
+			}
+			volume.ReadOnly = vol.ReadOnly
+			svc.Volumes = append(svc.Volumes, volume)
+			// 具名卷需要在顶层声明
+			if volume.Type == "volume" && volume.Source != "" {
+				if project.Volumes == nil {
+					project.Volumes = make(map[string]*dockerModel.ComposeVolume)
+				}
+				project.Volumes[volume.Source] = &dockerModel.ComposeVolume{}
+			}
+		}
+		project.Services[cfg.ServiceName] = svc
+	}
+	if len(project.Services) == 0 {
+		return nil, fmt.Errorf("at least one service is required")
+	}
+	return project, nil
+}
+
+// portMappingSpec 将端口映射转换为短语法 [ip:]host:container[/protocol]
+func portMappingSpec(port request.PortMapping) string {
+	spec := strconv.Itoa(port.ContainerPort)
+	if port.HostPort > 0 {
+		spec = strconv.Itoa(port.HostPort) + ":" + spec
+		if port.HostIP != "" {
+			spec = port.HostIP + ":" + spec
+		}
+	} else if port.HostIP != "" {
+		spec = port.HostIP + "::" + spec
+	}
+	if port.Protocol != "" {
+		spec += "/" + port.Protocol
+	}
+	return spec
+}
+
+// sortComposeServices 按 depends_on 拓扑排序，同层按名称排序保证结果稳定
+func sortComposeServices(project *dockerModel.ComposeProject) ([]string, error) {
+	inDegree := make(map[string]int, len(project.Services))
+	dependents := make(map[string][]string)
+	for name, svc := range project.Services {
+		inDegree[name] += 0
+		deps := append([]string{}, svc.DependsOn...)
+		if target, ok := strings.CutPrefix(svc.NetworkMode, "service:"); ok {
+			deps = append(deps, target)
+		}
+		for _, dep := range deps {
+			if _, ok := project.Services[dep]; !ok {
+				return nil, fmt.Errorf("service %s depends on undefined service %s", name, dep)
+			}
+			inDegree[name]++
+			dependents[dep] = append(dependents[dep], name)
+		}
+	}
+
+	var ready []string
+	for name, degree := range inDegree {
+		if degree == 0 {
+			ready = append(ready, name)
+		}
+	}
+	var order []string
+	for len(ready) > 0 {
+		sort.Strings(ready)
+		name := ready[0]
+		ready = ready[1:]
+		order = append(order, name)
+		for _, dependent := range dependents[name] {
+			inDegree[dependent]--
+			if inDegree[dependent] == 0 {
+				ready = append(ready, dependent)
+			}
+		}
+	}
+	if len(order) != len(project.Services) {
+		return nil, fmt.Errorf("circular dependency detected between services")
+	}
+	return order, nil
+}
+
+// composeResourceName 计算网络/存储卷的实际名称
+func composeResourceName(projectName, key, explicit string) string {
+	if explicit != "" {
+		return explicit
+	}
+	return projectName + "_" + key
+}
+
+// composeContainerName 计算服务容器名称
+func composeContainerName(projectName, serviceName string, svc *dockerModel.ComposeService) string {
+	if svc.ContainerName != "" {
+		return svc.ContainerName
+	}
+	return fmt.Sprintf("%s-%s-1", projectName, serviceName)
+}
+
+// composeServiceNetworks 返回服务加入的网络键，未声明时使用默认网络
+func composeServiceNetworks(svc *dockerModel.ComposeService) []string {
+	if svc.NetworkMode != "" {
+		return nil
+	}
+	if len(svc.Networks) == 0 {
+		return []string{composeDefaultNetwork}
+	}
+	return svc.Networks.Names()
+}
+
+// composeServiceHash 计算服务配置哈希，用于判断重新部署时是否需要重建容器
+func composeServiceHash(svc *dockerModel.ComposeService) (string, error) {
+	data, err := yaml.Marshal(svc)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// buildComposeContainerSpec 将 compose 服务转换为容器创建参数
+// networkNames/volumeNames 为 compose 键到实际资源名称的映射，containerIDs 用于解析 service:xxx 网络模式
+func buildComposeContainerSpec(projectName, workingDir, serviceName string, svc *dockerModel.ComposeService,
+	networkNames, volumeNames, containerIDs map[string]string) (*containerCreateSpec, error) {
+	hash, err := composeServiceHash(svc)
+	if err != nil {
+		return nil, fmt.Errorf("service %s: %v", serviceName, err)
+	}
+
+	labels := map[string]string{}
+	for key, value := range svc.Labels {
+		labels[key] = value
+	}
+	labels[composeProjectLabel] = projectName
+	labels[composeServiceLabel] = serviceName
+	labels[composeNumberLabel] = "1"
+	labels[composeOneoffLabel] = "False"
+	labels[composeConfigHashLabel] = hash
+	if workingDir != "" {
+		labels[composeWorkingDirLabel] = workingDir
+	}
+
+	config := &container.Config{
+		Image:      svc.Image,
+		Env:        svc.Environment.ToList(),
+		Labels:     labels,
+		WorkingDir: svc.WorkingDir,
+		User:       svc.User,
+		Hostname:   svc.Hostname,
+	}
+	if len(svc.Command) > 0 {
+		config.Cmd = []string(svc.Command)
+	}
+	if len(svc.Entrypoint) > 0 {
+		config.Entrypoint = []string(svc.Entrypoint)
+	}
+	if config.Healthcheck, err = convertComposeHealthcheck(svc.Healthcheck); err != nil {
+		return nil, fmt.Errorf("service %s: %v", serviceName, err)
+	}
+
+	hostConfig := &container.HostConfig{Privileged: svc.Privileged}
+	if hostConfig.RestartPolicy, err = parseRestartPolicy(svc.Restart); err != nil {
+		return nil, fmt.Errorf("service %s: %v", serviceName, err)
+	}
+
+	if len(svc.Ports) > 0 {
+		specs := make([]string, 0, len(svc.Ports))
+		for _, port := range svc.Ports {
+			specs = append(specs, string(port))
+		}
+		exposed, bindings, err := nat.ParsePortSpecs(specs)
+		if err != nil {
+			return nil, fmt.Errorf("service %s: invalid ports: %v", serviceName, err)
+		}
+		config.ExposedPorts = exposed
+		hostConfig.PortBindings = bindings
+	}
+
+	for _, vol := range svc.Volumes {
+		m := mount.Mount{Target: vol.Target, ReadOnly: vol.ReadOnly}
+		switch vol.Type {
+		case "bind":
+			m.Type = mount.TypeBind
+			if m.Source, err = resolveComposeBindPath(workingDir, vol.Source); err != nil {
+				return nil, fmt.Errorf("service %s: %v", serviceName, err)
+			}
+		default:
+			m.Type = mount.TypeVolume
+			if vol.Source != "" {
+				actual, ok := volumeNames[vol.Source]
+				if !ok {
+					return nil, fmt.Errorf("service %s refers to undefined volume %s", serviceName, vol.Source)
+				}
+				m.Source = actual
+			}
+		}
+		hostConfig.Mounts = append(hostConfig.Mounts, m)
+	}
+
+	spec := &containerCreateSpec{
+		Name:          composeContainerName(projectName, serviceName, svc),
+		Hash:          hash,
+		Config:        config,
+		HostConfig:    hostConfig,
+		Networking:    &network.NetworkingConfig{},
+		ExtraNetworks: map[string]*network.EndpointSettings{},
+	}
+
+	if svc.NetworkMode != "" {
+		mode := svc.NetworkMode
+		if target, ok := strings.CutPrefix(mode, "service:"); ok {
+			id, ok := containerIDs[target]
+			if !ok {
+				return nil, fmt.Errorf("service %s: network_mode refers to service %s which has no container", serviceName, target)
+			}
+			mode = "container:" + id
+		}
+		hostConfig.NetworkMode = container.NetworkMode(mode)
+		return spec, nil
+	}
+
+	for i, key := range composeServiceNetworks(svc) {
+		actual, ok := networkNames[key]
+		if !ok {
+			return nil, fmt.Errorf("service %s refers to undefined network %s", serviceName, key)
+		}
+		endpoint := &network.EndpointSettings{Aliases: []string{serviceName}}
+		if cfg := svc.Networks[key]; cfg != nil {
+			endpoint.Aliases = append(endpoint.Aliases, cfg.Aliases...)
+			if cfg.IPv4Address != "" || cfg.IPv6Address != "" {
+				endpoint.IPAMConfig = &network.EndpointIPAMConfig{IPv4Address: cfg.IPv4Address, IPv6Address: cfg.IPv6Address}
+			}
+		}
+		// API 1.41 创建容器时只能指定一个网络，其余网络在启动前连接
+		if i == 0 {
+			hostConfig.NetworkMode = container.NetworkMode(actual)
+			spec.Networking.EndpointsConfig = map[string]*network.EndpointSettings{actual: endpoint}
+		} else {
+			spec.ExtraNetworks[actual] = endpoint
+		}
+	}
+	return spec, nil
+}
+
+// resolveComposeBindPath 解析绑定挂载路径，相对路径基于编排工作目录
+func resolveComposeBindPath(workingDir, source string) (string, error) {
+	if strings.HasPrefix(source, "~") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("cannot resolve %s: %v", source, err)
+		}
+		return filepath.Join(home, strings.TrimPrefix(source, "~")), nil
+	}
+	if filepath.IsAbs(source) {
+		return filepath.Clean(source), nil
+	}
+	if workingDir == "" {
+		return "", fmt.Errorf("relative bind path %s requires a working directory", source)
+	}
+	return filepath.Join(workingDir, source), nil
+}
+
+// parseRestartPolicy 解析重启策略 no/always/unless-stopped/on-failure[:N]
+func parseRestartPolicy(policy string) (container.RestartPolicy, error) {
+	name, count, hasCount := strings.Cut(policy, ":")
+	switch name {
+	case "", "no":
+		return container.RestartPolicy{}, nil
+	case "always", "unless-stopped":
+		if hasCount {
+			return container.RestartPolicy{}, fmt.Errorf("restart policy %s does not accept a retry count", name)
+		}
+		return container.RestartPolicy{Name: name}, nil
+	case "on-failure":
+		result := container.RestartPolicy{Name: name}
+		if hasCount {
+			retries, err := strconv.Atoi(count)
+			if err != nil || retries < 0 {
+				return container.RestartPolicy{}, fmt.Errorf("invalid restart retry count %q", count)
+			}
+			result.MaximumRetryCount = retries
+		}
+		return result, nil
+	}
+	return container.RestartPolicy{}, fmt.Errorf("unsupported restart policy %q", policy)
+}
+
+// convertComposeHealthcheck 转换健康检查配置
+func convertComposeHealthcheck(hc *dockerModel.ComposeHealthcheck) (*container.HealthConfig, error) {
+	if hc == nil {
+		return nil, nil
+	}
+	if hc.Disable {
+		return &container.HealthConfig{Test: []string{"NONE"}}, nil
+	}
+	result := &container.HealthConfig{Retries: hc.Retries}
+	if len(hc.Test) > 0 {
+		switch hc.Test[0] {
+		case "NONE", "CMD", "CMD-SHELL":
+			result.Test = []string(hc.Test)
+		default:
+			result.Test = []string{"CMD-SHELL", strings.Join(hc.Test, " ")}
+		}
+	}
+	durations := []struct {
+		value  string
+		target *time.Duration
+	}{
+		{hc.Interval, &result.Interval},
+		{hc.Timeout, &result.Timeout},
+		{hc.StartPeriod, &result.StartPeriod},
+	}
+	for _, item := range durations {
+		if item.value == "" {
+			continue
+		}
+		parsed, err := time.ParseDuration(item.value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid healthcheck duration %q", item.value)
+		}
+		*item.target = parsed
+	}
+	return result, nil
+}
@@ -0,0 +1,136 @@
+package docker
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	dockerModel "github.com/flipped-aurora/gin-vue-admin/server/model/docker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testComposeContent = `
+services:
+  web:
+    image: nginx:${NGINX_TAG:-latest}
+    command: nginx -g "daemon off;"
+    ports:
+      - "8080:80"
+    volumes:
+      - ./html:/usr/share/nginx/html:ro
+      - data:/data
+    networks:
+      front:
+        aliases: [site]
+      back:
+    depends_on:
+      db:
+        condition: service_started
+    restart: on-failure:3
+  db:
+    image: mysql:8
+    environment:
+      - MYSQL_ROOT_PASSWORD=$${SECRET}
+    networks: [back]
+networks:
+  front:
+  back:
+volumes:
+  data:
+`
+
+func TestLoadComposeProject(t *testing.T) {
+	project, err := loadComposeProject(testComposeContent, "/opt/demo", "")
+	assert.Nil(t, err)
+
+	web := project.Services["web"]
+	assert.Equal(t, "nginx:latest", web.Image)
+	assert.Equal(t, []string{"nginx", "-g", "daemon off;"}, []string(web.Command))
+	assert.Equal(t, []string{"db"}, []string(web.DependsOn))
+	assert.Equal(t, []string{"back", "front"}, web.Networks.Names())
+	assert.Equal(t, "bind", web.Volumes[0].Type)
+	assert.True(t, web.Volumes[0].ReadOnly)
+	assert.Equal(t, "volume", web.Volumes[1].Type)
+	assert.Equal(t, "${SECRET}", project.Services["db"].Environment["MYSQL_ROOT_PASSWORD"])
+
+	order, err := sortComposeServices(project)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"db", "web"}, order)
+
+	networks := map[string]string{"front": "demo_front", "back": "demo_back"}
+	volumes := map[string]string{"data": "demo_data"}
+	spec, err := buildComposeContainerSpec("demo", "/opt/demo", "web", web, networks, volumes, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "demo-web-1", spec.Name)
+	assert.Equal(t, container.NetworkMode("demo_back"), spec.HostConfig.NetworkMode)
+	assert.Equal(t, []string{"web", "site"}, spec.ExtraNetworks["demo_front"].Aliases)
+	assert.Equal(t, "/opt/demo/html", spec.HostConfig.Mounts[0].Source)
+	assert.Equal(t, "demo_data", spec.HostConfig.Mounts[1].Source)
+	assert.Equal(t, container.RestartPolicy{Name: "on-failure", MaximumRetryCount: 3}, spec.HostConfig.RestartPolicy)
+	assert.Equal(t, "demo", spec.Config.Labels[composeProjectLabel])
+}
+
+func TestSortComposeServicesCycle(t *testing.T) {
+	project, err := loadComposeProject(`
+services:
+  a:
+    image: busybox
+    depends_on: [b]
+  b:
+    image: busybox
+    depends_on: [a]
+`, "", "")
+	assert.Nil(t, err)
+	_, err = sortComposeServices(project)
+	assert.NotNil(t, err)
+}
+
+func TestLoadComposeProjectInterpolatesValues(t *testing.T) {
+	workingDir := t.TempDir()
+	content := "DB_PASSWORD='p: w#rd'\nPORT=8080\n"
+	require.NoError(t, os.WriteFile(filepath.Join(workingDir, ".env"), []byte(content), 0o600))
+
+	project, err := loadComposeProject(`
+services:
+  app:
+    image: app:1.0
+    environment:
+      DB_PASSWORD: ${DB_PASSWORD}
+      # 注释中的变量不插值 ${MISSING:?required}
+    ports:
+      - ${PORT}:80
+`, workingDir, ".env")
+	require.NoError(t, err)
+
+	// 变量值中的 ": "、"#" 原样作为值，不会被解析为映射或注释
+	app := project.Services["app"]
+	assert.Equal(t, "p: w#rd", app.Environment["DB_PASSWORD"])
+	assert.Len(t, app.Environment, 1)
+	require.Len(t, app.Ports, 1)
+	assert.Equal(t, dockerModel.ComposePort("8080:80"), app.Ports[0])
+
+	_, err = loadComposeProject("services:\n  app:\n    image: app:${TAG:?tag is required}\n", "", "")
+	assert.EqualError(t, err, "required variable TAG is missing: tag is required")
+}
+
+func TestResolveComposeEnvFileStaysInWorkingDir(t *testing.T) {
+	root := t.TempDir()
+	workingDir := filepath.Join(root, "project")
+	require.NoError(t, os.MkdirAll(filepath.Join(workingDir, "config"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(workingDir, "config", "app.env"), []byte("TAG=1\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "secret.env"), []byte("TOKEN=x\n"), 0o600))
+	require.NoError(t, os.Symlink(filepath.Join(root, "secret.env"), filepath.Join(workingDir, "link.env")))
+
+	path, err := resolveComposeEnvFile(workingDir, "./config/app.env")
+	require.NoError(t, err)
+	assert.Equal(t, "app.env", filepath.Base(path))
+
+	for _, envFile := range []string{"../secret.env", filepath.Join(root, "secret.env"), "link.env"} {
+		_, err := resolveComposeEnvFile(workingDir, envFile)
+		assert.Error(t, err, envFile)
+	}
+	_, err = resolveComposeEnvFile("", "app.env")
+	assert.Error(t, err)
+}
@@ -0,0 +1,222 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/flipped-aurora/gin-vue-admin/server/config"
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	dockerRes "github.com/flipped-aurora/gin-vue-admin/server/model/docker/response"
+	"go.uber.org/zap"
+)
+
+// 默认Docker主机连接状态
+const (
+	DockerStateUnknown   = "unknown"   // 尚未检查
+	DockerStateConnected = "connected" // 连接正常
+	DockerStateDegraded  = "degraded"  // 响应缓慢或偶发失败
+	DockerStateDown      = "down"      // 连续失败或客户端无法创建
+)
+
+const (
+	defaultDockerHealthInterval = 10              // 默认检查间隔（秒）
+	dockerDownThreshold         = 3               // 连续失败达到该次数时视为不可用
+	dockerSlowPing              = time.Second     // ping 超过该耗时视为响应缓慢
+	dockerPingTimeout           = 5 * time.Second // 单次 ping 超时
+	dockerConnectionEventKeep   = 20              // 保留的状态变化记录数
+)
+
+// dockerConnectionManager 默认主机的连接状态，由定时任务检查；失败后下一次检查前重建客户端，
+// 以便守护进程重启、升级或配置中的端点变化后恢复连接
+type dockerConnectionManager struct {
+	checkMu     sync.Mutex // 串行执行检查
+	mu          sync.Mutex
+	status      dockerRes.DockerConnectionStatus
+	configKey   string // 当前客户端对应的连接配置，变化时重建客户端
+	recreate    bool
+	subscribers map[int]func(dockerRes.DockerConnectionEvent)
+	nextID      int
+}
+
+var dockerConnection = &dockerConnectionManager{
+	status:      dockerRes.DockerConnectionStatus{State: DockerStateUnknown, Events: []dockerRes.DockerConnectionEvent{}},
+	subscribers: make(map[int]func(dockerRes.DockerConnectionEvent)),
+}
+
+type DockerConnectionService struct{}
+
+// GetConnectionStatus 获取默认主机的连接状态
+func (s *DockerConnectionService) GetConnectionStatus() dockerRes.DockerConnectionStatus {
+	dockerConnection.mu.Lock()
+	defer dockerConnection.mu.Unlock()
+	status := dockerConnection.status
+	status.Host = global.GVA_CONFIG.Docker.Host
+	status.Events = append([]dockerRes.DockerConnectionEvent(nil), dockerConnection.status.Events...)
+	return status
+}
+
+// CheckConnection 立即检查一次连接并返回最新状态，由定时任务调用
+func (s *DockerConnectionService) CheckConnection() dockerRes.DockerConnectionStatus {
+	dockerConnection.check()
+	return s.GetConnectionStatus()
+}
+
+// Subscribe 订阅连接状态变化，返回取消订阅函数；回调在检查任务中同步执行，不应阻塞。
+// 首次检查、状态变化以及客户端重建后恢复连接时触发
+func (s *DockerConnectionService) Subscribe(fn func(event dockerRes.DockerConnectionEvent)) func() {
+	dockerConnection.mu.Lock()
+	defer dockerConnection.mu.Unlock()
+	id := dockerConnection.nextID
+	dockerConnection.nextID++
+	dockerConnection.subscribers[id] = fn
+	return func() {
+		dockerConnection.mu.Lock()
+		defer dockerConnection.mu.Unlock()
+		delete(dockerConnection.subscribers, id)
+	}
+}
+
+// HealthInterval 连接检查间隔
+func (s *DockerConnectionService) HealthInterval() time.Duration {
+	interval := global.GVA_CONFIG.Docker.HealthInterval
+	if interval <= 0 {
+		interval = defaultDockerHealthInterval
+	}
+	return time.Duration(interval) * time.Second
+}
+
+// dockerAvailable 默认主机是否可用；尚未检查时以客户端是否创建为准
+func dockerAvailable() bool {
+	if global.GetDocker() == nil {
+		return false
+	}
+	dockerConnection.mu.Lock()
+	defer dockerConnection.mu.Unlock()
+	return dockerConnection.status.State != DockerStateDown
+}
+
+// dockerConfigKey 影响客户端创建的配置项
+func dockerConfigKey(cfg config.Docker) string {
+	return fmt.Sprintf("%s|%s|%d|%t|%s", cfg.Host, cfg.Version, cfg.Timeout, cfg.TLSVerify, cfg.CertPath)
+}
+
+// setConfigKey 记录当前客户端对应的连接配置，重建客户端后调用
+func (m *dockerConnectionManager) setConfigKey(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.configKey = key
+	m.recreate = false
+}
+
+// check 按需重建客户端后 ping 守护进程，更新状态并通知订阅者
+func (m *dockerConnectionManager) check() {
+	m.checkMu.Lock()
+	defer m.checkMu.Unlock()
+
+	key := dockerConfigKey(global.GVA_CONFIG.Docker)
+	current := global.GetDocker()
+	m.mu.Lock()
+	if m.configKey == "" && current != nil {
+		// 启动时创建的客户端
+		m.configKey = key
+	}
+	rebuild := current == nil || m.recreate || m.configKey != key
+	knownVersion := m.status.ServerVersion != ""
+	m.mu.Unlock()
+
+	var err error
+	reconnected := false
+	if rebuild {
+		if err = reconnectDefaultDocker(); err == nil {
+			reconnected = true
+		}
+	}
+
+	var latency time.Duration
+	var version types.Version
+	if err == nil {
+		cli := global.GetDocker()
+		ctx, cancel := context.WithTimeout(context.Background(), dockerPingTimeout)
+		start := time.Now()
+		_, err = cli.Ping(ctx)
+		latency = time.Since(start)
+		if err == nil && (reconnected || !knownVersion) {
+			version, _ = cli.ServerVersion(ctx)
+		}
+		cancel()
+	}
+	m.record(err, latency, version, reconnected)
+}
+
+// record 更新连接状态，状态变化或重建客户端后恢复时通知订阅者
+func (m *dockerConnectionManager) record(err error, latency time.Duration, version types.Version, reconnected bool) {
+	now := time.Now()
+	m.mu.Lock()
+	status := &m.status
+	prev := status.State
+	status.LastCheckAt = &now
+	if reconnected {
+		status.Reconnects++
+	}
+
+	state := DockerStateConnected
+	if err == nil {
+		status.Failures = 0
+		status.Latency = latency.Milliseconds()
+		if version.Version != "" {
+			status.ServerVersion = version.Version
+			status.APIVersion = version.APIVersion
+		}
+		if latency > dockerSlowPing {
+			state = DockerStateDegraded
+		}
+	} else {
+		status.Failures++
+		status.LastError = err.Error()
+		m.recreate = true
+		state = DockerStateDegraded
+		if status.Failures >= dockerDownThreshold || global.GetDocker() == nil {
+			state = DockerStateDown
+		}
+	}
+	status.State = state
+
+	var subscribers []func(dockerRes.DockerConnectionEvent)
+	var event dockerRes.DockerConnectionEvent
+	notify := state != prev || (reconnected && err == nil)
+	if notify {
+		event = dockerRes.DockerConnectionEvent{Time: now, From: prev, To: state, Reconnected: reconnected && err == nil}
+		if err != nil {
+			event.Error = err.Error()
+		}
+		if state != prev {
+			status.LastChangeAt = &now
+		}
+		status.Events = append([]dockerRes.DockerConnectionEvent{event}, status.Events...)
+		if len(status.Events) > dockerConnectionEventKeep {
+			status.Events = status.Events[:dockerConnectionEventKeep]
+		}
+		for _, fn := range m.subscribers {
+			subscribers = append(subscribers, fn)
+		}
+	}
+	m.mu.Unlock()
+
+	if !notify {
+		return
+	}
+	switch state {
+	case DockerStateConnected:
+		global.GVA_LOG.Info("Docker connection is healthy", zap.String("from", prev), zap.Bool("reconnected", event.Reconnected))
+	case DockerStateDegraded:
+		global.GVA_LOG.Warn("Docker connection is degraded", zap.String("from", prev), zap.Int64("latency", latency.Milliseconds()), zap.String("error", event.Error))
+	default:
+		global.GVA_LOG.Error("Docker connection is down", zap.String("from", prev), zap.String("error", event.Error))
+	}
+	for _, fn := range subscribers {
+		fn(event)
+	}
+}
@@ -0,0 +1,119 @@
+package docker
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/flipped-aurora/gin-vue-admin/server/config"
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	dockerRes "github.com/flipped-aurora/gin-vue-admin/server/model/docker/response"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestDockerConnectionRecord(t *testing.T) {
+	cli, err := client.NewClientWithOpts(client.WithHost("unix:///nonexistent/docker.sock"))
+	require.NoError(t, err)
+	oldDocker, oldStream := global.SetDocker(cli, cli)
+	oldLog := global.GVA_LOG
+	global.GVA_LOG = zap.NewNop()
+	defer func() { global.GVA_LOG = oldLog; global.SetDocker(oldDocker, oldStream) }()
+
+	var events []dockerRes.DockerConnectionEvent
+	m := &dockerConnectionManager{
+		status:      dockerRes.DockerConnectionStatus{State: DockerStateUnknown},
+		subscribers: map[int]func(dockerRes.DockerConnectionEvent){0: func(e dockerRes.DockerConnectionEvent) { events = append(events, e) }},
+	}
+
+	m.record(nil, 5*time.Millisecond, types.Version{Version: "24.0.7", APIVersion: "1.43"}, false)
+	assert.Equal(t, DockerStateConnected, m.status.State)
+	assert.Equal(t, "24.0.7", m.status.ServerVersion)
+	require.Len(t, events, 1)
+	assert.Equal(t, DockerStateUnknown, events[0].From)
+
+	// 偶发失败为 degraded，连续失败达到阈值后为 down，且下一次检查前重建客户端
+	pingErr := errors.New("connection refused")
+	m.record(pingErr, 0, types.Version{}, false)
+	assert.Equal(t, DockerStateDegraded, m.status.State)
+	assert.True(t, m.recreate)
+	m.record(pingErr, 0, types.Version{}, false)
+	assert.Len(t, events, 2)
+	m.record(pingErr, 0, types.Version{}, false)
+	assert.Equal(t, DockerStateDown, m.status.State)
+	assert.Equal(t, 3, m.status.Failures)
+	require.Len(t, events, 3)
+	assert.Equal(t, "connection refused", events[2].Error)
+
+	m.record(nil, 5*time.Millisecond, types.Version{}, true)
+	assert.Equal(t, DockerStateConnected, m.status.State)
+	assert.Equal(t, 0, m.status.Failures)
+	assert.Equal(t, 1, m.status.Reconnects)
+	require.Len(t, events, 4)
+	assert.True(t, events[3].Reconnected)
+
+	m.record(nil, 2*time.Second, types.Version{}, false)
+	assert.Equal(t, DockerStateDegraded, m.status.State)
+	assert.Equal(t, "connection refused", m.status.LastError)
+	assert.Len(t, m.status.Events, 5)
+	assert.Equal(t, DockerStateDegraded, m.status.Events[0].To)
+}
+
+func TestReconnectDefaultDockerConcurrentReads(t *testing.T) {
+	oldConfig, oldDocker, oldStream := global.GVA_CONFIG.Docker, global.GetDocker(), global.GetDockerStream()
+	defer func() {
+		global.GVA_CONFIG.Docker = oldConfig
+		global.SetDocker(oldDocker, oldStream)
+	}()
+	global.GVA_CONFIG.Docker.Host = "unix:///nonexistent/docker.sock"
+	global.GVA_CONFIG.Docker.Version = "1.41"
+	require.NoError(t, reconnectDefaultDocker())
+
+	// 请求与后台任务读取客户端的同时，连接检查重建客户端
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					assert.Equal(t, "1.41", global.GetDocker().ClientVersion())
+					assert.Equal(t, "1.41", global.GetDockerStream().ClientVersion())
+				}
+			}
+		}()
+	}
+	for i := 0; i < 20; i++ {
+		require.NoError(t, reconnectDefaultDocker())
+	}
+	close(stop)
+	wg.Wait()
+}
+
+func TestReconnectDefaultDockerAppliesConfigDefaults(t *testing.T) {
+	oldConfig, oldDocker, oldStream := global.GVA_CONFIG.Docker, global.GetDocker(), global.GetDockerStream()
+	defer func() {
+		global.GVA_CONFIG.Docker = oldConfig
+		global.SetDocker(oldDocker, oldStream)
+	}()
+
+	// 未配置端点与版本时与启动时一样使用本机套接字
+	global.GVA_CONFIG.Docker = config.Docker{}
+	require.NoError(t, reconnectDefaultDocker())
+	assert.Equal(t, "unix:///var/run/docker.sock", global.GetDocker().DaemonHost())
+	assert.Equal(t, "1.41", global.GetDocker().ClientVersion())
+
+	// 证书校验失败时保留原客户端
+	current := global.GetDocker()
+	global.GVA_CONFIG.Docker = config.Docker{Host: "tcp://10.0.0.2:2376", TLSVerify: true}
+	assert.EqualError(t, reconnectDefaultDocker(), "docker TLS verification is enabled but cert-path is not configured")
+	assert.Same(t, current, global.GetDocker())
+}
@@ -2,6 +2,7 @@ package docker
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"sort"
@@ -16,14 +17,17 @@ import (
 	"github.com/flipped-aurora/gin-vue-admin/server/model/docker/request"
 	"github.com/flipped-aurora/gin-vue-admin/server/model/docker/response"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
-type DockerContainerService struct{}
+type DockerContainerService struct {
+	hostClient
+}
 
 // GetContainerList 获取容器列表
 func (d *DockerContainerService) GetContainerList(filter request.ContainerFilter) ([]response.ContainerInfo, int64, error) {
 	// 检查Docker客户端是否可用
-	if global.GVA_DOCKER == nil {
+	if d.cli() == nil {
 		return nil, 0, fmt.Errorf("Docker client is not available")
 	}
 
@@ -51,7 +55,7 @@ func (d *DockerContainerService) GetContainerList(filter request.ContainerFilter
 	}
 
 	// 调用Docker API获取容器列表
-	containers, err := global.GVA_DOCKER.ContainerList(ctx, options)
+	containers, err := d.cli().ContainerList(ctx, options)
 	if err != nil {
 		global.GVA_LOG.Error("Failed to get container list", zap.Error(err))
 		return nil, 0, fmt.Errorf("failed to get container list: %v", err)
@@ -89,7 +93,7 @@ func (d *DockerContainerService) GetContainerList(filter request.ContainerFilter
 // GetContainerDetail 获取容器详细信息
 func (d *DockerContainerService) GetContainerDetail(containerID string) (*response.ContainerDetail, error) {
 	// 检查Docker客户端是否可用
-	if global.GVA_DOCKER == nil {
+	if d.cli() == nil {
 		return nil, fmt.Errorf("Docker client is not available")
 	}
 
@@ -103,7 +107,7 @@ func (d *DockerContainerService) GetContainerDetail(containerID string) (*respon
 	defer cancel()
 
 	// 调用Docker API获取容器详细信息
-	containerJSON, err := global.GVA_DOCKER.ContainerInspect(ctx, containerID)
+	containerJSON, err := d.cli().ContainerInspect(ctx, containerID)
 	if err != nil {
 		if client.IsErrNotFound(err) {
 			return nil, fmt.Errorf("container not found")
@@ -121,7 +125,7 @@ func (d *DockerContainerService) GetContainerDetail(containerID string) (*respon
 // GetContainerLogs 获取容器日志
 func (d *DockerContainerService) GetContainerLogs(containerID string, options request.LogOptions) (string, error) {
 	// 检查Docker客户端是否可用
-	if global.GVA_DOCKER == nil {
+	if d.cli() == nil {
 		return "", fmt.Errorf("Docker client is not available")
 	}
 
@@ -138,9 +142,9 @@ func (d *DockerContainerService) GetContainerLogs(containerID string, options re
 	logOptions := types.ContainerLogsOptions{
 		ShowStdout: true,
 		ShowStderr: true,
-		Follow:     options.Follow,
 		Timestamps: true,
 	}
+	// 一次性读取接口不支持跟踪，跟踪日志请使用 StreamContainerLogs，避免请求挂起到超时后被截断
 
 	// 设置tail选项
 	if options.Tail != "" {
@@ -155,7 +159,7 @@ func (d *DockerContainerService) GetContainerLogs(containerID string, options re
 	}
 
 	// 调用Docker API获取容器日志
-	logReader, err := global.GVA_DOCKER.ContainerLogs(ctx, containerID, logOptions)
+	logReader, err := d.cli().ContainerLogs(ctx, containerID, logOptions)
 	if err != nil {
 		if client.IsErrNotFound(err) {
 			return "", fmt.Errorf("container not found")
@@ -225,27 +229,27 @@ func (d *DockerContainerService) processDockerLogs(logBytes []byte) string {
 
 // IsDockerAvailable 检查Docker是否可用
 func (d *DockerContainerService) IsDockerAvailable() bool {
-	if global.GVA_DOCKER == nil {
+	if d.cli() == nil {
 		return false
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	_, err := global.GVA_DOCKER.Ping(ctx)
+	_, err := d.cli().Ping(ctx)
 	return err == nil
 }
 
 // GetDockerInfo 获取Docker系统信息
 func (d *DockerContainerService) GetDockerInfo() (*types.Info, error) {
-	if global.GVA_DOCKER == nil {
+	if d.cli() == nil {
 		return nil, fmt.Errorf("Docker client is not available")
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	info, err := global.GVA_DOCKER.Info(ctx)
+	info, err := d.cli().Info(ctx)
 	if err != nil {
 		global.GVA_LOG.Error("Failed to get Docker info", zap.Error(err))
 		return nil, fmt.Errorf("failed to get Docker info: %v", err)
@@ -256,7 +260,7 @@ func (d *DockerContainerService) GetDockerInfo() (*types.Info, error) {
 
 // StartContainer 启动容器
 func (d *DockerContainerService) StartContainer(containerID string) error {
-	if global.GVA_DOCKER == nil {
+	if d.cli() == nil {
 		return fmt.Errorf("Docker client is not available")
 	}
 
@@ -267,7 +271,7 @@ func (d *DockerContainerService) StartContainer(containerID string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	err := global.GVA_DOCKER.ContainerStart(ctx, containerID, types.ContainerStartOptions{})
+	err := d.cli().ContainerStart(ctx, containerID, types.ContainerStartOptions{})
 	if err != nil {
 		if client.IsErrNotFound(err) {
 			return fmt.Errorf("container not found")
@@ -282,7 +286,7 @@ func (d *DockerContainerService) StartContainer(containerID string) error {
 
 // StopContainer 停止容器
 func (d *DockerContainerService) StopContainer(containerID string, timeout *int) error {
-	if global.GVA_DOCKER == nil {
+	if d.cli() == nil {
 		return fmt.Errorf("Docker client is not available")
 	}
 
@@ -300,7 +304,7 @@ func (d *DockerContainerService) StopContainer(containerID string, timeout *int)
 		stopTimeout = &duration
 	}
 
-	err := global.GVA_DOCKER.ContainerStop(ctx, containerID, stopTimeout)
+	err := d.cli().ContainerStop(ctx, containerID, stopTimeout)
 	if err != nil {
 		if client.IsErrNotFound(err) {
 			return fmt.Errorf("container not found")
@@ -315,7 +319,7 @@ func (d *DockerContainerService) StopContainer(containerID string, timeout *int)
 
 // RestartContainer 重启容器
 func (d *DockerContainerService) RestartContainer(containerID string, timeout *int) error {
-	if global.GVA_DOCKER == nil {
+	if d.cli() == nil {
 		return fmt.Errorf("Docker client is not available")
 	}
 
@@ -333,7 +337,7 @@ func (d *DockerContainerService) RestartContainer(containerID string, timeout *i
 		restartTimeout = &duration
 	}
 
-	err := global.GVA_DOCKER.ContainerRestart(ctx, containerID, restartTimeout)
+	err := d.cli().ContainerRestart(ctx, containerID, restartTimeout)
 	if err != nil {
 		if client.IsErrNotFound(err) {
 			return fmt.Errorf("container not found")
@@ -348,7 +352,7 @@ func (d *DockerContainerService) RestartContainer(containerID string, timeout *i
 
 // RemoveContainer 删除容器
 func (d *DockerContainerService) RemoveContainer(containerID string, force bool) error {
-	if global.GVA_DOCKER == nil {
+	if d.cli() == nil {
 		return fmt.Errorf("Docker client is not available")
 	}
 
@@ -359,7 +363,7 @@ func (d *DockerContainerService) RemoveContainer(containerID string, force bool)
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	err := global.GVA_DOCKER.ContainerRemove(ctx, containerID, types.ContainerRemoveOptions{
+	err := d.cli().ContainerRemove(ctx, containerID, types.ContainerRemoveOptions{
 		Force: force,
 	})
 	if err != nil {
@@ -376,11 +380,11 @@ func (d *DockerContainerService) RemoveContainer(containerID string, force bool)
 
 // BatchOperateByOrchestrationLabel 对同一label分组的容器批量操作
 func (d *DockerContainerService) BatchOperateByOrchestrationLabel(label string, op string, timeout *int, force bool) (successIDs []string, failed map[string]string) {
-	if global.GVA_DOCKER == nil {
+	if d.cli() == nil {
 		return nil, map[string]string{"_global": "Docker client is not available"}
 	}
 	ctx := context.Background()
-	containers, err := global.GVA_DOCKER.ContainerList(ctx, types.ContainerListOptions{All: true})
+	containers, err := d.cli().ContainerList(ctx, types.ContainerListOptions{All: true})
 	if err != nil {
 		return nil, map[string]string{"_global": err.Error()}
 	}
@@ -430,14 +434,14 @@ func (d *DockerContainerService) BatchOperateByOrchestrationLabel(label string,
 
 // GetOrchestrationList 获取编排列表
 func (d *DockerContainerService) GetOrchestrationList(page, pageSize int, search, statusFilter string) (interface{}, error) {
-	if global.GVA_DOCKER == nil {
+	if d.cli() == nil {
 		return nil, fmt.Errorf("Docker client is not available")
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	containers, err := global.GVA_DOCKER.ContainerList(ctx, types.ContainerListOptions{All: true})
+	containers, err := d.cli().ContainerList(ctx, types.ContainerListOptions{All: true})
 	if err != nil {
 		global.GVA_LOG.Error("Failed to get container list for orchestration", zap.Error(err))
 		return nil, fmt.Errorf("failed to get container list: %v", err)
@@ -535,14 +539,14 @@ func (d *DockerContainerService) GetOrchestrationList(page, pageSize int, search
 
 // GetOrchestrationDetail 获取编排详情
 func (d *DockerContainerService) GetOrchestrationDetail(name string) ([]types.Container, error) {
-	if global.GVA_DOCKER == nil {
+	if d.cli() == nil {
 		return nil, fmt.Errorf("Docker client is not available")
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	containers, err := global.GVA_DOCKER.ContainerList(ctx, types.ContainerListOptions{All: true})
+	containers, err := d.cli().ContainerList(ctx, types.ContainerListOptions{All: true})
 	if err != nil {
 		global.GVA_LOG.Error("Failed to get container list for orchestration detail", zap.String("name", name), zap.Error(err))
 		return nil, fmt.Errorf("failed to get container list: %v", err)
@@ -580,14 +584,14 @@ func (d *DockerContainerService) GetOrchestrationDetail(name string) ([]types.Co
 
 // GetOrchestrationStatus 获取编排状态
 func (d *DockerContainerService) GetOrchestrationStatus(name string) (map[string]interface{}, error) {
-	if global.GVA_DOCKER == nil {
+	if d.cli() == nil {
 		return nil, fmt.Errorf("Docker client is not available")
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	containers, err := global.GVA_DOCKER.ContainerList(ctx, types.ContainerListOptions{All: true})
+	containers, err := d.cli().ContainerList(ctx, types.ContainerListOptions{All: true})
 	if err != nil {
 		global.GVA_LOG.Error("Failed to get container list for orchestration status", zap.String("name", name), zap.Error(err))
 		return nil, fmt.Errorf("failed to get container list: %v", err)
@@ -647,14 +651,14 @@ func (d *DockerContainerService) GetOrchestrationStatus(name string) (map[string
 
 // DeleteOrchestration 删除编排（删除所有相关容器）
 func (d *DockerContainerService) DeleteOrchestration(name string) ([]string, error) {
-	if global.GVA_DOCKER == nil {
+	if d.cli() == nil {
 		return nil, fmt.Errorf("Docker client is not available")
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 	defer cancel()
 
-	containers, err := global.GVA_DOCKER.ContainerList(ctx, types.ContainerListOptions{All: true})
+	containers, err := d.cli().ContainerList(ctx, types.ContainerListOptions{All: true})
 	if err != nil {
 		global.GVA_LOG.Error("Failed to get container list for orchestration deletion", zap.String("name", name), zap.Error(err))
 		return nil, fmt.Errorf("failed to get container list: %v", err)
@@ -679,7 +683,7 @@ func (d *DockerContainerService) DeleteOrchestration(name string) ([]string, err
 		}
 		
 		if orchestrationName == name {
-			err := global.GVA_DOCKER.ContainerRemove(ctx, ctn.ID, types.ContainerRemoveOptions{Force: true})
+			err := d.cli().ContainerRemove(ctx, ctn.ID, types.ContainerRemoveOptions{Force: true})
 			if err != nil {
 				global.GVA_LOG.Error("Failed to remove container in orchestration", zap.String("containerID", ctn.ID), zap.String("orchestration", name), zap.Error(err))
 				failed = append(failed, ctn.ID)
@@ -691,6 +695,25 @@ func (d *DockerContainerService) DeleteOrchestration(name string) ([]string, err
 		return failed, fmt.Errorf("some containers failed to delete")
 	}
 
+	// 同步删除编排记录及服务配置
+	err = global.GVA_DB.Transaction(func(tx *gorm.DB) error {
+		var record dockerModel.DockerOrchestration
+		if err := tx.Where("host_id = ? AND name = ?", d.hostID(), name).First(&record).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+		if err := tx.Where("orchestration_id = ?", record.ID).Delete(&dockerModel.DockerOrchestrationService{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(&record).Error
+	})
+	if err != nil {
+		global.GVA_LOG.Error("Failed to delete orchestration record", zap.String("name", name), zap.Error(err))
+		return nil, fmt.Errorf("failed to delete orchestration record: %v", err)
+	}
+
 	global.GVA_LOG.Info("Orchestration deleted successfully", zap.String("name", name))
 	return nil, nil
 }
@@ -0,0 +1,482 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/client"
+	"github.com/docker/go-connections/nat"
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	dockerModel "github.com/flipped-aurora/gin-vue-admin/server/model/docker"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/docker/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/docker/response"
+	"go.uber.org/zap"
+)
+
+// containerCreateTimeout 创建/重建容器（含拉取镜像）的超时时间
+const containerCreateTimeout = 10 * time.Minute
+
+// CreateContainer 按完整配置创建容器
+func (d *DockerContainerService) CreateContainer(req request.ContainerCreateRequest) (*response.ContainerDetail, error) {
+	if d.cli() == nil {
+		return nil, fmt.Errorf("Docker client is not available")
+	}
+
+	spec, err := buildContainerCreateSpec(req)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), containerCreateTimeout)
+	defer cancel()
+
+	if req.PullImage {
+		if err := pullImage(ctx, d.streamCli(), req.Image); err != nil {
+			return nil, err
+		}
+	}
+
+	containerID, err := createContainerFromSpec(ctx, d.streamCli(), spec, req.Start)
+	if err != nil {
+		// 创建成功但连接网络或启动失败时清理半成品容器
+		if containerID != "" {
+			_ = d.cli().ContainerRemove(ctx, containerID, types.ContainerRemoveOptions{Force: true})
+		}
+		global.GVA_LOG.Error("Failed to create container", zap.String("image", req.Image), zap.Error(err))
+		return nil, err
+	}
+
+	global.GVA_LOG.Info("Container created successfully", zap.String("containerID", containerID), zap.String("name", req.Name))
+	return d.GetContainerDetail(containerID)
+}
+
+// RecreateContainer 应用修改后重建容器，未修改的配置与原容器一致，保留容器名称、网络别名与匿名卷
+// 重建失败时恢复原容器
+func (d *DockerContainerService) RecreateContainer(containerID string, req request.ContainerRecreateRequest) (*response.ContainerDetail, error) {
+	if d.cli() == nil {
+		return nil, fmt.Errorf("Docker client is not available")
+	}
+
+	if containerID == "" {
+		return nil, fmt.Errorf("container ID cannot be empty")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), containerCreateTimeout)
+	defer cancel()
+
+	containerJSON, err := d.cli().ContainerInspect(ctx, containerID)
+	if err != nil {
+		if client.IsErrNotFound(err) {
+			return nil, fmt.Errorf("container not found")
+		}
+		return nil, fmt.Errorf("failed to inspect container: %v", err)
+	}
+	if containerJSON.ContainerJSONBase == nil || containerJSON.Config == nil || containerJSON.HostConfig == nil {
+		return nil, fmt.Errorf("failed to inspect container: incomplete configuration")
+	}
+	name := strings.TrimPrefix(containerJSON.Name, "/")
+
+	// 更换镜像时读取旧镜像的默认配置，用于判断命令、入口点与环境变量是否来自旧镜像
+	var oldImage *container.Config
+	if req.Image != "" && req.Image != containerJSON.Config.Image {
+		if inspect, _, err := d.cli().ImageInspectWithRaw(ctx, containerJSON.Image); err == nil {
+			oldImage = inspect.Config
+		}
+	}
+	createSpec, err := containerRecreateSpec(containerJSON, oldImage, req)
+	if err != nil {
+		return nil, err
+	}
+	if req.PullImage {
+		if err := pullImage(ctx, d.streamCli(), createSpec.Config.Image); err != nil {
+			return nil, err
+		}
+	} else if err := ensureImage(ctx, d.streamCli(), createSpec.Config.Image); err != nil {
+		return nil, err
+	}
+
+	wasRunning := containerJSON.State != nil && containerJSON.State.Running
+	if wasRunning {
+		if err := d.cli().ContainerStop(ctx, containerJSON.ID, nil); err != nil {
+			return nil, fmt.Errorf("failed to stop container: %v", err)
+		}
+	}
+
+	// 先将原容器改名让出名称，新容器创建失败时再改回
+	backupName := fmt.Sprintf("%s_old_%d", name, time.Now().Unix())
+	if err := d.cli().ContainerRename(ctx, containerJSON.ID, backupName); err != nil {
+		d.restoreContainer(ctx, containerJSON.ID, "", wasRunning)
+		return nil, fmt.Errorf("failed to rename container: %v", err)
+	}
+
+	newID, err := createContainerFromSpec(ctx, d.streamCli(), createSpec, wasRunning)
+	if err != nil {
+		if newID != "" {
+			_ = d.cli().ContainerRemove(ctx, newID, types.ContainerRemoveOptions{Force: true})
+		}
+		d.restoreContainer(ctx, containerJSON.ID, name, wasRunning)
+		global.GVA_LOG.Error("Failed to recreate container", zap.String("containerID", containerJSON.ID), zap.Error(err))
+		return nil, err
+	}
+
+	if err := d.cli().ContainerRemove(ctx, containerJSON.ID, types.ContainerRemoveOptions{Force: true}); err != nil {
+		global.GVA_LOG.Warn("Failed to remove old container after recreate", zap.String("containerID", containerJSON.ID), zap.Error(err))
+	}
+
+	global.GVA_LOG.Info("Container recreated successfully", zap.String("oldID", containerJSON.ID), zap.String("newID", newID), zap.String("name", name))
+	return d.GetContainerDetail(newID)
+}
+
+// restoreContainer 重建失败时恢复原容器名称与运行状态
+func (d *DockerContainerService) restoreContainer(ctx context.Context, containerID, name string, start bool) {
+	if name != "" {
+		if err := d.cli().ContainerRename(ctx, containerID, name); err != nil {
+			global.GVA_LOG.Error("Failed to restore container name", zap.String("containerID", containerID), zap.Error(err))
+		}
+	}
+	if start {
+		if err := d.cli().ContainerStart(ctx, containerID, types.ContainerStartOptions{}); err != nil {
+			global.GVA_LOG.Error("Failed to restart original container", zap.String("containerID", containerID), zap.Error(err))
+		}
+	}
+}
+
+// buildContainerCreateSpec 将创建请求转换为容器创建参数
+func buildContainerCreateSpec(req request.ContainerCreateRequest) (*containerCreateSpec, error) {
+	if req.Image == "" {
+		return nil, fmt.Errorf("image is required")
+	}
+	if req.NetworkMode != "" && len(req.Networks) > 0 {
+		return nil, fmt.Errorf("networkMode and networks cannot be used together")
+	}
+	if req.CPUs < 0 || req.Memory < 0 || req.MemoryReservation < 0 || req.CPUShares < 0 {
+		return nil, fmt.Errorf("resource limits cannot be negative")
+	}
+
+	config := &container.Config{
+		Image:      req.Image,
+		Cmd:        req.Cmd,
+		Entrypoint: req.Entrypoint,
+		Env:        req.Env,
+		WorkingDir: req.WorkingDir,
+		User:       req.User,
+		Hostname:   req.Hostname,
+		Labels:     req.Labels,
+		Tty:        req.Tty,
+		OpenStdin:  req.OpenStdin,
+	}
+	healthcheck, err := convertContainerHealthcheck(req.Healthcheck)
+	if err != nil {
+		return nil, err
+	}
+	config.Healthcheck = healthcheck
+
+	hostConfig := &container.HostConfig{Privileged: req.Privileged}
+	if hostConfig.RestartPolicy, err = parseRestartPolicy(req.RestartPolicy); err != nil {
+		return nil, err
+	}
+	hostConfig.NanoCPUs = int64(req.CPUs * 1e9)
+	hostConfig.CPUShares = req.CPUShares
+	hostConfig.Memory = req.Memory
+	hostConfig.MemoryReservation = req.MemoryReservation
+
+	if len(req.Ports) > 0 {
+		if config.ExposedPorts, hostConfig.PortBindings, err = convertPortMappings(req.Ports); err != nil {
+			return nil, err
+		}
+	}
+	if hostConfig.Mounts, err = convertVolumeMounts(req.Mounts); err != nil {
+		return nil, err
+	}
+
+	spec := &containerCreateSpec{
+		Name:          req.Name,
+		Config:        config,
+		HostConfig:    hostConfig,
+		Networking:    &network.NetworkingConfig{},
+		ExtraNetworks: map[string]*network.EndpointSettings{},
+	}
+	if req.NetworkMode != "" {
+		hostConfig.NetworkMode = container.NetworkMode(req.NetworkMode)
+	}
+	for i, nw := range req.Networks {
+		endpoint := &network.EndpointSettings{Aliases: nw.Aliases}
+		if nw.IPv4Address != "" || nw.IPv6Address != "" {
+			endpoint.IPAMConfig = &network.EndpointIPAMConfig{IPv4Address: nw.IPv4Address, IPv6Address: nw.IPv6Address}
+		}
+		if i == 0 {
+			hostConfig.NetworkMode = container.NetworkMode(nw.Name)
+			spec.Networking.EndpointsConfig = map[string]*network.EndpointSettings{nw.Name: endpoint}
+		} else {
+			spec.ExtraNetworks[nw.Name] = endpoint
+		}
+	}
+	return spec, nil
+}
+
+// convertPortMappings 将端口映射转换为暴露端口与端口绑定
+func convertPortMappings(ports []request.PortMapping) (nat.PortSet, nat.PortMap, error) {
+	specs := make([]string, 0, len(ports))
+	for _, port := range ports {
+		if port.ContainerPort <= 0 || port.ContainerPort > 65535 || port.HostPort < 0 || port.HostPort > 65535 {
+			return nil, nil, fmt.Errorf("invalid port mapping %d:%d", port.HostPort, port.ContainerPort)
+		}
+		specs = append(specs, portMappingSpec(port))
+	}
+	exposed, bindings, err := nat.ParsePortSpecs(specs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid ports: %v", err)
+	}
+	return exposed, bindings, nil
+}
+
+// convertVolumeMounts 将挂载列表转换为容器挂载，bind 来源必须为绝对路径
+func convertVolumeMounts(volumes []request.VolumeMount) ([]mount.Mount, error) {
+	var mounts []mount.Mount
+	for _, vol := range volumes {
+		if !strings.HasPrefix(vol.ContainerPath, "/") {
+			return nil, fmt.Errorf("mount target must be an absolute path: %q", vol.ContainerPath)
+		}
+		m := mount.Mount{Target: vol.ContainerPath, ReadOnly: vol.ReadOnly, Source: vol.HostPath}
+		switch vol.Type {
+		case "bind":
+			if !filepath.IsAbs(vol.HostPath) {
+				return nil, fmt.Errorf("bind source must be an absolute path: %q", vol.HostPath)
+			}
+			m.Type = mount.TypeBind
+		case "", "volume":
+			m.Type = mount.TypeVolume
+		case "tmpfs":
+			m.Type = mount.TypeTmpfs
+			m.Source = ""
+		default:
+			return nil, fmt.Errorf("unsupported mount type %q", vol.Type)
+		}
+		mounts = append(mounts, m)
+	}
+	return mounts, nil
+}
+
+// convertContainerHealthcheck 转换健康检查配置，复用 compose 的解析规则
+func convertContainerHealthcheck(hc *request.ContainerHealthcheck) (*container.HealthConfig, error) {
+	if hc == nil {
+		return nil, nil
+	}
+	return convertComposeHealthcheck(&dockerModel.ComposeHealthcheck{
+		Test:        hc.Test,
+		Interval:    hc.Interval,
+		Timeout:     hc.Timeout,
+		StartPeriod: hc.StartPeriod,
+		Retries:     hc.Retries,
+		Disable:     hc.Disable,
+	})
+}
+
+// containerRecreateSpec 以原容器的完整配置为基础生成重建参数，只覆盖重建请求中修改的字段；
+// 能力、设备、日志、DNS、ulimit、sysctl 等未开放编辑的配置原样沿用。oldImage 为更换镜像时旧镜像的默认配置
+func containerRecreateSpec(containerJSON types.ContainerJSON, oldImage *container.Config, edits request.ContainerRecreateRequest) (*containerCreateSpec, error) {
+	config := *containerJSON.Config
+	hostConfig := *containerJSON.HostConfig
+	shortID := containerJSON.ID
+	if len(shortID) > 12 {
+		shortID = shortID[:12]
+	}
+
+	// 默认主机名为容器短ID，不沿用以免与新容器不一致
+	if config.Hostname == shortID {
+		config.Hostname = ""
+	}
+	if edits.Image != "" && edits.Image != config.Image {
+		// 更换镜像时，沿用自旧镜像默认值的命令、入口点与环境变量改用新镜像的默认值
+		if oldImage != nil {
+			if reflect.DeepEqual(config.Cmd, oldImage.Cmd) {
+				config.Cmd = nil
+			}
+			if reflect.DeepEqual(config.Entrypoint, oldImage.Entrypoint) {
+				config.Entrypoint = nil
+			}
+			config.Env = withoutImageEnv(config.Env, oldImage.Env)
+		}
+		config.Image = edits.Image
+	}
+
+	if edits.Cmd != nil {
+		config.Cmd = edits.Cmd
+	}
+	if edits.Entrypoint != nil {
+		config.Entrypoint = edits.Entrypoint
+	}
+	if edits.Env != nil {
+		config.Env = edits.Env
+	}
+	labels := config.Labels
+	if edits.Labels != nil {
+		labels = edits.Labels
+	}
+	config.Labels = make(map[string]string, len(labels))
+	for key, value := range labels {
+		config.Labels[key] = value
+	}
+	// 手动修改后的容器不再与编排文件一致，去掉配置哈希以便下次部署编排时重建
+	delete(config.Labels, composeConfigHashLabel)
+	if edits.Healthcheck != nil {
+		healthcheck, err := convertContainerHealthcheck(edits.Healthcheck)
+		if err != nil {
+			return nil, err
+		}
+		config.Healthcheck = healthcheck
+	}
+
+	if edits.Ports != nil {
+		exposed, bindings, err := convertPortMappings(edits.Ports)
+		if err != nil {
+			return nil, err
+		}
+		merged := make(nat.PortSet, len(config.ExposedPorts)+len(exposed))
+		for port := range config.ExposedPorts {
+			merged[port] = struct{}{}
+		}
+		for port := range exposed {
+			merged[port] = struct{}{}
+		}
+		config.ExposedPorts = merged
+		hostConfig.PortBindings = bindings
+	}
+
+	if edits.Mounts != nil {
+		mounts, err := convertVolumeMounts(edits.Mounts)
+		if err != nil {
+			return nil, err
+		}
+		hostConfig.Binds = nil
+		hostConfig.Mounts = mounts
+	} else {
+		hostConfig.Mounts = append(append([]mount.Mount(nil), hostConfig.Mounts...), anonymousVolumeMounts(containerJSON)...)
+	}
+
+	if edits.RestartPolicy != "" {
+		policy, err := parseRestartPolicy(edits.RestartPolicy)
+		if err != nil {
+			return nil, err
+		}
+		hostConfig.RestartPolicy = policy
+	}
+	if (edits.CPUs != nil && *edits.CPUs < 0) || (edits.CPUShares != nil && *edits.CPUShares < 0) ||
+		(edits.Memory != nil && *edits.Memory < 0) || (edits.MemoryReservation != nil && *edits.MemoryReservation < 0) {
+		return nil, fmt.Errorf("resource limits cannot be negative")
+	}
+	if edits.CPUs != nil {
+		hostConfig.NanoCPUs = int64(*edits.CPUs * 1e9)
+		// NanoCPUs 与 CPUPeriod/CPUQuota 不能同时设置
+		if hostConfig.NanoCPUs > 0 {
+			hostConfig.CPUPeriod = 0
+			hostConfig.CPUQuota = 0
+		}
+	}
+	if edits.CPUShares != nil {
+		hostConfig.CPUShares = *edits.CPUShares
+	}
+	if edits.Memory != nil {
+		hostConfig.Memory = *edits.Memory
+		// 原交换上限小于新的内存限制时无法创建，改用默认值
+		if hostConfig.MemorySwap > 0 && hostConfig.MemorySwap < hostConfig.Memory {
+			hostConfig.MemorySwap = 0
+		}
+	}
+	if edits.MemoryReservation != nil {
+		hostConfig.MemoryReservation = *edits.MemoryReservation
+	}
+	if edits.Privileged != nil {
+		hostConfig.Privileged = *edits.Privileged
+	}
+
+	spec := &containerCreateSpec{
+		Name:          strings.TrimPrefix(containerJSON.Name, "/"),
+		Config:        &config,
+		HostConfig:    &hostConfig,
+		Networking:    &network.NetworkingConfig{},
+		ExtraNetworks: map[string]*network.EndpointSettings{},
+	}
+	mode := string(hostConfig.NetworkMode)
+	if mode == "host" || mode == "none" || strings.HasPrefix(mode, "container:") || containerJSON.NetworkSettings == nil {
+		return spec, nil
+	}
+	if mode == "" || mode == "default" {
+		mode = "bridge"
+	}
+	for name, current := range containerJSON.NetworkSettings.Networks {
+		if current == nil {
+			continue
+		}
+		// 只沿用创建时指定的端点配置，IP、网关等运行时分配的信息由 Docker 重新分配
+		endpoint := &network.EndpointSettings{Links: current.Links, DriverOpts: current.DriverOpts}
+		if current.IPAMConfig != nil {
+			ipam := *current.IPAMConfig
+			endpoint.IPAMConfig = &ipam
+		}
+		// 默认 bridge 网络不支持别名；Docker 自动添加的短ID别名不沿用
+		if name != "bridge" {
+			for _, alias := range current.Aliases {
+				if alias != shortID {
+					endpoint.Aliases = append(endpoint.Aliases, alias)
+				}
+			}
+		}
+		if name == mode {
+			spec.Networking.EndpointsConfig = map[string]*network.EndpointSettings{name: endpoint}
+		} else {
+			spec.ExtraNetworks[name] = endpoint
+		}
+	}
+	return spec, nil
+}
+
+// anonymousVolumeMounts 镜像声明的匿名卷不在 HostConfig 中，重建时按名称重新挂载以保留数据
+func anonymousVolumeMounts(containerJSON types.ContainerJSON) []mount.Mount {
+	configured := map[string]bool{}
+	for _, m := range containerJSON.HostConfig.Mounts {
+		configured[m.Target] = true
+	}
+	for _, bind := range containerJSON.HostConfig.Binds {
+		if parts := strings.Split(bind, ":"); len(parts) >= 2 {
+			configured[parts[1]] = true
+		}
+	}
+	for target := range containerJSON.HostConfig.Tmpfs {
+		configured[target] = true
+	}
+
+	var mounts []mount.Mount
+	for _, m := range containerJSON.Mounts {
+		if m.Type != mount.TypeVolume || m.Name == "" || configured[m.Destination] {
+			continue
+		}
+		mounts = append(mounts, mount.Mount{Type: mount.TypeVolume, Source: m.Name, Target: m.Destination, ReadOnly: !m.RW})
+	}
+	sort.Slice(mounts, func(i, j int) bool { return mounts[i].Target < mounts[j].Target })
+	return mounts
+}
+
+// withoutImageEnv 去掉与镜像默认值相同的环境变量
+func withoutImageEnv(env, imageEnv []string) []string {
+	defaults := make(map[string]bool, len(imageEnv))
+	for _, item := range imageEnv {
+		defaults[item] = true
+	}
+	var result []string
+	for _, item := range env {
+		if !defaults[item] {
+			result = append(result, item)
+		}
+	}
+	return result
+}
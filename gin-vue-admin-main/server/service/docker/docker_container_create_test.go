@@ -0,0 +1,228 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/strslice"
+	"github.com/docker/go-connections/nat"
+	"github.com/docker/go-units"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/docker/request"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recreateTestContainer 包含大量未开放编辑的配置的容器
+func recreateTestContainer() types.ContainerJSON {
+	stopTimeout := 30
+	init := true
+	return types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{
+			ID:    "0123456789abcdef0123",
+			Name:  "/web",
+			Image: "sha256:old",
+			HostConfig: &container.HostConfig{
+				Binds:         []string{"/srv/conf:/etc/nginx/conf.d:ro"},
+				NetworkMode:   "app",
+				RestartPolicy: container.RestartPolicy{Name: "on-failure", MaximumRetryCount: 3},
+				CapAdd:        strslice.StrSlice{"NET_ADMIN"},
+				CapDrop:       strslice.StrSlice{"MKNOD"},
+				DNS:           []string{"10.0.0.2"},
+				DNSSearch:     []string{"corp.local"},
+				ExtraHosts:    []string{"db.local:10.0.0.5"},
+				GroupAdd:      []string{"audio"},
+				IpcMode:       "shareable",
+				PidMode:       "host",
+				SecurityOpt:   []string{"no-new-privileges"},
+				ShmSize:       256 << 20,
+				Sysctls:       map[string]string{"net.core.somaxconn": "1024"},
+				Tmpfs:         map[string]string{"/run": "size=64m"},
+				Init:          &init,
+				LogConfig:     container.LogConfig{Type: "json-file", Config: map[string]string{"max-size": "10m"}},
+				Resources: container.Resources{
+					Memory:     512 << 20,
+					MemorySwap: 600 << 20,
+					CPUPeriod:  100000,
+					CPUQuota:   50000,
+					Devices:    []container.DeviceMapping{{PathOnHost: "/dev/fuse", PathInContainer: "/dev/fuse", CgroupPermissions: "rwm"}},
+					Ulimits:    []*units.Ulimit{{Name: "nofile", Soft: 65535, Hard: 65535}},
+				},
+			},
+		},
+		Mounts: []types.MountPoint{
+			{Type: mount.TypeBind, Source: "/srv/conf", Destination: "/etc/nginx/conf.d"},
+			{Type: mount.TypeVolume, Name: "3f9a1c", Destination: "/var/cache/nginx", RW: true},
+		},
+		Config: &container.Config{
+			Hostname:     "0123456789ab",
+			Image:        "nginx:1.24",
+			Cmd:          strslice.StrSlice{"nginx", "-g", "daemon off;"},
+			Env:          []string{"PATH=/usr/sbin:/usr/bin", "NGINX_VERSION=1.24.0", "APP_ENV=prod"},
+			Labels:       map[string]string{"app": "web", composeConfigHashLabel: "abc"},
+			ExposedPorts: nat.PortSet{"80/tcp": {}},
+			StopSignal:   "SIGQUIT",
+			StopTimeout:  &stopTimeout,
+		},
+		NetworkSettings: &types.NetworkSettings{
+			Networks: map[string]*network.EndpointSettings{
+				"app": {
+					Aliases:    []string{"web", "0123456789ab"},
+					IPAMConfig: &network.EndpointIPAMConfig{IPv4Address: "172.30.0.10"},
+					IPAddress:  "172.30.0.10",
+					NetworkID:  "n1",
+				},
+				"monitor": {Aliases: []string{"web-metrics"}, IPAddress: "172.31.0.4"},
+			},
+		},
+	}
+}
+
+func TestContainerRecreateSpecKeepsUneditedFields(t *testing.T) {
+	memory := int64(1 << 30)
+	cpus := 1.5
+	original := recreateTestContainer()
+	spec, err := containerRecreateSpec(original, nil, request.ContainerRecreateRequest{
+		Env:    []string{"APP_ENV=staging"},
+		Memory: &memory,
+		CPUs:   &cpus,
+	})
+	require.NoError(t, err)
+
+	// 修改的字段
+	assert.Equal(t, []string{"APP_ENV=staging"}, spec.Config.Env)
+	assert.Equal(t, memory, spec.HostConfig.Memory)
+	assert.Equal(t, int64(0), spec.HostConfig.MemorySwap)
+	assert.Equal(t, int64(1.5e9), spec.HostConfig.NanoCPUs)
+	assert.Zero(t, spec.HostConfig.CPUQuota)
+
+	// 未修改的字段原样沿用
+	hostConfig := original.HostConfig
+	assert.Equal(t, "web", spec.Name)
+	assert.Equal(t, hostConfig.CapAdd, spec.HostConfig.CapAdd)
+	assert.Equal(t, hostConfig.CapDrop, spec.HostConfig.CapDrop)
+	assert.Equal(t, hostConfig.Devices, spec.HostConfig.Devices)
+	assert.Equal(t, hostConfig.LogConfig, spec.HostConfig.LogConfig)
+	assert.Equal(t, hostConfig.DNS, spec.HostConfig.DNS)
+	assert.Equal(t, hostConfig.DNSSearch, spec.HostConfig.DNSSearch)
+	assert.Equal(t, hostConfig.ExtraHosts, spec.HostConfig.ExtraHosts)
+	assert.Equal(t, hostConfig.Ulimits, spec.HostConfig.Ulimits)
+	assert.Equal(t, hostConfig.Sysctls, spec.HostConfig.Sysctls)
+	assert.Equal(t, hostConfig.ShmSize, spec.HostConfig.ShmSize)
+	assert.Equal(t, hostConfig.SecurityOpt, spec.HostConfig.SecurityOpt)
+	assert.Equal(t, hostConfig.PidMode, spec.HostConfig.PidMode)
+	assert.Equal(t, hostConfig.IpcMode, spec.HostConfig.IpcMode)
+	assert.Equal(t, hostConfig.Tmpfs, spec.HostConfig.Tmpfs)
+	assert.Equal(t, hostConfig.GroupAdd, spec.HostConfig.GroupAdd)
+	assert.Equal(t, hostConfig.Init, spec.HostConfig.Init)
+	assert.Equal(t, hostConfig.RestartPolicy, spec.HostConfig.RestartPolicy)
+	assert.Equal(t, hostConfig.Binds, spec.HostConfig.Binds)
+	assert.Equal(t, "SIGQUIT", spec.Config.StopSignal)
+	assert.Equal(t, 30, *spec.Config.StopTimeout)
+	assert.Equal(t, original.Config.Cmd, spec.Config.Cmd)
+
+	// 默认主机名与编排配置哈希不沿用，原容器配置不被修改
+	assert.Empty(t, spec.Config.Hostname)
+	assert.Equal(t, map[string]string{"app": "web"}, spec.Config.Labels)
+	assert.Equal(t, "abc", original.Config.Labels[composeConfigHashLabel])
+	assert.Equal(t, int64(512<<20), original.HostConfig.Memory)
+
+	// 匿名卷按名称重新挂载，bind 挂载已在 Binds 中不重复添加
+	assert.Equal(t, []mount.Mount{{Type: mount.TypeVolume, Source: "3f9a1c", Target: "/var/cache/nginx"}}, spec.HostConfig.Mounts)
+
+	// 主网络在创建时加入，只沿用指定的地址与别名
+	require.Contains(t, spec.Networking.EndpointsConfig, "app")
+	app := spec.Networking.EndpointsConfig["app"]
+	assert.Equal(t, []string{"web"}, app.Aliases)
+	assert.Equal(t, "172.30.0.10", app.IPAMConfig.IPv4Address)
+	assert.Empty(t, app.IPAddress)
+	assert.Empty(t, app.NetworkID)
+	require.Contains(t, spec.ExtraNetworks, "monitor")
+	assert.Equal(t, []string{"web-metrics"}, spec.ExtraNetworks["monitor"].Aliases)
+}
+
+func TestContainerRecreateSpecChangeImage(t *testing.T) {
+	original := recreateTestContainer()
+	oldImage := &container.Config{
+		Cmd: strslice.StrSlice{"nginx", "-g", "daemon off;"},
+		Env: []string{"PATH=/usr/sbin:/usr/bin", "NGINX_VERSION=1.24.0"},
+	}
+	spec, err := containerRecreateSpec(original, oldImage, request.ContainerRecreateRequest{
+		Image: "nginx:1.25",
+		Ports: []request.PortMapping{{HostPort: 8080, ContainerPort: 80}},
+		Mounts: []request.VolumeMount{
+			{Type: "volume", HostPath: "cache", ContainerPath: "/var/cache/nginx"},
+		},
+	})
+	require.NoError(t, err)
+
+	// 来自旧镜像默认值的命令与环境变量改用新镜像的默认值
+	assert.Equal(t, "nginx:1.25", spec.Config.Image)
+	assert.Nil(t, spec.Config.Cmd)
+	assert.Equal(t, []string{"APP_ENV=prod"}, spec.Config.Env)
+
+	assert.Equal(t, []nat.PortBinding{{HostPort: "8080"}}, spec.HostConfig.PortBindings["80/tcp"])
+	assert.Nil(t, spec.HostConfig.Binds)
+	assert.Equal(t, []mount.Mount{{Type: mount.TypeVolume, Source: "cache", Target: "/var/cache/nginx"}}, spec.HostConfig.Mounts)
+	assert.Equal(t, original.HostConfig.CapAdd, spec.HostConfig.CapAdd)
+}
+
+func TestContainerRecreateSpecInvalidEdits(t *testing.T) {
+	negative := int64(-1)
+	cases := []struct {
+		name  string
+		edits request.ContainerRecreateRequest
+		err   string
+	}{
+		{"restart", request.ContainerRecreateRequest{RestartPolicy: "sometimes"}, "unsupported restart policy"},
+		{"memory", request.ContainerRecreateRequest{Memory: &negative}, "resource limits cannot be negative"},
+		{"port", request.ContainerRecreateRequest{Ports: []request.PortMapping{{ContainerPort: 70000}}}, "invalid port mapping"},
+		{"mount", request.ContainerRecreateRequest{Mounts: []request.VolumeMount{{Type: "bind", HostPath: "data", ContainerPath: "/data"}}}, "bind source must be an absolute path"},
+	}
+	for _, c := range cases {
+		_, err := containerRecreateSpec(recreateTestContainer(), nil, c.edits)
+		assert.ErrorContains(t, err, c.err, c.name)
+	}
+}
+
+func TestBuildContainerCreateSpec(t *testing.T) {
+	spec, err := buildContainerCreateSpec(request.ContainerCreateRequest{
+		Name:          "api",
+		Image:         "app:1.0",
+		RestartPolicy: "unless-stopped",
+		CPUs:          0.5,
+		Ports:         []request.PortMapping{{HostIP: "127.0.0.1", HostPort: 8080, ContainerPort: 80}},
+		Mounts: []request.VolumeMount{
+			{Type: "bind", HostPath: "/srv/data", ContainerPath: "/data", ReadOnly: true},
+			{HostPath: "logs", ContainerPath: "/logs"},
+			{Type: "tmpfs", HostPath: "ignored", ContainerPath: "/tmp"},
+		},
+		Networks: []request.ContainerNetworkSpec{
+			{Name: "app", Aliases: []string{"api"}, IPv4Address: "172.30.0.20"},
+			{Name: "monitor"},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "app:1.0", spec.Config.Image)
+	assert.Equal(t, container.RestartPolicy{Name: "unless-stopped"}, spec.HostConfig.RestartPolicy)
+	assert.Equal(t, int64(5e8), spec.HostConfig.NanoCPUs)
+	assert.Contains(t, spec.Config.ExposedPorts, nat.Port("80/tcp"))
+	assert.Equal(t, []nat.PortBinding{{HostIP: "127.0.0.1", HostPort: "8080"}}, spec.HostConfig.PortBindings["80/tcp"])
+	assert.Equal(t, []mount.Mount{
+		{Type: mount.TypeBind, Source: "/srv/data", Target: "/data", ReadOnly: true},
+		{Type: mount.TypeVolume, Source: "logs", Target: "/logs"},
+		{Type: mount.TypeTmpfs, Target: "/tmp"},
+	}, spec.HostConfig.Mounts)
+	assert.Equal(t, container.NetworkMode("app"), spec.HostConfig.NetworkMode)
+	assert.Equal(t, []string{"api"}, spec.Networking.EndpointsConfig["app"].Aliases)
+	assert.Equal(t, "172.30.0.20", spec.Networking.EndpointsConfig["app"].IPAMConfig.IPv4Address)
+	assert.Contains(t, spec.ExtraNetworks, "monitor")
+
+	_, err = buildContainerCreateSpec(request.ContainerCreateRequest{Image: "app:1.0", NetworkMode: "host", Networks: []request.ContainerNetworkSpec{{Name: "app"}}})
+	assert.EqualError(t, err, "networkMode and networks cannot be used together")
+	_, err = buildContainerCreateSpec(request.ContainerCreateRequest{Image: "app:1.0", Mounts: []request.VolumeMount{{ContainerPath: "data"}}})
+	assert.ErrorContains(t, err, "mount target must be an absolute path")
+}
@@ -0,0 +1,127 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/docker/request"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultExecShell       = "/bin/sh"
+	defaultExecIdleTimeout = 10 * time.Minute
+	maxExecIdleTimeout     = time.Hour
+)
+
+// execShellRegexp shell 只允许单个可执行文件路径，避免拼接任意命令
+var execShellRegexp = regexp.MustCompile(`^[A-Za-z0-9_./-]+$`)
+
+// ContainerExecSession 容器终端会话
+type ContainerExecSession struct {
+	ID          string                 // exec ID
+	ContainerID string                 // 容器ID
+	Conn        types.HijackedResponse // 与容器进程的双向连接，TTY 模式下输出不带多路复用头部
+	IdleTimeout time.Duration          // 空闲超时时间
+	cli         *client.Client         // 会话所在主机的客户端
+}
+
+// Resize 调整终端窗口大小
+func (s *ContainerExecSession) Resize(ctx context.Context, rows, cols uint) error {
+	if rows == 0 || cols == 0 {
+		return nil
+	}
+	return s.cli.ContainerExecResize(ctx, s.ID, types.ResizeOptions{Height: rows, Width: cols})
+}
+
+// ExitCode 获取终端进程退出码，进程仍在运行时返回 -1
+func (s *ContainerExecSession) ExitCode(ctx context.Context) int {
+	inspect, err := s.cli.ContainerExecInspect(ctx, s.ID)
+	if err != nil || inspect.Running {
+		return -1
+	}
+	return inspect.ExitCode
+}
+
+// Close 关闭会话连接，容器内的 shell 会随之收到 EOF 退出
+func (s *ContainerExecSession) Close() {
+	s.Conn.Close()
+}
+
+// StartExecSession 在运行中的容器内启动交互式 shell
+func (d *DockerContainerService) StartExecSession(ctx context.Context, containerID string, options request.ContainerExecOptions) (*ContainerExecSession, error) {
+	if d.cli() == nil {
+		return nil, fmt.Errorf("Docker client is not available")
+	}
+
+	if containerID == "" {
+		return nil, fmt.Errorf("container ID cannot be empty")
+	}
+
+	shell := options.Shell
+	if shell == "" {
+		shell = defaultExecShell
+	}
+	if !execShellRegexp.MatchString(shell) {
+		return nil, fmt.Errorf("invalid shell %q", shell)
+	}
+
+	idleTimeout := defaultExecIdleTimeout
+	if options.IdleTimeout > 0 {
+		idleTimeout = time.Duration(options.IdleTimeout) * time.Second
+		if idleTimeout > maxExecIdleTimeout {
+			idleTimeout = maxExecIdleTimeout
+		}
+	}
+
+	containerJSON, err := d.cli().ContainerInspect(ctx, containerID)
+	if err != nil {
+		if client.IsErrNotFound(err) {
+			return nil, fmt.Errorf("container not found")
+		}
+		return nil, fmt.Errorf("failed to inspect container: %v", err)
+	}
+	if containerJSON.State == nil || !containerJSON.State.Running {
+		return nil, fmt.Errorf("container is not running")
+	}
+
+	created, err := d.cli().ContainerExecCreate(ctx, containerJSON.ID, types.ExecConfig{
+		User:         options.User,
+		WorkingDir:   options.WorkDir,
+		Tty:          true,
+		AttachStdin:  true,
+		AttachStdout: true,
+		AttachStderr: true,
+		Env:          []string{"TERM=xterm-256color"},
+		Cmd:          []string{shell},
+	})
+	if err != nil {
+		global.GVA_LOG.Error("Failed to create exec", zap.String("containerID", containerID), zap.Error(err))
+		return nil, fmt.Errorf("failed to create exec: %v", err)
+	}
+
+	conn, err := d.cli().ContainerExecAttach(ctx, created.ID, types.ExecStartCheck{Tty: true})
+	if err != nil {
+		global.GVA_LOG.Error("Failed to attach exec", zap.String("containerID", containerID), zap.Error(err))
+		return nil, fmt.Errorf("failed to attach exec: %v", err)
+	}
+
+	session := &ContainerExecSession{
+		ID:          created.ID,
+		ContainerID: containerJSON.ID,
+		Conn:        conn,
+		IdleTimeout: idleTimeout,
+		cli:         d.cli(),
+	}
+	if err := session.Resize(ctx, options.Rows, options.Cols); err != nil {
+		global.GVA_LOG.Warn("Failed to resize exec", zap.String("execID", created.ID), zap.Error(err))
+	}
+
+	global.GVA_LOG.Info("Exec session started", zap.String("containerID", containerJSON.ID), zap.String("execID", created.ID), zap.String("shell", shell))
+	return session, nil
+}
@@ -0,0 +1,136 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/docker/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/docker/response"
+	"go.uber.org/zap"
+)
+
+// maxLogLineSize 单行日志的最大缓冲长度，超出部分按行强制输出
+const maxLogLineSize = 64 * 1024
+
+// StreamContainerLogs 持续跟踪容器日志，按行回调 emit
+// ctx 取消（客户端断开）或到达 until 时返回；emit 返回错误时停止推送；日志流不受 docker.timeout 限制
+func (d *DockerContainerService) StreamContainerLogs(ctx context.Context, containerID string, options request.LogStreamOptions, emit func(response.ContainerLogLine) error) error {
+	if d.cli() == nil {
+		return fmt.Errorf("Docker client is not available")
+	}
+
+	if containerID == "" {
+		return fmt.Errorf("container ID cannot be empty")
+	}
+
+	// TTY 容器的日志没有多路复用头部，需要区别处理
+	containerJSON, err := d.cli().ContainerInspect(ctx, containerID)
+	if err != nil {
+		if client.IsErrNotFound(err) {
+			return fmt.Errorf("container not found")
+		}
+		return fmt.Errorf("failed to inspect container: %v", err)
+	}
+
+	logOptions := types.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Follow:     true,
+		Timestamps: options.Timestamps,
+		Since:      options.Since,
+		Until:      options.Until,
+		Tail:       options.Tail,
+	}
+	if logOptions.Tail == "" {
+		logOptions.Tail = "100"
+	}
+
+	streamCtx, connected, cancel := streamContext(ctx, dockerStreamConnectTimeout)
+	defer cancel()
+	logReader, err := d.streamCli().ContainerLogs(streamCtx, containerID, logOptions)
+	connected()
+	if err != nil {
+		if client.IsErrNotFound(err) {
+			return fmt.Errorf("container not found")
+		}
+		global.GVA_LOG.Error("Failed to stream container logs", zap.String("containerID", containerID), zap.Error(err))
+		return fmt.Errorf("failed to get container logs: %v", err)
+	}
+	defer logReader.Close()
+
+	stdout := &logLineWriter{stream: "stdout", timestamps: options.Timestamps, emit: emit}
+	stderr := &logLineWriter{stream: "stderr", timestamps: options.Timestamps, emit: emit}
+	if containerJSON.Config != nil && containerJSON.Config.Tty {
+		_, err = io.Copy(stdout, logReader)
+	} else {
+		_, err = stdcopy.StdCopy(stdout, stderr, logReader)
+	}
+	if flushErr := stdout.Flush(); err == nil {
+		err = flushErr
+	}
+	if flushErr := stderr.Flush(); err == nil {
+		err = flushErr
+	}
+
+	// 客户端断开导致的读取错误视为正常结束
+	if ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
+
+// logLineWriter 将日志字节流切分为行后回调
+type logLineWriter struct {
+	stream     string
+	timestamps bool
+	buf        []byte
+	emit       func(response.ContainerLogLine) error
+}
+
+func (w *logLineWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		idx := bytes.IndexByte(w.buf, '\n')
+		if idx < 0 {
+			break
+		}
+		if err := w.send(w.buf[:idx]); err != nil {
+			return 0, err
+		}
+		w.buf = w.buf[idx+1:]
+	}
+	if len(w.buf) >= maxLogLineSize {
+		if err := w.send(w.buf); err != nil {
+			return 0, err
+		}
+		w.buf = nil
+	}
+	return len(p), nil
+}
+
+// Flush 输出缓冲区中不以换行结尾的剩余内容
+func (w *logLineWriter) Flush() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+	err := w.send(w.buf)
+	w.buf = nil
+	return err
+}
+
+func (w *logLineWriter) send(raw []byte) error {
+	line := response.ContainerLogLine{Stream: w.stream, Line: strings.TrimSuffix(string(raw), "\r")}
+	if w.timestamps {
+		if ts, rest, ok := strings.Cut(line.Line, " "); ok {
+			line.Timestamp, line.Line = ts, rest
+		}
+	}
+	return w.emit(line)
+}
@@ -0,0 +1,113 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/docker/response"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamClientOutlivesRequestTimeout(t *testing.T) {
+	// 模拟守护进程持续输出日志，总时长超过客户端的请求超时
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for i := 0; i < 5; i++ {
+			fmt.Fprintf(w, "line %d\n", i)
+			w.(http.Flusher).Flush()
+			time.Sleep(100 * time.Millisecond)
+		}
+	}))
+	defer server.Close()
+
+	conn, err := utils.NewDockerClient(utils.DockerClientOptions{
+		Host:    "tcp://" + server.Listener.Addr().String(),
+		Version: "1.41",
+		Timeout: 200 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	defer conn.Close()
+
+	follow := func(cli *client.Client) (string, error) {
+		ctx, connected, cancel := streamContext(context.Background(), 200*time.Millisecond)
+		defer cancel()
+		reader, err := cli.ContainerLogs(ctx, "web", types.ContainerLogsOptions{ShowStdout: true, Follow: true})
+		connected()
+		if err != nil {
+			return "", err
+		}
+		defer reader.Close()
+		content, err := io.ReadAll(reader)
+		return string(content), err
+	}
+
+	_, err = follow(conn.Client)
+	assert.ErrorContains(t, err, "Client.Timeout")
+	content, err := follow(conn.Stream)
+	require.NoError(t, err)
+	assert.Equal(t, 5, strings.Count(content, "\n"))
+}
+
+func TestStreamContext(t *testing.T) {
+	ctx, connected, cancel := streamContext(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.True(t, connected())
+	time.Sleep(50 * time.Millisecond)
+	assert.NoError(t, ctx.Err())
+
+	ctx, connected, cancel = streamContext(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	<-ctx.Done()
+	assert.False(t, connected())
+}
+
+func TestLogLineWriter(t *testing.T) {
+	var lines []response.ContainerLogLine
+	w := &logLineWriter{stream: "stderr", timestamps: true, emit: func(line response.ContainerLogLine) error {
+		lines = append(lines, line)
+		return nil
+	}}
+
+	// 一行可能分多次写入，一次写入也可能包含多行
+	for _, chunk := range []string{"2024-05-01T10:00:00Z sta", "rting\r\n2024-05-01T10:00:01Z ready\n2024-05-01T10:00:02Z", " tail"} {
+		n, err := w.Write([]byte(chunk))
+		require.NoError(t, err)
+		assert.Equal(t, len(chunk), n)
+	}
+	require.Len(t, lines, 2)
+	assert.Equal(t, response.ContainerLogLine{Stream: "stderr", Timestamp: "2024-05-01T10:00:00Z", Line: "starting"}, lines[0])
+	assert.Equal(t, "ready", lines[1].Line)
+
+	require.NoError(t, w.Flush())
+	require.Len(t, lines, 3)
+	assert.Equal(t, "tail", lines[2].Line)
+	require.NoError(t, w.Flush())
+	assert.Len(t, lines, 3)
+}
+
+func TestLogLineWriterLongLineAndEmitError(t *testing.T) {
+	var lines []string
+	w := &logLineWriter{stream: "stdout", emit: func(line response.ContainerLogLine) error {
+		lines = append(lines, line.Line)
+		return nil
+	}}
+	_, err := w.Write([]byte(strings.Repeat("x", maxLogLineSize+10)))
+	require.NoError(t, err)
+	require.Len(t, lines, 1)
+	assert.Len(t, lines[0], maxLogLineSize+10)
+
+	// 推送失败（客户端断开）时停止写入
+	w = &logLineWriter{stream: "stdout", emit: func(response.ContainerLogLine) error { return errors.New("closed") }}
+	_, err = w.Write([]byte("a\nb\n"))
+	assert.EqualError(t, err, "closed")
+}
@@ -4,11 +4,14 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"strings"
 	"time"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	dockerRes "github.com/flipped-aurora/gin-vue-admin/server/model/docker/response"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils"
 )
 
 type DockerDiagnosticService struct{}
@@ -52,9 +55,10 @@ type VersionCompatibilityResult struct {
 
 // ConfigValidationResult 配置验证结果
 type ConfigValidationResult struct {
-	IsValid     bool     `json:"isValid"`
-	Issues      []string `json:"issues"`
-	Suggestions []string `json:"suggestions"`
+	IsValid      bool                              `json:"isValid"`
+	Issues       []string                          `json:"issues"`
+	Suggestions  []string                          `json:"suggestions"`
+	Certificates []dockerRes.DockerCertificateInfo `json:"certificates,omitempty"` // TLS 证书有效期
 }
 
 // PermissionCheckResult 权限检查结果
@@ -98,8 +102,9 @@ func (d *DockerDiagnosticService) DiagnoseConnection() (*DiagnosticResult, error
 
 // CheckClientStatus 检查Docker客户端状态
 func (d *DockerDiagnosticService) CheckClientStatus() *ClientStatusResult {
+	cli := global.GetDocker()
 	result := &ClientStatusResult{
-		IsInitialized: global.GVA_DOCKER != nil,
+		IsInitialized: cli != nil,
 	}
 
 	if !result.IsInitialized {
@@ -112,7 +117,7 @@ func (d *DockerDiagnosticService) CheckClientStatus() *ClientStatusResult {
 	defer cancel()
 
 	start := time.Now()
-	_, err := global.GVA_DOCKER.Ping(ctx)
+	_, err := cli.Ping(ctx)
 	result.PingLatency = time.Since(start).Milliseconds()
 
 	if err != nil {
@@ -173,11 +178,12 @@ func (d *DockerDiagnosticService) TestNetworkConnectivity() *NetworkTestResult {
 
 // ValidateAPIVersion 验证API版本兼容性
 func (d *DockerDiagnosticService) ValidateAPIVersion() *VersionCompatibilityResult {
+	cli := global.GetDocker()
 	result := &VersionCompatibilityResult{
 		ClientVersion: global.GVA_CONFIG.Docker.Version,
 	}
 
-	if global.GVA_DOCKER == nil {
+	if cli == nil {
 		result.Error = "Docker client is not initialized"
 		return result
 	}
@@ -186,7 +192,7 @@ func (d *DockerDiagnosticService) ValidateAPIVersion() *VersionCompatibilityResu
 	defer cancel()
 
 	// 获取服务器版本
-	version, err := global.GVA_DOCKER.ServerVersion(ctx)
+	version, err := cli.ServerVersion(ctx)
 	if err != nil {
 		result.Error = err.Error()
 		return result
@@ -235,17 +241,43 @@ func (d *DockerDiagnosticService) ValidateConfig() *ConfigValidationResult {
 		result.IsValid = false
 	}
 
+	// 检查TLS证书文件与有效期
+	if strings.HasPrefix(dockerConfig.Host, "tcp://") && dockerConfig.CertPath != "" {
+		certs, err := utils.LoadDockerCertificates(dockerConfig.CertPath, dockerConfig.TLSVerify)
+		if err != nil {
+			result.Issues = append(result.Issues, "TLS certificates are invalid: "+err.Error())
+			result.Suggestions = append(result.Suggestions, "Upload ca.pem, cert.pem and key.pem through the docker config TLS API")
+			result.IsValid = false
+		} else {
+			result.Certificates = dockerCertificateInfos(certs, time.Now())
+			rotate := false
+			for _, cert := range result.Certificates {
+				rotate = rotate || cert.Expired || cert.ExpiringSoon
+				if cert.Expired {
+					result.Issues = append(result.Issues, fmt.Sprintf("TLS certificate %s expired at %s", cert.File, cert.NotAfter.Format(time.RFC3339)))
+					result.IsValid = false
+				} else if cert.ExpiringSoon {
+					result.Issues = append(result.Issues, fmt.Sprintf("TLS certificate %s expires in %d days", cert.File, cert.DaysLeft))
+				}
+			}
+			if rotate {
+				result.Suggestions = append(result.Suggestions, "Rotate the TLS certificates before they expire")
+			}
+		}
+	}
+
 	return result
 }
 
 // CheckPermissions 检查权限
 func (d *DockerDiagnosticService) CheckPermissions() *PermissionCheckResult {
+	cli := global.GetDocker()
 	result := &PermissionCheckResult{
 		Errors:      []string{},
 		Suggestions: []string{},
 	}
 
-	if global.GVA_DOCKER == nil {
+	if cli == nil {
 		result.Errors = append(result.Errors, "Docker client is not initialized")
 		return result
 	}
@@ -254,7 +286,7 @@ func (d *DockerDiagnosticService) CheckPermissions() *PermissionCheckResult {
 	defer cancel()
 
 	// 测试容器列表权限
-	_, err := global.GVA_DOCKER.ContainerList(ctx, types.ContainerListOptions{Limit: 1})
+	_, err := cli.ContainerList(ctx, types.ContainerListOptions{Limit: 1})
 	if err != nil {
 		result.CanListContainers = false
 		result.Errors = append(result.Errors, fmt.Sprintf("Cannot list containers: %v", err))
@@ -263,7 +295,7 @@ func (d *DockerDiagnosticService) CheckPermissions() *PermissionCheckResult {
 	}
 
 	// 测试镜像列表权限
-	_, err = global.GVA_DOCKER.ImageList(ctx, types.ImageListOptions{})
+	_, err = cli.ImageList(ctx, types.ImageListOptions{})
 	if err != nil {
 		result.CanListImages = false
 		result.Errors = append(result.Errors, fmt.Sprintf("Cannot list images: %v", err))
@@ -272,7 +304,7 @@ func (d *DockerDiagnosticService) CheckPermissions() *PermissionCheckResult {
 	}
 
 	// 测试网络列表权限
-	_, err = global.GVA_DOCKER.NetworkList(ctx, types.NetworkListOptions{})
+	_, err = cli.NetworkList(ctx, types.NetworkListOptions{})
 	if err != nil {
 		result.CanListNetworks = false
 		result.Errors = append(result.Errors, fmt.Sprintf("Cannot list networks: %v", err))
@@ -281,7 +313,7 @@ func (d *DockerDiagnosticService) CheckPermissions() *PermissionCheckResult {
 	}
 
 	// 测试存储卷列表权限
-	_, err = global.GVA_DOCKER.VolumeList(ctx, filters.Args{})
+	_, err = cli.VolumeList(ctx, filters.Args{})
 	if err != nil {
 		result.CanListVolumes = false
 		result.Errors = append(result.Errors, fmt.Sprintf("Cannot list volumes: %v", err))
@@ -290,7 +322,7 @@ func (d *DockerDiagnosticService) CheckPermissions() *PermissionCheckResult {
 	}
 
 	// 测试系统信息权限
-	_, err = global.GVA_DOCKER.Info(ctx)
+	_, err = cli.Info(ctx)
 	if err != nil {
 		result.CanGetInfo = false
 		result.Errors = append(result.Errors, fmt.Sprintf("Cannot get system info: %v", err))
@@ -299,7 +331,7 @@ func (d *DockerDiagnosticService) CheckPermissions() *PermissionCheckResult {
 	}
 
 	// 测试版本信息权限
-	_, err = global.GVA_DOCKER.ServerVersion(ctx)
+	_, err = cli.ServerVersion(ctx)
 	if err != nil {
 		result.CanGetVersion = false
 		result.Errors = append(result.Errors, fmt.Sprintf("Cannot get version: %v", err))
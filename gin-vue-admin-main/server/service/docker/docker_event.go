@@ -0,0 +1,375 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	dockerModel "github.com/flipped-aurora/gin-vue-admin/server/model/docker"
+	dockerReq "github.com/flipped-aurora/gin-vue-admin/server/model/docker/request"
+	dockerRes "github.com/flipped-aurora/gin-vue-admin/server/model/docker/response"
+	"go.uber.org/zap"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	defaultEventRetention = 7               // 默认事件保留天数
+	eventRetryInterval    = 5 * time.Second // 事件流中断后的重连间隔
+	eventActionMaxLength  = 100             // 动作字段长度上限，exec 类动作会带上命令
+	eventLiveBuffer       = 64              // 每个实时订阅的缓冲事件数
+)
+
+// eventTypes 记录的事件对象类型
+var eventTypes = []string{events.ContainerEventType, events.ImageEventType, events.NetworkEventType, events.VolumeEventType}
+
+// ignoredEventActions 不记录的高频动作：健康检查每次都会产生 exec 事件，终端操作产生 resize/attach 等事件
+var ignoredEventActions = []string{"exec_create", "exec_start", "exec_detach", "exec_die", "top", "resize", "attach", "detach", "archive-path", "extract-to-dir"}
+
+// dockerEventRecorder 订阅一台主机的事件流写入 docker_events，并转发给实时订阅者；
+// 连接恢复或客户端重建后从该主机最后记录的事件时间续接
+type dockerEventRecorder struct {
+	hostID   uint
+	mu       sync.Mutex
+	cancel   context.CancelFunc
+	lastNano int64
+}
+
+// dockerEventHub 各主机的事件记录器与实时订阅者，默认主机随连接状态启停，其他已启用的主机在增删改后同步
+var dockerEventHub = struct {
+	sync.Mutex
+	started   bool
+	recorders map[uint]*dockerEventRecorder
+	live      map[int]chan dockerModel.DockerEvent
+	nextID    int
+}{recorders: make(map[uint]*dockerEventRecorder), live: make(map[int]chan dockerModel.DockerEvent)}
+
+type DockerEventService struct {
+	hostClient
+}
+
+// StartRecorder 开始记录各主机的事件：默认主机订阅连接状态，连接可用时记录，不可用时停止；重复调用只注册一次
+func (s *DockerEventService) StartRecorder() {
+	dockerEventHub.Lock()
+	if dockerEventHub.started {
+		dockerEventHub.Unlock()
+		return
+	}
+	dockerEventHub.started = true
+	local := &dockerEventRecorder{}
+	dockerEventHub.recorders[0] = local
+	dockerEventHub.Unlock()
+
+	(&DockerConnectionService{}).Subscribe(func(event dockerRes.DockerConnectionEvent) {
+		switch {
+		case event.To == DockerStateDown:
+			local.stop()
+		case event.Reconnected || event.From == DockerStateUnknown || event.From == DockerStateDown:
+			local.restart()
+		}
+	})
+	syncHostEventRecorders()
+}
+
+// syncHostEventRecorders 为已启用的主机启动事件记录，停止已删除或停用的主机；主机配置变化后客户端重建，
+// 事件流中断后按新配置续接
+func syncHostEventRecorders() {
+	if global.GVA_DB == nil {
+		return
+	}
+	dockerEventHub.Lock()
+	defer dockerEventHub.Unlock()
+	if !dockerEventHub.started {
+		return
+	}
+	var ids []uint
+	if err := global.GVA_DB.Model(&dockerModel.DockerHost{}).Where("enabled = ?", true).Pluck("id", &ids).Error; err != nil {
+		global.GVA_LOG.Warn("Failed to query docker hosts for event recording", zap.Error(err))
+		return
+	}
+	enabled := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		enabled[id] = true
+		if _, ok := dockerEventHub.recorders[id]; !ok {
+			r := &dockerEventRecorder{hostID: id}
+			dockerEventHub.recorders[id] = r
+			r.restart()
+		}
+	}
+	for id, r := range dockerEventHub.recorders {
+		if id != 0 && !enabled[id] {
+			r.stop()
+			delete(dockerEventHub.recorders, id)
+		}
+	}
+}
+
+// GetEventList 分页查询所选主机的事件时间线，按时间倒序
+func (s *DockerEventService) GetEventList(filter dockerReq.DockerEventFilter) ([]dockerModel.DockerEvent, int64, error) {
+	if global.GVA_DB == nil {
+		return nil, 0, fmt.Errorf("database is not available")
+	}
+	db := global.GVA_DB.Model(&dockerModel.DockerEvent{}).Where("host_id = ?", s.hostID())
+	if filter.Type != "" {
+		db = db.Where("type = ?", filter.Type)
+	}
+	if filter.Action != "" {
+		db = db.Where("action LIKE ? ESCAPE '!'", escapeLikePrefix(filter.Action))
+	}
+	if filter.Object != "" {
+		db = db.Where("(object_name = ? OR object_id LIKE ? ESCAPE '!')", strings.TrimPrefix(filter.Object, "/"), escapeLikePrefix(filter.Object))
+	}
+	if !filter.StartTime.IsZero() {
+		db = db.Where("time >= ?", filter.StartTime)
+	}
+	if !filter.EndTime.IsZero() {
+		db = db.Where("time <= ?", filter.EndTime)
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count events: %v", err)
+	}
+	var list []dockerModel.DockerEvent
+	if err := db.Order("time_nano desc").Offset(filter.PageSize * (filter.Page - 1)).Limit(filter.PageSize).Find(&list).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to query events: %v", err)
+	}
+	return list, total, nil
+}
+
+// StreamEvents 实时推送所选主机新记录的事件，直到 ctx 取消
+func (s *DockerEventService) StreamEvents(ctx context.Context, options dockerReq.DockerEventStreamOptions, emit func(event dockerModel.DockerEvent) error) error {
+	ch, unsubscribe := subscribeDockerEvents()
+	defer unsubscribe()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event := <-ch:
+			if event.HostID != s.hostID() || !matchEventStream(event, options) {
+				continue
+			}
+			if err := emit(event); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Cleanup 删除超过保留天数的事件
+func (s *DockerEventService) Cleanup() error {
+	if global.GVA_DB == nil {
+		return nil
+	}
+	days := global.GVA_CONFIG.Docker.EventRetention
+	if days <= 0 {
+		days = defaultEventRetention
+	}
+	before := time.Now().AddDate(0, 0, -days)
+	if err := global.GVA_DB.Where("time < ?", before).Delete(&dockerModel.DockerEvent{}).Error; err != nil {
+		return fmt.Errorf("failed to clean up events: %v", err)
+	}
+	return nil
+}
+
+// restart 重新订阅事件流
+func (r *dockerEventRecorder) restart() {
+	if global.GVA_DB == nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	r.mu.Lock()
+	if r.cancel != nil {
+		r.cancel()
+	}
+	r.cancel = cancel
+	r.mu.Unlock()
+	go r.run(ctx)
+}
+
+// stop 停止订阅事件流
+func (r *dockerEventRecorder) stop() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.cancel != nil {
+		r.cancel()
+		r.cancel = nil
+	}
+}
+
+// run 订阅事件流，中断后等待重连，直到 ctx 取消或主机被删除；每次订阅使用主机当前的客户端，
+// 证书轮换、主机配置修改等操作重建客户端后自动切换
+func (r *dockerEventRecorder) run(ctx context.Context) {
+	args := filters.NewArgs()
+	for _, eventType := range eventTypes {
+		args.Add("type", eventType)
+	}
+	failures := 0
+	for {
+		options := types.EventsOptions{Filters: args}
+		since := r.resumeFrom()
+		if since > 0 {
+			options.Since = fmt.Sprintf("%d.%09d", since/int64(time.Second), since%int64(time.Second))
+		}
+		cli, err := r.streamClient()
+		if err == nil {
+			messages, errs := cli.Events(ctx, options)
+			err = r.consume(ctx, messages, errs)
+		}
+		if ctx.Err() != nil || errors.Is(err, errDockerHostNotFound) {
+			return
+		}
+		// 主机长时间不可用时只在首次中断时告警，记录到新事件后重新计数
+		if r.resumeFrom() > since {
+			failures = 0
+		}
+		if failures++; failures == 1 {
+			global.GVA_LOG.Warn("Docker event stream interrupted", zap.Uint("hostId", r.hostID), zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(eventRetryInterval):
+		}
+	}
+}
+
+// streamClient 返回主机不设置 HTTP 超时的客户端
+func (r *dockerEventRecorder) streamClient() (*client.Client, error) {
+	if r.hostID == 0 {
+		if cli := global.GetDockerStream(); cli != nil {
+			return cli, nil
+		}
+		return nil, fmt.Errorf("docker client is not initialized")
+	}
+	conn, err := dockerHostConn(r.hostID)
+	if err != nil {
+		return nil, err
+	}
+	return conn.Stream, nil
+}
+
+// consume 记录事件直到事件流出错或 ctx 取消
+func (r *dockerEventRecorder) consume(ctx context.Context, messages <-chan events.Message, errs <-chan error) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-errs:
+			return err
+		case message := <-messages:
+			event, ok := convertDockerEvent(message)
+			if !ok {
+				continue
+			}
+			event.HostID = r.hostID
+			err := global.GVA_DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&event).Error
+			if err != nil {
+				global.GVA_LOG.Warn("Failed to record docker event", zap.String("type", event.Type), zap.String("action", event.Action), zap.Error(err))
+				continue
+			}
+			r.mu.Lock()
+			if event.TimeNano > r.lastNano {
+				r.lastNano = event.TimeNano
+			}
+			r.mu.Unlock()
+			r.publish(event)
+		}
+	}
+}
+
+// resumeFrom 续接位置：该主机最后记录的事件时间，首次启动时从数据库读取，没有记录时只接收新事件
+func (r *dockerEventRecorder) resumeFrom() int64 {
+	r.mu.Lock()
+	last := r.lastNano
+	r.mu.Unlock()
+	if last > 0 {
+		return last
+	}
+	var latest dockerModel.DockerEvent
+	if err := global.GVA_DB.Select("time_nano").Where("host_id = ?", r.hostID).Order("time_nano desc").Limit(1).Find(&latest).Error; err != nil {
+		return 0
+	}
+	return latest.TimeNano
+}
+
+// subscribeDockerEvents 添加实时订阅，接收所有主机的事件
+func subscribeDockerEvents() (<-chan dockerModel.DockerEvent, func()) {
+	ch := make(chan dockerModel.DockerEvent, eventLiveBuffer)
+	dockerEventHub.Lock()
+	id := dockerEventHub.nextID
+	dockerEventHub.nextID++
+	dockerEventHub.live[id] = ch
+	dockerEventHub.Unlock()
+	return ch, func() {
+		dockerEventHub.Lock()
+		delete(dockerEventHub.live, id)
+		dockerEventHub.Unlock()
+	}
+}
+
+// publish 转发给实时订阅者，订阅者处理过慢时丢弃事件，不阻塞记录
+func (r *dockerEventRecorder) publish(event dockerModel.DockerEvent) {
+	dockerEventHub.Lock()
+	defer dockerEventHub.Unlock()
+	for _, ch := range dockerEventHub.live {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// escapeLikePrefix 转义 LIKE 通配符后作为前缀匹配，配合 ESCAPE '!' 使用
+func escapeLikePrefix(prefix string) string {
+	return strings.NewReplacer("!", "!!", "%", "!%", "_", "!_").Replace(prefix) + "%"
+}
+
+// convertDockerEvent 转换守护进程事件，忽略高频动作
+func convertDockerEvent(message events.Message) (dockerModel.DockerEvent, bool) {
+	action := message.Action
+	for _, ignored := range ignoredEventActions {
+		if action == ignored || strings.HasPrefix(action, ignored+":") {
+			return dockerModel.DockerEvent{}, false
+		}
+	}
+	if len(action) > eventActionMaxLength {
+		action = action[:eventActionMaxLength]
+	}
+
+	timeNano := message.TimeNano
+	if timeNano == 0 {
+		timeNano = message.Time * int64(time.Second)
+	}
+	name := message.Actor.Attributes["name"]
+	if name == "" {
+		name = message.Actor.ID
+	}
+	return dockerModel.DockerEvent{
+		Time:       time.Unix(0, timeNano),
+		TimeNano:   timeNano,
+		Type:       message.Type,
+		Action:     action,
+		ObjectID:   message.Actor.ID,
+		ObjectName: name,
+		Attributes: message.Actor.Attributes,
+	}, true
+}
+
+// matchEventStream 实时推送的过滤条件
+func matchEventStream(event dockerModel.DockerEvent, options dockerReq.DockerEventStreamOptions) bool {
+	if options.Type != "" && event.Type != options.Type {
+		return false
+	}
+	if options.Object != "" && event.ObjectName != strings.TrimPrefix(options.Object, "/") && !strings.HasPrefix(event.ObjectID, options.Object) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,82 @@
+package docker
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/events"
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	dockerModel "github.com/flipped-aurora/gin-vue-admin/server/model/docker"
+	dockerReq "github.com/flipped-aurora/gin-vue-admin/server/model/docker/request"
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestConvertDockerEvent(t *testing.T) {
+	nano := time.Date(2024, 3, 1, 3, 0, 0, 123, time.UTC).UnixNano()
+	event, ok := convertDockerEvent(events.Message{
+		Type:     events.ContainerEventType,
+		Action:   "die",
+		Actor:    events.Actor{ID: "abc123", Attributes: map[string]string{"name": "web", "exitCode": "137"}},
+		TimeNano: nano,
+	})
+	require.True(t, ok)
+	assert.Equal(t, "web", event.ObjectName)
+	assert.Equal(t, "abc123", event.ObjectID)
+	assert.Equal(t, nano, event.TimeNano)
+	assert.Equal(t, "137", event.Attributes["exitCode"])
+
+	// 健康检查与终端操作产生的 exec 类事件不记录
+	_, ok = convertDockerEvent(events.Message{Type: events.ContainerEventType, Action: "exec_start: /bin/sh -c curl -f localhost"})
+	assert.False(t, ok)
+	event, ok = convertDockerEvent(events.Message{Type: events.ContainerEventType, Action: "health_status: unhealthy", Actor: events.Actor{ID: "abc123"}, Time: 1700000000})
+	require.True(t, ok)
+	assert.Equal(t, "abc123", event.ObjectName)
+	assert.Equal(t, int64(1700000000)*int64(time.Second), event.TimeNano)
+
+	assert.True(t, matchEventStream(event, dockerReq.DockerEventStreamOptions{Type: "container", Object: "abc"}))
+	assert.False(t, matchEventStream(event, dockerReq.DockerEventStreamOptions{Type: "image"}))
+}
+
+func TestGetEventListFiltersHostAndEscapesObject(t *testing.T) {
+	oldDB := global.GVA_DB
+	t.Cleanup(func() { global.GVA_DB = oldDB })
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "events.db")), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&dockerModel.DockerEvent{}))
+	global.GVA_DB = db
+
+	now := time.Now()
+	for i, event := range []dockerModel.DockerEvent{
+		{HostID: 0, ObjectID: "web_1", ObjectName: "web_1", Action: "start"},
+		{HostID: 0, ObjectID: "webX1", ObjectName: "webX1", Action: "start"},
+		{HostID: 2, ObjectID: "web_1", ObjectName: "web_1", Action: "start"},
+	} {
+		event.Time, event.TimeNano, event.Type = now, now.UnixNano()+int64(i), events.ContainerEventType
+		require.NoError(t, db.Create(&event).Error)
+	}
+
+	filter := dockerReq.DockerEventFilter{Page: 1, PageSize: 10, Object: "web_"}
+	list, total, err := (&DockerEventService{}).GetEventList(filter)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, list, 1)
+	assert.Equal(t, "web_1", list[0].ObjectID)
+	assert.Zero(t, list[0].HostID)
+
+	remote := &DockerEventService{}
+	remote.UseHost(2, nil)
+	list, total, err = remote.GetEventList(filter)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, uint(2), list[0].HostID)
+
+	// 通配符按字面匹配
+	filter.Object = "%"
+	_, total, err = (&DockerEventService{}).GetEventList(filter)
+	require.NoError(t, err)
+	assert.Zero(t, total)
+}
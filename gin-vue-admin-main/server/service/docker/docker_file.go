@@ -0,0 +1,633 @@
+package docker
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	dockerReq "github.com/flipped-aurora/gin-vue-admin/server/model/docker/request"
+	dockerRes "github.com/flipped-aurora/gin-vue-admin/server/model/docker/response"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultFileMaxSize = 100              // 默认单个文件大小上限（MB）
+	fileListMaxBytes   = 256 << 20        // 通过归档接口列目录时读取的数据上限，目录内容过大时拒绝列出
+	fileOperateTimeout = 10 * time.Minute // 单次文件操作超时
+)
+
+// fileBrowser 文件浏览的后端，path 均为经过 cleanFilePath 规范化的绝对路径
+type fileBrowser interface {
+	stat(ctx context.Context, p string) (*dockerRes.FileEntry, error)
+	list(ctx context.Context, p string) ([]dockerRes.FileEntry, error)
+	open(ctx context.Context, p string) (io.ReadCloser, error)
+	write(ctx context.Context, dir, name string, content io.Reader, size int64) error
+	mkdir(ctx context.Context, p string) error
+	remove(ctx context.Context, p string) error
+}
+
+type DockerFileService struct {
+	hostClient
+}
+
+// ListFiles 列出目录内容，目录在前并按名称排序
+func (d *DockerFileService) ListFiles(target dockerReq.FileTarget, p string) ([]dockerRes.FileEntry, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), fileOperateTimeout)
+	defer cancel()
+	p, err := cleanFilePath(p)
+	if err != nil {
+		return nil, err
+	}
+	browser, release, err := d.browser(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	entry, err := browser.stat(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	if !entry.IsDir {
+		return nil, fmt.Errorf("not a directory")
+	}
+	entries, err := browser.list(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].IsDir != entries[j].IsDir {
+			return entries[i].IsDir
+		}
+		return entries[i].Name < entries[j].Name
+	})
+	return entries, nil
+}
+
+// StatFile 获取文件或目录信息
+func (d *DockerFileService) StatFile(target dockerReq.FileTarget, p string) (*dockerRes.FileEntry, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), fileOperateTimeout)
+	defer cancel()
+	p, err := cleanFilePath(p)
+	if err != nil {
+		return nil, err
+	}
+	browser, release, err := d.browser(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return browser.stat(ctx, p)
+}
+
+// OpenFile 打开文件用于下载，只支持不超过大小上限的普通文件；数据流在 ctx 结束前有效，调用方读取完毕后必须关闭
+func (d *DockerFileService) OpenFile(ctx context.Context, target dockerReq.FileTarget, p string) (io.ReadCloser, *dockerRes.FileEntry, error) {
+	p, err := cleanFilePath(p)
+	if err != nil {
+		return nil, nil, err
+	}
+	browser, release, err := d.browser(ctx, target)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	entry, err := browser.stat(ctx, p)
+	if err == nil {
+		err = checkRegularFile(entry)
+	}
+	if err != nil {
+		release()
+		return nil, nil, err
+	}
+	reader, err := browser.open(ctx, p)
+	if err != nil {
+		release()
+		return nil, nil, err
+	}
+	return &releaseReadCloser{ReadCloser: reader, release: release}, entry, nil
+}
+
+// UploadFile 上传文件到目录，同名文件已存在时需开启覆盖
+func (d *DockerFileService) UploadFile(target dockerReq.FileTarget, dir, name string, content io.Reader, size int64, overwrite bool) (*dockerRes.FileEntry, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), fileOperateTimeout)
+	defer cancel()
+	dir, err := cleanFilePath(dir)
+	if err != nil {
+		return nil, err
+	}
+	name = path.Base(strings.ReplaceAll(name, "\\", "/"))
+	if name == "" || name == "." || name == ".." || name == "/" || strings.ContainsRune(name, 0) {
+		return nil, fmt.Errorf("invalid file name")
+	}
+	if size > fileMaxSize() {
+		return nil, fmt.Errorf("file exceeds size limit of %d MB", fileMaxSize()>>20)
+	}
+	browser, release, err := d.browser(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	if entry, err := browser.stat(ctx, dir); err != nil {
+		return nil, err
+	} else if !entry.IsDir {
+		return nil, fmt.Errorf("not a directory")
+	}
+	p := path.Join(dir, name)
+	if entry, err := browser.stat(ctx, p); err == nil {
+		// 不覆盖目录与符号链接，避免经由符号链接写到存储卷之外
+		if entry.IsDir || entry.IsLink || !overwrite {
+			return nil, fmt.Errorf("file already exists")
+		}
+	} else if err.Error() != "file not found" {
+		return nil, err
+	}
+
+	if err := browser.write(ctx, dir, name, content, size); err != nil {
+		return nil, fmt.Errorf("failed to upload file: %v", err)
+	}
+	global.GVA_LOG.Info("File uploaded", zap.String("container", target.Container), zap.String("volume", target.Volume), zap.String("path", p), zap.Int64("size", size))
+	return browser.stat(ctx, p)
+}
+
+// MakeDir 创建目录，上级目录必须存在
+func (d *DockerFileService) MakeDir(target dockerReq.FileTarget, p string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), fileOperateTimeout)
+	defer cancel()
+	p, err := cleanFilePath(p)
+	if err != nil {
+		return err
+	}
+	if p == "/" {
+		return fmt.Errorf("file already exists")
+	}
+	browser, release, err := d.browser(ctx, target)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	if entry, err := browser.stat(ctx, path.Dir(p)); err != nil {
+		return err
+	} else if !entry.IsDir {
+		return fmt.Errorf("not a directory")
+	}
+	if _, err := browser.stat(ctx, p); err == nil {
+		return fmt.Errorf("file already exists")
+	} else if err.Error() != "file not found" {
+		return err
+	}
+	if err := browser.mkdir(ctx, p); err != nil {
+		return fmt.Errorf("failed to create directory: %v", err)
+	}
+	global.GVA_LOG.Info("Directory created", zap.String("container", target.Container), zap.String("volume", target.Volume), zap.String("path", p))
+	return nil
+}
+
+// DeleteFile 删除文件或目录（递归），不允许删除根目录；容器中的文件需在容器运行时删除
+func (d *DockerFileService) DeleteFile(target dockerReq.FileTarget, p string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), fileOperateTimeout)
+	defer cancel()
+	p, err := cleanFilePath(p)
+	if err != nil {
+		return err
+	}
+	if p == "/" {
+		return fmt.Errorf("cannot delete root directory")
+	}
+	browser, release, err := d.browser(ctx, target)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	if _, err := browser.stat(ctx, p); err != nil {
+		return err
+	}
+	if err := browser.remove(ctx, p); err != nil {
+		return fmt.Errorf("failed to delete file: %v", err)
+	}
+	global.GVA_LOG.Info("File deleted", zap.String("container", target.Container), zap.String("volume", target.Volume), zap.String("path", p))
+	return nil
+}
+
+// browser 按对象选择后端：容器使用归档接口；存储卷优先直接读写本机挂载点，否则使用挂载存储卷的辅助容器（不启动）。
+// release 删除辅助容器
+func (d *DockerFileService) browser(ctx context.Context, target dockerReq.FileTarget) (fileBrowser, func(), error) {
+	if d.cli() == nil {
+		return nil, nil, fmt.Errorf("Docker client is not available")
+	}
+	if target.Container != "" {
+		containerJSON, err := d.cli().ContainerInspect(ctx, target.Container)
+		if err != nil {
+			if client.IsErrNotFound(err) {
+				return nil, nil, fmt.Errorf("container not found")
+			}
+			return nil, nil, fmt.Errorf("failed to inspect container: %v", err)
+		}
+		return &containerFiles{cli: d.cli(), stream: d.streamCli(), id: containerJSON.ID}, func() {}, nil
+	}
+
+	vol, err := d.cli().VolumeInspect(ctx, target.Volume)
+	if err != nil {
+		if client.IsErrNotFound(err) {
+			return nil, nil, fmt.Errorf("volume not found")
+		}
+		return nil, nil, fmt.Errorf("failed to inspect volume: %v", err)
+	}
+	volumes := &DockerVolumeService{hostClient: d.hostClient}
+	if volumes.mountpointReadable(vol) {
+		return &localFiles{root: vol.Mountpoint}, func() {}, nil
+	}
+	helperID, err := volumes.createVolumeHelper(ctx, vol.Name, false, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	files := &containerFiles{cli: d.cli(), stream: d.streamCli(), id: helperID, root: volumeHelperMount, volume: vol.Name, volumes: volumes}
+	return files, func() { volumes.removeVolumeHelper(helperID) }, nil
+}
+
+// containerFiles 通过归档接口访问容器文件系统；root 非空时为挂载存储卷的辅助容器，路径相对于挂载点
+type containerFiles struct {
+	cli     *client.Client
+	stream  *client.Client
+	id      string
+	root    string
+	volume  string
+	volumes *DockerVolumeService
+}
+
+func (f *containerFiles) full(p string) string {
+	if f.root == "" {
+		return p
+	}
+	return path.Join(f.root, p)
+}
+
+func (f *containerFiles) stat(ctx context.Context, p string) (*dockerRes.FileEntry, error) {
+	st, err := f.cli.ContainerStatPath(ctx, f.id, f.full(p))
+	if err != nil {
+		if client.IsErrNotFound(err) {
+			return nil, fmt.Errorf("file not found")
+		}
+		return nil, fmt.Errorf("failed to stat file: %v", err)
+	}
+	name := st.Name
+	if p == "/" {
+		name = "/"
+	}
+	return &dockerRes.FileEntry{
+		Name:       name,
+		Path:       p,
+		Size:       st.Size,
+		Mode:       st.Mode.String(),
+		IsDir:      st.Mode.IsDir(),
+		IsLink:     st.Mode&os.ModeSymlink != 0,
+		LinkTarget: st.LinkTarget,
+		ModTime:    st.Mtime,
+	}, nil
+}
+
+// list 读取目录归档中的条目头，只保留直接子项；归档包含整个子树，超过读取上限时拒绝
+func (f *containerFiles) list(ctx context.Context, p string) ([]dockerRes.FileEntry, error) {
+	// 以 /. 结尾时归档只包含目录内容，条目名为 ./子项
+	reader, _, err := f.cli.CopyFromContainer(ctx, f.id, strings.TrimSuffix(f.full(p), "/")+"/.")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list directory: %v", err)
+	}
+	defer reader.Close()
+
+	limited := &io.LimitedReader{R: reader, N: fileListMaxBytes}
+	tr := tar.NewReader(limited)
+	entries := []dockerRes.FileEntry{}
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if limited.N <= 0 {
+				return nil, fmt.Errorf("directory is too large to list")
+			}
+			return nil, fmt.Errorf("failed to list directory: %v", err)
+		}
+		name := strings.TrimSuffix(strings.TrimPrefix(header.Name, "./"), "/")
+		if name == "" || name == "." || strings.Contains(name, "/") {
+			continue
+		}
+		entries = append(entries, tarFileEntry(header, name, path.Join(p, name)))
+	}
+	return entries, nil
+}
+
+func (f *containerFiles) open(ctx context.Context, p string) (io.ReadCloser, error) {
+	streamCtx, connected, cancel := streamContext(ctx, dockerStreamConnectTimeout)
+	reader, _, err := f.stream.CopyFromContainer(streamCtx, f.id, f.full(p))
+	connected()
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to read file: %v", err)
+	}
+	tr := tar.NewReader(reader)
+	if _, err := tr.Next(); err != nil {
+		reader.Close()
+		cancel()
+		return nil, fmt.Errorf("failed to read file: %v", err)
+	}
+	return &releaseReadCloser{ReadCloser: struct {
+		io.Reader
+		io.Closer
+	}{tr, reader}, release: cancel}, nil
+}
+
+func (f *containerFiles) write(ctx context.Context, dir, name string, content io.Reader, size int64) error {
+	pr, pw := io.Pipe()
+	go func() {
+		tw := tar.NewWriter(pw)
+		err := tw.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: name, Mode: 0o644, Size: size, ModTime: time.Now()})
+		if err == nil {
+			_, err = io.CopyN(tw, content, size)
+		}
+		if err == nil {
+			err = tw.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+	err := f.stream.CopyToContainer(ctx, f.id, f.full(dir), pr, types.CopyToContainerOptions{})
+	_ = pr.Close()
+	return err
+}
+
+func (f *containerFiles) mkdir(ctx context.Context, p string) error {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	if err := tw.WriteHeader(&tar.Header{Typeflag: tar.TypeDir, Name: path.Base(p) + "/", Mode: 0o755, ModTime: time.Now()}); err != nil {
+		return err
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return f.cli.CopyToContainer(ctx, f.id, f.full(path.Dir(p)), &buf, types.CopyToContainerOptions{})
+}
+
+// remove 归档接口不支持删除：容器中通过 exec 执行 rm，存储卷通过运行一次性辅助容器执行 rm
+func (f *containerFiles) remove(ctx context.Context, p string) error {
+	cmd := []string{"rm", "-rf", "--", f.full(p)}
+	if f.volumes != nil {
+		helperID, err := f.volumes.createVolumeHelper(ctx, f.volume, false, cmd)
+		if err != nil {
+			return err
+		}
+		defer f.volumes.removeVolumeHelper(helperID)
+		return f.volumes.runVolumeHelper(ctx, helperID)
+	}
+
+	containerJSON, err := f.cli.ContainerInspect(ctx, f.id)
+	if err != nil {
+		return err
+	}
+	if containerJSON.State == nil || !containerJSON.State.Running {
+		return fmt.Errorf("container must be running to delete files")
+	}
+	created, err := f.cli.ContainerExecCreate(ctx, f.id, types.ExecConfig{Cmd: cmd, AttachStdout: true, AttachStderr: true})
+	if err != nil {
+		return err
+	}
+	attach, err := f.cli.ContainerExecAttach(ctx, created.ID, types.ExecStartCheck{})
+	if err != nil {
+		return err
+	}
+	var stderr bytes.Buffer
+	_, err = stdcopy.StdCopy(io.Discard, &stderr, attach.Reader)
+	attach.Close()
+	if err != nil {
+		return err
+	}
+	inspect, err := f.cli.ContainerExecInspect(ctx, created.ID)
+	if err != nil {
+		return err
+	}
+	if inspect.ExitCode != 0 {
+		return fmt.Errorf("rm exited with code %d: %s", inspect.ExitCode, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// localFiles 直接访问本机的存储卷挂载点；解析路径时跟随上级目录中的符号链接并校验仍在挂载点内，
+// 最后一级不跟随，避免通过卷内的符号链接访问宿主机文件
+type localFiles struct {
+	root string
+}
+
+func (f *localFiles) resolve(p string) (string, error) {
+	root, err := filepath.EvalSymlinks(f.root)
+	if err != nil {
+		return "", err
+	}
+	if p == "/" {
+		return root, nil
+	}
+	parent, err := filepath.EvalSymlinks(filepath.Join(root, filepath.FromSlash(path.Dir(p))))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("file not found")
+		}
+		return "", err
+	}
+	if !withinDir(root, parent) {
+		return "", fmt.Errorf("path escapes volume")
+	}
+	return filepath.Join(parent, path.Base(p)), nil
+}
+
+func (f *localFiles) stat(ctx context.Context, p string) (*dockerRes.FileEntry, error) {
+	full, err := f.resolve(p)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Lstat(full)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("file not found")
+		}
+		return nil, fmt.Errorf("failed to stat file: %v", err)
+	}
+	entry := localFileEntry(full, info, p)
+	if p == "/" {
+		entry.Name = "/"
+	}
+	return &entry, nil
+}
+
+func (f *localFiles) list(ctx context.Context, p string) ([]dockerRes.FileEntry, error) {
+	full, err := f.resolve(p)
+	if err != nil {
+		return nil, err
+	}
+	dirEntries, err := os.ReadDir(full)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list directory: %v", err)
+	}
+	entries := make([]dockerRes.FileEntry, 0, len(dirEntries))
+	for _, dirEntry := range dirEntries {
+		info, err := dirEntry.Info()
+		if err != nil {
+			continue
+		}
+		entries = append(entries, localFileEntry(filepath.Join(full, dirEntry.Name()), info, path.Join(p, dirEntry.Name())))
+	}
+	return entries, nil
+}
+
+func (f *localFiles) open(ctx context.Context, p string) (io.ReadCloser, error) {
+	full, err := f.resolve(p)
+	if err != nil {
+		return nil, err
+	}
+	// 检查与打开之间最后一级可能被替换为符号链接，打开时不跟随并重新检查打开的文件
+	file, err := os.OpenFile(full, os.O_RDONLY|openNoFollow, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %v", err)
+	}
+	info, err := file.Stat()
+	if err == nil && !info.Mode().IsRegular() {
+		err = fmt.Errorf("path is not a regular file")
+	}
+	if err == nil && info.Size() > fileMaxSize() {
+		err = fmt.Errorf("file exceeds size limit of %d MB", fileMaxSize()>>20)
+	}
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return file, nil
+}
+
+func (f *localFiles) write(ctx context.Context, dir, name string, content io.Reader, size int64) error {
+	full, err := f.resolve(path.Join(dir, name))
+	if err != nil {
+		return err
+	}
+	// 最后一级为符号链接时打开失败，避免写入链接指向的卷外文件
+	file, err := os.OpenFile(full, os.O_CREATE|os.O_WRONLY|os.O_TRUNC|openNoFollow, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err == nil && !info.Mode().IsRegular() {
+		err = fmt.Errorf("path is not a regular file")
+	}
+	if err == nil {
+		_, err = io.CopyN(file, content, size)
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func (f *localFiles) mkdir(ctx context.Context, p string) error {
+	full, err := f.resolve(p)
+	if err != nil {
+		return err
+	}
+	return os.Mkdir(full, 0o755)
+}
+
+func (f *localFiles) remove(ctx context.Context, p string) error {
+	full, err := f.resolve(p)
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(full)
+}
+
+// releaseReadCloser 关闭数据流后释放后端资源
+type releaseReadCloser struct {
+	io.ReadCloser
+	release func()
+}
+
+func (r *releaseReadCloser) Close() error {
+	err := r.ReadCloser.Close()
+	r.release()
+	return err
+}
+
+// cleanFilePath 规范化用户传入的路径：统一为以 / 开头的绝对路径并消除 ..，拒绝空字节
+func cleanFilePath(p string) (string, error) {
+	if strings.ContainsRune(p, 0) {
+		return "", fmt.Errorf("invalid path")
+	}
+	return path.Clean("/" + strings.ReplaceAll(p, "\\", "/")), nil
+}
+
+// checkRegularFile 下载只支持普通文件且不超过大小上限
+func checkRegularFile(entry *dockerRes.FileEntry) error {
+	switch {
+	case entry.IsDir:
+		return fmt.Errorf("path is a directory")
+	case entry.IsLink:
+		return fmt.Errorf("path is a symbolic link")
+	case entry.Size > fileMaxSize():
+		return fmt.Errorf("file exceeds size limit of %d MB", fileMaxSize()>>20)
+	}
+	return nil
+}
+
+// fileMaxSize 单个文件大小上限（字节）
+func fileMaxSize() int64 {
+	size := global.GVA_CONFIG.Docker.FileMaxSize
+	if size <= 0 {
+		size = defaultFileMaxSize
+	}
+	return int64(size) << 20
+}
+
+// withinDir 判断 target 是否为 root 或其子路径
+func withinDir(root, target string) bool {
+	rel, err := filepath.Rel(root, target)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+func tarFileEntry(header *tar.Header, name, p string) dockerRes.FileEntry {
+	mode := header.FileInfo().Mode()
+	return dockerRes.FileEntry{
+		Name:       name,
+		Path:       p,
+		Size:       header.Size,
+		Mode:       mode.String(),
+		IsDir:      mode.IsDir(),
+		IsLink:     mode&os.ModeSymlink != 0,
+		LinkTarget: header.Linkname,
+		ModTime:    header.ModTime,
+	}
+}
+
+func localFileEntry(full string, info os.FileInfo, p string) dockerRes.FileEntry {
+	entry := dockerRes.FileEntry{
+		Name:    info.Name(),
+		Path:    p,
+		Size:    info.Size(),
+		Mode:    info.Mode().String(),
+		IsDir:   info.IsDir(),
+		IsLink:  info.Mode()&os.ModeSymlink != 0,
+		ModTime: info.ModTime(),
+	}
+	if entry.IsLink {
+		entry.LinkTarget, _ = os.Readlink(full)
+	}
+	return entry
+}
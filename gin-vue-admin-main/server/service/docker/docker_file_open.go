@@ -0,0 +1,8 @@
+//go:build !windows
+
+package docker
+
+import "syscall"
+
+// openNoFollow 打开文件时不跟随最后一级的符号链接
+const openNoFollow = syscall.O_NOFOLLOW
@@ -0,0 +1,4 @@
+package docker
+
+// openNoFollow Windows 不支持 O_NOFOLLOW，仅依赖打开后的文件类型检查
+const openNoFollow = 0
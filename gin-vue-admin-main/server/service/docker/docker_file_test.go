@@ -0,0 +1,83 @@
+package docker
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCleanFilePath(t *testing.T) {
+	for input, want := range map[string]string{
+		"":                 "/",
+		"etc/nginx":        "/etc/nginx",
+		"/data/../../etc":  "/etc",
+		`..\..\etc\passwd`: "/etc/passwd",
+	} {
+		got, err := cleanFilePath(input)
+		require.NoError(t, err)
+		assert.Equal(t, want, got, input)
+	}
+	_, err := cleanFilePath("/data\x00")
+	assert.Error(t, err)
+}
+
+func TestLocalFilesStayInVolume(t *testing.T) {
+	outside := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(outside, "secret"), []byte("secret"), 0o600))
+	root := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(root, "data"), 0o755))
+	require.NoError(t, os.Symlink(outside, filepath.Join(root, "escape")))
+
+	ctx := context.Background()
+	files := &localFiles{root: root}
+	require.NoError(t, files.write(ctx, "/data", "app.conf", strings.NewReader("listen 80"), 9))
+	reader, err := files.open(ctx, "/data/app.conf")
+	require.NoError(t, err)
+	content, _ := io.ReadAll(reader)
+	reader.Close()
+	assert.Equal(t, "listen 80", string(content))
+
+	// 符号链接本身可以查看，但不能经由它访问存储卷之外的文件
+	entry, err := files.stat(ctx, "/escape")
+	require.NoError(t, err)
+	assert.True(t, entry.IsLink)
+	assert.Equal(t, outside, entry.LinkTarget)
+	_, err = files.stat(ctx, "/escape/secret")
+	assert.EqualError(t, err, "path escapes volume")
+
+	entries, err := files.list(ctx, "/")
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+
+	require.NoError(t, files.remove(ctx, "/escape"))
+	_, err = os.Stat(filepath.Join(outside, "secret"))
+	assert.NoError(t, err)
+}
+
+func TestLocalFilesDoNotFollowFinalSymlink(t *testing.T) {
+	outside := t.TempDir()
+	secret := filepath.Join(outside, "secret")
+	require.NoError(t, os.WriteFile(secret, []byte("secret"), 0o600))
+	root := t.TempDir()
+	// 模拟检查通过后最后一级被替换为指向卷外文件的符号链接
+	require.NoError(t, os.Symlink(secret, filepath.Join(root, "app.conf")))
+
+	ctx := context.Background()
+	files := &localFiles{root: root}
+	_, err := files.open(ctx, "/app.conf")
+	assert.Error(t, err)
+	assert.Error(t, files.write(ctx, "/", "app.conf", strings.NewReader("listen 80"), 9))
+	content, err := os.ReadFile(secret)
+	require.NoError(t, err)
+	assert.Equal(t, "secret", string(content))
+
+	require.NoError(t, os.Mkdir(filepath.Join(root, "data"), 0o755))
+	_, err = files.open(ctx, "/data")
+	assert.EqualError(t, err, "path is not a regular file")
+}
@@ -0,0 +1,433 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/client"
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	dockerModel "github.com/flipped-aurora/gin-vue-admin/server/model/docker"
+	dockerReq "github.com/flipped-aurora/gin-vue-admin/server/model/docker/request"
+	dockerRes "github.com/flipped-aurora/gin-vue-admin/server/model/docker/response"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// defaultDockerHostTimeout 主机未配置超时时的请求超时
+const defaultDockerHostTimeout = 30 * time.Second
+
+// dockerStreamConnectTimeout 长连接请求收到响应头之前的超时，之后由调用方的 context 控制
+const dockerStreamConnectTimeout = 30 * time.Second
+
+// hostClient 服务使用的Docker主机，由接口层按请求选择的主机绑定，未绑定时使用配置文件中的默认主机
+type hostClient struct {
+	boundID     uint
+	boundClient *client.Client
+}
+
+// UseHost 绑定Docker主机，服务以值的方式复制后绑定，不影响其他请求
+func (h *hostClient) UseHost(hostID uint, cli *client.Client) {
+	h.boundID = hostID
+	h.boundClient = cli
+}
+
+// cli 返回绑定主机的客户端，未绑定时返回默认主机的客户端
+func (h *hostClient) cli() *client.Client {
+	if h.boundClient != nil {
+		return h.boundClient
+	}
+	return global.GetDocker()
+}
+
+// streamCli 返回绑定主机不设置 HTTP 超时的客户端，未绑定时返回默认主机的。
+// cli 的 HTTP 超时会限制读取响应体的总时长，跟随日志与统计、拉取推送构建镜像的进度流、
+// 镜像与存储卷归档的传输、等待容器退出等请求都应使用该客户端，时长由调用方的 context 控制
+func (h *hostClient) streamCli() *client.Client {
+	if h.boundID == 0 {
+		if stream := global.GetDockerStream(); stream != nil || h.boundClient == nil {
+			return stream
+		}
+		return h.boundClient
+	}
+	if conn, err := dockerHostConn(h.boundID); err == nil {
+		return conn.Stream
+	}
+	return h.boundClient
+}
+
+// streamContext 返回只限制长连接建立阶段的 context：connected 调用之前超过 timeout 时取消，
+// 调用之后只随 parent 结束；connected 返回 false 表示已经超时取消
+func streamContext(parent context.Context, timeout time.Duration) (ctx context.Context, connected func() bool, cancel context.CancelFunc) {
+	ctx, cancel = context.WithCancel(parent)
+	timer := time.AfterFunc(timeout, cancel)
+	return ctx, timer.Stop, cancel
+}
+
+// hostID 返回绑定的主机ID，0为默认主机
+func (h *hostClient) hostID() uint {
+	return h.boundID
+}
+
+// dockerHostPool 已创建的主机客户端，按主机ID缓存，主机修改、停用或删除时关闭
+var dockerHostPool = struct {
+	sync.Mutex
+	conns map[uint]*utils.DockerConn
+}{conns: make(map[uint]*utils.DockerConn)}
+
+type DockerHostService struct{}
+
+// GetDockerHostList 获取Docker主机列表，第一项为配置文件中的默认主机
+func (s *DockerHostService) GetDockerHostList() ([]dockerModel.DockerHost, error) {
+	var hosts []dockerModel.DockerHost
+	if err := global.GVA_DB.Order("id asc").Find(&hosts).Error; err != nil {
+		return nil, fmt.Errorf("failed to query docker hosts: %v", err)
+	}
+	for i := range hosts {
+		maskDockerHost(&hosts[i])
+	}
+
+	cfg := global.GVA_CONFIG.Docker
+	local := dockerModel.DockerHost{
+		Name:      dockerModel.DefaultDockerHostName,
+		Endpoint:  cfg.Host,
+		Version:   cfg.Version,
+		Timeout:   cfg.Timeout,
+		TLSVerify: cfg.TLSVerify,
+		CertPath:  cfg.CertPath,
+		Enabled:   global.GetDocker() != nil,
+	}
+	if local.Endpoint == "" {
+		local.Endpoint = client.DefaultDockerHost
+	}
+	return append([]dockerModel.DockerHost{local}, hosts...), nil
+}
+
+// CreateDockerHost 添加Docker主机，ssh 凭据加密保存
+func (s *DockerHostService) CreateDockerHost(req dockerReq.DockerHostRequest) (*dockerModel.DockerHost, error) {
+	host := dockerModel.DockerHost{}
+	if err := applyDockerHostRequest(&host, req); err != nil {
+		return nil, err
+	}
+	if err := checkDockerHostName(host.Name, 0); err != nil {
+		return nil, err
+	}
+	if err := global.GVA_DB.Create(&host).Error; err != nil {
+		return nil, fmt.Errorf("failed to create docker host: %v", err)
+	}
+	syncHostEventRecorders()
+	maskDockerHost(&host)
+	return &host, nil
+}
+
+// UpdateDockerHost 更新Docker主机，凭据为空时保持不变；端点变化时重新记录 ssh 主机指纹
+func (s *DockerHostService) UpdateDockerHost(req dockerReq.DockerHostRequest) (*dockerModel.DockerHost, error) {
+	host, err := getDockerHost(req.ID)
+	if err != nil {
+		return nil, err
+	}
+	oldEndpoint := host.Endpoint
+	if err := applyDockerHostRequest(host, req); err != nil {
+		return nil, err
+	}
+	if err := checkDockerHostName(host.Name, host.ID); err != nil {
+		return nil, err
+	}
+	if host.Endpoint != oldEndpoint || req.ResetHostKey {
+		host.SSHHostKey = ""
+	}
+	if err := global.GVA_DB.Save(host).Error; err != nil {
+		return nil, fmt.Errorf("failed to update docker host: %v", err)
+	}
+	closeDockerHostClient(host.ID)
+	syncHostEventRecorders()
+	maskDockerHost(host)
+	return host, nil
+}
+
+// DeleteDockerHost 删除Docker主机并关闭其连接
+func (s *DockerHostService) DeleteDockerHost(id uint) error {
+	result := global.GVA_DB.Delete(&dockerModel.DockerHost{}, id)
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete docker host: %v", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return errDockerHostNotFound
+	}
+	closeDockerHostClient(id)
+	syncHostEventRecorders()
+	return nil
+}
+
+// TestDockerHost 测试主机连接并记录结果，ID为0时测试默认主机
+func (s *DockerHostService) TestDockerHost(id uint) (*dockerRes.DockerHostStatus, error) {
+	cli, err := s.Client(id)
+	status := &dockerRes.DockerHostStatus{HostID: id}
+	if err == nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+		start := time.Now()
+		var version string
+		if version, err = pingDockerHost(ctx, cli); err == nil {
+			status.Reachable = true
+			status.Version = version
+			status.Latency = time.Since(start).Milliseconds()
+			if info, infoErr := cli.Info(ctx); infoErr == nil {
+				status.Name = info.Name
+				status.OperatingSystem = info.OperatingSystem
+				status.Architecture = info.Architecture
+				status.Containers = info.Containers
+				status.Images = info.Images
+			}
+		}
+	}
+	if err != nil {
+		status.Error = err.Error()
+		if errors.Is(err, errDockerHostNotFound) {
+			return nil, err
+		}
+	}
+
+	if id != 0 {
+		now := time.Now()
+		result := "ok"
+		if status.Error != "" {
+			result = truncateRunes(status.Error, 500)
+		}
+		global.GVA_DB.Model(&dockerModel.DockerHost{}).Where("id = ?", id).UpdateColumns(map[string]interface{}{
+			"last_check_at": now,
+			"last_status":   result,
+		})
+	}
+	return status, nil
+}
+
+// errDockerHostNotFound 主机不存在或已删除
+var errDockerHostNotFound = errors.New("docker host not found")
+
+// Client 获取主机客户端，ID为0时返回默认主机；首次使用时创建并缓存
+func (s *DockerHostService) Client(id uint) (*client.Client, error) {
+	cli := global.GetDocker()
+	if id == 0 {
+		if cli == nil {
+			return nil, fmt.Errorf("Docker client is not available")
+		}
+		return cli, nil
+	}
+
+	conn, err := dockerHostConn(id)
+	if err != nil {
+		return nil, err
+	}
+	return conn.Client, nil
+}
+
+// dockerHostConn 获取缓存的主机连接，首次使用时创建
+func dockerHostConn(id uint) (*utils.DockerConn, error) {
+	dockerHostPool.Lock()
+	defer dockerHostPool.Unlock()
+	if conn, ok := dockerHostPool.conns[id]; ok {
+		return conn, nil
+	}
+
+	host, err := getDockerHost(id)
+	if err != nil {
+		return nil, err
+	}
+	if !host.Enabled {
+		return nil, fmt.Errorf("docker host is disabled")
+	}
+	conn, err := newDockerHostConn(host)
+	if err != nil {
+		return nil, err
+	}
+	dockerHostPool.conns[id] = conn
+	return conn, nil
+}
+
+// ResolveHost 按主机ID或名称选择主机，返回主机ID与客户端；local 与 0 表示默认主机
+func (s *DockerHostService) ResolveHost(selector string) (uint, *client.Client, error) {
+	selector = strings.TrimSpace(selector)
+	if selector == "" || selector == "0" || selector == dockerModel.DefaultDockerHostName {
+		cli, err := s.Client(0)
+		return 0, cli, err
+	}
+	if id, err := strconv.ParseUint(selector, 10, 32); err == nil {
+		cli, err := s.Client(uint(id))
+		return uint(id), cli, err
+	}
+
+	var host dockerModel.DockerHost
+	if err := global.GVA_DB.Select("id").Where("name = ?", selector).First(&host).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, nil, errDockerHostNotFound
+		}
+		return 0, nil, fmt.Errorf("failed to query docker host: %v", err)
+	}
+	cli, err := s.Client(host.ID)
+	return host.ID, cli, err
+}
+
+// newDockerHostConn 按主机配置创建客户端，ssh 主机首次连接时记录主机指纹
+func newDockerHostConn(host *dockerModel.DockerHost) (*utils.DockerConn, error) {
+	opts := utils.DockerClientOptions{
+		Host:       host.Endpoint,
+		Version:    host.Version,
+		Timeout:    defaultDockerHostTimeout,
+		TLSVerify:  host.TLSVerify,
+		CertPath:   host.CertPath,
+		SSHHostKey: host.SSHHostKey,
+	}
+	if host.Timeout > 0 {
+		opts.Timeout = time.Duration(host.Timeout) * time.Second
+	}
+	var err error
+	if host.SSHKey != "" {
+		if opts.SSHKey, err = utils.AesDecrypt(host.SSHKey, registryCredentialKey()); err != nil {
+			return nil, fmt.Errorf("failed to decrypt ssh key, please re-enter it")
+		}
+	}
+	if host.SSHPassword != "" {
+		if opts.SSHPassword, err = utils.AesDecrypt(host.SSHPassword, registryCredentialKey()); err != nil {
+			return nil, fmt.Errorf("failed to decrypt ssh password, please re-enter it")
+		}
+	}
+	if host.SSHHostKey == "" {
+		hostID := host.ID
+		var once sync.Once
+		opts.OnHostKey = func(fingerprint string) {
+			once.Do(func() {
+				global.GVA_LOG.Info("Recorded ssh host key for docker host", zap.Uint("id", hostID), zap.String("fingerprint", fingerprint))
+				global.GVA_DB.Model(&dockerModel.DockerHost{}).Where("id = ? AND ssh_host_key = ''", hostID).UpdateColumn("ssh_host_key", fingerprint)
+			})
+		}
+	}
+	return utils.NewDockerClient(opts)
+}
+
+// closeDockerHostClient 关闭并移除缓存的主机客户端，下次使用时按最新配置重建
+func closeDockerHostClient(id uint) {
+	dockerHostPool.Lock()
+	conn, ok := dockerHostPool.conns[id]
+	delete(dockerHostPool.conns, id)
+	dockerHostPool.Unlock()
+	if ok {
+		conn.Close()
+	}
+}
+
+// pingDockerHost 测试连接并返回守护进程版本
+func pingDockerHost(ctx context.Context, cli *client.Client) (string, error) {
+	if _, err := cli.Ping(ctx); err != nil {
+		return "", err
+	}
+	version, err := cli.ServerVersion(ctx)
+	if err != nil {
+		return "", err
+	}
+	return version.Version, nil
+}
+
+// applyDockerHostRequest 校验请求并写入主机配置
+func applyDockerHostRequest(host *dockerModel.DockerHost, req dockerReq.DockerHostRequest) error {
+	req.Name = strings.TrimSpace(req.Name)
+	req.Endpoint = strings.TrimSpace(req.Endpoint)
+	if req.Name == dockerModel.DefaultDockerHostName || strings.Trim(req.Name, "0123456789") == "" {
+		return fmt.Errorf("invalid docker host name")
+	}
+	if req.Timeout < 0 {
+		return fmt.Errorf("timeout cannot be negative")
+	}
+
+	endpoint, err := url.Parse(req.Endpoint)
+	if err != nil {
+		return fmt.Errorf("invalid docker host endpoint: %v", err)
+	}
+	switch endpoint.Scheme {
+	case "unix":
+		if endpoint.Path == "" {
+			return fmt.Errorf("unix endpoint requires a socket path")
+		}
+	case "tcp":
+		if endpoint.Hostname() == "" || endpoint.Port() == "" {
+			return fmt.Errorf("tcp endpoint requires host and port")
+		}
+		if req.CertPath != "" {
+			if info, err := os.Stat(req.CertPath); err != nil || !info.IsDir() {
+				return fmt.Errorf("certificate directory not found: %s", req.CertPath)
+			}
+		}
+	case "ssh":
+		if endpoint.User == nil || endpoint.User.Username() == "" || endpoint.Hostname() == "" {
+			return fmt.Errorf("ssh endpoint requires user and host")
+		}
+		if req.SSHKey == "" && req.SSHPassword == "" && host.SSHKey == "" && host.SSHPassword == "" {
+			return fmt.Errorf("ssh endpoint requires a private key or password")
+		}
+	default:
+		return fmt.Errorf("unsupported docker host scheme: %s", endpoint.Scheme)
+	}
+
+	if req.SSHKey != "" {
+		encrypted, err := utils.AesEncrypt(req.SSHKey, registryCredentialKey())
+		if err != nil {
+			return fmt.Errorf("failed to encrypt ssh key: %v", err)
+		}
+		host.SSHKey = encrypted
+	}
+	if req.SSHPassword != "" {
+		encrypted, err := utils.AesEncrypt(req.SSHPassword, registryCredentialKey())
+		if err != nil {
+			return fmt.Errorf("failed to encrypt ssh password: %v", err)
+		}
+		host.SSHPassword = encrypted
+	}
+	host.Name = req.Name
+	host.Endpoint = req.Endpoint
+	host.Version = req.Version
+	host.Timeout = req.Timeout
+	host.TLSVerify = req.TLSVerify
+	host.CertPath = req.CertPath
+	host.Enabled = req.Enabled
+	host.Description = req.Description
+	return nil
+}
+
+// checkDockerHostName 检查主机名称是否已被使用
+func checkDockerHostName(name string, excludeID uint) error {
+	var count int64
+	if err := global.GVA_DB.Model(&dockerModel.DockerHost{}).Where("name = ? AND id <> ?", name, excludeID).Count(&count).Error; err != nil {
+		return fmt.Errorf("failed to query docker host: %v", err)
+	}
+	if count > 0 {
+		return fmt.Errorf("docker host name already exists")
+	}
+	return nil
+}
+
+// getDockerHost 按ID查询主机
+func getDockerHost(id uint) (*dockerModel.DockerHost, error) {
+	var host dockerModel.DockerHost
+	if err := global.GVA_DB.First(&host, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errDockerHostNotFound
+		}
+		return nil, fmt.Errorf("failed to query docker host: %v", err)
+	}
+	return &host, nil
+}
+
+// maskDockerHost 隐藏 ssh 凭据，只返回是否已保存
+func maskDockerHost(host *dockerModel.DockerHost) {
+	host.HasSSHKey = host.SSHKey != ""
+	host.HasPassword = host.SSHPassword != ""
+	host.SSHKey = ""
+	host.SSHPassword = ""
+}
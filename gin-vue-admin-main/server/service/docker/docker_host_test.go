@@ -0,0 +1,39 @@
+package docker
+
+import (
+	"testing"
+
+	dockerModel "github.com/flipped-aurora/gin-vue-admin/server/model/docker"
+	dockerReq "github.com/flipped-aurora/gin-vue-admin/server/model/docker/request"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyDockerHostRequest(t *testing.T) {
+	var host dockerModel.DockerHost
+	req := dockerReq.DockerHostRequest{Name: "node-1", Endpoint: " tcp://10.0.0.2:2375 ", Version: "1.41", Timeout: 10, Enabled: true}
+	require.NoError(t, applyDockerHostRequest(&host, req))
+	assert.Equal(t, "tcp://10.0.0.2:2375", host.Endpoint)
+	assert.Equal(t, 10, host.Timeout)
+
+	// 已保存 ssh 凭据时更新可不重新填写
+	saved := dockerModel.DockerHost{SSHKey: "encrypted"}
+	require.NoError(t, applyDockerHostRequest(&saved, dockerReq.DockerHostRequest{Name: "node-2", Endpoint: "ssh://root@10.0.0.3"}))
+	assert.Equal(t, "encrypted", saved.SSHKey)
+
+	invalid := []dockerReq.DockerHostRequest{
+		{Name: "local", Endpoint: "unix:///var/run/docker.sock"},
+		{Name: "12", Endpoint: "unix:///var/run/docker.sock"},
+		{Name: "a", Endpoint: "unix://"},
+		{Name: "a", Endpoint: "tcp://10.0.0.2"},
+		{Name: "a", Endpoint: "tcp://10.0.0.2:2376", CertPath: "/nonexistent/certs"},
+		{Name: "a", Endpoint: "ssh://10.0.0.3"},
+		{Name: "a", Endpoint: "ssh://root@10.0.0.3"},
+		{Name: "a", Endpoint: "http://10.0.0.2:2375"},
+		{Name: "a", Endpoint: "unix:///var/run/docker.sock", Timeout: -1},
+	}
+	for _, r := range invalid {
+		var h dockerModel.DockerHost
+		assert.Error(t, applyDockerHostRequest(&h, r), r.Endpoint)
+	}
+}
@@ -9,19 +9,23 @@ import (
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
 	"github.com/docker/go-connections/nat"
 	"github.com/flipped-aurora/gin-vue-admin/server/global"
 	"github.com/flipped-aurora/gin-vue-admin/server/model/docker/request"
 	"github.com/flipped-aurora/gin-vue-admin/server/model/docker/response"
+	cionService "github.com/flipped-aurora/gin-vue-admin/server/service/cion"
 	"go.uber.org/zap"
 )
 
-type DockerImageService struct{}
+type DockerImageService struct {
+	hostClient
+}
 
 // GetImageList 获取镜像列表
 func (d *DockerImageService) GetImageList(filter request.ImageFilter) ([]response.ImageInfo, int64, error) {
 	// 检查Docker客户端是否可用
-	if global.GVA_DOCKER == nil {
+	if d.cli() == nil {
 		return nil, 0, fmt.Errorf("Docker client is not available")
 	}
 
@@ -53,7 +57,7 @@ func (d *DockerImageService) GetImageList(filter request.ImageFilter) ([]respons
 	}
 
 	// 调用Docker API获取镜像列表
-	images, err := global.GVA_DOCKER.ImageList(ctx, options)
+	images, err := d.cli().ImageList(ctx, options)
 	if err != nil {
 		global.GVA_LOG.Error("Failed to get image list", zap.Error(err))
 		return nil, 0, fmt.Errorf("failed to get image list: %v", err)
@@ -91,7 +95,7 @@ func (d *DockerImageService) GetImageList(filter request.ImageFilter) ([]respons
 // GetImageDetail 获取镜像详细信息
 func (d *DockerImageService) GetImageDetail(imageID string) (*response.ImageDetail, error) {
 	// 检查Docker客户端是否可用
-	if global.GVA_DOCKER == nil {
+	if d.cli() == nil {
 		return nil, fmt.Errorf("Docker client is not available")
 	}
 
@@ -105,7 +109,7 @@ func (d *DockerImageService) GetImageDetail(imageID string) (*response.ImageDeta
 	defer cancel()
 
 	// 调用Docker API获取镜像详细信息
-	imageInspect, _, err := global.GVA_DOCKER.ImageInspectWithRaw(ctx, imageID)
+	imageInspect, _, err := d.cli().ImageInspectWithRaw(ctx, imageID)
 	if err != nil {
 		global.GVA_LOG.Error("Failed to get image detail", zap.String("imageID", imageID), zap.Error(err))
 		return nil, fmt.Errorf("failed to get image detail: %v", err)
@@ -117,46 +121,128 @@ func (d *DockerImageService) GetImageDetail(imageID string) (*response.ImageDeta
 	return &imageDetail, nil
 }
 
-// PullImage 拉取镜像
-func (d *DockerImageService) PullImage(pullReq request.ImagePullRequest) (string, error) {
+// PullImage 拉取镜像，成功后在镜像库存中记录拉取者
+func (d *DockerImageService) PullImage(pullReq request.ImagePullRequest, userID uint) (string, error) {
 	// 检查Docker客户端是否可用
-	if global.GVA_DOCKER == nil {
+	if d.cli() == nil {
 		return "", fmt.Errorf("Docker client is not available")
 	}
 
-	// 构建完整的镜像名称
-	imageName := pullReq.Image
-	if pullReq.Tag != "" && !strings.Contains(imageName, ":") {
-		imageName = fmt.Sprintf("%s:%s", imageName, pullReq.Tag)
-	}
+	imageName := pullImageName(pullReq)
 
 	// 创建上下文
 	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Second) // 拉取镜像可能需要较长时间
 	defer cancel()
 
-	// 拉取镜像
-	reader, err := global.GVA_DOCKER.ImagePull(ctx, imageName, types.ImagePullOptions{})
+	reader, err := startImagePull(ctx, d.streamCli(), imageName)
 	if err != nil {
-		global.GVA_LOG.Error("Failed to pull image", zap.String("image", imageName), zap.Error(err))
-		return "", fmt.Errorf("failed to pull image: %v", err)
+		return "", err
 	}
 	defer reader.Close()
 
 	// 读取拉取日志
-	pullLog, err := io.ReadAll(reader)
+	pullLog, err := readDockerJSONStream(reader)
 	if err != nil {
-		global.GVA_LOG.Error("Failed to read pull log", zap.String("image", imageName), zap.Error(err))
-		return "", fmt.Errorf("failed to read pull log: %v", err)
+		global.GVA_LOG.Error("Failed to pull image", zap.String("image", imageName), zap.Error(err))
+		return pullLog, fmt.Errorf("failed to pull image: %v", err)
 	}
 
 	global.GVA_LOG.Info("Image pulled successfully", zap.String("image", imageName))
-	return string(pullLog), nil
+	if d.hostID() == 0 {
+		recordImagePull(imageName, userID)
+	}
+	return pullLog, nil
+}
+
+// recordImagePull 同步镜像库存并记录拉取者，失败只记录日志，不影响拉取结果
+func recordImagePull(imageName string, userID uint) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := (&cionService.ImageService{}).RecordImagePull(ctx, imageName, userID); err != nil {
+		global.GVA_LOG.Warn("Failed to record image pull", zap.String("image", imageName), zap.Error(err))
+	}
+}
+
+// pullImageName 构建完整的镜像名称，未指定仓库地址时使用默认仓库
+func pullImageName(pullReq request.ImagePullRequest) string {
+	imageName := pullReq.Image
+	if pullReq.Tag != "" && !strings.Contains(imageName[strings.LastIndex(imageName, "/")+1:], ":") {
+		imageName = fmt.Sprintf("%s:%s", imageName, pullReq.Tag)
+	}
+	return applyDefaultRegistry(imageName)
+}
+
+// startImagePull 使用已保存的仓库凭据发起拉取，返回进度消息流
+func startImagePull(ctx context.Context, cli *client.Client, imageName string) (io.ReadCloser, error) {
+	registryAuth, err := registryAuthForImage(imageName)
+	if err != nil {
+		return nil, err
+	}
+	reader, err := cli.ImagePull(ctx, imageName, types.ImagePullOptions{RegistryAuth: registryAuth})
+	if err != nil {
+		global.GVA_LOG.Error("Failed to pull image", zap.String("image", imageName), zap.Error(err))
+		return nil, fmt.Errorf("failed to pull image: %v", err)
+	}
+	return reader, nil
+}
+
+// PushImage 推送镜像到仓库
+// 镜像未指定仓库地址且设置了默认仓库时，先打上默认仓库的标签再推送
+func (d *DockerImageService) PushImage(pushReq request.ImagePushRequest) (string, error) {
+	// 检查Docker客户端是否可用
+	if d.cli() == nil {
+		return "", fmt.Errorf("Docker client is not available")
+	}
+
+	// 构建完整的镜像名称
+	imageName := pushReq.Image
+	if pushReq.Tag != "" && !strings.Contains(imageName[strings.LastIndex(imageName, "/")+1:], ":") {
+		imageName = fmt.Sprintf("%s:%s", imageName, pushReq.Tag)
+	}
+	targetImage := applyDefaultRegistry(imageName)
+
+	// 创建上下文
+	ctx, cancel := context.WithTimeout(context.Background(), 600*time.Second) // 推送镜像可能需要较长时间
+	defer cancel()
+
+	if targetImage != imageName {
+		if err := d.cli().ImageTag(ctx, imageName, targetImage); err != nil {
+			global.GVA_LOG.Error("Failed to tag image",
+				zap.String("source", imageName),
+				zap.String("target", targetImage),
+				zap.Error(err))
+			return "", fmt.Errorf("failed to tag image: %v", err)
+		}
+	}
+
+	// 使用已保存的仓库凭据认证
+	registryAuth, err := registryAuthForImage(targetImage)
+	if err != nil {
+		return "", err
+	}
+
+	reader, err := d.streamCli().ImagePush(ctx, targetImage, types.ImagePushOptions{RegistryAuth: registryAuth})
+	if err != nil {
+		global.GVA_LOG.Error("Failed to push image", zap.String("image", targetImage), zap.Error(err))
+		return "", fmt.Errorf("failed to push image: %v", err)
+	}
+	defer reader.Close()
+
+	// 读取推送日志
+	pushLog, err := readDockerJSONStream(reader)
+	if err != nil {
+		global.GVA_LOG.Error("Failed to push image", zap.String("image", targetImage), zap.Error(err))
+		return pushLog, fmt.Errorf("failed to push image: %v", err)
+	}
+
+	global.GVA_LOG.Info("Image pushed successfully", zap.String("image", targetImage))
+	return pushLog, nil
 }
 
 // RemoveImage 删除镜像
 func (d *DockerImageService) RemoveImage(imageID string, force bool) error {
 	// 检查Docker客户端是否可用
-	if global.GVA_DOCKER == nil {
+	if d.cli() == nil {
 		return fmt.Errorf("Docker client is not available")
 	}
 
@@ -169,7 +255,7 @@ func (d *DockerImageService) RemoveImage(imageID string, force bool) error {
 	defer cancel()
 
 	// 删除镜像
-	_, err := global.GVA_DOCKER.ImageRemove(ctx, imageID, types.ImageRemoveOptions{
+	_, err := d.cli().ImageRemove(ctx, imageID, types.ImageRemoveOptions{
 		Force:         force,
 		PruneChildren: true,
 	})
@@ -185,7 +271,7 @@ func (d *DockerImageService) RemoveImage(imageID string, force bool) error {
 // TagImage 给镜像打标签
 func (d *DockerImageService) TagImage(tagReq request.ImageTagRequest) error {
 	// 检查Docker客户端是否可用
-	if global.GVA_DOCKER == nil {
+	if d.cli() == nil {
 		return fmt.Errorf("Docker client is not available")
 	}
 
@@ -194,7 +280,7 @@ func (d *DockerImageService) TagImage(tagReq request.ImageTagRequest) error {
 	defer cancel()
 
 	// 给镜像打标签
-	err := global.GVA_DOCKER.ImageTag(ctx, tagReq.SourceImage, tagReq.TargetImage)
+	err := d.cli().ImageTag(ctx, tagReq.SourceImage, tagReq.TargetImage)
 	if err != nil {
 		global.GVA_LOG.Error("Failed to tag image", 
 			zap.String("source", tagReq.SourceImage), 
@@ -212,7 +298,7 @@ func (d *DockerImageService) TagImage(tagReq request.ImageTagRequest) error {
 // PruneImages 清理未使用的镜像
 func (d *DockerImageService) PruneImages(dangling bool) (int64, int64, error) {
 	// 检查Docker客户端是否可用
-	if global.GVA_DOCKER == nil {
+	if d.cli() == nil {
 		return 0, 0, fmt.Errorf("Docker client is not available")
 	}
 
@@ -227,7 +313,7 @@ func (d *DockerImageService) PruneImages(dangling bool) (int64, int64, error) {
 	}
 
 	// 清理镜像
-	pruneReport, err := global.GVA_DOCKER.ImagesPrune(ctx, filterArgs)
+	pruneReport, err := d.cli().ImagesPrune(ctx, filterArgs)
 	if err != nil {
 		global.GVA_LOG.Error("Failed to prune images", zap.Error(err))
 		return 0, 0, fmt.Errorf("failed to prune images: %v", err)
@@ -307,7 +393,7 @@ func (d *DockerImageService) convertToImageDetail(dockerImage types.ImageInspect
 // BuildImage 构建镜像
 func (d *DockerImageService) BuildImage(buildReq request.ImageBuildRequest) (string, error) {
 	// 检查Docker客户端是否可用
-	if global.GVA_DOCKER == nil {
+	if d.cli() == nil {
 		return "", fmt.Errorf("Docker client is not available")
 	}
 
@@ -315,127 +401,31 @@ func (d *DockerImageService) BuildImage(buildReq request.ImageBuildRequest) (str
 	ctx, cancel := context.WithTimeout(context.Background(), 600*time.Second) // 构建镜像可能需要很长时间
 	defer cancel()
 
-	// 构建镜像名称和标签
-	imageName := buildReq.ImageName
-	if buildReq.Tag != "" {
-		imageName = fmt.Sprintf("%s:%s", buildReq.ImageName, buildReq.Tag)
-	} else {
-		imageName = fmt.Sprintf("%s:latest", buildReq.ImageName)
-	}
-
-	// 创建构建上下文（使用Dockerfile内容）
-	dockerfileContent := buildReq.Dockerfile
-	buildContext := strings.NewReader(dockerfileContent)
-
-	// 转换构建参数
-	buildArgs := make(map[string]*string)
-	for key, value := range buildReq.BuildArgs {
-		buildArgs[key] = &value
-	}
-
-	// 设置构建选项
-	buildOptions := types.ImageBuildOptions{
-		Tags:       []string{imageName},
-		Dockerfile: "Dockerfile",
-		BuildArgs:  buildArgs,
-		Remove:     true, // 构建完成后删除中间容器
-	}
-
-	// 如果指定了构建上下文路径，需要创建tar包
-	if buildReq.Context != "" {
-		// 这里简化处理，实际应该创建包含Dockerfile和上下文文件的tar包
-		global.GVA_LOG.Warn("Build context path specified but not fully implemented", zap.String("context", buildReq.Context))
-	}
+	imageName := buildImageName(buildReq)
 
-	// 构建镜像
-	buildResponse, err := global.GVA_DOCKER.ImageBuild(ctx, buildContext, buildOptions)
+	reader, err := startImageBuild(ctx, d.streamCli(), buildReq, imageName)
 	if err != nil {
-		global.GVA_LOG.Error("Failed to build image", zap.String("imageName", imageName), zap.Error(err))
-		return "", fmt.Errorf("failed to build image: %v", err)
+		return "", err
 	}
-	defer buildResponse.Body.Close()
+	defer reader.Close()
 
 	// 读取构建日志
-	buildLog, err := io.ReadAll(buildResponse.Body)
+	buildLog, err := readDockerJSONStream(reader)
 	if err != nil {
-		global.GVA_LOG.Error("Failed to read build log", zap.String("imageName", imageName), zap.Error(err))
-		return "", fmt.Errorf("failed to read build log: %v", err)
+		global.GVA_LOG.Error("Failed to build image", zap.String("imageName", imageName), zap.Error(err))
+		return buildLog, fmt.Errorf("failed to build image: %v", err)
 	}
 
 	global.GVA_LOG.Info("Image built successfully", zap.String("imageName", imageName))
-	return string(buildLog), nil
+	return buildLog, nil
 }
 
-// ExportImage 导出镜像
-func (d *DockerImageService) ExportImage(exportReq request.ImageExportRequest) (io.ReadCloser, error) {
-	// 检查Docker客户端是否可用
-	if global.GVA_DOCKER == nil {
-		return nil, fmt.Errorf("Docker client is not available")
-	}
-
-	if len(exportReq.Images) == 0 {
-		return nil, fmt.Errorf("no images specified for export")
-	}
-
-	// 创建上下文
-	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Second)
-	defer cancel()
-
-	// 导出镜像
-	reader, err := global.GVA_DOCKER.ImageSave(ctx, exportReq.Images)
-	if err != nil {
-		global.GVA_LOG.Error("Failed to export images", zap.Strings("images", exportReq.Images), zap.Error(err))
-		return nil, fmt.Errorf("failed to export images: %v", err)
-	}
-
-	global.GVA_LOG.Info("Images exported successfully", zap.Strings("images", exportReq.Images))
-	return reader, nil
-}
-
-// ImportImage 导入镜像
-func (d *DockerImageService) ImportImage(importReq request.ImageImportRequest) (string, error) {
-	// 检查Docker客户端是否可用
-	if global.GVA_DOCKER == nil {
-		return "", fmt.Errorf("Docker client is not available")
-	}
-
-	// 创建上下文
-	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Second)
-	defer cancel()
-
-	// 构建导入选项
-	importOptions := types.ImageImportOptions{}
-	if importReq.Tag != "" {
-		importOptions.Tag = importReq.Tag
-	}
-
-	// 导入镜像 - 这里简化处理，实际应该根据source类型处理不同的导入源
-	var source io.Reader
-	if strings.HasPrefix(importReq.Source, "http://") || strings.HasPrefix(importReq.Source, "https://") {
-		// URL导入 - 实际实现中需要下载文件
-		return "", fmt.Errorf("URL import not implemented yet")
-	} else {
-		// 文件路径导入 - 实际实现中需要读取文件
-		return "", fmt.Errorf("file import not implemented yet - source: %s", importReq.Source)
-	}
-
-	// 执行导入
-	importResponse, err := global.GVA_DOCKER.ImageImport(ctx, types.ImageImportSource{Source: source}, "", importOptions)
-	if err != nil {
-		global.GVA_LOG.Error("Failed to import image", zap.String("source", importReq.Source), zap.Error(err))
-		return "", fmt.Errorf("failed to import image: %v", err)
-	}
-	defer importResponse.Close()
-
-	// 读取导入日志
-	importLog, err := io.ReadAll(importResponse)
-	if err != nil {
-		global.GVA_LOG.Error("Failed to read import log", zap.String("source", importReq.Source), zap.Error(err))
-		return "", fmt.Errorf("failed to read import log: %v", err)
+// buildImageName 构建镜像名称和标签，未指定标签时为 latest
+func buildImageName(buildReq request.ImageBuildRequest) string {
+	if buildReq.Tag != "" {
+		return fmt.Sprintf("%s:%s", buildReq.ImageName, buildReq.Tag)
 	}
-
-	global.GVA_LOG.Info("Image imported successfully", zap.String("source", importReq.Source))
-	return string(importLog), nil
+	return fmt.Sprintf("%s:latest", buildReq.ImageName)
 }
 
 // convertExposedPorts 转换暴露端口
@@ -0,0 +1,765 @@
+package docker
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"compress/gzip"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/fileutils"
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/docker/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/docker/response"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// 构建上下文来源
+const (
+	buildContextDockerfile = "dockerfile" // 仅使用请求中的Dockerfile内容
+	buildContextUpload     = "upload"     // 上传的 tar/tar.gz/zip 包
+	buildContextDirectory  = "directory"  // 服务器上的目录
+	buildContextGit        = "git"        // Git仓库
+)
+
+const (
+	buildUploadMaxSize    = 1 << 30        // 上传的构建上下文包大小上限
+	buildContextMaxSize   = 4 << 30        // 解压后的构建上下文大小上限
+	buildWorkDirRetention = 24 * time.Hour // 上传包与临时目录的保留时间
+)
+
+var (
+	buildUploadIDPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+	scpLikeGitURLPattern = regexp.MustCompile(`^[\w.-]+@[\w.-]+:[^-]`)
+)
+
+// buildArchiveExts 支持上传的构建上下文包格式
+var buildArchiveExts = []string{".tar.gz", ".tgz", ".tar", ".zip"}
+
+// buildContext 准备好的构建上下文
+type buildContext struct {
+	root       string            // 上下文目录，为空表示仅包含 extra 中的文件
+	dockerfile string            // 上下文中的Dockerfile相对路径
+	extra      map[string][]byte // 额外写入上下文的文件，如请求中的Dockerfile内容
+	cleanup    func()            // 构建结束后清理临时目录
+}
+
+// cleanupReadCloser 关闭时执行清理
+type cleanupReadCloser struct {
+	io.ReadCloser
+	cleanup func()
+}
+
+func (r *cleanupReadCloser) Close() error {
+	err := r.ReadCloser.Close()
+	r.cleanup()
+	return err
+}
+
+// SaveBuildContext 保存上传的构建上下文包，返回供构建请求引用的上传ID
+func (d *DockerImageService) SaveBuildContext(header *multipart.FileHeader) (*response.BuildContextUploadResponse, error) {
+	ext := buildArchiveExt(header.Filename)
+	if ext == "" {
+		return nil, fmt.Errorf("unsupported build context archive, expected .tar, .tar.gz, .tgz or .zip")
+	}
+	if header.Size > buildUploadMaxSize {
+		return nil, fmt.Errorf("build context archive exceeds %d MB", buildUploadMaxSize>>20)
+	}
+
+	dir := buildUploadDir()
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create upload directory: %v", err)
+	}
+
+	src, err := header.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open uploaded file: %v", err)
+	}
+	defer src.Close()
+
+	uploadID := uuid.NewString()
+	target := filepath.Join(dir, uploadID+ext)
+	dst, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save uploaded file: %v", err)
+	}
+	size, err := io.Copy(dst, io.LimitReader(src, buildUploadMaxSize+1))
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil && size > buildUploadMaxSize {
+		err = fmt.Errorf("build context archive exceeds %d MB", buildUploadMaxSize>>20)
+	}
+	if err != nil {
+		_ = os.Remove(target)
+		return nil, err
+	}
+
+	global.GVA_LOG.Info("Build context uploaded", zap.String("uploadId", uploadID), zap.String("file", header.Filename), zap.Int64("size", size))
+	return &response.BuildContextUploadResponse{
+		UploadID: uploadID,
+		FileName: header.Filename,
+		Size:     size,
+	}, nil
+}
+
+// startImageBuild 准备构建上下文并发起镜像构建，返回构建输出消息流
+// 构建上下文以 tar 流的形式边打包边发送，消息流关闭时清理临时目录
+func startImageBuild(ctx context.Context, cli *client.Client, buildReq request.ImageBuildRequest, imageName string) (io.ReadCloser, error) {
+	bc, err := prepareBuildContext(ctx, buildReq)
+	if err != nil {
+		return nil, err
+	}
+
+	// 转换构建参数
+	buildArgs := make(map[string]*string)
+	for key, value := range buildReq.BuildArgs {
+		buildArgs[key] = &value
+	}
+
+	// 设置构建选项
+	buildOptions := types.ImageBuildOptions{
+		Tags:       []string{imageName},
+		Dockerfile: bc.dockerfile,
+		BuildArgs:  buildArgs,
+		Remove:     true, // 构建完成后删除中间容器
+		Target:     buildReq.Target,
+		Platform:   buildReq.Platform,
+		Labels:     buildReq.Labels,
+		NoCache:    buildReq.NoCache,
+		PullParent: buildReq.Pull,
+	}
+
+	reader, writer := io.Pipe()
+	written := make(chan struct{})
+	go func() {
+		defer close(written)
+		writer.CloseWithError(writeBuildContext(writer, bc))
+	}()
+	cleanup := func() {
+		_ = reader.Close()
+		<-written
+		bc.cleanup()
+	}
+
+	buildResponse, err := cli.ImageBuild(ctx, reader, buildOptions)
+	if err != nil {
+		cleanup()
+		global.GVA_LOG.Error("Failed to build image", zap.String("imageName", imageName), zap.Error(err))
+		return nil, fmt.Errorf("failed to build image: %v", err)
+	}
+	return &cleanupReadCloser{ReadCloser: buildResponse.Body, cleanup: cleanup}, nil
+}
+
+// prepareBuildContext 按来源准备构建上下文目录并确定Dockerfile位置
+// 未指定来源时按填写的字段推断：目录、上传ID、Git地址，均未填写时仅使用Dockerfile内容
+func prepareBuildContext(ctx context.Context, buildReq request.ImageBuildRequest) (*buildContext, error) {
+	contextType := buildReq.ContextType
+	if contextType == "" {
+		switch {
+		case buildReq.Context != "":
+			contextType = buildContextDirectory
+		case buildReq.UploadID != "":
+			contextType = buildContextUpload
+		case buildReq.GitURL != "":
+			contextType = buildContextGit
+		default:
+			contextType = buildContextDockerfile
+		}
+	}
+
+	dockerfile := "Dockerfile"
+	if buildReq.DockerfilePath != "" {
+		cleaned, err := cleanRelativePath(buildReq.DockerfilePath)
+		if err != nil {
+			return nil, fmt.Errorf("invalid dockerfile path: %v", err)
+		}
+		dockerfile = cleaned
+	}
+
+	bc := &buildContext{dockerfile: dockerfile, cleanup: func() {}}
+	switch contextType {
+	case buildContextDockerfile:
+		if strings.TrimSpace(buildReq.Dockerfile) == "" {
+			return nil, fmt.Errorf("dockerfile content is required")
+		}
+		bc.dockerfile = "Dockerfile"
+		bc.extra = map[string][]byte{bc.dockerfile: []byte(buildReq.Dockerfile)}
+		return bc, nil
+
+	case buildContextDirectory:
+		root, err := filepath.Abs(buildReq.Context)
+		if err != nil {
+			return nil, fmt.Errorf("invalid build context directory: %v", err)
+		}
+		info, err := os.Stat(root)
+		if err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("build context directory not found: %s", buildReq.Context)
+		}
+		bc.root = root
+
+	case buildContextUpload:
+		archive, err := findBuildUpload(buildReq.UploadID)
+		if err != nil {
+			return nil, err
+		}
+		dir, err := newBuildTempDir()
+		if err != nil {
+			return nil, err
+		}
+		bc.cleanup = func() { _ = os.RemoveAll(dir) }
+		if err := extractBuildArchive(archive, dir); err != nil {
+			bc.cleanup()
+			return nil, err
+		}
+		bc.root = dir
+		// 压缩包中只有一个顶层目录且根目录没有Dockerfile时，以该目录为上下文
+		if buildReq.Dockerfile == "" && !fileExists(filepath.Join(dir, dockerfile)) {
+			if sub := singleSubdirectory(dir); sub != "" {
+				bc.root = sub
+			}
+		}
+
+	case buildContextGit:
+		dir, err := newBuildTempDir()
+		if err != nil {
+			return nil, err
+		}
+		bc.cleanup = func() { _ = os.RemoveAll(dir) }
+		if err := cloneBuildRepo(ctx, buildReq.GitURL, buildReq.GitRef, dir); err != nil {
+			bc.cleanup()
+			return nil, err
+		}
+		bc.root = dir
+		if buildReq.GitSubdir != "" {
+			root, err := resolveSubdirectory(dir, buildReq.GitSubdir)
+			if err != nil {
+				bc.cleanup()
+				return nil, err
+			}
+			bc.root = root
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported build context type: %s", contextType)
+	}
+
+	// 请求中填写了Dockerfile内容时以随机文件名加入上下文，不覆盖上下文中的文件
+	if buildReq.Dockerfile != "" {
+		bc.dockerfile = ".dockerfile." + randomHex(8)
+		bc.extra = map[string][]byte{bc.dockerfile: []byte(buildReq.Dockerfile)}
+		return bc, nil
+	}
+	if !fileExists(filepath.Join(bc.root, bc.dockerfile)) {
+		bc.cleanup()
+		return nil, fmt.Errorf("dockerfile not found in build context: %s", bc.dockerfile)
+	}
+	return bc, nil
+}
+
+// writeBuildContext 将构建上下文写为 tar 流，按 .dockerignore 排除文件
+func writeBuildContext(w io.Writer, bc *buildContext) error {
+	tw := tar.NewWriter(w)
+	if bc.root != "" {
+		matcher, err := readDockerignore(bc.root, bc.dockerfile)
+		if err != nil {
+			return err
+		}
+		err = filepath.Walk(bc.root, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			rel, err := filepath.Rel(bc.root, path)
+			if err != nil || rel == "." {
+				return err
+			}
+			if matcher != nil {
+				excluded, err := matcher.Matches(rel)
+				if err != nil {
+					return err
+				}
+				if excluded {
+					// 没有 ! 例外规则时可以跳过整个目录
+					if info.IsDir() && !matcher.Exclusions() {
+						return filepath.SkipDir
+					}
+					return nil
+				}
+			}
+			return addBuildContextEntry(tw, path, filepath.ToSlash(rel), info)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to archive build context: %v", err)
+		}
+	}
+
+	for name, data := range bc.extra {
+		header := &tar.Header{
+			Name:     name,
+			Mode:     0o644,
+			Size:     int64(len(data)),
+			ModTime:  time.Now(),
+			Typeflag: tar.TypeReg,
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err := tw.Write(data); err != nil {
+			return err
+		}
+	}
+	return tw.Close()
+}
+
+// addBuildContextEntry 写入一个文件、目录或符号链接，跳过设备、套接字等特殊文件
+func addBuildContextEntry(tw *tar.Writer, path, name string, info os.FileInfo) error {
+	link := ""
+	switch {
+	case info.Mode()&os.ModeSymlink != 0:
+		target, err := os.Readlink(path)
+		if err != nil {
+			return err
+		}
+		link = target
+	case !info.Mode().IsRegular() && !info.IsDir():
+		return nil
+	}
+
+	header, err := tar.FileInfoHeader(info, link)
+	if err != nil {
+		return err
+	}
+	header.Name = name
+	if info.IsDir() {
+		header.Name += "/"
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	if !info.Mode().IsRegular() {
+		return nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = io.Copy(tw, file)
+	return err
+}
+
+// readDockerignore 读取 .dockerignore，文件不存在时返回 nil
+// 与 docker CLI 一致，.dockerignore 与 Dockerfile 本身被排除时仍会发送
+func readDockerignore(root, dockerfile string) (*fileutils.PatternMatcher, error) {
+	file, err := os.Open(filepath.Join(root, ".dockerignore"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read .dockerignore: %v", err)
+	}
+	defer file.Close()
+
+	var patterns []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		pattern := strings.TrimSpace(scanner.Text())
+		if pattern == "" || strings.HasPrefix(pattern, "#") {
+			continue
+		}
+		exclusion := strings.HasPrefix(pattern, "!")
+		pattern = strings.TrimSpace(strings.TrimPrefix(pattern, "!"))
+		pattern = filepath.Clean(strings.TrimPrefix(filepath.FromSlash(pattern), string(filepath.Separator)))
+		if pattern == "." {
+			continue
+		}
+		if exclusion {
+			pattern = "!" + pattern
+		}
+		patterns = append(patterns, pattern)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read .dockerignore: %v", err)
+	}
+	if len(patterns) == 0 {
+		return nil, nil
+	}
+
+	for _, keep := range []string{".dockerignore", filepath.FromSlash(dockerfile)} {
+		if excluded, _ := fileutils.Matches(keep, patterns); excluded {
+			patterns = append(patterns, "!"+keep)
+		}
+	}
+	matcher, err := fileutils.NewPatternMatcher(patterns)
+	if err != nil {
+		return nil, fmt.Errorf("invalid .dockerignore pattern: %v", err)
+	}
+	return matcher, nil
+}
+
+// extractBuildArchive 解压上传的构建上下文包，拒绝越出目标目录的路径
+// 符号链接在所有文件写入后再创建，避免借助压缩包中的链接写到目录之外
+func extractBuildArchive(archive, dest string) error {
+	extractor := &archiveExtractor{dest: dest, remaining: buildContextMaxSize}
+	var err error
+	if strings.HasSuffix(strings.ToLower(archive), ".zip") {
+		err = extractor.extractZip(archive)
+	} else {
+		err = extractor.extractTar(archive)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to extract build context: %v", err)
+	}
+	for _, link := range extractor.links {
+		if err := os.MkdirAll(filepath.Dir(link[0]), 0o755); err != nil {
+			return fmt.Errorf("failed to extract build context: %v", err)
+		}
+		if err := os.Symlink(link[1], link[0]); err != nil && !errors.Is(err, os.ErrExist) {
+			return fmt.Errorf("failed to extract build context: %v", err)
+		}
+	}
+	return nil
+}
+
+// archiveExtractor 解压状态，remaining 为剩余可写入字节数
+type archiveExtractor struct {
+	dest      string
+	remaining int64
+	links     [][2]string // 待创建的符号链接：路径、目标
+}
+
+func (e *archiveExtractor) extractTar(archive string) error {
+	file, err := os.Open(archive)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var reader io.Reader = file
+	lower := strings.ToLower(archive)
+	if strings.HasSuffix(lower, ".tar.gz") || strings.HasSuffix(lower, ".tgz") {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		reader = gz
+	}
+
+	tr := tar.NewReader(reader)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		target, err := safeJoin(e.dest, header.Name)
+		if err != nil {
+			return err
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			err = os.MkdirAll(target, 0o755)
+		case tar.TypeReg:
+			err = e.writeFile(target, tr, header.FileInfo().Mode().Perm())
+		case tar.TypeSymlink:
+			e.links = append(e.links, [2]string{target, header.Linkname})
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (e *archiveExtractor) extractZip(archive string) error {
+	zr, err := zip.OpenReader(archive)
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+
+	for _, f := range zr.File {
+		target, err := safeJoin(e.dest, f.Name)
+		if err != nil {
+			return err
+		}
+		mode := f.Mode()
+		switch {
+		case mode.IsDir():
+			err = os.MkdirAll(target, 0o755)
+		case mode&os.ModeSymlink != 0:
+			err = e.readZipLink(f, target)
+		case mode.IsRegular():
+			err = e.writeZipFile(f, target)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *archiveExtractor) readZipLink(f *zip.File, target string) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	linkname, err := io.ReadAll(io.LimitReader(rc, 4096))
+	if err != nil {
+		return err
+	}
+	e.links = append(e.links, [2]string{target, string(linkname)})
+	return nil
+}
+
+func (e *archiveExtractor) writeZipFile(f *zip.File, target string) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	return e.writeFile(target, rc, f.Mode().Perm())
+}
+
+// writeFile 写入普通文件，累计大小超过上限时报错
+func (e *archiveExtractor) writeFile(target string, src io.Reader, perm os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	if perm == 0 {
+		perm = 0o644
+	}
+	file, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	written, err := io.Copy(file, io.LimitReader(src, e.remaining+1))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	e.remaining -= written
+	if e.remaining < 0 {
+		return fmt.Errorf("build context exceeds %d MB after extraction", buildContextMaxSize>>20)
+	}
+	return nil
+}
+
+// cloneBuildRepo 将Git仓库指定引用浅克隆到目录
+// 使用 fetch 而非 clone --branch，以同时支持分支、标签与提交
+func cloneBuildRepo(ctx context.Context, repoURL, ref, dest string) error {
+	if err := validateGitURL(repoURL); err != nil {
+		return err
+	}
+	if strings.HasPrefix(ref, "-") {
+		return fmt.Errorf("invalid git ref: %s", ref)
+	}
+	if ref == "" {
+		ref = "HEAD"
+	}
+	if _, err := exec.LookPath("git"); err != nil {
+		return fmt.Errorf("git is not installed on the server")
+	}
+
+	run := func(args ...string) error {
+		cmd := exec.CommandContext(ctx, "git", args...)
+		cmd.Dir = dest
+		// 禁止交互式输入凭据，并只允许常规传输协议
+		cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "GIT_ALLOW_PROTOCOL=https:http:ssh:git")
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("git %s failed: %v: %s", args[0], err, strings.TrimSpace(string(output)))
+		}
+		return nil
+	}
+
+	steps := [][]string{
+		{"init", "-q"},
+		{"remote", "add", "origin", repoURL},
+		{"fetch", "-q", "--depth", "1", "origin", ref},
+		{"checkout", "-q", "--detach", "FETCH_HEAD"},
+	}
+	for _, args := range steps {
+		if err := run(args...); err != nil {
+			return err
+		}
+	}
+	if fileExists(filepath.Join(dest, ".gitmodules")) {
+		return run("submodule", "update", "-q", "--init", "--recursive", "--depth", "1")
+	}
+	return nil
+}
+
+// validateGitURL 仅允许 http(s)、ssh、git 协议及 user@host:path 形式的地址
+func validateGitURL(repoURL string) error {
+	lower := strings.ToLower(repoURL)
+	for _, prefix := range []string{"https://", "http://", "ssh://", "git://"} {
+		if strings.HasPrefix(lower, prefix) && len(repoURL) > len(prefix) {
+			return nil
+		}
+	}
+	if scpLikeGitURLPattern.MatchString(repoURL) {
+		return nil
+	}
+	return fmt.Errorf("unsupported git url: %s", repoURL)
+}
+
+// findBuildUpload 根据上传ID查找已上传的构建上下文包
+func findBuildUpload(uploadID string) (string, error) {
+	if !buildUploadIDPattern.MatchString(uploadID) {
+		return "", fmt.Errorf("invalid upload id")
+	}
+	for _, ext := range buildArchiveExts {
+		path := filepath.Join(buildUploadDir(), uploadID+ext)
+		if fileExists(path) {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("build context upload not found or expired")
+}
+
+// cleanupBuildWorkDir 删除过期的上传包与遗留的临时目录
+func cleanupBuildWorkDir() error {
+	expire := time.Now().Add(-buildWorkDirRetention)
+	for _, dir := range []string{buildWorkDir(), buildUploadDir()} {
+		entries, err := os.ReadDir(dir)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if dir == buildWorkDir() && entry.Name() == "uploads" {
+				continue
+			}
+			info, err := entry.Info()
+			if err != nil || info.ModTime().After(expire) {
+				continue
+			}
+			if err := os.RemoveAll(filepath.Join(dir, entry.Name())); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// buildWorkDir 构建上下文临时目录
+func buildWorkDir() string {
+	return filepath.Join(os.TempDir(), "gva-docker-build")
+}
+
+// buildUploadDir 上传的构建上下文包存放目录
+func buildUploadDir() string {
+	return filepath.Join(buildWorkDir(), "uploads")
+}
+
+// newBuildTempDir 创建一次构建使用的临时目录
+func newBuildTempDir() (string, error) {
+	if err := os.MkdirAll(buildWorkDir(), 0o700); err != nil {
+		return "", fmt.Errorf("failed to create build directory: %v", err)
+	}
+	dir, err := os.MkdirTemp(buildWorkDir(), "context-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create build directory: %v", err)
+	}
+	return dir, nil
+}
+
+// buildArchiveExt 返回支持的压缩包扩展名，不支持时为空
+func buildArchiveExt(name string) string {
+	lower := strings.ToLower(name)
+	for _, ext := range buildArchiveExts {
+		if strings.HasSuffix(lower, ext) {
+			return ext
+		}
+	}
+	return ""
+}
+
+// safeJoin 拼接压缩包内的路径，拒绝绝对路径与越出 base 的相对路径
+func safeJoin(base, name string) (string, error) {
+	cleaned, err := cleanRelativePath(name)
+	if err != nil {
+		return "", fmt.Errorf("illegal path in archive: %s", name)
+	}
+	return filepath.Join(base, cleaned), nil
+}
+
+// cleanRelativePath 规范化相对路径，拒绝绝对路径与 ..
+func cleanRelativePath(name string) (string, error) {
+	cleaned := filepath.Clean(filepath.FromSlash(strings.TrimPrefix(name, "./")))
+	if filepath.IsAbs(cleaned) || cleaned == ".." || strings.HasPrefix(cleaned, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("path must be relative and inside the build context: %s", name)
+	}
+	return cleaned, nil
+}
+
+// resolveSubdirectory 解析 base 下的子目录，解析符号链接后仍须位于 base 内
+func resolveSubdirectory(base, subdir string) (string, error) {
+	cleaned, err := cleanRelativePath(subdir)
+	if err != nil {
+		return "", err
+	}
+	resolvedBase, err := filepath.EvalSymlinks(base)
+	if err != nil {
+		return "", err
+	}
+	resolved, err := filepath.EvalSymlinks(filepath.Join(base, cleaned))
+	if err != nil {
+		return "", fmt.Errorf("build context subdirectory not found: %s", subdir)
+	}
+	rel, err := filepath.Rel(resolvedBase, resolved)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("build context subdirectory is outside the repository: %s", subdir)
+	}
+	info, err := os.Stat(resolved)
+	if err != nil || !info.IsDir() {
+		return "", fmt.Errorf("build context subdirectory not found: %s", subdir)
+	}
+	return resolved, nil
+}
+
+// singleSubdirectory 目录中只有一个子目录且没有其他文件时返回该子目录
+func singleSubdirectory(dir string) string {
+	entries, err := os.ReadDir(dir)
+	if err != nil || len(entries) != 1 || !entries[0].IsDir() {
+		return ""
+	}
+	return filepath.Join(dir, entries[0].Name())
+}
+
+// fileExists 判断路径是否存在
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// randomHex 生成随机十六进制字符串
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
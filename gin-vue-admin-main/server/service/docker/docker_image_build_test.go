@@ -0,0 +1,90 @@
+package docker
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteBuildContextDockerignore(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"Dockerfile":          "FROM alpine",
+		".dockerignore":       "# comment\n.dockerignore\nDockerfile\nnode_modules\n**/*.log\n!keep.log\n",
+		"main.go":             "package main",
+		"debug.log":           "x",
+		"keep.log":            "x",
+		"node_modules/a/b.js": "x",
+		"src/app/handler.go":  "package app",
+		"src/app/handler.log": "x",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+
+	var buf bytes.Buffer
+	bc := &buildContext{root: root, dockerfile: "Dockerfile", cleanup: func() {}}
+	require.NoError(t, writeBuildContext(&buf, bc))
+
+	var names []string
+	tr := tar.NewReader(&buf)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		if header.Typeflag == tar.TypeReg {
+			names = append(names, header.Name)
+		}
+	}
+	sort.Strings(names)
+	// Dockerfile 与 .dockerignore 即使被忽略也会发送
+	assert.Equal(t, []string{".dockerignore", "Dockerfile", "keep.log", "main.go", "src/app/handler.go"}, names)
+}
+
+func TestCleanRelativePath(t *testing.T) {
+	cleaned, err := cleanRelativePath("./docker/Dockerfile.prod")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.FromSlash("docker/Dockerfile.prod"), cleaned)
+
+	for _, name := range []string{"../Dockerfile", "/etc/passwd", "a/../../b"} {
+		_, err := cleanRelativePath(name)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestExtractBuildArchiveRejectsTraversal(t *testing.T) {
+	dir := t.TempDir()
+	archive := filepath.Join(dir, "context.tar")
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "../evil", Mode: 0o644, Size: 1, Typeflag: tar.TypeReg}))
+	_, _ = tw.Write([]byte("x"))
+	require.NoError(t, tw.Close())
+	require.NoError(t, os.WriteFile(archive, buf.Bytes(), 0o644))
+
+	dest := filepath.Join(dir, "out")
+	require.NoError(t, os.MkdirAll(dest, 0o755))
+	assert.Error(t, extractBuildArchive(archive, dest))
+	_, err := os.Stat(filepath.Join(dir, "evil"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestValidateGitURL(t *testing.T) {
+	for _, u := range []string{"https://github.com/org/repo.git", "ssh://git@host/repo", "git@github.com:org/repo.git"} {
+		assert.NoError(t, validateGitURL(u), u)
+	}
+	for _, u := range []string{"--upload-pack=touch /tmp/x", "ext::sh -c id", "file:///etc", "/srv/repo", "https://"} {
+		assert.Error(t, validateGitURL(u), u)
+	}
+}
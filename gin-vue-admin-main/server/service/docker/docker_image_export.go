@@ -0,0 +1,159 @@
+package docker
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/docker/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/example"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils/upload"
+	"go.uber.org/zap"
+)
+
+// imageExportOSSTimeout 导出到对象存储的超时时间
+const imageExportOSSTimeout = 2 * time.Hour
+
+var exportFileNamePattern = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// ExportImage 导出镜像为 docker save 格式的 tar 流，可选gzip压缩，返回数据流与下载文件名
+// 数据流在 ctx 结束前有效，调用方读取完毕后必须关闭
+func (d *DockerImageService) ExportImage(ctx context.Context, exportReq request.ImageExportRequest) (io.ReadCloser, string, error) {
+	// 检查Docker客户端是否可用
+	if d.cli() == nil {
+		return nil, "", fmt.Errorf("Docker client is not available")
+	}
+
+	if len(exportReq.Images) == 0 {
+		return nil, "", fmt.Errorf("no images specified for export")
+	}
+
+	// 先确认镜像存在，避免开始传输后才发现错误
+	for _, image := range exportReq.Images {
+		if _, _, err := d.cli().ImageInspectWithRaw(ctx, image); err != nil {
+			return nil, "", fmt.Errorf("image not found: %s", image)
+		}
+	}
+
+	reader, err := d.streamCli().ImageSave(ctx, exportReq.Images)
+	if err != nil {
+		global.GVA_LOG.Error("Failed to export images", zap.Strings("images", exportReq.Images), zap.Error(err))
+		return nil, "", fmt.Errorf("failed to export images: %v", err)
+	}
+
+	fileName := exportFileName(exportReq)
+	if !exportReq.Compress {
+		return reader, fileName, nil
+	}
+
+	// 边读边压缩，不在内存或磁盘中缓存整个镜像；关闭 pr 后写入失败，协程随之退出
+	pr, pw := io.Pipe()
+	go func() {
+		gz, _ := gzip.NewWriterLevel(pw, gzip.BestSpeed)
+		_, err := io.Copy(gz, reader)
+		if closeErr := gz.Close(); err == nil {
+			err = closeErr
+		}
+		_ = reader.Close()
+		pw.CloseWithError(err)
+	}()
+	return pr, fileName, nil
+}
+
+// ExportImageToOSS 导出镜像并上传到配置的对象存储，登记到文件管理中供后续下载
+func (d *DockerImageService) ExportImageToOSS(exportReq request.ImageExportRequest) (*example.ExaFileUploadAndDownload, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), imageExportOSSTimeout)
+	defer cancel()
+
+	reader, fileName, err := d.ExportImage(ctx, exportReq)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	header, cleanup, err := streamToFileHeader(reader, fileName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export images: %v", err)
+	}
+	defer cleanup()
+
+	filePath, key, err := upload.NewOss().UploadFile(header)
+	if err != nil {
+		global.GVA_LOG.Error("Failed to upload exported images", zap.String("file", fileName), zap.Error(err))
+		return nil, fmt.Errorf("failed to upload exported images: %v", err)
+	}
+
+	s := strings.Split(fileName, ".")
+	file := example.ExaFileUploadAndDownload{
+		Url:     filePath,
+		Name:    fileName,
+		ClassId: exportReq.ClassId,
+		Tag:     s[len(s)-1],
+		Key:     key,
+	}
+	if err := global.GVA_DB.Create(&file).Error; err != nil {
+		return nil, fmt.Errorf("failed to save file record: %v", err)
+	}
+
+	global.GVA_LOG.Info("Images exported to object storage", zap.Strings("images", exportReq.Images), zap.String("url", filePath), zap.Int64("size", header.Size))
+	return &file, nil
+}
+
+// streamToFileHeader 将数据流转换为 multipart.FileHeader，以复用 upload.OSS 的上传接口
+// 通过 multipart 编解码将数据写入临时文件，不在内存中缓存；cleanup 删除临时文件
+func streamToFileHeader(reader io.Reader, fileName string) (*multipart.FileHeader, func(), error) {
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+	go func() {
+		part, err := mw.CreateFormFile("file", fileName)
+		if err == nil {
+			_, err = io.Copy(part, reader)
+		}
+		if err == nil {
+			err = mw.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+
+	form, err := multipart.NewReader(pr, mw.Boundary()).ReadForm(1 << 20)
+	_ = pr.Close()
+	if err != nil {
+		return nil, func() {}, err
+	}
+	cleanup := func() { _ = form.RemoveAll() }
+	files := form.File["file"]
+	if len(files) == 0 {
+		cleanup()
+		return nil, func() {}, fmt.Errorf("empty export stream")
+	}
+	return files[0], cleanup, nil
+}
+
+// exportFileName 导出文件名，未指定时单个镜像使用镜像名，多个镜像使用 images
+func exportFileName(exportReq request.ImageExportRequest) string {
+	name := exportReq.FileName
+	if name == "" {
+		name = "images"
+		if len(exportReq.Images) == 1 {
+			name = exportReq.Images[0]
+		}
+		name += "-" + time.Now().Format("20060102150405")
+	}
+	name = strings.Trim(exportFileNamePattern.ReplaceAllString(name, "_"), "._")
+	if len(name) > 100 {
+		name = name[:100]
+	}
+	if name == "" {
+		name = "images"
+	}
+	if exportReq.Compress {
+		return name + ".tar.gz"
+	}
+	return name + ".tar"
+}
@@ -0,0 +1,92 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/docker/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamToFileHeader(t *testing.T) {
+	content := strings.Repeat("layer-data", 300*1024) // 超过内存阈值，写入临时文件
+	header, cleanup, err := streamToFileHeader(strings.NewReader(content), "nginx.tar.gz")
+	require.NoError(t, err)
+	defer cleanup()
+
+	assert.Equal(t, "nginx.tar.gz", header.Filename)
+	assert.Equal(t, int64(len(content)), header.Size)
+	file, err := header.Open()
+	require.NoError(t, err)
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	require.NoError(t, err)
+	assert.Equal(t, content, string(data))
+}
+
+func TestExportFileName(t *testing.T) {
+	name := exportFileName(request.ImageExportRequest{Images: []string{"nginx"}, FileName: "harbor.local/app:1.0", Compress: true})
+	assert.Equal(t, "harbor.local_app_1.0.tar.gz", name)
+
+	name = exportFileName(request.ImageExportRequest{Images: []string{"a", "b"}})
+	assert.True(t, strings.HasPrefix(name, "images-"))
+	assert.True(t, strings.HasSuffix(name, ".tar"))
+}
+
+// slowReader 每次只读取少量数据并等待，模拟下载速度较慢的客户端
+type slowReader struct {
+	r     io.Reader
+	delay time.Duration
+}
+
+func (s *slowReader) Read(p []byte) (int, error) {
+	time.Sleep(s.delay)
+	if len(p) > 4096 {
+		p = p[:4096]
+	}
+	return s.r.Read(p)
+}
+
+func TestExportImageSlowReader(t *testing.T) {
+	content := strings.Repeat("layer-data", 4096)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/images/nginx/json"):
+			fmt.Fprint(w, `{"Id":"sha256:abc"}`)
+		case strings.HasSuffix(r.URL.Path, "/images/get"):
+			fmt.Fprint(w, content)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	conn, err := utils.NewDockerClient(utils.DockerClientOptions{
+		Host:    "tcp://" + server.Listener.Addr().String(),
+		Version: "1.41",
+		Timeout: 200 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	defer conn.Close()
+	oldDocker, oldStream := global.SetDocker(conn.Client, conn.Stream)
+	defer global.SetDocker(oldDocker, oldStream)
+
+	// 读取总耗时超过客户端的请求超时，导出不应被中断
+	reader, fileName, err := (&DockerImageService{}).ExportImage(context.Background(), request.ImageExportRequest{Images: []string{"nginx"}})
+	require.NoError(t, err)
+	defer reader.Close()
+	assert.True(t, strings.HasSuffix(fileName, ".tar"))
+
+	data, err := io.ReadAll(&slowReader{r: reader, delay: 30 * time.Millisecond})
+	require.NoError(t, err)
+	assert.Equal(t, content, string(data))
+}
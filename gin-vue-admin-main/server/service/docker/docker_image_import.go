@@ -0,0 +1,428 @@
+package docker
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/docker/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/docker/response"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils"
+	"go.uber.org/zap"
+)
+
+// 镜像导入来源
+const (
+	imageImportPath   = "path"   // 服务器上的文件
+	imageImportURL    = "url"    // 远程URL
+	imageImportUpload = "upload" // 分片上传的文件
+)
+
+// 镜像包格式
+const (
+	imageArchiveSave   = "save"   // docker save 导出的镜像包，使用 ImageLoad 导入
+	imageArchiveRootfs = "rootfs" // 文件系统包（docker export 或 rootfs tar），使用 ImageImport 导入
+)
+
+// imageImportTimeout 导入镜像超时时间，包含下载时间
+const imageImportTimeout = 30 * time.Minute
+
+// importChunkDir utils.BreakPointContinue 保存分片的目录，每个文件的分片在以文件MD5命名的子目录中
+var importChunkDir = "./breakpointDir/"
+
+var (
+	fileMd5Pattern       = regexp.MustCompile(`^[0-9a-fA-F]{32}$`)
+	loadedImagePattern   = regexp.MustCompile(`^Loaded image: (\S+)`)
+	loadedImageIDPattern = regexp.MustCompile(`^Loaded image ID: (\S+)`)
+	importedIDPattern    = regexp.MustCompile(`^sha256:[0-9a-f]{64}$`)
+)
+
+// rootfsTopDirs 文件系统包常见的顶层目录，出现时可直接判定为 rootfs 包
+var rootfsTopDirs = map[string]bool{
+	"bin": true, "etc": true, "lib": true, "sbin": true, "usr": true, "var": true,
+}
+
+// ImportImage 导入镜像
+// 来源可以是服务器文件、URL或分片上传的文件，自动识别 docker save 镜像包与 rootfs 包
+func (d *DockerImageService) ImportImage(importReq request.ImageImportRequest) (*response.ImageImportResult, error) {
+	// 检查Docker客户端是否可用
+	if d.cli() == nil {
+		return nil, fmt.Errorf("Docker client is not available")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), imageImportTimeout)
+	defer cancel()
+
+	sourceType := importReq.SourceType
+	if sourceType == "" {
+		switch {
+		case importReq.FileMd5 != "":
+			sourceType = imageImportUpload
+		case strings.HasPrefix(importReq.Source, "http://") || strings.HasPrefix(importReq.Source, "https://"):
+			sourceType = imageImportURL
+		default:
+			sourceType = imageImportPath
+		}
+	}
+
+	switch sourceType {
+	case imageImportPath:
+		archive, err := filepath.Abs(importReq.Source)
+		if err != nil || importReq.Source == "" {
+			return nil, fmt.Errorf("invalid import path: %s", importReq.Source)
+		}
+		info, err := os.Stat(archive)
+		if err != nil || !info.Mode().IsRegular() {
+			return nil, fmt.Errorf("import file not found: %s", importReq.Source)
+		}
+		return importImageArchive(ctx, d.streamCli(), archive, importReq)
+
+	case imageImportURL:
+		archive, err := downloadImportArchive(ctx, importReq.Source)
+		if err != nil {
+			return nil, err
+		}
+		defer os.Remove(archive)
+		return importImageArchive(ctx, d.streamCli(), archive, importReq)
+
+	case imageImportUpload:
+		archive, err := mergeImportChunks(importReq.FileMd5, importReq.FileName)
+		if err != nil {
+			return nil, err
+		}
+		defer os.Remove(archive)
+		result, err := importImageArchive(ctx, d.streamCli(), archive, importReq)
+		if err != nil {
+			// 保留分片，便于更换格式等参数后重试
+			return nil, err
+		}
+		if err := utils.RemoveChunk(importReq.FileMd5); err != nil {
+			global.GVA_LOG.Warn("Failed to remove import chunks", zap.String("fileMd5", importReq.FileMd5), zap.Error(err))
+		}
+		return result, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported import source type: %s", sourceType)
+	}
+}
+
+// ImportImageUpload 导入直接上传的镜像包，适用于较小的文件
+func (d *DockerImageService) ImportImageUpload(header *multipart.FileHeader, importReq request.ImageImportRequest) (*response.ImageImportResult, error) {
+	if d.cli() == nil {
+		return nil, fmt.Errorf("Docker client is not available")
+	}
+
+	src, err := header.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open uploaded file: %v", err)
+	}
+	defer src.Close()
+
+	archive, err := saveImportTempFile(src)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(archive)
+
+	ctx, cancel := context.WithTimeout(context.Background(), imageImportTimeout)
+	defer cancel()
+	return importImageArchive(ctx, d.streamCli(), archive, importReq)
+}
+
+// SaveImportChunk 保存一个导入包分片，分片全部上传后以 upload 来源调用 ImportImage 合并导入
+func (d *DockerImageService) SaveImportChunk(chunkReq request.ImageImportChunkRequest, content []byte) error {
+	if err := validateImportUpload(chunkReq.FileMd5, chunkReq.FileName); err != nil {
+		return err
+	}
+	if chunkReq.ChunkNumber < 0 || (chunkReq.ChunkTotal > 0 && chunkReq.ChunkNumber >= chunkReq.ChunkTotal) {
+		return fmt.Errorf("invalid chunk number: %d", chunkReq.ChunkNumber)
+	}
+	if !utils.CheckMd5(content, chunkReq.ChunkMd5) {
+		return fmt.Errorf("chunk md5 mismatch")
+	}
+	if _, err := utils.BreakPointContinue(content, chunkReq.FileName, chunkReq.ChunkNumber, chunkReq.ChunkTotal, chunkReq.FileMd5); err != nil {
+		return fmt.Errorf("failed to save chunk: %v", err)
+	}
+	return nil
+}
+
+// importImageArchive 按格式导入镜像包并汇总导入的镜像
+func importImageArchive(ctx context.Context, cli *client.Client, archive string, importReq request.ImageImportRequest) (*response.ImageImportResult, error) {
+	format := importReq.Format
+	if format == "" || format == "auto" {
+		detected, err := detectImageArchiveFormat(archive)
+		if err != nil {
+			return nil, err
+		}
+		format = detected
+	}
+
+	file, err := os.Open(archive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open import file: %v", err)
+	}
+	defer file.Close()
+
+	var refs []string
+	var importLog string
+	switch format {
+	case imageArchiveSave:
+		loadResponse, err := cli.ImageLoad(ctx, file, false)
+		if err != nil {
+			global.GVA_LOG.Error("Failed to load image", zap.String("file", archive), zap.Error(err))
+			return nil, fmt.Errorf("failed to load image: %v", err)
+		}
+		defer loadResponse.Body.Close()
+		importLog, err = readImportLog(loadResponse.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load image: %v", err)
+		}
+		for _, line := range strings.Split(importLog, "\n") {
+			if match := loadedImagePattern.FindStringSubmatch(line); match != nil {
+				refs = append(refs, match[1])
+			} else if match := loadedImageIDPattern.FindStringSubmatch(line); match != nil {
+				refs = append(refs, match[1])
+			}
+		}
+
+	case imageArchiveRootfs:
+		if importReq.Tag == "" {
+			return nil, fmt.Errorf("tag is required when importing a rootfs archive")
+		}
+		reader, err := cli.ImageImport(ctx, types.ImageImportSource{Source: file, SourceName: "-"}, importReq.Tag, types.ImageImportOptions{
+			Changes:  importReq.Changes,
+			Message:  importReq.Message,
+			Platform: importReq.Platform,
+		})
+		if err != nil {
+			global.GVA_LOG.Error("Failed to import image", zap.String("file", archive), zap.Error(err))
+			return nil, fmt.Errorf("failed to import image: %v", err)
+		}
+		defer reader.Close()
+		importLog, err = readImportLog(reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to import image: %v", err)
+		}
+		for _, line := range strings.Split(importLog, "\n") {
+			if importedIDPattern.MatchString(strings.TrimSpace(line)) {
+				refs = append(refs, strings.TrimSpace(line))
+			}
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported image archive format: %s", format)
+	}
+
+	images := collectImportedImages(ctx, cli, refs)
+	// docker save 包只包含一个镜像时追加指定的标签
+	if format == imageArchiveSave && importReq.Tag != "" && len(images) == 1 {
+		if err := cli.ImageTag(ctx, images[0].ID, importReq.Tag); err != nil {
+			return nil, fmt.Errorf("failed to tag image: %v", err)
+		}
+		images[0].Tags = append(images[0].Tags, importReq.Tag)
+	}
+
+	global.GVA_LOG.Info("Image imported successfully", zap.String("format", format), zap.Int("images", len(images)))
+	return &response.ImageImportResult{
+		Format: format,
+		Images: images,
+		Log:    importLog,
+	}, nil
+}
+
+// detectImageArchiveFormat 扫描 tar 包目录，含 manifest.json 或 repositories 的为 docker save 镜像包
+// 支持未压缩与 gzip 压缩的包，其他压缩格式需要指定格式
+func detectImageArchiveFormat(archive string) (string, error) {
+	file, err := os.Open(archive)
+	if err != nil {
+		return "", fmt.Errorf("failed to open import file: %v", err)
+	}
+	defer file.Close()
+
+	br := bufio.NewReader(file)
+	magic, _ := br.Peek(6)
+	var reader io.Reader = br
+	switch {
+	case bytes.HasPrefix(magic, []byte{0x1f, 0x8b}):
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return "", fmt.Errorf("invalid gzip archive: %v", err)
+		}
+		defer gz.Close()
+		reader = gz
+	case bytes.HasPrefix(magic, []byte{0xfd, '7', 'z', 'X', 'Z', 0x00}), bytes.HasPrefix(magic, []byte("BZh")):
+		return "", fmt.Errorf("cannot detect the format of a compressed archive, please specify the format")
+	}
+
+	tr := tar.NewReader(reader)
+	entries := 0
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("not a valid tar archive: %v", err)
+		}
+		entries++
+		name := strings.TrimPrefix(path.Clean("/"+header.Name), "/")
+		if name == "manifest.json" || name == "repositories" {
+			return imageArchiveSave, nil
+		}
+		if top, _, _ := strings.Cut(name, "/"); rootfsTopDirs[top] {
+			return imageArchiveRootfs, nil
+		}
+	}
+	if entries == 0 {
+		return "", fmt.Errorf("import archive is empty")
+	}
+	return imageArchiveRootfs, nil
+}
+
+// readImportLog 读取导入接口返回的消息流，返回可读日志
+func readImportLog(reader io.Reader) (string, error) {
+	tracker := newImageProgressTracker("")
+	var log strings.Builder
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line, err := tracker.handle(scanner.Bytes())
+		if line != "" {
+			log.WriteString(line)
+			log.WriteByte('\n')
+		}
+		if err != nil {
+			return log.String(), err
+		}
+	}
+	return log.String(), scanner.Err()
+}
+
+// collectImportedImages 查询导入的镜像引用，按镜像ID去重
+func collectImportedImages(ctx context.Context, cli *client.Client, refs []string) []response.ImportedImage {
+	images := make([]response.ImportedImage, 0, len(refs))
+	index := make(map[string]int)
+	for _, ref := range refs {
+		inspect, _, err := cli.ImageInspectWithRaw(ctx, ref)
+		if err != nil {
+			global.GVA_LOG.Warn("Failed to inspect imported image", zap.String("ref", ref), zap.Error(err))
+			continue
+		}
+		if _, ok := index[inspect.ID]; ok {
+			continue
+		}
+		index[inspect.ID] = len(images)
+		tags := inspect.RepoTags
+		if tags == nil {
+			tags = []string{}
+		}
+		images = append(images, response.ImportedImage{ID: inspect.ID, Tags: tags})
+	}
+	return images
+}
+
+// downloadImportArchive 下载远程镜像包到临时文件
+func downloadImportArchive(ctx context.Context, source string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+	if err != nil {
+		return "", fmt.Errorf("invalid import url: %v", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to download import file: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to download import file: status %d", resp.StatusCode)
+	}
+	return saveImportTempFile(resp.Body)
+}
+
+// saveImportTempFile 将导入包写入临时文件
+func saveImportTempFile(src io.Reader) (string, error) {
+	file, err := os.CreateTemp("", "gva-image-import-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file: %v", err)
+	}
+	_, err = io.Copy(file, src)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(file.Name())
+		return "", fmt.Errorf("failed to save import file: %v", err)
+	}
+	return file.Name(), nil
+}
+
+// mergeImportChunks 按序号合并分片上传的导入包到临时文件，返回合并后的文件路径
+func mergeImportChunks(fileMd5, fileName string) (string, error) {
+	if err := validateImportUpload(fileMd5, fileName); err != nil {
+		return "", err
+	}
+	chunkDir := filepath.Join(importChunkDir, fileMd5)
+	entries, err := os.ReadDir(chunkDir)
+	if errors.Is(err, os.ErrNotExist) || (err == nil && len(entries) == 0) {
+		return "", fmt.Errorf("uploaded chunks not found")
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to merge chunks: %v", err)
+	}
+
+	file, err := os.CreateTemp("", "gva-image-import-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file: %v", err)
+	}
+	for i := range entries {
+		if err = appendImportChunk(file, filepath.Join(chunkDir, fileName+"_"+strconv.Itoa(i))); err != nil {
+			break
+		}
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(file.Name())
+		return "", fmt.Errorf("failed to merge chunks: %v", err)
+	}
+	return file.Name(), nil
+}
+
+// appendImportChunk 将一个分片追加到合并文件
+func appendImportChunk(dst io.Writer, chunkPath string) error {
+	chunk, err := os.Open(chunkPath)
+	if err != nil {
+		return err
+	}
+	defer chunk.Close()
+	_, err = io.Copy(dst, chunk)
+	return err
+}
+
+// validateImportUpload 校验分片上传的文件MD5与文件名，文件名不能包含路径
+func validateImportUpload(fileMd5, fileName string) error {
+	if !fileMd5Pattern.MatchString(fileMd5) {
+		return fmt.Errorf("invalid file md5")
+	}
+	if fileName == "" || fileName != filepath.Base(fileName) || strings.Contains(fileName, "..") {
+		return fmt.Errorf("invalid file name")
+	}
+	return nil
+}
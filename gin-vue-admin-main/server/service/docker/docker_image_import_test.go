@@ -0,0 +1,101 @@
+package docker
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestTar(t *testing.T, path string, names []string, compress bool) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, name := range names {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: 2, Typeflag: tar.TypeReg}))
+		_, err := tw.Write([]byte("{}"))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+
+	data := buf.Bytes()
+	if compress {
+		var gzBuf bytes.Buffer
+		gz := gzip.NewWriter(&gzBuf)
+		_, err := gz.Write(data)
+		require.NoError(t, err)
+		require.NoError(t, gz.Close())
+		data = gzBuf.Bytes()
+	}
+	require.NoError(t, os.WriteFile(path, data, 0o644))
+}
+
+func TestDetectImageArchiveFormat(t *testing.T) {
+	dir := t.TempDir()
+
+	save := filepath.Join(dir, "save.tar")
+	writeTestTar(t, save, []string{"abc/layer.tar", "abc/json", "abc.json", "manifest.json"}, false)
+	format, err := detectImageArchiveFormat(save)
+	assert.NoError(t, err)
+	assert.Equal(t, imageArchiveSave, format)
+
+	saveGz := filepath.Join(dir, "save.tar.gz")
+	writeTestTar(t, saveGz, []string{"./repositories", "abc/layer.tar"}, true)
+	format, err = detectImageArchiveFormat(saveGz)
+	assert.NoError(t, err)
+	assert.Equal(t, imageArchiveSave, format)
+
+	rootfs := filepath.Join(dir, "rootfs.tar")
+	writeTestTar(t, rootfs, []string{"./etc/os-release", "./bin/sh"}, false)
+	format, err = detectImageArchiveFormat(rootfs)
+	assert.NoError(t, err)
+	assert.Equal(t, imageArchiveRootfs, format)
+
+	invalid := filepath.Join(dir, "invalid.tar")
+	require.NoError(t, os.WriteFile(invalid, []byte("not a tar archive"), 0o644))
+	_, err = detectImageArchiveFormat(invalid)
+	assert.Error(t, err)
+}
+
+func TestValidateImportUpload(t *testing.T) {
+	assert.NoError(t, validateImportUpload("d41d8cd98f00b204e9800998ecf8427e", "nginx.tar"))
+	assert.Error(t, validateImportUpload("d41d8cd98f00b204e9800998ecf8427e", "../nginx.tar"))
+	assert.Error(t, validateImportUpload("../../etc", "nginx.tar"))
+}
+
+func TestMergeImportChunks(t *testing.T) {
+	oldDir := importChunkDir
+	importChunkDir = t.TempDir()
+	t.Cleanup(func() { importChunkDir = oldDir })
+
+	fileMd5 := "d41d8cd98f00b204e9800998ecf8427e"
+	_, err := mergeImportChunks(fileMd5, "nginx.tar")
+	assert.EqualError(t, err, "uploaded chunks not found")
+
+	// 分片按序号而不是文件名顺序合并
+	chunkDir := filepath.Join(importChunkDir, fileMd5)
+	require.NoError(t, os.MkdirAll(chunkDir, 0o755))
+	var want bytes.Buffer
+	for i := 0; i < 12; i++ {
+		chunk := []byte{byte('a' + i)}
+		want.Write(chunk)
+		require.NoError(t, os.WriteFile(filepath.Join(chunkDir, "nginx.tar_"+strconv.Itoa(i)), chunk, 0o644))
+	}
+	archive, err := mergeImportChunks(fileMd5, "nginx.tar")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.Remove(archive) })
+	merged, err := os.ReadFile(archive)
+	require.NoError(t, err)
+	assert.Equal(t, want.String(), string(merged))
+
+	// 缺少分片时不导入不完整的文件
+	require.NoError(t, os.Remove(filepath.Join(chunkDir, "nginx.tar_5")))
+	require.NoError(t, os.WriteFile(filepath.Join(chunkDir, "nginx.tar_12"), []byte("m"), 0o644))
+	_, err = mergeImportChunks(fileMd5, "nginx.tar")
+	assert.ErrorContains(t, err, "failed to merge chunks")
+}
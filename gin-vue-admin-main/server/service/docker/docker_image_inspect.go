@@ -0,0 +1,825 @@
+package docker
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/client"
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	dockerModel "github.com/flipped-aurora/gin-vue-admin/server/model/docker"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/docker/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/docker/response"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	imageInspectTimeout     = time.Hour      // 单次检查最长执行时间
+	imageInspectConcurrency = 1              // 同时执行的检查数，解包会占用与镜像大小相当的磁盘空间
+	imageInspectFileLimit   = 256 << 20      // 单个清单文件读取上限
+	imageInspectRetention   = 24 * time.Hour // 遗留临时目录的保留时间
+	imageReportBatchSize    = 500            // 批量写入条数
+)
+
+// imageInspections 执行中的检查
+var imageInspections = struct {
+	sync.Mutex
+	running map[uint]bool
+	slots   chan struct{}
+}{
+	running: make(map[uint]bool),
+	slots:   make(chan struct{}, imageInspectConcurrency),
+}
+
+type DockerImageInspectService struct {
+	hostClient
+}
+
+// imageInventory 镜像内容清单
+type imageInventory struct {
+	OS        string
+	OSID      string
+	OSVersion string
+	Packages  []dockerModel.DockerImageReportPackage
+	Files     []dockerModel.DockerImageReportFile
+	Warnings  []string
+}
+
+// layerFile 合并镜像层过程中跟踪的文件
+type layerFile struct {
+	layer   int
+	mode    int64
+	uid     int
+	gid     int
+	size    int64
+	setuid  bool
+	local   string // 清单文件内容在临时目录中的副本
+	skipped bool   // 超过大小上限未读取
+}
+
+// InspectImage 提交镜像内容检查，后台解包镜像层并生成报告，立即返回报告记录
+func (s *DockerImageInspectService) InspectImage(inspectReq request.ImageInspectRequest, userID uint) (*dockerModel.DockerImageReport, error) {
+	if s.cli() == nil {
+		return nil, fmt.Errorf("Docker client is not available")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	inspect, _, err := s.cli().ImageInspectWithRaw(ctx, inspectReq.Image)
+	if err != nil {
+		return nil, fmt.Errorf("image not found: %s", inspectReq.Image)
+	}
+
+	var running int64
+	if err := global.GVA_DB.Model(&dockerModel.DockerImageReport{}).
+		Where("image_id = ? AND status = ?", inspect.ID, dockerModel.ImageReportStatusRunning).
+		Count(&running).Error; err != nil {
+		return nil, err
+	}
+	if running > 0 {
+		return nil, fmt.Errorf("image inspection is already running")
+	}
+
+	report := dockerModel.DockerImageReport{
+		ImageID:    inspect.ID,
+		ImageRef:   inspectReq.Image,
+		Status:     dockerModel.ImageReportStatusRunning,
+		LayerCount: len(inspect.RootFS.Layers),
+		Size:       inspect.Size,
+		Warnings:   []string{},
+		HostID:     s.hostID(),
+		CreatedBy:  userID,
+	}
+	if err := global.GVA_DB.Create(&report).Error; err != nil {
+		return nil, err
+	}
+
+	imageInspections.Lock()
+	imageInspections.running[report.ID] = true
+	imageInspections.Unlock()
+	go runImageInspection(s.streamCli(), report.ID, inspect.ID)
+
+	global.GVA_LOG.Info("Image inspection submitted", zap.Uint("reportId", report.ID), zap.String("image", inspectReq.Image))
+	return &report, nil
+}
+
+// GetImageReportList 分页获取检查报告，不含明细
+func (s *DockerImageInspectService) GetImageReportList(filter request.ImageReportFilter) ([]dockerModel.DockerImageReport, int64, error) {
+	db := global.GVA_DB.Model(&dockerModel.DockerImageReport{})
+	if filter.Image != "" {
+		db = db.Where("image_id LIKE ? OR image_ref LIKE ?", "%"+filter.Image+"%", "%"+filter.Image+"%")
+	}
+	if filter.Status != "" {
+		db = db.Where("status = ?", filter.Status)
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var reports []dockerModel.DockerImageReport
+	limit := filter.PageSize
+	offset := filter.PageSize * (filter.Page - 1)
+	if limit > 0 {
+		db = db.Limit(limit).Offset(offset)
+	}
+	err := db.Order("id desc").Find(&reports).Error
+	return reports, total, err
+}
+
+// GetImageReport 获取检查报告及软件包、漏洞与 setuid 文件明细
+func (s *DockerImageInspectService) GetImageReport(id uint) (*dockerModel.DockerImageReport, error) {
+	var report dockerModel.DockerImageReport
+	err := global.GVA_DB.
+		Preload("Packages", func(db *gorm.DB) *gorm.DB { return db.Order("type, name, version") }).
+		Preload("Findings", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		Preload("Files", func(db *gorm.DB) *gorm.DB { return db.Order("path") }).
+		First(&report, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("image report not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// DeleteImageReport 删除检查报告及明细
+func (s *DockerImageInspectService) DeleteImageReport(id uint) error {
+	report, err := getImageReport(id)
+	if err != nil {
+		return err
+	}
+	if report.Status == dockerModel.ImageReportStatusRunning && imageInspectionRunning(id) {
+		return fmt.Errorf("image inspection is still running")
+	}
+	return global.GVA_DB.Transaction(func(tx *gorm.DB) error {
+		return deleteImageReport(tx, id)
+	})
+}
+
+// RematchImageReport 使用当前离线漏洞库重新匹配报告中的软件包，导入新漏洞库后无需重新解包镜像
+func (s *DockerImageInspectService) RematchImageReport(id uint) (*dockerModel.DockerImageReport, error) {
+	report, err := getImageReport(id)
+	if err != nil {
+		return nil, err
+	}
+	if report.Status != dockerModel.ImageReportStatusSuccess {
+		return nil, fmt.Errorf("image report is not completed")
+	}
+
+	var packages []dockerModel.DockerImageReportPackage
+	if err := global.GVA_DB.Where("report_id = ?", id).Find(&packages).Error; err != nil {
+		return nil, err
+	}
+	findings, err := matchVulnerabilities(packages, report.OSID, report.OSVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	err = global.GVA_DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("report_id = ?", id).Delete(&dockerModel.DockerImageReportFinding{}).Error; err != nil {
+			return err
+		}
+		for i := range findings {
+			findings[i].ReportID = id
+		}
+		if len(findings) > 0 {
+			if err := tx.CreateInBatches(findings, imageReportBatchSize).Error; err != nil {
+				return err
+			}
+		}
+		applyFindingCounts(report, findings)
+		return tx.Model(report).Select("vuln_count", "critical_count", "high_count", "medium_count", "low_count", "matched_at").Updates(report).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// CompareImageReports 对比两份报告的软件包、漏洞与 setuid 文件变化
+func (s *DockerImageInspectService) CompareImageReports(compareReq request.ImageReportCompareRequest) (*response.ImageReportCompare, error) {
+	base, err := s.GetImageReport(compareReq.BaseID)
+	if err != nil {
+		return nil, err
+	}
+	target, err := s.GetImageReport(compareReq.TargetID)
+	if err != nil {
+		return nil, err
+	}
+	if base.Status != dockerModel.ImageReportStatusSuccess || target.Status != dockerModel.ImageReportStatusSuccess {
+		return nil, fmt.Errorf("image report is not completed")
+	}
+	return compareImageReports(base, target), nil
+}
+
+// Cleanup 将服务重启前中断的检查标记为失败，并删除遗留的临时目录
+func (s *DockerImageInspectService) Cleanup() error {
+	err := global.GVA_DB.Model(&dockerModel.DockerImageReport{}).
+		Where("status = ? AND created_at < ?", dockerModel.ImageReportStatusRunning, imageJobBootTime).
+		Updates(map[string]interface{}{"status": dockerModel.ImageReportStatusFailed, "error": "服务重启，检查已中断"}).Error
+	if err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(imageInspectWorkDir())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	expire := time.Now().Add(-imageInspectRetention)
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || info.ModTime().After(expire) {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(imageInspectWorkDir(), entry.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runImageInspection 后台执行检查并保存结果
+func runImageInspection(cli *client.Client, reportID uint, imageID string) {
+	defer func() {
+		imageInspections.Lock()
+		delete(imageInspections.running, reportID)
+		imageInspections.Unlock()
+	}()
+	imageInspections.slots <- struct{}{}
+	defer func() { <-imageInspections.slots }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), imageInspectTimeout)
+	defer cancel()
+
+	started := time.Now()
+	inventory, err := inspectImageContent(ctx, cli, imageID)
+	if err == nil {
+		err = saveImageInventory(reportID, inventory)
+	}
+	if err != nil {
+		global.GVA_LOG.Error("Image inspection failed", zap.Uint("reportId", reportID), zap.String("imageId", imageID), zap.Error(err))
+		global.GVA_DB.Model(&dockerModel.DockerImageReport{}).Where("id = ?", reportID).
+			Updates(map[string]interface{}{"status": dockerModel.ImageReportStatusFailed, "error": err.Error()})
+		return
+	}
+	global.GVA_LOG.Info("Image inspection completed", zap.Uint("reportId", reportID), zap.Int("packages", len(inventory.Packages)), zap.Duration("elapsed", time.Since(started)))
+}
+
+// inspectImageContent 导出镜像并按层合并，生成内容清单
+func inspectImageContent(ctx context.Context, cli *client.Client, imageID string) (*imageInventory, error) {
+	if err := os.MkdirAll(imageInspectWorkDir(), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create inspect directory: %v", err)
+	}
+	dir, err := os.MkdirTemp(imageInspectWorkDir(), "image-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create inspect directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	reader, err := cli.ImageSave(ctx, []string{imageID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save image: %v", err)
+	}
+	layers, err := splitImageArchive(reader, dir)
+	_ = reader.Close()
+	if err != nil {
+		return nil, err
+	}
+	return scanImageLayers(layers, dir)
+}
+
+// splitImageArchive 将 docker save 数据流中的各层写入临时目录，按 manifest.json 的顺序返回层文件
+// 兼容旧格式的 <id>/layer.tar 与 OCI 布局的 blobs/sha256/<digest>（旧路径可能是指向 blob 的符号链接）
+func splitImageArchive(r io.Reader, dir string) ([]string, error) {
+	var manifest []struct {
+		Layers []string `json:"Layers"`
+	}
+	files := make(map[string]string)
+	links := make(map[string]string)
+
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read image archive: %v", err)
+		}
+		name := cleanLayerPath(hdr.Name)
+		switch {
+		case hdr.Typeflag == tar.TypeSymlink:
+			links[name] = cleanLayerPath(path.Join(path.Dir(name), hdr.Linkname))
+		case hdr.Typeflag != tar.TypeReg:
+			continue
+		case name == "manifest.json":
+			if err := json.NewDecoder(io.LimitReader(tr, 16<<20)).Decode(&manifest); err != nil {
+				return nil, fmt.Errorf("invalid image manifest: %v", err)
+			}
+		case strings.HasSuffix(name, "/layer.tar") || strings.HasPrefix(name, "blobs/"):
+			local := filepath.Join(dir, "layer-"+strconv.Itoa(len(files)))
+			if err := writeLocalFile(local, tr); err != nil {
+				return nil, fmt.Errorf("failed to save image layer: %v", err)
+			}
+			files[name] = local
+		}
+	}
+
+	if len(manifest) == 0 {
+		return nil, fmt.Errorf("invalid image archive: manifest.json not found")
+	}
+	layers := make([]string, 0, len(manifest[0].Layers))
+	for _, layer := range manifest[0].Layers {
+		name := cleanLayerPath(layer)
+		if target, ok := links[name]; ok {
+			name = target
+		}
+		local, ok := files[name]
+		if !ok {
+			return nil, fmt.Errorf("invalid image archive: layer %s not found", layer)
+		}
+		layers = append(layers, local)
+	}
+	return layers, nil
+}
+
+// scanImageLayers 按顺序合并镜像层：上层文件覆盖下层，whiteout 删除下层文件，最后从合并结果生成清单
+func scanImageLayers(layers []string, workDir string) (*imageInventory, error) {
+	filesDir := filepath.Join(workDir, "files")
+	if err := os.MkdirAll(filesDir, 0o700); err != nil {
+		return nil, err
+	}
+	view := make(map[string]*layerFile)
+	for i, layer := range layers {
+		if err := scanLayer(i, layer, view, filesDir); err != nil {
+			return nil, fmt.Errorf("failed to read image layer %d: %v", i+1, err)
+		}
+	}
+	return buildInventory(view), nil
+}
+
+// scanLayer 读取单个层，更新合并视图
+func scanLayer(index int, layerPath string, view map[string]*layerFile, filesDir string) error {
+	f, err := os.Open(layerPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	reader, err := openLayerReader(f)
+	if err != nil {
+		return err
+	}
+	tr := tar.NewReader(reader)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		name := cleanLayerPath(hdr.Name)
+		if name == "" {
+			continue
+		}
+
+		base := path.Base(name)
+		if base == ".wh..wh..opq" {
+			// 不透明目录：隐藏下层该目录中的全部内容
+			removeLowerFiles(view, path.Dir(name)+"/", index, true)
+			continue
+		}
+		if strings.HasPrefix(base, ".wh.") {
+			target := path.Join(path.Dir(name), strings.TrimPrefix(base, ".wh."))
+			removeLowerFiles(view, target, index, false)
+			continue
+		}
+
+		if hdr.Typeflag != tar.TypeReg && hdr.Typeflag != tar.TypeLink {
+			delete(view, name)
+			continue
+		}
+		setuid := hdr.Mode&(04000|02000) != 0
+		inventory := hdr.Typeflag == tar.TypeReg && isInventoryFile(name)
+		if !setuid && !inventory {
+			// 普通文件覆盖了下层被跟踪的文件
+			delete(view, name)
+			continue
+		}
+
+		entry := &layerFile{layer: index, mode: hdr.Mode, uid: hdr.Uid, gid: hdr.Gid, size: hdr.Size, setuid: setuid}
+		if inventory {
+			if hdr.Size > imageInspectFileLimit {
+				entry.skipped = true
+			} else {
+				entry.local = filepath.Join(filesDir, strconv.Itoa(index)+"-"+strconv.Itoa(len(view))+"-"+randomHex(4))
+				if err := writeLocalFile(entry.local, tr); err != nil {
+					return err
+				}
+			}
+		}
+		view[name] = entry
+	}
+}
+
+// openLayerReader 识别层是否经过 gzip 压缩
+func openLayerReader(f *os.File) (io.Reader, error) {
+	br := bufio.NewReader(f)
+	magic, _ := br.Peek(4)
+	switch {
+	case len(magic) >= 2 && magic[0] == 0x1f && magic[1] == 0x8b:
+		return gzip.NewReader(br)
+	case bytes.Equal(magic, []byte{0x28, 0xb5, 0x2f, 0xfd}):
+		return nil, fmt.Errorf("zstd compressed layers are not supported")
+	}
+	return br, nil
+}
+
+// removeLowerFiles 删除下层中的文件或目录，prefixOnly 时只删除目录下的内容
+func removeLowerFiles(view map[string]*layerFile, target string, index int, prefixOnly bool) {
+	if !prefixOnly {
+		if entry, ok := view[target]; ok && entry.layer < index {
+			delete(view, target)
+		}
+		target += "/"
+	}
+	for name, entry := range view {
+		if entry.layer < index && strings.HasPrefix(name, target) {
+			delete(view, name)
+		}
+	}
+}
+
+// buildInventory 从合并视图生成清单
+func buildInventory(view map[string]*layerFile) *imageInventory {
+	inventory := &imageInventory{Warnings: []string{}}
+	names := make([]string, 0, len(view))
+	for name := range view {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range []string{osReleasePath, osReleaseFallback} {
+		if entry, ok := view[name]; ok && entry.local != "" {
+			if data, err := os.ReadFile(entry.local); err == nil {
+				values := parseOSRelease(data)
+				inventory.OS = values["PRETTY_NAME"]
+				inventory.OSID = values["ID"]
+				inventory.OSVersion = values["VERSION_ID"]
+				break
+			}
+		}
+	}
+
+	var legacyRpm []string
+	rpmParsed := false
+	for _, name := range names {
+		entry := view[name]
+		if entry.setuid {
+			inventory.Files = append(inventory.Files, dockerModel.DockerImageReportFile{
+				Path: "/" + name,
+				Mode: unixModeString(entry.mode),
+				Uid:  entry.uid,
+				Gid:  entry.gid,
+				Size: entry.size,
+			})
+		}
+		if entry.skipped {
+			inventory.Warnings = append(inventory.Warnings, fmt.Sprintf("文件超过 %d MB，未解析: /%s", imageInspectFileLimit>>20, name))
+			continue
+		}
+		if entry.local == "" {
+			continue
+		}
+
+		var packages []dockerModel.DockerImageReportPackage
+		var err error
+		switch {
+		case name == osReleasePath || name == osReleaseFallback:
+			continue
+		case name == dpkgStatusPath || strings.HasPrefix(name, dpkgStatusDir):
+			packages, err = parseInventoryFile(entry.local, func(data []byte) ([]dockerModel.DockerImageReportPackage, error) {
+				return parseDpkgStatus(data), nil
+			})
+		case name == apkInstalledPath:
+			packages, err = parseInventoryFile(entry.local, func(data []byte) ([]dockerModel.DockerImageReportPackage, error) {
+				return parseApkInstalled(data), nil
+			})
+		case rpmSqliteDBs[name]:
+			packages, err = parseRpmSqlite(entry.local)
+			rpmParsed = rpmParsed || err == nil
+		case rpmLegacyDBs[name] != "":
+			legacyRpm = append(legacyRpm, name)
+			continue
+		default:
+			parser := lockfileParsers[path.Base(name)]
+			packages, err = parseInventoryFile(entry.local, parser.Parse)
+			for i := range packages {
+				packages[i].Type = parser.Type
+			}
+		}
+		if err != nil {
+			inventory.Warnings = append(inventory.Warnings, fmt.Sprintf("解析失败 /%s: %v", name, err))
+			continue
+		}
+		for i := range packages {
+			packages[i].Path = "/" + name
+		}
+		inventory.Packages = append(inventory.Packages, packages...)
+	}
+	if !rpmParsed {
+		for _, name := range legacyRpm {
+			inventory.Warnings = append(inventory.Warnings, fmt.Sprintf("rpm 数据库格式 %s 暂不支持解析，未列出 rpm 软件包: /%s", rpmLegacyDBs[name], name))
+		}
+	}
+
+	sortPackages(inventory.Packages)
+	return inventory
+}
+
+// parseInventoryFile 读取临时副本并解析
+func parseInventoryFile(local string, parse func(data []byte) ([]dockerModel.DockerImageReportPackage, error)) ([]dockerModel.DockerImageReportPackage, error) {
+	data, err := os.ReadFile(local)
+	if err != nil {
+		return nil, err
+	}
+	return parse(data)
+}
+
+// saveImageInventory 匹配漏洞并保存报告明细
+func saveImageInventory(reportID uint, inventory *imageInventory) error {
+	findings, err := matchVulnerabilities(inventory.Packages, inventory.OSID, inventory.OSVersion)
+	if err != nil {
+		return err
+	}
+
+	return global.GVA_DB.Transaction(func(tx *gorm.DB) error {
+		for i := range inventory.Packages {
+			inventory.Packages[i].ReportID = reportID
+		}
+		for i := range inventory.Files {
+			inventory.Files[i].ReportID = reportID
+		}
+		for i := range findings {
+			findings[i].ReportID = reportID
+		}
+		if len(inventory.Packages) > 0 {
+			if err := tx.CreateInBatches(inventory.Packages, imageReportBatchSize).Error; err != nil {
+				return err
+			}
+		}
+		if len(inventory.Files) > 0 {
+			if err := tx.CreateInBatches(inventory.Files, imageReportBatchSize).Error; err != nil {
+				return err
+			}
+		}
+		if len(findings) > 0 {
+			if err := tx.CreateInBatches(findings, imageReportBatchSize).Error; err != nil {
+				return err
+			}
+		}
+
+		report := dockerModel.DockerImageReport{
+			ID:           reportID,
+			Status:       dockerModel.ImageReportStatusSuccess,
+			OS:           truncateRunes(inventory.OS, 200),
+			OSID:         truncateRunes(inventory.OSID, 50),
+			OSVersion:    truncateRunes(inventory.OSVersion, 50),
+			PackageCount: len(inventory.Packages),
+			SetuidCount:  len(inventory.Files),
+			Warnings:     inventory.Warnings,
+		}
+		applyFindingCounts(&report, findings)
+		return tx.Model(&report).Select("status", "os", "os_id", "os_version", "package_count", "setuid_count", "warnings",
+			"vuln_count", "critical_count", "high_count", "medium_count", "low_count", "matched_at").Updates(&report).Error
+	})
+}
+
+// applyFindingCounts 按严重程度统计漏洞数量
+func applyFindingCounts(report *dockerModel.DockerImageReport, findings []dockerModel.DockerImageReportFinding) {
+	report.VulnCount = len(findings)
+	report.CriticalCount, report.HighCount, report.MediumCount, report.LowCount = 0, 0, 0, 0
+	for _, finding := range findings {
+		switch finding.Severity {
+		case dockerModel.SeverityCritical:
+			report.CriticalCount++
+		case dockerModel.SeverityHigh:
+			report.HighCount++
+		case dockerModel.SeverityMedium:
+			report.MediumCount++
+		case dockerModel.SeverityLow:
+			report.LowCount++
+		}
+	}
+	now := time.Now()
+	report.MatchedAt = &now
+}
+
+// compareImageReports 计算两份报告的差异
+func compareImageReports(base, target *dockerModel.DockerImageReport) *response.ImageReportCompare {
+	result := &response.ImageReportCompare{
+		BaseID:          base.ID,
+		TargetID:        target.ID,
+		BaseImage:       base.ImageRef,
+		TargetImage:     target.ImageRef,
+		AddedPackages:   []response.ReportPackageChange{},
+		RemovedPackages: []response.ReportPackageChange{},
+		ChangedPackages: []response.ReportPackageChange{},
+		NewFindings:     []response.ReportFindingChange{},
+		FixedFindings:   []response.ReportFindingChange{},
+		AddedSetuid:     []string{},
+		RemovedSetuid:   []string{},
+	}
+
+	// 同一来源文件中同名包可能有多个版本（如嵌套的 npm 依赖），按版本集合比较
+	packageVersions := func(packages []dockerModel.DockerImageReportPackage) (map[string]string, map[string]dockerModel.DockerImageReportPackage) {
+		versions := make(map[string][]string)
+		refs := make(map[string]dockerModel.DockerImageReportPackage)
+		for _, pkg := range packages {
+			key := pkg.Type + "|" + pkg.Name + "|" + pkg.Path
+			versions[key] = append(versions[key], pkg.Version)
+			refs[key] = pkg
+		}
+		joined := make(map[string]string, len(versions))
+		for key, list := range versions {
+			sort.Strings(list)
+			joined[key] = strings.Join(list, ", ")
+		}
+		return joined, refs
+	}
+	baseVersions, baseRefs := packageVersions(base.Packages)
+	targetVersions, targetRefs := packageVersions(target.Packages)
+	for key, version := range targetVersions {
+		pkg := targetRefs[key]
+		change := response.ReportPackageChange{Type: pkg.Type, Name: pkg.Name, Path: pkg.Path, TargetVersion: version}
+		if baseVersion, ok := baseVersions[key]; !ok {
+			result.AddedPackages = append(result.AddedPackages, change)
+		} else if baseVersion != version {
+			change.BaseVersion = baseVersion
+			result.ChangedPackages = append(result.ChangedPackages, change)
+		}
+	}
+	for key, version := range baseVersions {
+		if _, ok := targetVersions[key]; !ok {
+			pkg := baseRefs[key]
+			result.RemovedPackages = append(result.RemovedPackages, response.ReportPackageChange{Type: pkg.Type, Name: pkg.Name, Path: pkg.Path, BaseVersion: version})
+		}
+	}
+
+	findingKey := func(f dockerModel.DockerImageReportFinding) string {
+		return f.VulnID + "|" + f.PackageType + "|" + f.Package + "|" + f.Path
+	}
+	toChange := func(f dockerModel.DockerImageReportFinding) response.ReportFindingChange {
+		return response.ReportFindingChange{VulnID: f.VulnID, PackageType: f.PackageType, Package: f.Package, Version: f.Version, FixedVersion: f.FixedVersion, Severity: f.Severity, Summary: f.Summary}
+	}
+	baseFindings := make(map[string]bool)
+	for _, f := range base.Findings {
+		baseFindings[findingKey(f)] = true
+	}
+	targetFindings := make(map[string]bool)
+	for _, f := range target.Findings {
+		targetFindings[findingKey(f)] = true
+		if !baseFindings[findingKey(f)] {
+			result.NewFindings = append(result.NewFindings, toChange(f))
+			baseFindings[findingKey(f)] = true // 同一漏洞只列一次
+		}
+	}
+	for _, f := range base.Findings {
+		if !targetFindings[findingKey(f)] {
+			result.FixedFindings = append(result.FixedFindings, toChange(f))
+			targetFindings[findingKey(f)] = true
+		}
+	}
+
+	baseFiles := make(map[string]bool)
+	for _, f := range base.Files {
+		baseFiles[f.Path] = true
+	}
+	targetFiles := make(map[string]bool)
+	for _, f := range target.Files {
+		targetFiles[f.Path] = true
+		if !baseFiles[f.Path] {
+			result.AddedSetuid = append(result.AddedSetuid, f.Path)
+		}
+	}
+	for _, f := range base.Files {
+		if !targetFiles[f.Path] {
+			result.RemovedSetuid = append(result.RemovedSetuid, f.Path)
+		}
+	}
+
+	for _, list := range [][]response.ReportPackageChange{result.AddedPackages, result.RemovedPackages, result.ChangedPackages} {
+		sort.Slice(list, func(i, j int) bool {
+			if list[i].Type != list[j].Type {
+				return list[i].Type < list[j].Type
+			}
+			if list[i].Name != list[j].Name {
+				return list[i].Name < list[j].Name
+			}
+			return list[i].Path < list[j].Path
+		})
+	}
+	return result
+}
+
+// getImageReport 获取报告记录，不含明细
+func getImageReport(id uint) (*dockerModel.DockerImageReport, error) {
+	var report dockerModel.DockerImageReport
+	err := global.GVA_DB.First(&report, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("image report not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// deleteImageReport 删除报告及明细
+func deleteImageReport(tx *gorm.DB, id uint) error {
+	for _, model := range []interface{}{&dockerModel.DockerImageReportPackage{}, &dockerModel.DockerImageReportFinding{}, &dockerModel.DockerImageReportFile{}} {
+		if err := tx.Where("report_id = ?", id).Delete(model).Error; err != nil {
+			return err
+		}
+	}
+	return tx.Delete(&dockerModel.DockerImageReport{}, id).Error
+}
+
+// imageInspectionRunning 检查是否仍在执行
+func imageInspectionRunning(id uint) bool {
+	imageInspections.Lock()
+	defer imageInspections.Unlock()
+	return imageInspections.running[id]
+}
+
+// imageInspectWorkDir 镜像解包临时目录
+func imageInspectWorkDir() string {
+	return filepath.Join(os.TempDir(), "gva-docker-inspect")
+}
+
+// cleanLayerPath 规范化层内路径，去掉开头的 ./ 与 /，根目录返回空
+func cleanLayerPath(name string) string {
+	return strings.TrimPrefix(path.Clean("/"+strings.TrimPrefix(name, "./")), "/")
+}
+
+// writeLocalFile 将数据写入临时文件
+func writeLocalFile(name string, r io.Reader) error {
+	f, err := os.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(f, r)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// unixModeString 以 ls -l 的格式显示权限，如 -rwsr-xr-x
+func unixModeString(mode int64) string {
+	const rwx = "rwxrwxrwx"
+	buf := []byte("-")
+	for i := 0; i < 9; i++ {
+		if mode&(1<<uint(8-i)) != 0 {
+			buf = append(buf, rwx[i])
+		} else {
+			buf = append(buf, '-')
+		}
+	}
+	special := func(pos int, bit int64, set, unset byte) {
+		if mode&bit == 0 {
+			return
+		}
+		if buf[pos] == '-' {
+			buf[pos] = unset
+		} else {
+			buf[pos] = set
+		}
+	}
+	special(3, 04000, 's', 'S')
+	special(6, 02000, 's', 'S')
+	special(9, 01000, 't', 'T')
+	return string(buf)
+}
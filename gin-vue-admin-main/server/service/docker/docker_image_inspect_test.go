@@ -0,0 +1,262 @@
+package docker
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	dockerModel "github.com/flipped-aurora/gin-vue-admin/server/model/docker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testLayerEntry struct {
+	name     string
+	mode     int64
+	content  string
+	typeflag byte
+}
+
+func writeTestLayer(t *testing.T, path string, entries []testLayerEntry) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, e := range entries {
+		typeflag := e.typeflag
+		if typeflag == 0 {
+			typeflag = tar.TypeReg
+		}
+		mode := e.mode
+		if mode == 0 {
+			mode = 0o644
+		}
+		hdr := &tar.Header{Name: e.name, Mode: mode, Typeflag: typeflag}
+		if typeflag == tar.TypeReg {
+			hdr.Size = int64(len(e.content))
+		}
+		require.NoError(t, tw.WriteHeader(hdr))
+		if typeflag == tar.TypeReg {
+			_, err := tw.Write([]byte(e.content))
+			require.NoError(t, err)
+		}
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0o644))
+}
+
+func TestCompareVersions(t *testing.T) {
+	cases := []struct {
+		ecosystem string
+		a, b      string
+		want      int
+	}{
+		{dockerModel.PackageTypeDeb, "1.2.3-1", "1.2.3-2", -1},
+		{dockerModel.PackageTypeDeb, "1:1.0-1", "2.0-1", 1},
+		{dockerModel.PackageTypeDeb, "1.0~rc1-1", "1.0-1", -1},
+		{dockerModel.PackageTypeDeb, "3.0.11-1~deb12u2", "3.0.11-1~deb12u1", 1},
+		{dockerModel.PackageTypeDeb, "2.36-9+deb12u4", "2.36-9+deb12u4", 0},
+		{dockerModel.PackageTypeRpm, "1.0-1.el9", "1.0-2.el9", -1},
+		{dockerModel.PackageTypeRpm, "1:3.0.7-16.el9", "3.0.7-27.el9", 1},
+		{dockerModel.PackageTypeRpm, "1.0~beta", "1.0", -1},
+		{dockerModel.PackageTypeRpm, "1.0a", "1.0.1", -1},
+		{dockerModel.PackageTypeApk, "3.1.4-r0", "3.1.4-r1", -1},
+		{dockerModel.PackageTypeApk, "1.2.3_rc1-r0", "1.2.3-r0", -1},
+		{dockerModel.PackageTypeNpm, "4.17.21", "4.17.3", 1},
+		{dockerModel.PackageTypeNpm, "1.0.0-beta.2", "1.0.0", -1},
+		{dockerModel.PackageTypePypi, "2.0.0rc1", "2.0.0", -1},
+		{dockerModel.PackageTypePypi, "1.0.post1", "1.0", 1},
+		{dockerModel.PackageTypeGo, "v0.17.0", "v0.9.1", 1},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.want, compareVersions(c.ecosystem, c.a, c.b), "%s %s vs %s", c.ecosystem, c.a, c.b)
+		assert.Equal(t, -c.want, compareVersions(c.ecosystem, c.b, c.a), "%s %s vs %s", c.ecosystem, c.b, c.a)
+	}
+}
+
+func TestParseSystemPackages(t *testing.T) {
+	dpkg := parseDpkgStatus([]byte("Package: libssl3\nStatus: install ok installed\nSource: openssl (3.0.11-1)\nVersion: 3.0.11-1~deb12u2\nDescription: x\n continued\n\n" +
+		"Package: removed\nStatus: deinstall ok config-files\nVersion: 1.0\n"))
+	require.Len(t, dpkg, 1)
+	assert.Equal(t, "libssl3", dpkg[0].Name)
+	assert.Equal(t, "openssl", dpkg[0].Source)
+	assert.Equal(t, "3.0.11-1~deb12u2", dpkg[0].Version)
+
+	apk := parseApkInstalled([]byte("C:Q1abc\nP:libcrypto3\nV:3.1.4-r0\no:openssl\n\nP:musl\nV:1.2.4-r2\n"))
+	require.Len(t, apk, 2)
+	assert.Equal(t, "openssl", apk[0].Source)
+	assert.Equal(t, "1.2.4-r2", apk[1].Version)
+
+	assert.Equal(t, "openssl", rpmSourceName("openssl-3.0.7-16.el9.src.rpm"))
+	assert.Equal(t, "python3-libs", rpmSourceName("python3-libs-3.9.16-1.el9.src.rpm"))
+}
+
+func TestParseRpmHeader(t *testing.T) {
+	var store bytes.Buffer
+	type entry struct{ tag, typ, offset uint32 }
+	var entries []entry
+	addString := func(tag uint32, value string) {
+		entries = append(entries, entry{tag, rpmTypeString, uint32(store.Len())})
+		store.WriteString(value)
+		store.WriteByte(0)
+	}
+	addString(rpmTagName, "openssl-libs")
+	addString(rpmTagVersion, "3.0.7")
+	addString(rpmTagRelease, "16.el9")
+	addString(rpmTagSourceRPM, "openssl-3.0.7-16.el9.src.rpm")
+	for store.Len()%4 != 0 {
+		store.WriteByte(0)
+	}
+	entries = append(entries, entry{rpmTagEpoch, rpmTypeInt32, uint32(store.Len())})
+	store.Write([]byte{0, 0, 0, 1})
+
+	var blob bytes.Buffer
+	write := func(v uint32) { blob.Write([]byte{byte(v >> 24), byte(v >> 16), byte(v >> 8), byte(v)}) }
+	write(uint32(len(entries)))
+	write(uint32(store.Len()))
+	for _, e := range entries {
+		write(e.tag)
+		write(e.typ)
+		write(e.offset)
+		write(1)
+	}
+	blob.Write(store.Bytes())
+
+	pkg, err := parseRpmHeader(blob.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, "openssl-libs", pkg.Name)
+	assert.Equal(t, "1:3.0.7-16.el9", pkg.Version)
+	assert.Equal(t, "openssl", pkg.Source)
+}
+
+func TestParseLockfiles(t *testing.T) {
+	npm, err := parsePackageLock([]byte(`{"lockfileVersion":3,"packages":{"":{"name":"app"},"node_modules/lodash":{"version":"4.17.20"},"node_modules/a/node_modules/@scope/b":{"version":"1.0.0"},"node_modules/local":{"link":true}}}`))
+	require.NoError(t, err)
+	sortPackages(npm)
+	require.Len(t, npm, 2)
+	assert.Equal(t, "@scope/b", npm[0].Name)
+	assert.Equal(t, "lodash", npm[1].Name)
+
+	yarn, err := parseYarnLock([]byte("# yarn lockfile v1\n\n\"@babel/core@^7.0.0\", \"@babel/core@^7.1.0\":\n  version \"7.23.0\"\n  dependencies:\n    version \"9.9.9\"\n\nlodash@^4.17.0:\n  version \"4.17.21\"\n"))
+	require.NoError(t, err)
+	require.Len(t, yarn, 2)
+	assert.Equal(t, "@babel/core", yarn[0].Name)
+	assert.Equal(t, "7.23.0", yarn[0].Version)
+
+	reqs, err := parseRequirements([]byte("# comment\nDjango==3.2.1\nrequests[socks] == 2.25.0 ; python_version>'3'\nflask>=2.0\n-r other.txt\n"))
+	require.NoError(t, err)
+	require.Len(t, reqs, 2)
+	assert.Equal(t, "requests", reqs[1].Name)
+	assert.Equal(t, "2.25.0", reqs[1].Version)
+
+	cargo, err := parseTomlPackages([]byte("version = 3\n\n[[package]]\nname = \"serde\"\nversion = \"1.0.190\"\n\n[package.metadata]\nname = \"x\"\n\n[[package]]\nname = \"libc\"\nversion = \"0.2.149\"\n"))
+	require.NoError(t, err)
+	require.Len(t, cargo, 2)
+	assert.Equal(t, "libc", cargo[1].Name)
+
+	gems, err := parseGemfileLock([]byte("GEM\n  remote: https://rubygems.org/\n  specs:\n    rack (2.2.8)\n      base64 (>= 0)\n    nokogiri (1.15.4-x86_64-linux)\n\nPLATFORMS\n  x86_64-linux\n"))
+	require.NoError(t, err)
+	require.Len(t, gems, 2)
+	assert.Equal(t, "rack", gems[0].Name)
+
+	gomod, err := parseGoMod([]byte("module example.com/app\n\ngo 1.21\n\nrequire golang.org/x/net v0.17.0\n\nrequire (\n\tgithub.com/gin-gonic/gin v1.9.1\n\tgolang.org/x/text v0.13.0 // indirect\n)\n\nreplace golang.org/x/net => ../net\n"))
+	require.NoError(t, err)
+	require.Len(t, gomod, 3)
+	assert.Equal(t, "golang.org/x/text", gomod[2].Name)
+}
+
+func TestScanImageLayers(t *testing.T) {
+	dir := t.TempDir()
+	layer1 := filepath.Join(dir, "layer1.tar")
+	layer2 := filepath.Join(dir, "layer2.tar")
+	writeTestLayer(t, layer1, []testLayerEntry{
+		{name: "etc/", typeflag: tar.TypeDir, mode: 0o755},
+		{name: "etc/os-release", content: "ID=debian\nVERSION_ID=\"12\"\nPRETTY_NAME=\"Debian GNU/Linux 12 (bookworm)\"\n"},
+		{name: "var/lib/dpkg/status", content: "Package: old\nStatus: install ok installed\nVersion: 1.0\n"},
+		{name: "usr/bin/passwd", mode: 04755, content: "x"},
+		{name: "usr/bin/su", mode: 04755, content: "x"},
+		{name: "usr/bin/wall", mode: 02755, content: "x"},
+		{name: "app/package-lock.json", content: `{"packages":{"node_modules/lodash":{"version":"4.17.20"}}}`},
+	})
+	writeTestLayer(t, layer2, []testLayerEntry{
+		{name: "./var/lib/dpkg/status", content: "Package: libssl3\nStatus: install ok installed\nSource: openssl\nVersion: 3.0.11-1\n"},
+		{name: "usr/bin/.wh.su", content: ""},
+		{name: "usr/bin/wall", mode: 0o755, content: "y"},
+		{name: "app/.wh..wh..opq", content: ""},
+		{name: "app/node_modules/x/package-lock.json", content: `{}`},
+	})
+
+	inventory, err := scanImageLayers([]string{layer1, layer2}, dir)
+	require.NoError(t, err)
+
+	assert.Equal(t, "debian", inventory.OSID)
+	assert.Equal(t, "12", inventory.OSVersion)
+	require.Len(t, inventory.Packages, 1)
+	assert.Equal(t, "libssl3", inventory.Packages[0].Name)
+	assert.Equal(t, "/var/lib/dpkg/status", inventory.Packages[0].Path)
+
+	require.Len(t, inventory.Files, 1)
+	assert.Equal(t, "/usr/bin/passwd", inventory.Files[0].Path)
+	assert.Equal(t, "-rwsr-xr-x", inventory.Files[0].Mode)
+}
+
+func TestSplitImageArchive(t *testing.T) {
+	dir := t.TempDir()
+	manifest, _ := json.Marshal([]map[string]interface{}{{"Layers": []string{"abc/layer.tar", "blobs/sha256/def"}}})
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, e := range []struct{ name, content string }{
+		{"blobs/sha256/def", "second"},
+		{"blobs/sha256/abc", "first"},
+		{"manifest.json", string(manifest)},
+	} {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: e.name, Mode: 0o644, Size: int64(len(e.content)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write([]byte(e.content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "abc/layer.tar", Linkname: "../blobs/sha256/abc", Typeflag: tar.TypeSymlink}))
+	require.NoError(t, tw.Close())
+
+	layers, err := splitImageArchive(&buf, dir)
+	require.NoError(t, err)
+	require.Len(t, layers, 2)
+	first, _ := os.ReadFile(layers[0])
+	second, _ := os.ReadFile(layers[1])
+	assert.Equal(t, "first", string(first))
+	assert.Equal(t, "second", string(second))
+}
+
+func TestOSVToVulnerabilities(t *testing.T) {
+	var record osvRecord
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"id": "DSA-1-1",
+		"summary": "openssl security update",
+		"severity": [{"type": "CVSS_V3", "score": "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"}],
+		"affected": [
+			{"package": {"ecosystem": "Debian:12", "name": "openssl"},
+			 "ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "0"}, {"fixed": "3.0.11-1~deb12u2"}]}]},
+			{"package": {"ecosystem": "Unknown", "name": "x"}, "versions": ["1"]}
+		]
+	}`), &record))
+
+	entries := osvToVulnerabilities(record)
+	require.Len(t, entries, 1)
+	vuln := entries[0]
+	assert.Equal(t, dockerModel.PackageTypeDeb, vuln.Ecosystem)
+	assert.Equal(t, "debian:12", vuln.Distro)
+	assert.Equal(t, "", vuln.Introduced)
+	assert.Equal(t, dockerModel.SeverityCritical, vuln.Severity)
+
+	assert.True(t, vulnerabilityAffects(vuln, "3.0.9-1~deb12u1"))
+	assert.False(t, vulnerabilityAffects(vuln, "3.0.11-1~deb12u2"))
+	assert.True(t, distroMatches(vuln.Distro, "debian", "12"))
+	assert.False(t, distroMatches(vuln.Distro, "debian", "11"))
+	assert.False(t, distroMatches(vuln.Distro, "ubuntu", "12"))
+	assert.True(t, distroMatches("alpine:3.18", "alpine", "3.18.4"))
+
+	score, ok := cvss3BaseScore("CVSS:3.1/AV:N/AC:L/PR:L/UI:N/S:C/C:L/I:L/A:N")
+	require.True(t, ok)
+	assert.Equal(t, 6.4, score)
+}
@@ -0,0 +1,486 @@
+package docker
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/client"
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	dockerModel "github.com/flipped-aurora/gin-vue-admin/server/model/docker"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/docker/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/docker/response"
+	"go.uber.org/zap"
+)
+
+const (
+	imageJobTimeout        = time.Hour              // 单个任务最长执行时间
+	imageJobConcurrency    = 2                      // 同时执行的任务数，其余排队
+	imageJobLogLimit       = 60 * 1024              // 保存的日志上限，超出时保留末尾部分
+	imageJobRetention      = 30 * 24 * time.Hour    // 已结束任务的保留时间
+	imageJobStreamInterval = 500 * time.Millisecond // 实时进度推送间隔
+)
+
+// imageJobLogTruncated 日志被截断时的提示
+const imageJobLogTruncated = "...（日志过长，已省略前面部分）\n"
+
+// imageJobRunner 执行中任务的内存状态
+type imageJobRunner struct {
+	mu        sync.Mutex
+	job       dockerModel.DockerImageJob
+	cli       *client.Client
+	tracker   *imageProgressTracker
+	log       []byte
+	truncated bool
+	version   uint64
+	cancelled bool
+	cancel    context.CancelFunc
+	done      chan struct{}
+}
+
+// imageJobBootTime 服务启动时间，早于此时间提交且未结束的任务已随重启中断
+var imageJobBootTime = time.Now()
+
+// imageJobs 执行中任务，按任务ID索引
+var imageJobs = struct {
+	sync.Mutex
+	runners map[uint]*imageJobRunner
+	slots   chan struct{}
+}{
+	runners: make(map[uint]*imageJobRunner),
+	slots:   make(chan struct{}, imageJobConcurrency),
+}
+
+type DockerImageJobService struct {
+	hostClient
+}
+
+// SubmitPullJob 提交后台拉取镜像任务
+func (s *DockerImageJobService) SubmitPullJob(pullReq request.ImagePullRequest, userID uint) (*dockerModel.DockerImageJob, error) {
+	if s.cli() == nil {
+		return nil, fmt.Errorf("Docker client is not available")
+	}
+	imageName := pullImageName(pullReq)
+	return submitImageJob(s.hostID(), s.cli(), dockerModel.ImageJobTypePull, imageName, userID, func(ctx context.Context) (io.ReadCloser, error) {
+		return startImagePull(ctx, s.streamCli(), imageName)
+	})
+}
+
+// SubmitBuildJob 提交后台构建镜像任务
+func (s *DockerImageJobService) SubmitBuildJob(buildReq request.ImageBuildRequest, userID uint) (*dockerModel.DockerImageJob, error) {
+	if s.cli() == nil {
+		return nil, fmt.Errorf("Docker client is not available")
+	}
+	imageName := buildImageName(buildReq)
+	return submitImageJob(s.hostID(), s.cli(), dockerModel.ImageJobTypeBuild, imageName, userID, func(ctx context.Context) (io.ReadCloser, error) {
+		return startImageBuild(ctx, s.streamCli(), buildReq, imageName)
+	})
+}
+
+// GetImageJobList 分页获取镜像任务列表，不返回日志
+func (s *DockerImageJobService) GetImageJobList(filter request.ImageJobFilter) ([]dockerModel.DockerImageJob, int64, error) {
+	db := global.GVA_DB.Model(&dockerModel.DockerImageJob{})
+	if filter.Type != "" {
+		db = db.Where("type = ?", filter.Type)
+	}
+	if filter.Status != "" {
+		db = db.Where("status = ?", filter.Status)
+	}
+	if filter.Image != "" {
+		db = db.Where("image LIKE ?", "%"+filter.Image+"%")
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var jobs []dockerModel.DockerImageJob
+	limit := filter.PageSize
+	offset := filter.PageSize * (filter.Page - 1)
+	if limit > 0 {
+		db = db.Limit(limit).Offset(offset)
+	}
+	if err := db.Omit("log").Order("id desc").Find(&jobs).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// 执行中的任务以内存中的进度为准
+	for i := range jobs {
+		if runner := getImageJobRunner(jobs[i].ID); runner != nil {
+			progress := runner.snapshot()
+			jobs[i].Status = progress.Status
+			jobs[i].Progress = progress.Progress
+			jobs[i].Message = progress.Message
+			jobs[i].StartedAt = progress.StartedAt
+		}
+	}
+	return jobs, total, nil
+}
+
+// GetImageJob 获取任务进度
+func (s *DockerImageJobService) GetImageJob(id uint) (*response.ImageJobProgress, error) {
+	if runner := getImageJobRunner(id); runner != nil {
+		progress := runner.snapshot()
+		return &progress, nil
+	}
+	job, err := getImageJob(id)
+	if err != nil {
+		return nil, err
+	}
+	progress := imageJobProgress(*job)
+	return &progress, nil
+}
+
+// GetImageJobLog 获取任务日志，执行中的任务返回当前已产生的日志
+func (s *DockerImageJobService) GetImageJobLog(id uint) (string, error) {
+	if runner := getImageJobRunner(id); runner != nil {
+		return runner.logText(), nil
+	}
+	job, err := getImageJob(id)
+	if err != nil {
+		return "", err
+	}
+	return job.Log, nil
+}
+
+// StreamImageJob 持续推送任务进度直到任务结束，已结束的任务只推送一次最终状态
+func (s *DockerImageJobService) StreamImageJob(ctx context.Context, id uint, emit func(progress response.ImageJobProgress) error) error {
+	runner := getImageJobRunner(id)
+	if runner == nil {
+		job, err := getImageJob(id)
+		if err != nil {
+			return err
+		}
+		return emit(imageJobProgress(*job))
+	}
+
+	ticker := time.NewTicker(imageJobStreamInterval)
+	defer ticker.Stop()
+
+	var lastVersion uint64
+	for {
+		progress, version := runner.snapshotVersion()
+		if version != lastVersion {
+			if err := emit(progress); err != nil {
+				return err
+			}
+			lastVersion = version
+		}
+
+		select {
+		case <-runner.done:
+			progress, version = runner.snapshotVersion()
+			if version != lastVersion {
+				return emit(progress)
+			}
+			return nil
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// CancelImageJob 取消排队中或执行中的任务
+func (s *DockerImageJobService) CancelImageJob(id uint) error {
+	runner := getImageJobRunner(id)
+	if runner == nil {
+		if _, err := getImageJob(id); err != nil {
+			return err
+		}
+		return fmt.Errorf("image job is not running")
+	}
+
+	runner.mu.Lock()
+	runner.cancelled = true
+	runner.mu.Unlock()
+	runner.cancel()
+	return nil
+}
+
+// DeleteImageJob 删除已结束的任务
+func (s *DockerImageJobService) DeleteImageJob(id uint) error {
+	if getImageJobRunner(id) != nil {
+		return fmt.Errorf("image job is still running")
+	}
+	if _, err := getImageJob(id); err != nil {
+		return err
+	}
+	return global.GVA_DB.Delete(&dockerModel.DockerImageJob{}, id).Error
+}
+
+// Cleanup 将服务重启前未结束的任务标记为失败，删除超过保留时间的任务与构建上下文
+func (s *DockerImageJobService) Cleanup() error {
+	if global.GVA_DB == nil {
+		return nil
+	}
+
+	now := time.Now()
+	err := global.GVA_DB.Model(&dockerModel.DockerImageJob{}).
+		Where("status IN ?", []string{dockerModel.ImageJobStatusPending, dockerModel.ImageJobStatusRunning}).
+		Where("created_at < ?", imageJobBootTime).
+		Updates(map[string]interface{}{
+			"status":      dockerModel.ImageJobStatusFailed,
+			"error":       "job interrupted by server restart",
+			"finished_at": now,
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to mark interrupted image jobs: %v", err)
+	}
+
+	err = global.GVA_DB.Where("finished_at < ?", now.Add(-imageJobRetention)).Delete(&dockerModel.DockerImageJob{}).Error
+	if err != nil {
+		return fmt.Errorf("failed to clean up image jobs: %v", err)
+	}
+
+	if err := cleanupBuildWorkDir(); err != nil {
+		return fmt.Errorf("failed to clean up build directory: %v", err)
+	}
+	return nil
+}
+
+// submitImageJob 保存任务记录并在后台执行，cli 为任务所在主机的客户端
+func submitImageJob(hostID uint, cli *client.Client, jobType, imageName string, userID uint, start func(ctx context.Context) (io.ReadCloser, error)) (*dockerModel.DockerImageJob, error) {
+	job := dockerModel.DockerImageJob{
+		Type:      jobType,
+		Image:     imageName,
+		Status:    dockerModel.ImageJobStatusPending,
+		Message:   "waiting",
+		HostID:    hostID,
+		CreatedBy: userID,
+	}
+	if err := global.GVA_DB.Create(&job).Error; err != nil {
+		return nil, fmt.Errorf("failed to create image job: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), imageJobTimeout)
+	runner := &imageJobRunner{
+		job:     job,
+		cli:     cli,
+		tracker: newImageProgressTracker(jobType),
+		version: 1,
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+	imageJobs.Lock()
+	imageJobs.runners[job.ID] = runner
+	imageJobs.Unlock()
+
+	go runner.run(ctx, start)
+	return &job, nil
+}
+
+// run 排队等待执行名额，执行任务并保存结果
+func (r *imageJobRunner) run(ctx context.Context, start func(ctx context.Context) (io.ReadCloser, error)) {
+	defer r.cancel()
+
+	select {
+	case imageJobs.slots <- struct{}{}:
+		defer func() { <-imageJobs.slots }()
+	case <-ctx.Done():
+		r.finish(ctx, ctx.Err())
+		return
+	}
+
+	now := time.Now()
+	r.update(func() {
+		r.job.Status = dockerModel.ImageJobStatusRunning
+		r.job.Message = "started"
+		r.job.StartedAt = &now
+	})
+	err := global.GVA_DB.Model(&dockerModel.DockerImageJob{}).Where("id = ?", r.job.ID).Updates(map[string]interface{}{
+		"status":     dockerModel.ImageJobStatusRunning,
+		"started_at": now,
+	}).Error
+	if err != nil {
+		global.GVA_LOG.Error("Failed to update image job", zap.Uint("id", r.job.ID), zap.Error(err))
+	}
+
+	r.finish(ctx, r.consume(ctx, start))
+}
+
+// consume 读取消息流并更新进度
+func (r *imageJobRunner) consume(ctx context.Context, start func(ctx context.Context) (io.ReadCloser, error)) error {
+	reader, err := start(ctx)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var handleErr error
+		r.update(func() {
+			var line string
+			line, handleErr = r.tracker.handle(scanner.Bytes())
+			if line != "" {
+				r.appendLog(line)
+			}
+		})
+		if handleErr != nil {
+			return handleErr
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	// 未从消息中获得镜像ID时查询一次
+	if r.tracker.imageID == "" {
+		if inspect, _, err := r.cli.ImageInspectWithRaw(ctx, r.job.Image); err == nil {
+			r.update(func() { r.tracker.imageID = inspect.ID })
+		}
+	}
+	return nil
+}
+
+// finish 记录任务结果并移出执行列表
+func (r *imageJobRunner) finish(ctx context.Context, err error) {
+	now := time.Now()
+	r.update(func() {
+		r.job.FinishedAt = &now
+		r.job.ImageID = r.tracker.imageID
+		switch {
+		case err == nil:
+			r.job.Status = dockerModel.ImageJobStatusSuccess
+			r.job.Progress = 100
+			r.job.Message = "completed"
+		case r.cancelled:
+			r.job.Status = dockerModel.ImageJobStatusCancelled
+			r.job.Message = "cancelled"
+		case errors.Is(ctx.Err(), context.DeadlineExceeded):
+			r.job.Status = dockerModel.ImageJobStatusFailed
+			r.job.Error = "job timed out"
+		default:
+			r.job.Status = dockerModel.ImageJobStatusFailed
+			r.job.Error = err.Error()
+		}
+		if r.job.Error != "" {
+			r.appendLog(r.job.Error)
+		}
+	})
+
+	r.mu.Lock()
+	job := r.job
+	job.Log = r.logTextLocked()
+	r.mu.Unlock()
+
+	if err := global.GVA_DB.Save(&job).Error; err != nil {
+		global.GVA_LOG.Error("Failed to save image job", zap.Uint("id", job.ID), zap.Error(err))
+	}
+	if job.Status == dockerModel.ImageJobStatusSuccess {
+		global.GVA_LOG.Info("Image job completed", zap.Uint("id", job.ID), zap.String("type", job.Type), zap.String("image", job.Image))
+		// 镜像库存只跟踪默认主机
+		if job.Type == dockerModel.ImageJobTypePull && job.HostID == 0 {
+			recordImagePull(job.Image, job.CreatedBy)
+		}
+	} else {
+		global.GVA_LOG.Warn("Image job not completed", zap.Uint("id", job.ID), zap.String("status", job.Status), zap.String("error", job.Error))
+	}
+
+	imageJobs.Lock()
+	delete(imageJobs.runners, job.ID)
+	imageJobs.Unlock()
+	close(r.done)
+}
+
+// update 在锁内修改状态并增加版本号，进度取自解析结果
+func (r *imageJobRunner) update(fn func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	fn()
+	if r.job.Status == dockerModel.ImageJobStatusRunning {
+		r.job.Progress = r.tracker.progress()
+		if r.tracker.message != "" {
+			r.job.Message = r.tracker.message
+		}
+	}
+	r.version++
+}
+
+// appendLog 追加一行日志，超出上限时丢弃最早的部分，调用方需持有锁
+func (r *imageJobRunner) appendLog(line string) {
+	r.log = append(r.log, line...)
+	r.log = append(r.log, '\n')
+	if len(r.log) <= imageJobLogLimit {
+		return
+	}
+	cut := len(r.log) - imageJobLogLimit
+	for cut < len(r.log) && r.log[cut-1] != '\n' {
+		cut++
+	}
+	r.log = append(r.log[:0], r.log[cut:]...)
+	r.truncated = true
+}
+
+// logText 当前日志
+func (r *imageJobRunner) logText() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.logTextLocked()
+}
+
+func (r *imageJobRunner) logTextLocked() string {
+	if r.truncated {
+		return imageJobLogTruncated + string(r.log)
+	}
+	return string(r.log)
+}
+
+// snapshot 当前进度
+func (r *imageJobRunner) snapshot() response.ImageJobProgress {
+	progress, _ := r.snapshotVersion()
+	return progress
+}
+
+func (r *imageJobRunner) snapshotVersion() (response.ImageJobProgress, uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	progress := imageJobProgress(r.job)
+	progress.ImageID = r.tracker.imageID
+	progress.Layers = r.tracker.layerList()
+	progress.Step = r.tracker.step
+	progress.TotalSteps = r.tracker.totalSteps
+	return progress, r.version
+}
+
+// getImageJobRunner 获取执行中的任务
+func getImageJobRunner(id uint) *imageJobRunner {
+	imageJobs.Lock()
+	defer imageJobs.Unlock()
+	return imageJobs.runners[id]
+}
+
+// getImageJob 从数据库获取任务
+func getImageJob(id uint) (*dockerModel.DockerImageJob, error) {
+	var job dockerModel.DockerImageJob
+	if err := global.GVA_DB.Where("id = ?", id).Limit(1).Find(&job).Error; err != nil {
+		return nil, err
+	}
+	if job.ID == 0 {
+		return nil, fmt.Errorf("image job not found")
+	}
+	return &job, nil
+}
+
+// imageJobProgress 将任务记录转换为进度响应
+func imageJobProgress(job dockerModel.DockerImageJob) response.ImageJobProgress {
+	return response.ImageJobProgress{
+		ID:         job.ID,
+		Type:       job.Type,
+		Image:      job.Image,
+		Status:     job.Status,
+		Progress:   job.Progress,
+		Message:    job.Message,
+		Error:      job.Error,
+		ImageID:    job.ImageID,
+		StartedAt:  job.StartedAt,
+		FinishedAt: job.FinishedAt,
+	}
+}
@@ -0,0 +1,556 @@
+package docker
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	dockerModel "github.com/flipped-aurora/gin-vue-admin/server/model/docker"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// 软件包数据库在镜像中的路径
+const (
+	dpkgStatusPath    = "var/lib/dpkg/status"
+	dpkgStatusDir     = "var/lib/dpkg/status.d/"
+	apkInstalledPath  = "lib/apk/db/installed"
+	osReleasePath     = "etc/os-release"
+	osReleaseFallback = "usr/lib/os-release"
+)
+
+// rpmSqliteDBs rpm 4.16 起默认的 sqlite 数据库
+var rpmSqliteDBs = map[string]bool{
+	"var/lib/rpm/rpmdb.sqlite":          true,
+	"usr/lib/sysimage/rpm/rpmdb.sqlite": true,
+}
+
+// rpmLegacyDBs 旧版 BerkeleyDB 与 SUSE 使用的 NDB 格式，暂不解析，只给出提示
+var rpmLegacyDBs = map[string]string{
+	"var/lib/rpm/Packages":             "BerkeleyDB",
+	"var/lib/rpm/Packages.db":          "NDB",
+	"usr/lib/sysimage/rpm/Packages.db": "NDB",
+}
+
+// lockfileParsers 按文件名识别的语言依赖锁文件
+var lockfileParsers = map[string]struct {
+	Type  string
+	Parse func(data []byte) ([]dockerModel.DockerImageReportPackage, error)
+}{
+	"package-lock.json": {dockerModel.PackageTypeNpm, parsePackageLock},
+	"yarn.lock":         {dockerModel.PackageTypeNpm, parseYarnLock},
+	"requirements.txt":  {dockerModel.PackageTypePypi, parseRequirements},
+	"Pipfile.lock":      {dockerModel.PackageTypePypi, parsePipfileLock},
+	"poetry.lock":       {dockerModel.PackageTypePypi, parseTomlPackages},
+	"Cargo.lock":        {dockerModel.PackageTypeCargo, parseTomlPackages},
+	"Gemfile.lock":      {dockerModel.PackageTypeGem, parseGemfileLock},
+	"composer.lock":     {dockerModel.PackageTypeComposer, parseComposerLock},
+	"go.mod":            {dockerModel.PackageTypeGo, parseGoMod},
+}
+
+// isInventoryFile 是否为需要读取内容的清单文件
+func isInventoryFile(name string) bool {
+	switch {
+	case name == osReleasePath, name == osReleaseFallback, name == dpkgStatusPath, name == apkInstalledPath:
+		return true
+	case strings.HasPrefix(name, dpkgStatusDir) && !strings.HasSuffix(name, ".md5sums"):
+		return true
+	case rpmSqliteDBs[name]:
+		return true
+	}
+	if _, ok := rpmLegacyDBs[name]; ok {
+		return true
+	}
+	return isLockfile(name)
+}
+
+// isLockfile 是否为支持的锁文件，跳过依赖目录与模块缓存中的副本
+func isLockfile(name string) bool {
+	if _, ok := lockfileParsers[path.Base(name)]; !ok {
+		return false
+	}
+	return !strings.Contains(name, "node_modules/") && !strings.Contains(name, "/pkg/mod/") &&
+		!strings.Contains(name, "/vendor/") && !strings.Contains(name, "/site-packages/")
+}
+
+// parseOSRelease 解析 os-release 的 KEY=VALUE 格式
+func parseOSRelease(data []byte) map[string]string {
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		values[key] = strings.Trim(value, `"'`)
+	}
+	return values
+}
+
+// parseDpkgStatus 解析 dpkg status 文件，只保留已安装的包；distroless 的 status.d 文件没有 Status 字段
+func parseDpkgStatus(data []byte) []dockerModel.DockerImageReportPackage {
+	var packages []dockerModel.DockerImageReportPackage
+	for _, paragraph := range splitParagraphs(data) {
+		fields := parseControlFields(paragraph)
+		if fields["Package"] == "" || fields["Version"] == "" {
+			continue
+		}
+		if status, ok := fields["Status"]; ok && !strings.HasSuffix(status, " installed") {
+			continue
+		}
+		pkg := dockerModel.DockerImageReportPackage{
+			Type:    dockerModel.PackageTypeDeb,
+			Name:    fields["Package"],
+			Version: fields["Version"],
+		}
+		// Source 字段可能带版本：openssl (3.0.11-1)
+		if source := fields["Source"]; source != "" {
+			pkg.Source = strings.TrimSpace(strings.SplitN(source, " ", 2)[0])
+		}
+		packages = append(packages, pkg)
+	}
+	return packages
+}
+
+// parseApkInstalled 解析 apk 的 installed 数据库
+func parseApkInstalled(data []byte) []dockerModel.DockerImageReportPackage {
+	var packages []dockerModel.DockerImageReportPackage
+	for _, paragraph := range splitParagraphs(data) {
+		var pkg dockerModel.DockerImageReportPackage
+		for _, line := range strings.Split(paragraph, "\n") {
+			if len(line) < 2 || line[1] != ':' {
+				continue
+			}
+			switch line[0] {
+			case 'P':
+				pkg.Name = line[2:]
+			case 'V':
+				pkg.Version = line[2:]
+			case 'o':
+				pkg.Source = line[2:]
+			}
+		}
+		if pkg.Name != "" && pkg.Version != "" {
+			pkg.Type = dockerModel.PackageTypeApk
+			packages = append(packages, pkg)
+		}
+	}
+	return packages
+}
+
+// splitParagraphs 按空行拆分段落
+func splitParagraphs(data []byte) []string {
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	var paragraphs []string
+	for _, p := range strings.Split(text, "\n\n") {
+		if p = strings.Trim(p, "\n"); p != "" {
+			paragraphs = append(paragraphs, p)
+		}
+	}
+	return paragraphs
+}
+
+// parseControlFields 解析 Debian control 格式的字段，忽略续行
+func parseControlFields(paragraph string) map[string]string {
+	fields := make(map[string]string)
+	for _, line := range strings.Split(paragraph, "\n") {
+		if line == "" || line[0] == ' ' || line[0] == '\t' {
+			continue
+		}
+		if key, value, ok := strings.Cut(line, ":"); ok {
+			fields[key] = strings.TrimSpace(value)
+		}
+	}
+	return fields
+}
+
+// rpm 头部标签与数据类型
+const (
+	rpmTagName      = 1000
+	rpmTagVersion   = 1001
+	rpmTagRelease   = 1002
+	rpmTagEpoch     = 1003
+	rpmTagSourceRPM = 1044
+	rpmTypeInt32    = 4
+	rpmTypeString   = 6
+)
+
+// parseRpmSqlite 读取 rpmdb.sqlite 中的软件包头部
+func parseRpmSqlite(dbPath string) ([]dockerModel.DockerImageReportPackage, error) {
+	db, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		return nil, err
+	}
+	if sqlDB, err := db.DB(); err == nil {
+		defer sqlDB.Close()
+	}
+
+	var blobs [][]byte
+	if err := db.Raw("SELECT blob FROM Packages").Scan(&blobs).Error; err != nil {
+		return nil, err
+	}
+
+	var packages []dockerModel.DockerImageReportPackage
+	for _, blob := range blobs {
+		pkg, err := parseRpmHeader(blob)
+		if err != nil || pkg.Name == "" || pkg.Name == "gpg-pubkey" {
+			continue
+		}
+		packages = append(packages, pkg)
+	}
+	return packages, nil
+}
+
+// parseRpmHeader 解析 rpmdb 中存储的头部数据（不含魔数前导）：
+// 索引条数、数据区长度，随后是 16 字节的索引项（标签、类型、偏移、数量）与数据区
+func parseRpmHeader(blob []byte) (dockerModel.DockerImageReportPackage, error) {
+	var pkg dockerModel.DockerImageReportPackage
+	if len(blob) < 8 {
+		return pkg, fmt.Errorf("rpm header too short")
+	}
+	indexCount := int(binary.BigEndian.Uint32(blob[0:4]))
+	dataLength := int(binary.BigEndian.Uint32(blob[4:8]))
+	dataStart := 8 + indexCount*16
+	if indexCount <= 0 || dataLength < 0 || dataStart+dataLength > len(blob) {
+		return pkg, fmt.Errorf("invalid rpm header")
+	}
+	store := blob[dataStart : dataStart+dataLength]
+
+	var version, release, sourceRPM string
+	epoch := -1
+	for i := 0; i < indexCount; i++ {
+		entry := blob[8+i*16 : 8+(i+1)*16]
+		tag := binary.BigEndian.Uint32(entry[0:4])
+		typ := binary.BigEndian.Uint32(entry[4:8])
+		offset := int(binary.BigEndian.Uint32(entry[8:12]))
+		if offset < 0 || offset >= len(store) {
+			continue
+		}
+		switch {
+		case typ == rpmTypeString:
+			value := store[offset:]
+			if end := bytes.IndexByte(value, 0); end >= 0 {
+				value = value[:end]
+			}
+			switch tag {
+			case rpmTagName:
+				pkg.Name = string(value)
+			case rpmTagVersion:
+				version = string(value)
+			case rpmTagRelease:
+				release = string(value)
+			case rpmTagSourceRPM:
+				sourceRPM = string(value)
+			}
+		case typ == rpmTypeInt32 && tag == rpmTagEpoch && offset+4 <= len(store):
+			epoch = int(binary.BigEndian.Uint32(store[offset : offset+4]))
+		}
+	}
+
+	pkg.Type = dockerModel.PackageTypeRpm
+	pkg.Version = version
+	if release != "" {
+		pkg.Version += "-" + release
+	}
+	if epoch > 0 {
+		pkg.Version = strconv.Itoa(epoch) + ":" + pkg.Version
+	}
+	pkg.Source = rpmSourceName(sourceRPM)
+	return pkg, nil
+}
+
+// rpmSourceName 从源码包文件名中取包名，如 openssl-3.0.7-1.el9.src.rpm -> openssl
+func rpmSourceName(sourceRPM string) string {
+	name := strings.TrimSuffix(sourceRPM, ".src.rpm")
+	for i := 0; i < 2; i++ {
+		idx := strings.LastIndexByte(name, '-')
+		if idx <= 0 {
+			return ""
+		}
+		name = name[:idx]
+	}
+	return name
+}
+
+// parsePackageLock 解析 npm package-lock.json，兼容 v1 的 dependencies 与 v2/v3 的 packages
+func parsePackageLock(data []byte) ([]dockerModel.DockerImageReportPackage, error) {
+	type v1Dependency struct {
+		Version      string                     `json:"version"`
+		Dependencies map[string]json.RawMessage `json:"dependencies"`
+	}
+	var lock struct {
+		Packages map[string]struct {
+			Name    string `json:"name"`
+			Version string `json:"version"`
+			Link    bool   `json:"link"`
+		} `json:"packages"`
+		Dependencies map[string]json.RawMessage `json:"dependencies"`
+	}
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var packages []dockerModel.DockerImageReportPackage
+	add := func(name, version string) {
+		if name == "" || version == "" || seen[name+"@"+version] {
+			return
+		}
+		seen[name+"@"+version] = true
+		packages = append(packages, dockerModel.DockerImageReportPackage{Type: dockerModel.PackageTypeNpm, Name: name, Version: version})
+	}
+
+	if len(lock.Packages) > 0 {
+		for key, pkg := range lock.Packages {
+			// 空键为项目自身
+			if key == "" || pkg.Link {
+				continue
+			}
+			name := pkg.Name
+			if idx := strings.LastIndex(key, "node_modules/"); idx >= 0 {
+				name = key[idx+len("node_modules/"):]
+			}
+			add(name, pkg.Version)
+		}
+		return packages, nil
+	}
+
+	var walk func(deps map[string]json.RawMessage)
+	walk = func(deps map[string]json.RawMessage) {
+		for name, raw := range deps {
+			var dep v1Dependency
+			if json.Unmarshal(raw, &dep) != nil {
+				continue
+			}
+			add(name, dep.Version)
+			walk(dep.Dependencies)
+		}
+	}
+	walk(lock.Dependencies)
+	return packages, nil
+}
+
+// parseYarnLock 解析 yarn.lock，兼容 v1 与 berry 格式
+func parseYarnLock(data []byte) ([]dockerModel.DockerImageReportPackage, error) {
+	var packages []dockerModel.DockerImageReportPackage
+	seen := make(map[string]bool)
+	var name string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if line[0] != ' ' {
+			// 条目头：第一个说明符即可确定包名，如 "@babel/core@^7.0.0", "@babel/core@^7.1.0":
+			spec := strings.TrimSuffix(strings.TrimSpace(line), ":")
+			spec = strings.Trim(strings.SplitN(spec, ",", 2)[0], `"`)
+			name = ""
+			if idx := strings.LastIndex(spec, "@"); idx > 0 {
+				name = spec[:idx]
+			}
+			continue
+		}
+		trimmed := strings.TrimSpace(line)
+		if name == "" || !strings.HasPrefix(trimmed, "version") || strings.HasPrefix(line, "    ") {
+			continue
+		}
+		version := strings.TrimSpace(strings.TrimPrefix(trimmed, "version"))
+		version = strings.Trim(strings.TrimPrefix(version, ":"), ` "`)
+		if version != "" && !seen[name+"@"+version] {
+			seen[name+"@"+version] = true
+			packages = append(packages, dockerModel.DockerImageReportPackage{Type: dockerModel.PackageTypeNpm, Name: name, Version: version})
+		}
+		name = ""
+	}
+	return packages, scanner.Err()
+}
+
+var requirementPattern = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9._-]*)(\[[^\]]*\])?\s*===?\s*([^\s;#,]+)`)
+
+// parseRequirements 解析 requirements.txt 中固定版本（==）的依赖
+func parseRequirements(data []byte) ([]dockerModel.DockerImageReportPackage, error) {
+	var packages []dockerModel.DockerImageReportPackage
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		match := requirementPattern.FindStringSubmatch(strings.TrimSpace(scanner.Text()))
+		if match == nil {
+			continue
+		}
+		packages = append(packages, dockerModel.DockerImageReportPackage{Type: dockerModel.PackageTypePypi, Name: match[1], Version: match[3]})
+	}
+	return packages, scanner.Err()
+}
+
+// parsePipfileLock 解析 Pipfile.lock 的 default 与 develop 依赖
+func parsePipfileLock(data []byte) ([]dockerModel.DockerImageReportPackage, error) {
+	var lock map[string]json.RawMessage
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, err
+	}
+	var packages []dockerModel.DockerImageReportPackage
+	for _, section := range []string{"default", "develop"} {
+		var deps map[string]struct {
+			Version string `json:"version"`
+		}
+		if raw, ok := lock[section]; !ok || json.Unmarshal(raw, &deps) != nil {
+			continue
+		}
+		for name, dep := range deps {
+			version := strings.TrimPrefix(dep.Version, "==")
+			if version == "" {
+				continue
+			}
+			packages = append(packages, dockerModel.DockerImageReportPackage{Type: dockerModel.PackageTypePypi, Name: name, Version: version})
+		}
+	}
+	sortPackages(packages)
+	return packages, nil
+}
+
+// parseTomlPackages 解析 poetry.lock 与 Cargo.lock 中 [[package]] 表的 name/version
+func parseTomlPackages(data []byte) ([]dockerModel.DockerImageReportPackage, error) {
+	var packages []dockerModel.DockerImageReportPackage
+	var name, version string
+	inPackage := false
+	flush := func() {
+		if inPackage && name != "" && version != "" {
+			packages = append(packages, dockerModel.DockerImageReportPackage{Name: name, Version: version})
+		}
+		name, version = "", ""
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			flush()
+			inPackage = line == "[[package]]"
+			continue
+		}
+		if !inPackage {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		switch strings.TrimSpace(key) {
+		case "name":
+			name = strings.Trim(strings.TrimSpace(value), `"`)
+		case "version":
+			version = strings.Trim(strings.TrimSpace(value), `"`)
+		}
+	}
+	flush()
+	return packages, scanner.Err()
+}
+
+var gemSpecPattern = regexp.MustCompile(`^    ([^ ()]+) \(([^)]+)\)$`)
+
+// parseGemfileLock 解析 Gemfile.lock 中 GEM 段的 specs
+func parseGemfileLock(data []byte) ([]dockerModel.DockerImageReportPackage, error) {
+	var packages []dockerModel.DockerImageReportPackage
+	inGem := false
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line != "" && line[0] != ' ' {
+			inGem = line == "GEM"
+			continue
+		}
+		if !inGem {
+			continue
+		}
+		if match := gemSpecPattern.FindStringSubmatch(line); match != nil {
+			packages = append(packages, dockerModel.DockerImageReportPackage{Type: dockerModel.PackageTypeGem, Name: match[1], Version: match[2]})
+		}
+	}
+	return packages, scanner.Err()
+}
+
+// parseComposerLock 解析 composer.lock 的 packages 与 packages-dev
+func parseComposerLock(data []byte) ([]dockerModel.DockerImageReportPackage, error) {
+	var lock struct {
+		Packages []struct {
+			Name    string `json:"name"`
+			Version string `json:"version"`
+		} `json:"packages"`
+		PackagesDev []struct {
+			Name    string `json:"name"`
+			Version string `json:"version"`
+		} `json:"packages-dev"`
+	}
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, err
+	}
+	var packages []dockerModel.DockerImageReportPackage
+	for _, pkg := range append(lock.Packages, lock.PackagesDev...) {
+		if pkg.Name != "" && pkg.Version != "" {
+			packages = append(packages, dockerModel.DockerImageReportPackage{Type: dockerModel.PackageTypeComposer, Name: pkg.Name, Version: pkg.Version})
+		}
+	}
+	return packages, nil
+}
+
+// parseGoMod 解析 go.mod 中的 require 依赖
+func parseGoMod(data []byte) ([]dockerModel.DockerImageReportPackage, error) {
+	var packages []dockerModel.DockerImageReportPackage
+	inRequire := false
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if idx := strings.Index(line, "//"); idx >= 0 {
+			line = line[:idx]
+		}
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 0:
+			continue
+		case inRequire && fields[0] == ")":
+			inRequire = false
+			continue
+		case fields[0] == "require" && len(fields) == 2 && fields[1] == "(":
+			inRequire = true
+			continue
+		case fields[0] == "require" && len(fields) >= 3:
+			fields = fields[1:]
+		case !inRequire:
+			continue
+		}
+		if len(fields) >= 2 {
+			packages = append(packages, dockerModel.DockerImageReportPackage{Type: dockerModel.PackageTypeGo, Name: fields[0], Version: fields[1]})
+		}
+	}
+	return packages, scanner.Err()
+}
+
+// sortPackages 按类型、名称、版本排序，保证报告稳定
+func sortPackages(packages []dockerModel.DockerImageReportPackage) {
+	sort.SliceStable(packages, func(i, j int) bool {
+		a, b := packages[i], packages[j]
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		if a.Version != b.Version {
+			return a.Version < b.Version
+		}
+		return a.Path < b.Path
+	})
+}
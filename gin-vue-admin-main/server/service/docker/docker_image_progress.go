@@ -0,0 +1,197 @@
+package docker
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	dockerModel "github.com/flipped-aurora/gin-vue-admin/server/model/docker"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/docker/response"
+)
+
+var (
+	buildStepPattern    = regexp.MustCompile(`^Step (\d+)/(\d+) :`)
+	buildSuccessPattern = regexp.MustCompile(`^Successfully built ([0-9a-f]+)`)
+)
+
+// dockerStreamMessage 拉取/构建接口返回的 JSON 消息
+type dockerStreamMessage struct {
+	Stream         string `json:"stream"`
+	Status         string `json:"status"`
+	ID             string `json:"id"`
+	ProgressDetail struct {
+		Current int64 `json:"current"`
+		Total   int64 `json:"total"`
+	} `json:"progressDetail"`
+	Error       string `json:"error"`
+	ErrorDetail *struct {
+		Message string `json:"message"`
+	} `json:"errorDetail"`
+	Aux *struct {
+		ID string `json:"ID"`
+	} `json:"aux"`
+}
+
+// imageProgressTracker 解析消息流，统计镜像层进度与构建步骤
+type imageProgressTracker struct {
+	jobType    string
+	layers     map[string]*response.ImageLayerProgress
+	order      []string
+	step       int
+	totalSteps int
+	message    string
+	imageID    string
+}
+
+func newImageProgressTracker(jobType string) *imageProgressTracker {
+	return &imageProgressTracker{
+		jobType: jobType,
+		layers:  make(map[string]*response.ImageLayerProgress),
+	}
+}
+
+// handle 处理一行消息，返回需要写入日志的文本（为空则不记录）
+// 消息中带 error 时返回该错误
+func (t *imageProgressTracker) handle(line []byte) (string, error) {
+	var msg dockerStreamMessage
+	if err := json.Unmarshal(line, &msg); err != nil {
+		return strings.TrimSpace(string(line)), nil
+	}
+	if msg.ErrorDetail != nil && msg.ErrorDetail.Message != "" {
+		return msg.ErrorDetail.Message, fmt.Errorf("%s", msg.ErrorDetail.Message)
+	}
+	if msg.Error != "" {
+		return msg.Error, fmt.Errorf("%s", msg.Error)
+	}
+	if msg.Aux != nil && msg.Aux.ID != "" {
+		t.imageID = msg.Aux.ID
+	}
+
+	if msg.Stream != "" {
+		return t.handleBuildOutput(msg.Stream), nil
+	}
+	if msg.Status == "" {
+		return "", nil
+	}
+	if msg.ID != "" && isLayerStatus(msg) {
+		return t.handleLayer(msg), nil
+	}
+
+	t.message = msg.Status
+	if msg.ID != "" {
+		return msg.ID + ": " + msg.Status, nil
+	}
+	return msg.Status, nil
+}
+
+// handleBuildOutput 处理构建输出，识别 Step N/M 与构建结果
+func (t *imageProgressTracker) handleBuildOutput(stream string) string {
+	text := strings.TrimRight(stream, "\r\n")
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" {
+		return ""
+	}
+	if match := buildStepPattern.FindStringSubmatch(trimmed); match != nil {
+		t.step, _ = strconv.Atoi(match[1])
+		t.totalSteps, _ = strconv.Atoi(match[2])
+		t.message = trimmed
+	} else if match := buildSuccessPattern.FindStringSubmatch(trimmed); match != nil {
+		if t.imageID == "" {
+			t.imageID = match[1]
+		}
+		t.message = trimmed
+	}
+	return text
+}
+
+// handleLayer 更新镜像层进度，仅在状态变化时记录日志，避免下载进度刷屏
+func (t *imageProgressTracker) handleLayer(msg dockerStreamMessage) string {
+	layer, ok := t.layers[msg.ID]
+	if !ok {
+		layer = &response.ImageLayerProgress{ID: msg.ID}
+		t.layers[msg.ID] = layer
+		t.order = append(t.order, msg.ID)
+	}
+	changed := layer.Status != msg.Status
+	layer.Status = msg.Status
+	layer.Current = msg.ProgressDetail.Current
+	layer.Total = msg.ProgressDetail.Total
+	layer.Percent = layerPercent(msg.Status, layer.Current, layer.Total, layer.Percent)
+
+	if !changed {
+		return ""
+	}
+	return msg.ID + ": " + msg.Status
+}
+
+// isLayerStatus 判断消息是否为镜像层进度
+func isLayerStatus(msg dockerStreamMessage) bool {
+	if strings.HasPrefix(msg.Status, "Pulling from") {
+		return false
+	}
+	switch msg.Status {
+	case "Pulling fs layer", "Waiting", "Downloading", "Verifying Checksum", "Download complete",
+		"Extracting", "Pull complete", "Already exists":
+		return true
+	}
+	return msg.ProgressDetail.Total > 0
+}
+
+// layerPercent 计算镜像层整体进度，下载占前50%，解压占后50%
+func layerPercent(status string, current, total int64, previous float64) float64 {
+	ratio := 0.0
+	if total > 0 {
+		ratio = float64(current) / float64(total)
+		if ratio > 1 {
+			ratio = 1
+		}
+	}
+	switch status {
+	case "Pulling fs layer", "Waiting":
+		return 0
+	case "Downloading":
+		return ratio * 50
+	case "Verifying Checksum", "Download complete":
+		return 50
+	case "Extracting":
+		return 50 + ratio*50
+	case "Pull complete", "Already exists":
+		return 100
+	}
+	return previous
+}
+
+// progress 计算整体进度，未结束前最多为99%
+func (t *imageProgressTracker) progress() float64 {
+	var percent float64
+	switch {
+	case t.jobType == dockerModel.ImageJobTypeBuild && t.totalSteps > 0:
+		percent = float64(t.step-1) / float64(t.totalSteps) * 100
+	case len(t.order) > 0:
+		for _, id := range t.order {
+			percent += t.layers[id].Percent
+		}
+		percent /= float64(len(t.order))
+	}
+	if percent > 99 {
+		percent = 99
+	}
+	if percent < 0 {
+		percent = 0
+	}
+	return percent
+}
+
+// layerList 按出现顺序返回镜像层进度
+func (t *imageProgressTracker) layerList() []response.ImageLayerProgress {
+	if len(t.order) == 0 {
+		return nil
+	}
+	layers := make([]response.ImageLayerProgress, 0, len(t.order))
+	for _, id := range t.order {
+		layers = append(layers, *t.layers[id])
+	}
+	return layers
+}
@@ -0,0 +1,53 @@
+package docker
+
+import (
+	"testing"
+
+	dockerModel "github.com/flipped-aurora/gin-vue-admin/server/model/docker"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImageProgressTrackerPull(t *testing.T) {
+	tracker := newImageProgressTracker(dockerModel.ImageJobTypePull)
+	lines := []string{
+		`{"status":"Pulling from library/nginx","id":"latest"}`,
+		`{"status":"Pulling fs layer","progressDetail":{},"id":"a1"}`,
+		`{"status":"Already exists","progressDetail":{},"id":"b2"}`,
+		`{"status":"Downloading","progressDetail":{"current":50,"total":100},"id":"a1"}`,
+		`{"status":"Downloading","progressDetail":{"current":100,"total":100},"id":"a1"}`,
+	}
+	var logged []string
+	for _, line := range lines {
+		text, err := tracker.handle([]byte(line))
+		assert.NoError(t, err)
+		if text != "" {
+			logged = append(logged, text)
+		}
+	}
+
+	// 重复的 Downloading 不重复记录日志
+	assert.Equal(t, []string{"latest: Pulling from library/nginx", "a1: Pulling fs layer", "b2: Already exists", "a1: Downloading"}, logged)
+	assert.Len(t, tracker.layerList(), 2)
+	assert.InDelta(t, 75, tracker.progress(), 0.01)
+
+	_, err := tracker.handle([]byte(`{"errorDetail":{"message":"manifest unknown"},"error":"manifest unknown"}`))
+	assert.EqualError(t, err, "manifest unknown")
+}
+
+func TestImageProgressTrackerBuild(t *testing.T) {
+	tracker := newImageProgressTracker(dockerModel.ImageJobTypeBuild)
+	for _, line := range []string{
+		`{"stream":"Step 1/4 : FROM alpine"}`,
+		`{"stream":"\n"}`,
+		`{"stream":"Step 3/4 : RUN apk add curl"}`,
+		`{"aux":{"ID":"sha256:abc"}}`,
+		`{"stream":"Successfully built abc\n"}`,
+	} {
+		_, err := tracker.handle([]byte(line))
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 3, tracker.step)
+	assert.Equal(t, 4, tracker.totalSteps)
+	assert.InDelta(t, 50, tracker.progress(), 0.01)
+	assert.Equal(t, "sha256:abc", tracker.imageID)
+}
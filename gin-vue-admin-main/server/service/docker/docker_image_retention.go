@@ -0,0 +1,539 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	dockerModel "github.com/flipped-aurora/gin-vue-admin/server/model/docker"
+	dockerReq "github.com/flipped-aurora/gin-vue-admin/server/model/docker/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/docker/response"
+	cionService "github.com/flipped-aurora/gin-vue-admin/server/service/cion"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// imageRetentionCron 保留策略定时任务所在的 cron 名称，每个启用的策略对应一个任务
+const imageRetentionCron = "DockerImageRetention"
+
+const (
+	imageRetentionTimeout = 10 * time.Minute // 单次执行的超时时间
+	imageRetentionRunKeep = 50               // 每个策略保留的执行记录数
+)
+
+// imageRetentionLock 同一时间只执行一个保留策略，避免并发删除同一镜像
+var imageRetentionLock sync.Mutex
+
+// retentionImage 参与保留策略计算的镜像，大小取自 DiskUsage
+type retentionImage struct {
+	ID         string
+	RepoTags   []string
+	Created    time.Time
+	Size       int64
+	UniqueSize int64    // 不与其他镜像共享的大小，即镜像删除后实际释放的空间
+	Containers []string // 使用该镜像的容器，包括已停止的容器
+}
+
+type DockerImageRetentionService struct {
+	hostClient
+}
+
+// GetRetentionPolicyList 获取镜像保留策略列表
+func (d *DockerImageRetentionService) GetRetentionPolicyList() ([]dockerModel.DockerImageRetentionPolicy, error) {
+	var policies []dockerModel.DockerImageRetentionPolicy
+	if err := global.GVA_DB.Order("id asc").Find(&policies).Error; err != nil {
+		return nil, fmt.Errorf("failed to query retention policies: %v", err)
+	}
+	return policies, nil
+}
+
+// CreateRetentionPolicy 创建镜像保留策略并重新注册定时任务
+func (d *DockerImageRetentionService) CreateRetentionPolicy(req dockerReq.ImageRetentionPolicyRequest, userID uint) (*dockerModel.DockerImageRetentionPolicy, error) {
+	policy := dockerModel.DockerImageRetentionPolicy{HostID: d.hostID(), CreatedBy: userID}
+	if err := applyRetentionPolicyRequest(&policy, req); err != nil {
+		return nil, err
+	}
+	if err := global.GVA_DB.Create(&policy).Error; err != nil {
+		return nil, fmt.Errorf("failed to create retention policy: %v", err)
+	}
+	d.ScheduleRetentionPolicies()
+	return &policy, nil
+}
+
+// UpdateRetentionPolicy 更新镜像保留策略并重新注册定时任务
+func (d *DockerImageRetentionService) UpdateRetentionPolicy(req dockerReq.ImageRetentionPolicyRequest) (*dockerModel.DockerImageRetentionPolicy, error) {
+	policy, err := getRetentionPolicy(req.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyRetentionPolicyRequest(policy, req); err != nil {
+		return nil, err
+	}
+	if err := global.GVA_DB.Save(policy).Error; err != nil {
+		return nil, fmt.Errorf("failed to update retention policy: %v", err)
+	}
+	d.ScheduleRetentionPolicies()
+	return policy, nil
+}
+
+// DeleteRetentionPolicy 删除镜像保留策略，执行记录保留
+func (d *DockerImageRetentionService) DeleteRetentionPolicy(id uint) error {
+	result := global.GVA_DB.Delete(&dockerModel.DockerImageRetentionPolicy{}, id)
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete retention policy: %v", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("retention policy not found")
+	}
+	d.ScheduleRetentionPolicies()
+	return nil
+}
+
+// PreviewRetentionPolicy 预览已保存策略的执行结果，不删除任何镜像
+func (d *DockerImageRetentionService) PreviewRetentionPolicy(id uint) (*response.ImageRetentionPlan, error) {
+	policy, err := getRetentionPolicy(id)
+	if err != nil {
+		return nil, err
+	}
+	cli, err := retentionPolicyClient(*policy)
+	if err != nil {
+		return nil, err
+	}
+	return previewRetention(cli, *policy)
+}
+
+// PreviewRetention 预览未保存的策略，用于保存前调整规则
+func (d *DockerImageRetentionService) PreviewRetention(req dockerReq.ImageRetentionPolicyRequest) (*response.ImageRetentionPlan, error) {
+	var policy dockerModel.DockerImageRetentionPolicy
+	if err := applyRetentionPolicyRequest(&policy, req); err != nil {
+		return nil, err
+	}
+	return previewRetention(d.cli(), policy)
+}
+
+// RunRetentionPolicy 立即执行保留策略，返回执行记录
+func (d *DockerImageRetentionService) RunRetentionPolicy(id uint, userID uint) (*dockerModel.DockerImageRetentionRun, error) {
+	policy, err := getRetentionPolicy(id)
+	if err != nil {
+		return nil, err
+	}
+	cli, err := retentionPolicyClient(*policy)
+	if err != nil {
+		return nil, err
+	}
+	if !imageRetentionLock.TryLock() {
+		return nil, fmt.Errorf("image retention is already running")
+	}
+	defer imageRetentionLock.Unlock()
+	return runRetentionPolicy(cli, *policy, dockerModel.RetentionTriggerManual, userID)
+}
+
+// retentionPolicyClient 已保存的策略始终在创建时所在的主机上执行
+func retentionPolicyClient(policy dockerModel.DockerImageRetentionPolicy) (*client.Client, error) {
+	return (&DockerHostService{}).Client(policy.HostID)
+}
+
+// GetRetentionRunList 分页查询保留策略执行记录
+func (d *DockerImageRetentionService) GetRetentionRunList(filter dockerReq.ImageRetentionRunFilter) ([]dockerModel.DockerImageRetentionRun, int64, error) {
+	db := global.GVA_DB.Model(&dockerModel.DockerImageRetentionRun{})
+	if filter.PolicyID != 0 {
+		db = db.Where("policy_id = ?", filter.PolicyID)
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count retention runs: %v", err)
+	}
+
+	var runs []dockerModel.DockerImageRetentionRun
+	if err := db.Order("id desc").Offset(filter.PageSize * (filter.Page - 1)).Limit(filter.PageSize).Find(&runs).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to query retention runs: %v", err)
+	}
+	return runs, total, nil
+}
+
+// ScheduleRetentionPolicies 按启用策略的执行周期重新注册定时任务，策略变化后调用
+func (d *DockerImageRetentionService) ScheduleRetentionPolicies() {
+	global.GVA_Timer.Clear(imageRetentionCron)
+	if global.GVA_DB == nil {
+		return
+	}
+
+	var policies []dockerModel.DockerImageRetentionPolicy
+	if err := global.GVA_DB.Where("enabled = ? AND schedule <> ''", true).Find(&policies).Error; err != nil {
+		global.GVA_LOG.Error("Failed to load retention policies", zap.Error(err))
+		return
+	}
+	for _, policy := range policies {
+		policyID := policy.ID
+		_, err := global.GVA_Timer.AddTaskByFunc(imageRetentionCron, policy.Schedule, func() {
+			runScheduledRetention(policyID)
+		}, fmt.Sprintf("镜像保留策略[%d] %s", policy.ID, policy.Name), cron.WithChain(cron.SkipIfStillRunning(cron.DiscardLogger)))
+		if err != nil {
+			global.GVA_LOG.Error("Failed to schedule retention policy", zap.Uint("id", policy.ID), zap.String("schedule", policy.Schedule), zap.Error(err))
+		}
+	}
+}
+
+// runScheduledRetention 定时执行保留策略，重新读取策略以使用最新规则，其他策略执行中时跳过本次
+func runScheduledRetention(policyID uint) {
+	policy, err := getRetentionPolicy(policyID)
+	if err != nil || !policy.Enabled {
+		return
+	}
+	cli, err := retentionPolicyClient(*policy)
+	if err != nil {
+		global.GVA_LOG.Warn("Skip retention policy, docker host unavailable", zap.Uint("id", policyID), zap.Error(err))
+		return
+	}
+	if !imageRetentionLock.TryLock() {
+		global.GVA_LOG.Warn("Skip retention policy, another policy is running", zap.Uint("id", policyID))
+		return
+	}
+	defer imageRetentionLock.Unlock()
+	if _, err := runRetentionPolicy(cli, *policy, dockerModel.RetentionTriggerSchedule, 0); err != nil {
+		global.GVA_LOG.Error("Scheduled retention policy failed", zap.Uint("id", policyID), zap.Error(err))
+	}
+}
+
+// previewRetention 读取当前镜像并计算策略的执行结果
+func previewRetention(cli *client.Client, policy dockerModel.DockerImageRetentionPolicy) (*response.ImageRetentionPlan, error) {
+	if cli == nil {
+		return nil, fmt.Errorf("Docker client is not available")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	images, err := loadRetentionImages(ctx, cli)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := planImageRetention(policy, images, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+// runRetentionPolicy 执行保留策略，逐个删除标签并记录结果，调用方需持有 imageRetentionLock
+func runRetentionPolicy(cli *client.Client, policy dockerModel.DockerImageRetentionPolicy, trigger string, userID uint) (*dockerModel.DockerImageRetentionRun, error) {
+	if cli == nil {
+		return nil, fmt.Errorf("Docker client is not available")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), imageRetentionTimeout)
+	defer cancel()
+
+	start := time.Now()
+	run := dockerModel.DockerImageRetentionRun{
+		PolicyID:   policy.ID,
+		PolicyName: policy.Name,
+		Trigger:    trigger,
+		CreatedBy:  userID,
+	}
+
+	images, err := loadRetentionImages(ctx, cli)
+	var plan response.ImageRetentionPlan
+	if err == nil {
+		plan, err = planImageRetention(policy, images, start)
+	}
+	if err != nil {
+		run.Status = dockerModel.RetentionRunFailed
+		run.Errors = []string{err.Error()}
+		saveRetentionRun(&run, start)
+		return &run, err
+	}
+
+	uniqueSizes := make(map[string]int64, len(images))
+	for _, image := range images {
+		uniqueSizes[image.ID] = image.UniqueSize
+	}
+	run.ProtectedTags = len(plan.Protected)
+	for _, candidate := range plan.Candidates {
+		ref := candidate.Repository + ":" + candidate.Tag
+		// 不使用强制删除：镜像在计算后被新容器使用时，Docker 会拒绝删除最后一个标签
+		items, err := cli.ImageRemove(ctx, ref, types.ImageRemoveOptions{PruneChildren: true})
+		if err != nil {
+			run.Errors = append(run.Errors, fmt.Sprintf("%s: %v", ref, err))
+			continue
+		}
+		run.RemovedTags++
+		run.Removed = append(run.Removed, ref)
+		for _, item := range items {
+			if item.Deleted == candidate.ImageID {
+				run.RemovedImages++
+				run.ReclaimedBytes += uniqueSizes[candidate.ImageID]
+			}
+		}
+	}
+
+	switch {
+	case len(run.Errors) == 0:
+		run.Status = dockerModel.RetentionRunSuccess
+	case run.RemovedTags > 0:
+		run.Status = dockerModel.RetentionRunPartial
+	default:
+		run.Status = dockerModel.RetentionRunFailed
+	}
+	saveRetentionRun(&run, start)
+
+	if run.RemovedTags > 0 {
+		global.GVA_LOG.Info("Retention policy executed", zap.Uint("id", policy.ID), zap.Int("removedTags", run.RemovedTags),
+			zap.Int("removedImages", run.RemovedImages), zap.Int64("reclaimedBytes", run.ReclaimedBytes))
+		if policy.HostID == 0 {
+			if _, err := (&cionService.ImageService{}).SyncImages(ctx); err != nil {
+				global.GVA_LOG.Warn("Failed to sync image inventory after retention", zap.Error(err))
+			}
+		}
+	}
+	return &run, nil
+}
+
+// saveRetentionRun 保存执行记录，更新策略的最近执行状态并清理超出数量的旧记录
+func saveRetentionRun(run *dockerModel.DockerImageRetentionRun, start time.Time) {
+	run.Duration = time.Since(start).Milliseconds()
+	err := global.GVA_DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(run).Error; err != nil {
+			return err
+		}
+		if run.PolicyID == 0 {
+			return nil
+		}
+		if err := tx.Model(&dockerModel.DockerImageRetentionPolicy{}).Where("id = ?", run.PolicyID).UpdateColumns(map[string]interface{}{
+			"last_run_at":     start,
+			"last_run_status": run.Status,
+		}).Error; err != nil {
+			return err
+		}
+		var expired []uint
+		if err := tx.Model(&dockerModel.DockerImageRetentionRun{}).Where("policy_id = ?", run.PolicyID).
+			Order("id desc").Offset(imageRetentionRunKeep).Pluck("id", &expired).Error; err != nil {
+			return err
+		}
+		if len(expired) > 0 {
+			return tx.Delete(&dockerModel.DockerImageRetentionRun{}, expired).Error
+		}
+		return nil
+	})
+	if err != nil {
+		global.GVA_LOG.Error("Failed to save retention run", zap.Uint("policyId", run.PolicyID), zap.Error(err))
+	}
+}
+
+// loadRetentionImages 通过 DiskUsage 获取镜像、共享大小与使用镜像的容器
+func loadRetentionImages(ctx context.Context, cli *client.Client) ([]retentionImage, error) {
+	usage, err := cli.DiskUsage(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get disk usage: %v", err)
+	}
+
+	used := make(map[string][]string)
+	for _, container := range usage.Containers {
+		name := container.ID
+		if len(name) > 12 {
+			name = name[:12]
+		}
+		if len(container.Names) > 0 {
+			name = strings.TrimPrefix(container.Names[0], "/")
+		}
+		used[container.ImageID] = append(used[container.ImageID], name)
+	}
+
+	images := make([]retentionImage, 0, len(usage.Images))
+	for _, summary := range usage.Images {
+		unique := summary.Size
+		if summary.SharedSize > 0 {
+			unique -= summary.SharedSize
+		}
+		containers := used[summary.ID]
+		if len(containers) == 0 && summary.Containers > 0 {
+			// 容器列表与镜像统计不一致时按被使用处理
+			containers = []string{fmt.Sprintf("%d containers", summary.Containers)}
+		}
+		images = append(images, retentionImage{
+			ID:         summary.ID,
+			RepoTags:   summary.RepoTags,
+			Created:    time.Unix(summary.Created, 0),
+			Size:       summary.Size,
+			UniqueSize: unique,
+			Containers: containers,
+		})
+	}
+	return images, nil
+}
+
+// planImageRetention 按策略计算每个匹配仓库中需要删除的标签：
+// 按镜像创建时间倒序，保留最近 KeepLast 个、标签匹配 KeepPattern 的以及未超过 OlderThanDays 的标签，其余删除；
+// 被容器使用的镜像只列入保护列表。镜像的全部标签都被删除时才计入可释放空间
+func planImageRetention(policy dockerModel.DockerImageRetentionPolicy, images []retentionImage, now time.Time) (response.ImageRetentionPlan, error) {
+	plan := response.ImageRetentionPlan{
+		PolicyID:    policy.ID,
+		PolicyName:  policy.Name,
+		Candidates:  []response.ImageRetentionCandidate{},
+		Protected:   []response.ImageRetentionCandidate{},
+		GeneratedAt: now,
+	}
+	var keepPattern *regexp.Regexp
+	if policy.KeepPattern != "" {
+		re, err := regexp.Compile(policy.KeepPattern)
+		if err != nil {
+			return plan, fmt.Errorf("invalid keep pattern: %v", err)
+		}
+		keepPattern = re
+	}
+
+	type taggedImage struct {
+		image *retentionImage
+		tag   string
+	}
+	repositories := make(map[string][]taggedImage)
+	tagCounts := make(map[string]int)
+	for i := range images {
+		image := &images[i]
+		for _, ref := range image.RepoTags {
+			repository, tag, ok := splitRepoTag(ref)
+			if !ok {
+				continue
+			}
+			tagCounts[image.ID]++
+			if matched, _ := path.Match(policy.Repository, repository); matched {
+				repositories[repository] = append(repositories[repository], taggedImage{image: image, tag: tag})
+			}
+		}
+	}
+
+	names := make([]string, 0, len(repositories))
+	for name := range repositories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	removedTags := make(map[string]int)
+	for _, name := range names {
+		tagged := repositories[name]
+		sort.Slice(tagged, func(i, j int) bool {
+			if !tagged[i].image.Created.Equal(tagged[j].image.Created) {
+				return tagged[i].image.Created.After(tagged[j].image.Created)
+			}
+			return tagged[i].tag > tagged[j].tag
+		})
+		for i, t := range tagged {
+			plan.Matched++
+			age := now.Sub(t.image.Created)
+			switch {
+			case policy.KeepLast > 0 && i < policy.KeepLast,
+				keepPattern != nil && keepPattern.MatchString(t.tag),
+				policy.OlderThanDays > 0 && age < time.Duration(policy.OlderThanDays)*24*time.Hour:
+				plan.Kept++
+				continue
+			}
+
+			candidate := response.ImageRetentionCandidate{
+				Repository: name,
+				Tag:        t.tag,
+				ImageID:    t.image.ID,
+				Created:    t.image.Created,
+				AgeDays:    int(age.Hours() / 24),
+				Rank:       i + 1,
+				Size:       t.image.Size,
+			}
+			if len(t.image.Containers) > 0 {
+				candidate.Containers = t.image.Containers
+				plan.Protected = append(plan.Protected, candidate)
+				continue
+			}
+			plan.Candidates = append(plan.Candidates, candidate)
+			removedTags[t.image.ID]++
+		}
+	}
+
+	counted := make(map[string]bool)
+	for i := range plan.Candidates {
+		candidate := &plan.Candidates[i]
+		if removedTags[candidate.ImageID] < tagCounts[candidate.ImageID] {
+			continue
+		}
+		candidate.ImageRemoved = true
+		if !counted[candidate.ImageID] {
+			counted[candidate.ImageID] = true
+			for _, image := range images {
+				if image.ID == candidate.ImageID {
+					plan.ReclaimableBytes += image.UniqueSize
+					break
+				}
+			}
+		}
+	}
+	plan.ReclaimableSize = (&DockerOverviewService{}).formatBytes(plan.ReclaimableBytes)
+	return plan, nil
+}
+
+// splitRepoTag 拆分 RepoTags 中的仓库名与标签，悬空镜像与摘要引用返回 false
+func splitRepoTag(ref string) (string, string, bool) {
+	if ref == "" || ref == "<none>:<none>" || strings.Contains(ref, "@") {
+		return "", "", false
+	}
+	i := strings.LastIndex(ref, ":")
+	if i <= strings.LastIndex(ref, "/") {
+		return "", "", false
+	}
+	return ref[:i], ref[i+1:], true
+}
+
+// applyRetentionPolicyRequest 校验请求并写入策略，保留数量与保留天数至少设置一项以免清空整个仓库
+func applyRetentionPolicyRequest(policy *dockerModel.DockerImageRetentionPolicy, req dockerReq.ImageRetentionPolicyRequest) error {
+	req.Repository = strings.TrimSpace(req.Repository)
+	req.Schedule = strings.TrimSpace(req.Schedule)
+	if _, err := path.Match(req.Repository, ""); err != nil {
+		return fmt.Errorf("invalid repository pattern: %v", err)
+	}
+	if req.KeepLast < 0 || req.OlderThanDays < 0 {
+		return fmt.Errorf("keep last and older than days cannot be negative")
+	}
+	if req.KeepLast == 0 && req.OlderThanDays == 0 {
+		return fmt.Errorf("retention policy must set keep last or older than days")
+	}
+	if req.KeepPattern != "" {
+		if _, err := regexp.Compile(req.KeepPattern); err != nil {
+			return fmt.Errorf("invalid keep pattern: %v", err)
+		}
+	}
+	if req.Schedule != "" {
+		if _, err := cron.ParseStandard(req.Schedule); err != nil {
+			return fmt.Errorf("invalid schedule: %v", err)
+		}
+	} else if req.Enabled {
+		return fmt.Errorf("schedule is required when the policy is enabled")
+	}
+
+	policy.Name = req.Name
+	policy.Repository = req.Repository
+	policy.KeepLast = req.KeepLast
+	policy.KeepPattern = req.KeepPattern
+	policy.OlderThanDays = req.OlderThanDays
+	policy.Schedule = req.Schedule
+	policy.Enabled = req.Enabled
+	policy.Description = req.Description
+	return nil
+}
+
+// getRetentionPolicy 按ID查询保留策略
+func getRetentionPolicy(id uint) (*dockerModel.DockerImageRetentionPolicy, error) {
+	var policy dockerModel.DockerImageRetentionPolicy
+	if err := global.GVA_DB.First(&policy, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("retention policy not found")
+		}
+		return nil, fmt.Errorf("failed to query retention policy: %v", err)
+	}
+	return &policy, nil
+}
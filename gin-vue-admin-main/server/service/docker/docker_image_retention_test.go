@@ -0,0 +1,100 @@
+package docker
+
+import (
+	"testing"
+	"time"
+
+	dockerModel "github.com/flipped-aurora/gin-vue-admin/server/model/docker"
+	dockerReq "github.com/flipped-aurora/gin-vue-admin/server/model/docker/request"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitRepoTag(t *testing.T) {
+	repository, tag, ok := splitRepoTag("registry.local:5000/app:v1.2")
+	assert.True(t, ok)
+	assert.Equal(t, "registry.local:5000/app", repository)
+	assert.Equal(t, "v1.2", tag)
+
+	for _, ref := range []string{"", "<none>:<none>", "app@sha256:abc", "registry.local:5000/app"} {
+		_, _, ok := splitRepoTag(ref)
+		assert.False(t, ok, ref)
+	}
+}
+
+func TestPlanImageRetention(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	images := []retentionImage{
+		{ID: "sha256:a", RepoTags: []string{"app:v5", "app:latest"}, Created: now.Add(-1 * day), Size: 100, UniqueSize: 10},
+		{ID: "sha256:b", RepoTags: []string{"app:v4"}, Created: now.Add(-20 * day), Size: 100, UniqueSize: 20},
+		{ID: "sha256:c", RepoTags: []string{"app:v3"}, Created: now.Add(-40 * day), Size: 100, UniqueSize: 30},
+		{ID: "sha256:d", RepoTags: []string{"app:release-1", "other:v1"}, Created: now.Add(-50 * day), Size: 100, UniqueSize: 40},
+		{ID: "sha256:e", RepoTags: []string{"app:v2"}, Created: now.Add(-60 * day), Size: 100, UniqueSize: 50, Containers: []string{"web"}},
+		{ID: "sha256:f", RepoTags: []string{"app:v1"}, Created: now.Add(-70 * day), Size: 100, UniqueSize: 60},
+		{ID: "sha256:g", RepoTags: []string{"app:stable"}, Created: now.Add(-80 * day), Size: 100, UniqueSize: 70},
+		{ID: "sha256:h", RepoTags: []string{"<none>:<none>"}, Created: now.Add(-90 * day), Size: 100, UniqueSize: 80},
+	}
+	policy := dockerModel.DockerImageRetentionPolicy{
+		Repository:    "app",
+		KeepLast:      2,
+		KeepPattern:   "^stable$",
+		OlderThanDays: 30,
+	}
+
+	plan, err := planImageRetention(policy, images, now)
+	require.NoError(t, err)
+	assert.Equal(t, 8, plan.Matched)
+	// app:latest/app:v5 为最近两个，app:v4 未满30天，app:stable 匹配保留规则
+	assert.Equal(t, 4, plan.Kept)
+
+	refs := make([]string, 0, len(plan.Candidates))
+	for _, candidate := range plan.Candidates {
+		refs = append(refs, candidate.Repository+":"+candidate.Tag)
+	}
+	assert.Equal(t, []string{"app:v3", "app:release-1", "app:v1"}, refs)
+	assert.True(t, plan.Candidates[0].ImageRemoved)
+	assert.False(t, plan.Candidates[1].ImageRemoved, "other:v1 still references the image")
+	assert.Equal(t, 4, plan.Candidates[0].Rank)
+	assert.Equal(t, 40, plan.Candidates[0].AgeDays)
+
+	require.Len(t, plan.Protected, 1)
+	assert.Equal(t, "v2", plan.Protected[0].Tag)
+	assert.Equal(t, []string{"web"}, plan.Protected[0].Containers)
+	assert.Equal(t, int64(30+60), plan.ReclaimableBytes)
+}
+
+func TestPlanImageRetentionRepositoryPattern(t *testing.T) {
+	now := time.Now()
+	images := []retentionImage{
+		{ID: "sha256:a", RepoTags: []string{"registry.local/team/api:v1"}, Created: now.Add(-48 * time.Hour)},
+		{ID: "sha256:b", RepoTags: []string{"registry.local/team/api:v2"}, Created: now.Add(-24 * time.Hour)},
+		{ID: "sha256:c", RepoTags: []string{"registry.local/team/web:v1"}, Created: now},
+		{ID: "sha256:d", RepoTags: []string{"nginx:1.25"}, Created: now.Add(-72 * time.Hour)},
+	}
+	plan, err := planImageRetention(dockerModel.DockerImageRetentionPolicy{Repository: "registry.local/team/*", KeepLast: 1}, images, now)
+	require.NoError(t, err)
+	assert.Equal(t, 3, plan.Matched)
+	require.Len(t, plan.Candidates, 1)
+	assert.Equal(t, "registry.local/team/api", plan.Candidates[0].Repository)
+	assert.Equal(t, "v1", plan.Candidates[0].Tag)
+}
+
+func TestApplyRetentionPolicyRequest(t *testing.T) {
+	var policy dockerModel.DockerImageRetentionPolicy
+	req := dockerReq.ImageRetentionPolicyRequest{Name: "app", Repository: "app", KeepLast: 5, Schedule: "0 3 * * *", Enabled: true}
+	require.NoError(t, applyRetentionPolicyRequest(&policy, req))
+	assert.Equal(t, 5, policy.KeepLast)
+
+	invalid := []dockerReq.ImageRetentionPolicyRequest{
+		{Name: "a", Repository: "app"},
+		{Name: "a", Repository: "app", KeepLast: -1},
+		{Name: "a", Repository: "app[", KeepLast: 1},
+		{Name: "a", Repository: "app", KeepLast: 1, KeepPattern: "("},
+		{Name: "a", Repository: "app", KeepLast: 1, Schedule: "every day"},
+		{Name: "a", Repository: "app", KeepLast: 1, Enabled: true},
+	}
+	for _, r := range invalid {
+		assert.Error(t, applyRetentionPolicyRequest(&policy, r), r)
+	}
+}
@@ -0,0 +1,544 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/config"
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	dockerModel "github.com/flipped-aurora/gin-vue-admin/server/model/docker"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/docker/response"
+	"github.com/shirou/gopsutil/v3/cpu"
+	"github.com/shirou/gopsutil/v3/disk"
+	"github.com/shirou/gopsutil/v3/load"
+	"github.com/shirou/gopsutil/v3/mem"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultMetricsInterval        = 30  // 默认采样间隔（秒）
+	defaultMetricsRawRetention    = 24  // 原始数据默认保留小时数
+	defaultMetricsMinuteRetention = 168 // 5分钟聚合数据默认保留小时数
+	defaultMetricsHourRetention   = 720 // 1小时聚合数据默认保留小时数
+
+	// metricsRollupDelay 聚合时留出的写入延迟，避免采集尚未落库的区间被提前聚合
+	metricsRollupDelay = time.Minute
+	// metricsRollupBuckets 单次聚合处理的最大区间数，长时间停机后分多次追平
+	metricsRollupBuckets = 288
+)
+
+// metricRange 查询范围与所用数据精度
+type metricRange struct {
+	duration   time.Duration
+	resolution int
+}
+
+var metricRanges = map[string]metricRange{
+	"1h":  {duration: time.Hour, resolution: dockerModel.MetricResolutionRaw},
+	"24h": {duration: 24 * time.Hour, resolution: dockerModel.MetricResolutionMinute},
+	"7d":  {duration: 7 * 24 * time.Hour, resolution: dockerModel.MetricResolutionHour},
+}
+
+var containerIDPattern = regexp.MustCompile(`^[a-f0-9]{12,64}$`)
+
+type DockerMetricsService struct{}
+
+// MetricsConfig 获取指标采集配置，未配置的项使用默认值
+func (d *DockerMetricsService) MetricsConfig() config.DockerMetrics {
+	cfg := global.GVA_CONFIG.Docker.Metrics
+	if cfg.Interval <= 0 {
+		cfg.Interval = defaultMetricsInterval
+	}
+	if cfg.RawRetention <= 0 {
+		cfg.RawRetention = defaultMetricsRawRetention
+	}
+	if cfg.MinuteRetention <= 0 {
+		cfg.MinuteRetention = defaultMetricsMinuteRetention
+	}
+	if cfg.HourRetention <= 0 {
+		cfg.HourRetention = defaultMetricsHourRetention
+	}
+	return cfg
+}
+
+// Sample 采集一次主机、磁盘与运行中容器的资源使用情况并写入数据库
+func (d *DockerMetricsService) Sample() error {
+	if global.GVA_DB == nil {
+		return nil
+	}
+
+	now := time.Now()
+	records := make([]dockerModel.DockerMetric, 0)
+
+	host, err := sampleHostMetric(now)
+	if err != nil {
+		global.GVA_LOG.Warn("Failed to sample host metrics", zap.Error(err))
+	} else {
+		records = append(records, host)
+	}
+	records = append(records, sampleDiskMetrics(now)...)
+
+	// 守护进程不可用时跳过容器采样，避免每轮等待超时
+	if dockerAvailable() {
+		interval := time.Duration(d.MetricsConfig().Interval) * time.Second
+		ctx, cancel := context.WithTimeout(context.Background(), interval)
+		stats, err := collectAllContainerStats(ctx, global.GetDocker())
+		cancel()
+		if err != nil {
+			global.GVA_LOG.Warn("Failed to sample container metrics", zap.Error(err))
+		}
+		for _, s := range stats {
+			records = append(records, dockerModel.DockerMetric{
+				SampledAt:        now,
+				Resolution:       dockerModel.MetricResolutionRaw,
+				TargetType:       dockerModel.MetricTargetContainer,
+				TargetID:         s.ID,
+				TargetName:       s.Name,
+				CPUPercent:       s.CPUPercent,
+				CPUPercentMax:    s.CPUPercent,
+				MemoryUsage:      s.MemoryUsage,
+				MemoryTotal:      s.MemoryLimit,
+				MemoryPercent:    s.MemoryPercent,
+				MemoryPercentMax: s.MemoryPercent,
+				NetworkRx:        s.NetworkRx,
+				NetworkTx:        s.NetworkTx,
+				BlockRead:        s.BlockRead,
+				BlockWrite:       s.BlockWrite,
+				PIDs:             s.PIDs,
+			})
+		}
+	}
+
+	if len(records) == 0 {
+		return nil
+	}
+	if err := global.GVA_DB.CreateInBatches(records, 100).Error; err != nil {
+		return fmt.Errorf("failed to save metrics: %v", err)
+	}
+	return nil
+}
+
+// Rollup 将原始数据聚合为5分钟精度，再将5分钟数据聚合为1小时精度
+func (d *DockerMetricsService) Rollup() error {
+	if global.GVA_DB == nil {
+		return nil
+	}
+
+	now := time.Now()
+	if err := rollupMetrics(dockerModel.MetricResolutionRaw, dockerModel.MetricResolutionMinute, now); err != nil {
+		return err
+	}
+	return rollupMetrics(dockerModel.MetricResolutionMinute, dockerModel.MetricResolutionHour, now)
+}
+
+// Cleanup 按各精度的保留时长删除过期数据
+func (d *DockerMetricsService) Cleanup() error {
+	if global.GVA_DB == nil {
+		return nil
+	}
+
+	cfg := d.MetricsConfig()
+	retentions := map[int]int{
+		dockerModel.MetricResolutionRaw:    cfg.RawRetention,
+		dockerModel.MetricResolutionMinute: cfg.MinuteRetention,
+		dockerModel.MetricResolutionHour:   cfg.HourRetention,
+	}
+	now := time.Now()
+	for resolution, hours := range retentions {
+		before := now.Add(-time.Duration(hours) * time.Hour)
+		err := global.GVA_DB.Where("resolution = ? AND sampled_at < ?", resolution, before).Delete(&dockerModel.DockerMetric{}).Error
+		if err != nil {
+			return fmt.Errorf("failed to clean up metrics: %v", err)
+		}
+	}
+	return nil
+}
+
+// GetHostMetrics 查询主机与磁盘的历史指标
+func (d *DockerMetricsService) GetHostMetrics(rangeName string) (*response.HostMetricsResult, error) {
+	if global.GVA_DB == nil {
+		return nil, fmt.Errorf("database is not available")
+	}
+
+	name, r, err := resolveMetricRange(rangeName)
+	if err != nil {
+		return nil, err
+	}
+	end := time.Now()
+	start := end.Add(-r.duration)
+
+	var records []dockerModel.DockerMetric
+	err = global.GVA_DB.Where("resolution = ? AND target_type IN ? AND sampled_at >= ?",
+		r.resolution, []string{dockerModel.MetricTargetHost, dockerModel.MetricTargetDisk}, start).
+		Order("sampled_at asc").Find(&records).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to query metrics: %v", err)
+	}
+
+	result := &response.HostMetricsResult{
+		Range:      name,
+		Resolution: r.resolution,
+		Start:      start,
+		End:        end,
+		Host: response.MetricSeries{
+			TargetType: dockerModel.MetricTargetHost,
+			TargetID:   dockerModel.MetricTargetHost,
+			Points:     make([]response.MetricPoint, 0),
+		},
+		Disks: make([]response.MetricSeries, 0),
+	}
+	var hostRecords, diskRecords []dockerModel.DockerMetric
+	for _, record := range records {
+		if record.TargetType == dockerModel.MetricTargetHost {
+			hostRecords = append(hostRecords, record)
+		} else {
+			diskRecords = append(diskRecords, record)
+		}
+	}
+	for _, record := range hostRecords {
+		result.Host.Points = append(result.Host.Points, toMetricPoint(record))
+	}
+	result.Disks = groupMetricSeries(diskRecords)
+	return result, nil
+}
+
+// GetContainerMetrics 查询容器的历史指标，containerID 可为容器ID、ID前缀或容器名称
+func (d *DockerMetricsService) GetContainerMetrics(containerID string, rangeName string) (*response.ContainerMetricsResult, error) {
+	if global.GVA_DB == nil {
+		return nil, fmt.Errorf("database is not available")
+	}
+
+	if containerID == "" {
+		return nil, fmt.Errorf("container ID cannot be empty")
+	}
+
+	name, r, err := resolveMetricRange(rangeName)
+	if err != nil {
+		return nil, err
+	}
+	end := time.Now()
+	start := end.Add(-r.duration)
+
+	db := global.GVA_DB.Where("resolution = ? AND target_type = ? AND sampled_at >= ?",
+		r.resolution, dockerModel.MetricTargetContainer, start)
+	containerName := strings.TrimPrefix(containerID, "/")
+	if containerIDPattern.MatchString(containerID) {
+		db = db.Where("target_id LIKE ? OR target_name = ?", containerID+"%", containerName)
+	} else {
+		db = db.Where("target_name = ?", containerName)
+	}
+
+	var records []dockerModel.DockerMetric
+	if err := db.Order("sampled_at asc").Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to query metrics: %v", err)
+	}
+
+	return &response.ContainerMetricsResult{
+		Range:      name,
+		Resolution: r.resolution,
+		Start:      start,
+		End:        end,
+		Series:     groupMetricSeries(records),
+	}, nil
+}
+
+// GetContainerMetricsSummary 汇总时间范围内各容器的平均与峰值资源使用，按平均CPU使用率降序
+func (d *DockerMetricsService) GetContainerMetricsSummary(rangeName string) ([]response.ContainerMetricsSummary, error) {
+	if global.GVA_DB == nil {
+		return nil, fmt.Errorf("database is not available")
+	}
+
+	_, r, err := resolveMetricRange(rangeName)
+	if err != nil {
+		return nil, err
+	}
+	start := time.Now().Add(-r.duration)
+
+	var records []dockerModel.DockerMetric
+	err = global.GVA_DB.Where("resolution = ? AND target_type = ? AND sampled_at >= ?",
+		r.resolution, dockerModel.MetricTargetContainer, start).
+		Order("sampled_at asc").Find(&records).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to query metrics: %v", err)
+	}
+
+	type accumulator struct {
+		summary response.ContainerMetricsSummary
+		count   int
+	}
+	accumulators := make(map[string]*accumulator)
+	order := make([]string, 0)
+	for _, record := range records {
+		acc, ok := accumulators[record.TargetID]
+		if !ok {
+			acc = &accumulator{summary: response.ContainerMetricsSummary{TargetID: record.TargetID}}
+			accumulators[record.TargetID] = acc
+			order = append(order, record.TargetID)
+		}
+		acc.count++
+		acc.summary.TargetName = record.TargetName
+		acc.summary.LastSampledAt = record.SampledAt
+		acc.summary.CPUPercentAvg += record.CPUPercent
+		acc.summary.MemoryUsageAvg += float64(record.MemoryUsage)
+		acc.summary.CPUPercentMax = max(acc.summary.CPUPercentMax, record.CPUPercentMax)
+		acc.summary.MemoryPercentMax = max(acc.summary.MemoryPercentMax, record.MemoryPercentMax)
+	}
+
+	result := make([]response.ContainerMetricsSummary, 0, len(order))
+	for _, id := range order {
+		acc := accumulators[id]
+		acc.summary.CPUPercentAvg /= float64(acc.count)
+		acc.summary.MemoryUsageAvg /= float64(acc.count)
+		result = append(result, acc.summary)
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].CPUPercentAvg > result[j].CPUPercentAvg })
+	return result, nil
+}
+
+// resolveMetricRange 解析查询范围，为空时默认1h
+func resolveMetricRange(name string) (string, metricRange, error) {
+	if name == "" {
+		name = "1h"
+	}
+	r, ok := metricRanges[name]
+	if !ok {
+		return "", metricRange{}, fmt.Errorf("unsupported range: %s", name)
+	}
+	return name, r, nil
+}
+
+// sampleHostMetric 采集主机CPU、内存与负载
+// CPU使用率为距上次采集以来的平均值，避免瞬时抖动
+func sampleHostMetric(now time.Time) (dockerModel.DockerMetric, error) {
+	record := dockerModel.DockerMetric{
+		SampledAt:  now,
+		Resolution: dockerModel.MetricResolutionRaw,
+		TargetType: dockerModel.MetricTargetHost,
+		TargetID:   dockerModel.MetricTargetHost,
+		TargetName: dockerModel.MetricTargetHost,
+	}
+
+	percents, err := cpu.Percent(0, false)
+	if err != nil {
+		return record, err
+	}
+	if len(percents) > 0 {
+		record.CPUPercent = percents[0]
+		record.CPUPercentMax = percents[0]
+	}
+
+	vm, err := mem.VirtualMemory()
+	if err != nil {
+		return record, err
+	}
+	record.MemoryUsage = vm.Used
+	record.MemoryTotal = vm.Total
+	record.MemoryPercent = vm.UsedPercent
+	record.MemoryPercentMax = vm.UsedPercent
+
+	// 部分平台不支持负载信息，忽略错误
+	if avg, err := load.Avg(); err == nil {
+		record.Load1 = avg.Load1
+		record.Load5 = avg.Load5
+		record.Load15 = avg.Load15
+	}
+	return record, nil
+}
+
+// sampleDiskMetrics 采集配置中各挂载点的磁盘使用情况
+func sampleDiskMetrics(now time.Time) []dockerModel.DockerMetric {
+	records := make([]dockerModel.DockerMetric, 0, len(global.GVA_CONFIG.DiskList))
+	for _, item := range global.GVA_CONFIG.DiskList {
+		usage, err := disk.Usage(item.MountPoint)
+		if err != nil {
+			global.GVA_LOG.Warn("Failed to sample disk metrics", zap.String("mountPoint", item.MountPoint), zap.Error(err))
+			continue
+		}
+		records = append(records, dockerModel.DockerMetric{
+			SampledAt:   now,
+			Resolution:  dockerModel.MetricResolutionRaw,
+			TargetType:  dockerModel.MetricTargetDisk,
+			TargetID:    item.MountPoint,
+			TargetName:  item.MountPoint,
+			DiskUsed:    usage.Used,
+			DiskTotal:   usage.Total,
+			DiskPercent: usage.UsedPercent,
+		})
+	}
+	return records
+}
+
+// rollupMetrics 将 source 精度的数据聚合为 target 精度，只处理已完整结束的区间
+// 从上次聚合的下一个区间开始；中间没有数据的区间直接跳过
+func rollupMetrics(source, target int, now time.Time) error {
+	width := time.Duration(target) * time.Second
+	end := now.Add(-metricsRollupDelay).Truncate(width)
+
+	var from time.Time
+	var last []dockerModel.DockerMetric
+	if err := global.GVA_DB.Where("resolution = ?", target).Order("sampled_at desc").Limit(1).Find(&last).Error; err != nil {
+		return fmt.Errorf("failed to query metrics: %v", err)
+	}
+	if len(last) > 0 {
+		from = last[0].SampledAt.Add(width)
+	}
+
+	query := global.GVA_DB.Where("resolution = ?", source)
+	if !from.IsZero() {
+		query = query.Where("sampled_at >= ?", from)
+	}
+	var first []dockerModel.DockerMetric
+	if err := query.Order("sampled_at asc").Limit(1).Find(&first).Error; err != nil {
+		return fmt.Errorf("failed to query metrics: %v", err)
+	}
+	if len(first) == 0 {
+		return nil
+	}
+	start := first[0].SampledAt.Truncate(width)
+	if limit := start.Add(metricsRollupBuckets * width); end.After(limit) {
+		end = limit
+	}
+	if !start.Before(end) {
+		return nil
+	}
+
+	var samples []dockerModel.DockerMetric
+	err := global.GVA_DB.Where("resolution = ? AND sampled_at >= ? AND sampled_at < ?", source, start, end).
+		Order("sampled_at asc").Find(&samples).Error
+	if err != nil {
+		return fmt.Errorf("failed to query metrics: %v", err)
+	}
+
+	aggregated := aggregateMetrics(samples, target)
+	if len(aggregated) == 0 {
+		return nil
+	}
+	if err := global.GVA_DB.CreateInBatches(aggregated, 100).Error; err != nil {
+		return fmt.Errorf("failed to save aggregated metrics: %v", err)
+	}
+	return nil
+}
+
+// aggregateMetrics 按对象与区间聚合数据：使用率、用量与负载取平均，峰值取最大，累计计数与总量取区间末值
+// samples 需按时间升序排列
+func aggregateMetrics(samples []dockerModel.DockerMetric, resolution int) []dockerModel.DockerMetric {
+	type bucketKey struct {
+		targetType string
+		targetID   string
+		start      int64
+	}
+	type bucket struct {
+		record dockerModel.DockerMetric
+		count  int
+		memory float64
+		disk   float64
+		pids   float64
+	}
+
+	width := time.Duration(resolution) * time.Second
+	buckets := make(map[bucketKey]*bucket)
+	order := make([]bucketKey, 0)
+	for _, sample := range samples {
+		bucketStart := sample.SampledAt.Truncate(width)
+		key := bucketKey{targetType: sample.TargetType, targetID: sample.TargetID, start: bucketStart.Unix()}
+		b, ok := buckets[key]
+		if !ok {
+			b = &bucket{record: dockerModel.DockerMetric{
+				SampledAt:  bucketStart,
+				Resolution: resolution,
+				TargetType: sample.TargetType,
+				TargetID:   sample.TargetID,
+			}}
+			buckets[key] = b
+			order = append(order, key)
+		}
+
+		b.count++
+		r := &b.record
+		r.TargetName = sample.TargetName
+		r.CPUPercent += sample.CPUPercent
+		r.MemoryPercent += sample.MemoryPercent
+		r.DiskPercent += sample.DiskPercent
+		r.Load1 += sample.Load1
+		r.Load5 += sample.Load5
+		r.Load15 += sample.Load15
+		b.memory += float64(sample.MemoryUsage)
+		b.disk += float64(sample.DiskUsed)
+		b.pids += float64(sample.PIDs)
+		r.CPUPercentMax = max(r.CPUPercentMax, sample.CPUPercentMax, sample.CPUPercent)
+		r.MemoryPercentMax = max(r.MemoryPercentMax, sample.MemoryPercentMax, sample.MemoryPercent)
+		r.MemoryTotal = sample.MemoryTotal
+		r.DiskTotal = sample.DiskTotal
+		r.NetworkRx = sample.NetworkRx
+		r.NetworkTx = sample.NetworkTx
+		r.BlockRead = sample.BlockRead
+		r.BlockWrite = sample.BlockWrite
+	}
+
+	result := make([]dockerModel.DockerMetric, 0, len(order))
+	for _, key := range order {
+		b := buckets[key]
+		n := float64(b.count)
+		r := b.record
+		r.CPUPercent /= n
+		r.MemoryPercent /= n
+		r.DiskPercent /= n
+		r.Load1 /= n
+		r.Load5 /= n
+		r.Load15 /= n
+		r.MemoryUsage = uint64(b.memory / n)
+		r.DiskUsed = uint64(b.disk / n)
+		r.PIDs = uint64(b.pids/n + 0.5)
+		result = append(result, r)
+	}
+	return result
+}
+
+// groupMetricSeries 按对象拆分为多条序列，保持首次出现的顺序
+func groupMetricSeries(records []dockerModel.DockerMetric) []response.MetricSeries {
+	series := make([]response.MetricSeries, 0)
+	index := make(map[string]int)
+	for _, record := range records {
+		i, ok := index[record.TargetID]
+		if !ok {
+			i = len(series)
+			index[record.TargetID] = i
+			series = append(series, response.MetricSeries{
+				TargetType: record.TargetType,
+				TargetID:   record.TargetID,
+				Points:     make([]response.MetricPoint, 0),
+			})
+		}
+		series[i].TargetName = record.TargetName
+		series[i].Points = append(series[i].Points, toMetricPoint(record))
+	}
+	return series
+}
+
+// toMetricPoint 转换为接口返回的数据点
+func toMetricPoint(record dockerModel.DockerMetric) response.MetricPoint {
+	return response.MetricPoint{
+		Time:             record.SampledAt,
+		CPUPercent:       record.CPUPercent,
+		CPUPercentMax:    record.CPUPercentMax,
+		MemoryUsage:      record.MemoryUsage,
+		MemoryTotal:      record.MemoryTotal,
+		MemoryPercent:    record.MemoryPercent,
+		MemoryPercentMax: record.MemoryPercentMax,
+		DiskUsed:         record.DiskUsed,
+		DiskTotal:        record.DiskTotal,
+		DiskPercent:      record.DiskPercent,
+		Load1:            record.Load1,
+		Load5:            record.Load5,
+		Load15:           record.Load15,
+		NetworkRx:        record.NetworkRx,
+		NetworkTx:        record.NetworkTx,
+		BlockRead:        record.BlockRead,
+		BlockWrite:       record.BlockWrite,
+		PIDs:             record.PIDs,
+	}
+}
@@ -0,0 +1,35 @@
+package docker
+
+import (
+	"testing"
+	"time"
+
+	dockerModel "github.com/flipped-aurora/gin-vue-admin/server/model/docker"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAggregateMetrics(t *testing.T) {
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	samples := []dockerModel.DockerMetric{
+		{SampledAt: base, TargetType: "container", TargetID: "a", TargetName: "web", CPUPercent: 10, CPUPercentMax: 10, MemoryUsage: 100, NetworkRx: 1000},
+		{SampledAt: base.Add(2 * time.Minute), TargetType: "container", TargetID: "a", TargetName: "web", CPUPercent: 30, CPUPercentMax: 30, MemoryUsage: 300, NetworkRx: 2000},
+		{SampledAt: base.Add(6 * time.Minute), TargetType: "container", TargetID: "a", TargetName: "web", CPUPercent: 50, CPUPercentMax: 50, MemoryUsage: 500, NetworkRx: 3000},
+		{SampledAt: base.Add(time.Minute), TargetType: "host", TargetID: "host", CPUPercent: 40, Load1: 2},
+	}
+
+	result := aggregateMetrics(samples, dockerModel.MetricResolutionMinute)
+	assert.Len(t, result, 3)
+
+	first := result[0]
+	assert.Equal(t, base, first.SampledAt)
+	assert.Equal(t, dockerModel.MetricResolutionMinute, first.Resolution)
+	assert.Equal(t, float64(20), first.CPUPercent)
+	assert.Equal(t, float64(30), first.CPUPercentMax)
+	assert.Equal(t, uint64(200), first.MemoryUsage)
+	assert.Equal(t, uint64(2000), first.NetworkRx)
+
+	assert.Equal(t, base.Add(5*time.Minute), result[1].SampledAt)
+	assert.Equal(t, "host", result[2].TargetType)
+	assert.Equal(t, float64(40), result[2].CPUPercentMax)
+	assert.Equal(t, float64(2), result[2].Load1)
+}
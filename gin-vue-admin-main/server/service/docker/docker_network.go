@@ -15,12 +15,14 @@ import (
 	"go.uber.org/zap"
 )
 
-type DockerNetworkService struct{}
+type DockerNetworkService struct {
+	hostClient
+}
 
 // GetNetworkList 获取网络列表
 func (d *DockerNetworkService) GetNetworkList(filter dockerReq.NetworkFilter) ([]dockerRes.NetworkInfo, int64, error) {
 	// 检查Docker客户端是否可用
-	if global.GVA_DOCKER == nil {
+	if d.cli() == nil {
 		global.GVA_LOG.Error("Docker client is not available")
 		return nil, 0, fmt.Errorf("Docker client is not available")
 	}
@@ -38,7 +40,7 @@ func (d *DockerNetworkService) GetNetworkList(filter dockerReq.NetworkFilter) ([
 	}
 
 	// 调用Docker API获取网络列表
-	networks, err := global.GVA_DOCKER.NetworkList(ctx, types.NetworkListOptions{
+	networks, err := d.cli().NetworkList(ctx, types.NetworkListOptions{
 		Filters: filterArgs,
 	})
 	if err != nil {
@@ -77,7 +79,7 @@ func (d *DockerNetworkService) GetNetworkList(filter dockerReq.NetworkFilter) ([
 // GetNetworkDetail 获取网络详细信息
 func (d *DockerNetworkService) GetNetworkDetail(networkID string) (*dockerRes.NetworkDetail, error) {
 	// 检查Docker客户端是否可用
-	if global.GVA_DOCKER == nil {
+	if d.cli() == nil {
 		global.GVA_LOG.Error("Docker client is not available")
 		return nil, fmt.Errorf("Docker client is not available")
 	}
@@ -86,7 +88,7 @@ func (d *DockerNetworkService) GetNetworkDetail(networkID string) (*dockerRes.Ne
 	defer cancel()
 
 	// 调用Docker API获取网络详细信息
-	networkResource, err := global.GVA_DOCKER.NetworkInspect(ctx, networkID, types.NetworkInspectOptions{})
+	networkResource, err := d.cli().NetworkInspect(ctx, networkID, types.NetworkInspectOptions{})
 	if err != nil {
 		global.GVA_LOG.Error("Failed to get network detail", zap.String("networkID", networkID), zap.Error(err))
 		return nil, fmt.Errorf("failed to get network detail: %v", err)
@@ -94,13 +96,16 @@ func (d *DockerNetworkService) GetNetworkDetail(networkID string) (*dockerRes.Ne
 
 	// 转换为详细响应模型
 	networkDetail := d.convertToNetworkDetail(networkResource)
+	if err := d.attachNetworkMembers(ctx, &networkDetail, networkResource); err != nil {
+		global.GVA_LOG.Warn("Failed to resolve network members", zap.String("networkID", networkID), zap.Error(err))
+	}
 	return &networkDetail, nil
 }
 
 // CreateNetwork 创建网络
 func (d *DockerNetworkService) CreateNetwork(createReq dockerReq.NetworkCreateRequest) (string, error) {
 	// 检查Docker客户端是否可用
-	if global.GVA_DOCKER == nil {
+	if d.cli() == nil {
 		global.GVA_LOG.Error("Docker client is not available")
 		return "", fmt.Errorf("Docker client is not available")
 	}
@@ -108,6 +113,38 @@ func (d *DockerNetworkService) CreateNetwork(createReq dockerReq.NetworkCreateRe
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 	defer cancel()
 
+	// 校验地址池与驱动参数
+	ipamConfigs, err := networkIPAMConfigs(createReq)
+	if err != nil {
+		return "", err
+	}
+	options, err := networkDriverOptions(createReq)
+	if err != nil {
+		return "", err
+	}
+
+	// macvlan/ipvlan 的父接口必须存在于主机上
+	if createReq.Parent != "" {
+		interfaces, err := d.hostInterfaces(ctx)
+		if err != nil {
+			return "", fmt.Errorf("failed to list host interfaces: %v", err)
+		}
+		if err := validateParentInterface(createReq.Parent, interfaces); err != nil {
+			return "", err
+		}
+	}
+
+	// 子网不能与已有网络重叠
+	if len(ipamConfigs) > 0 {
+		existing, err := d.cli().NetworkList(ctx, types.NetworkListOptions{})
+		if err != nil {
+			return "", fmt.Errorf("failed to list networks: %v", err)
+		}
+		if err := checkSubnetConflicts(ipamConfigs, existing); err != nil {
+			return "", err
+		}
+	}
+
 	// 构建网络创建选项
 	createOptions := types.NetworkCreate{
 		Driver:     createReq.Driver,
@@ -115,20 +152,16 @@ func (d *DockerNetworkService) CreateNetwork(createReq dockerReq.NetworkCreateRe
 		Internal:   createReq.Internal,
 		Attachable: createReq.Attachable,
 		Labels:     createReq.Labels,
+		Options:    options,
 	}
 
 	// 配置IPAM
-	if createReq.Subnet != "" || createReq.Gateway != "" {
-		createOptions.IPAM = &network.IPAM{
-			Config: []network.IPAMConfig{{
-				Subnet:  createReq.Subnet,
-				Gateway: createReq.Gateway,
-			}},
-		}
+	if len(ipamConfigs) > 0 {
+		createOptions.IPAM = &network.IPAM{Config: ipamConfigs}
 	}
 
 	// 创建网络
-	response, err := global.GVA_DOCKER.NetworkCreate(ctx, createReq.Name, createOptions)
+	response, err := d.cli().NetworkCreate(ctx, createReq.Name, createOptions)
 	if err != nil {
 		global.GVA_LOG.Error("Failed to create network", 
 			zap.String("name", createReq.Name), 
@@ -147,7 +180,7 @@ func (d *DockerNetworkService) CreateNetwork(createReq dockerReq.NetworkCreateRe
 // RemoveNetwork 删除网络
 func (d *DockerNetworkService) RemoveNetwork(networkID string) error {
 	// 检查Docker客户端是否可用
-	if global.GVA_DOCKER == nil {
+	if d.cli() == nil {
 		global.GVA_LOG.Error("Docker client is not available")
 		return fmt.Errorf("Docker client is not available")
 	}
@@ -156,7 +189,7 @@ func (d *DockerNetworkService) RemoveNetwork(networkID string) error {
 	defer cancel()
 
 	// 删除网络
-	err := global.GVA_DOCKER.NetworkRemove(ctx, networkID)
+	err := d.cli().NetworkRemove(ctx, networkID)
 	if err != nil {
 		global.GVA_LOG.Error("Failed to remove network", zap.String("networkID", networkID), zap.Error(err))
 		return fmt.Errorf("failed to remove network: %v", err)
@@ -169,7 +202,7 @@ func (d *DockerNetworkService) RemoveNetwork(networkID string) error {
 // PruneNetworks 清理未使用的网络
 func (d *DockerNetworkService) PruneNetworks() (int64, int64, error) {
 	// 检查Docker客户端是否可用
-	if global.GVA_DOCKER == nil {
+	if d.cli() == nil {
 		global.GVA_LOG.Error("Docker client is not available")
 		return 0, 0, fmt.Errorf("Docker client is not available")
 	}
@@ -178,7 +211,7 @@ func (d *DockerNetworkService) PruneNetworks() (int64, int64, error) {
 	defer cancel()
 
 	// 清理网络
-	pruneReport, err := global.GVA_DOCKER.NetworksPrune(ctx, filters.NewArgs())
+	pruneReport, err := d.cli().NetworksPrune(ctx, filters.NewArgs())
 	if err != nil {
 		global.GVA_LOG.Error("Failed to prune networks", zap.Error(err))
 		return 0, 0, fmt.Errorf("failed to prune networks: %v", err)
@@ -0,0 +1,275 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	dockerReq "github.com/flipped-aurora/gin-vue-admin/server/model/docker/request"
+	"go.uber.org/zap"
+)
+
+// 驱动参数键
+const (
+	networkOptionMTU        = "com.docker.network.driver.mtu"
+	networkOptionBridgeName = "com.docker.network.bridge.name"
+	networkOptionICC        = "com.docker.network.bridge.enable_icc"
+	networkOptionParent     = "parent"
+	networkOptionMacvlan    = "macvlan_mode"
+	networkOptionIPvlan     = "ipvlan_mode"
+)
+
+const networkHelperLabel = "gva.network-helper" // 读取主机网络接口的辅助容器标签
+
+// networkModes macvlan/ipvlan 支持的模式
+var networkModes = map[string][]string{
+	"macvlan": {"bridge", "vepa", "private", "passthru"},
+	"ipvlan":  {"l2", "l3", "l3s"},
+}
+
+var bridgeNamePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]{0,14}$`)
+
+// networkIPAMConfigs 合并 Subnet/Gateway 简写与地址池列表，校验网关、地址范围、保留地址均在子网内且子网互不重叠
+func networkIPAMConfigs(createReq dockerReq.NetworkCreateRequest) ([]network.IPAMConfig, error) {
+	pools := createReq.IPAM
+	if createReq.Subnet != "" || createReq.Gateway != "" {
+		pools = append([]dockerReq.NetworkIPAMConfig{{Subnet: createReq.Subnet, Gateway: createReq.Gateway}}, pools...)
+	}
+
+	configs := make([]network.IPAMConfig, 0, len(pools))
+	subnets := make([]*net.IPNet, 0, len(pools))
+	for _, pool := range pools {
+		if pool.Subnet == "" {
+			return nil, fmt.Errorf("subnet is required")
+		}
+		_, subnet, err := net.ParseCIDR(pool.Subnet)
+		if err != nil {
+			return nil, fmt.Errorf("invalid subnet: %s", pool.Subnet)
+		}
+		v4 := subnet.IP.To4() != nil
+		if !v4 && !createReq.EnableIPv6 {
+			return nil, fmt.Errorf("ipv6 subnet requires enableIPv6: %s", pool.Subnet)
+		}
+		for _, other := range subnets {
+			if subnetsOverlap(subnet, other) {
+				return nil, fmt.Errorf("subnets overlap: %s and %s", other, subnet)
+			}
+		}
+		subnets = append(subnets, subnet)
+
+		if pool.Gateway != "" {
+			gateway := net.ParseIP(pool.Gateway)
+			if gateway == nil || (gateway.To4() != nil) != v4 || !subnet.Contains(gateway) {
+				return nil, fmt.Errorf("gateway is not in subnet: %s", pool.Gateway)
+			}
+		}
+		if pool.IPRange != "" {
+			_, ipRange, err := net.ParseCIDR(pool.IPRange)
+			if err != nil || (ipRange.IP.To4() != nil) != v4 || !cidrContains(subnet, ipRange) {
+				return nil, fmt.Errorf("ip range is not in subnet: %s", pool.IPRange)
+			}
+		}
+		for host, addr := range pool.AuxAddresses {
+			ip := net.ParseIP(addr)
+			if strings.TrimSpace(host) == "" || ip == nil || (ip.To4() != nil) != v4 || !subnet.Contains(ip) {
+				return nil, fmt.Errorf("aux address is not in subnet: %s", addr)
+			}
+		}
+
+		configs = append(configs, network.IPAMConfig{
+			Subnet:     pool.Subnet,
+			IPRange:    pool.IPRange,
+			Gateway:    pool.Gateway,
+			AuxAddress: pool.AuxAddresses,
+		})
+	}
+	return configs, nil
+}
+
+// networkDriverOptions 生成驱动参数；网桥名称与容器间通信仅适用于 bridge，父接口与模式仅适用于 macvlan/ipvlan
+func networkDriverOptions(createReq dockerReq.NetworkCreateRequest) (map[string]string, error) {
+	options := make(map[string]string, len(createReq.Options)+4)
+	for key, value := range createReq.Options {
+		options[key] = value
+	}
+
+	if createReq.MTU != 0 {
+		if createReq.MTU < 68 || createReq.MTU > 65535 {
+			return nil, fmt.Errorf("invalid mtu: %d", createReq.MTU)
+		}
+		options[networkOptionMTU] = strconv.Itoa(createReq.MTU)
+	}
+
+	if createReq.BridgeName != "" || createReq.ICC != nil {
+		if createReq.Driver != "bridge" {
+			return nil, fmt.Errorf("bridge options require bridge driver")
+		}
+		if createReq.BridgeName != "" {
+			if !bridgeNamePattern.MatchString(createReq.BridgeName) {
+				return nil, fmt.Errorf("invalid bridge name: %s", createReq.BridgeName)
+			}
+			options[networkOptionBridgeName] = createReq.BridgeName
+		}
+		if createReq.ICC != nil {
+			options[networkOptionICC] = strconv.FormatBool(*createReq.ICC)
+		}
+	}
+
+	modes, vlanDriver := networkModes[createReq.Driver]
+	if !vlanDriver {
+		if createReq.Parent != "" || createReq.Mode != "" {
+			return nil, fmt.Errorf("parent interface requires macvlan or ipvlan driver")
+		}
+		return options, nil
+	}
+	if createReq.Parent == "" && !createReq.Internal {
+		return nil, fmt.Errorf("parent interface is required")
+	}
+	if createReq.Parent != "" {
+		options[networkOptionParent] = createReq.Parent
+	}
+	if createReq.Mode != "" {
+		valid := false
+		for _, mode := range modes {
+			valid = valid || mode == createReq.Mode
+		}
+		if !valid {
+			return nil, fmt.Errorf("invalid %s mode: %s", createReq.Driver, createReq.Mode)
+		}
+		key := networkOptionMacvlan
+		if createReq.Driver == "ipvlan" {
+			key = networkOptionIPvlan
+		}
+		options[key] = createReq.Mode
+	}
+	return options, nil
+}
+
+// validateParentInterface 校验父接口存在于主机上；eth0.100 形式的 VLAN 子接口由 Docker 自动创建，只要求基础接口存在
+func validateParentInterface(parent string, interfaces []string) error {
+	base := parent
+	if i := strings.LastIndex(parent, "."); i > 0 {
+		if vlan, err := strconv.Atoi(parent[i+1:]); err == nil {
+			if vlan < 1 || vlan > 4094 {
+				return fmt.Errorf("invalid vlan id: %d", vlan)
+			}
+			base = parent[:i]
+		}
+	}
+	for _, name := range interfaces {
+		if name == parent || name == base {
+			return nil
+		}
+	}
+	return fmt.Errorf("parent interface not found on host: %s", parent)
+}
+
+// checkSubnetConflicts 检查地址池是否与已有网络的子网重叠
+func checkSubnetConflicts(configs []network.IPAMConfig, existing []types.NetworkResource) error {
+	for _, config := range configs {
+		_, subnet, err := net.ParseCIDR(config.Subnet)
+		if err != nil {
+			continue
+		}
+		for _, resource := range existing {
+			for _, other := range resource.IPAM.Config {
+				_, otherSubnet, err := net.ParseCIDR(other.Subnet)
+				if err != nil {
+					continue
+				}
+				if subnetsOverlap(subnet, otherSubnet) {
+					return fmt.Errorf("subnet %s overlaps with network %s (%s)", config.Subnet, resource.Name, other.Subnet)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func subnetsOverlap(a, b *net.IPNet) bool {
+	return a.Contains(b.IP) || b.Contains(a.IP)
+}
+
+// cidrContains inner 完全位于 outer 内
+func cidrContains(outer, inner *net.IPNet) bool {
+	outerOnes, _ := outer.Mask.Size()
+	innerOnes, _ := inner.Mask.Size()
+	return outer.Contains(inner.IP) && innerOnes >= outerOnes
+}
+
+// hostInterfaces 通过使用主机网络的辅助容器读取 Docker 主机的网络接口，远程主机与容器化部署均适用
+func (d *DockerNetworkService) hostInterfaces(ctx context.Context) ([]string, error) {
+	image := global.GVA_CONFIG.Docker.BackupImage
+	if image == "" {
+		image = defaultVolumeBackupImage
+	}
+	if err := ensureImage(ctx, d.streamCli(), image); err != nil {
+		return nil, err
+	}
+	created, err := d.cli().ContainerCreate(ctx, &container.Config{
+		Image:  image,
+		Cmd:    []string{"cat", "/proc/net/dev"},
+		Labels: map[string]string{networkHelperLabel: "interfaces"},
+	}, &container.HostConfig{NetworkMode: "host"}, nil, nil, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create helper container: %v", err)
+	}
+	defer func() {
+		removeCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := d.cli().ContainerRemove(removeCtx, created.ID, types.ContainerRemoveOptions{Force: true}); err != nil {
+			global.GVA_LOG.Warn("Failed to remove network helper container", zap.String("id", created.ID), zap.Error(err))
+		}
+	}()
+
+	if err := d.cli().ContainerStart(ctx, created.ID, types.ContainerStartOptions{}); err != nil {
+		return nil, fmt.Errorf("failed to start helper container: %v", err)
+	}
+	statusCh, errCh := d.cli().ContainerWait(ctx, created.ID, container.WaitConditionNotRunning)
+	select {
+	case err := <-errCh:
+		return nil, fmt.Errorf("failed to wait helper container: %v", err)
+	case status := <-statusCh:
+		if status.StatusCode != 0 {
+			return nil, fmt.Errorf("helper container exited with code %d", status.StatusCode)
+		}
+	}
+
+	logs, err := d.cli().ContainerLogs(ctx, created.ID, types.ContainerLogsOptions{ShowStdout: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read helper container output: %v", err)
+	}
+	defer logs.Close()
+	var stdout bytes.Buffer
+	if _, err := stdcopy.StdCopy(&stdout, &bytes.Buffer{}, logs); err != nil {
+		return nil, fmt.Errorf("failed to read helper container output: %v", err)
+	}
+	return parseNetDev(stdout.String()), nil
+}
+
+// parseNetDev 解析 /proc/net/dev 中的接口名称
+func parseNetDev(content string) []string {
+	var names []string
+	for _, line := range strings.Split(content, "\n") {
+		name, _, found := strings.Cut(line, ":")
+		if !found || strings.Contains(name, "|") {
+			continue
+		}
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,74 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/network"
+	dockerReq "github.com/flipped-aurora/gin-vue-admin/server/model/docker/request"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNetworkIPAMConfigs(t *testing.T) {
+	createReq := dockerReq.NetworkCreateRequest{
+		Subnet:     "10.10.0.0/16",
+		Gateway:    "10.10.0.1",
+		EnableIPv6: true,
+		IPAM: []dockerReq.NetworkIPAMConfig{{
+			Subnet:       "fd00:10::/64",
+			IPRange:      "fd00:10::/80",
+			AuxAddresses: map[string]string{"router": "fd00:10::2"},
+		}},
+	}
+	configs, err := networkIPAMConfigs(createReq)
+	require.NoError(t, err)
+	require.Len(t, configs, 2)
+	assert.Equal(t, "10.10.0.1", configs[0].Gateway)
+	assert.Equal(t, "fd00:10::2", configs[1].AuxAddress["router"])
+
+	for want, pools := range map[string][]dockerReq.NetworkIPAMConfig{
+		"subnets overlap: 10.10.0.0/16 and 10.10.5.0/24": {{Subnet: "10.10.5.0/24"}},
+		"gateway is not in subnet: 10.11.0.1":            {{Subnet: "10.20.0.0/24", Gateway: "10.11.0.1"}},
+		"ip range is not in subnet: 10.20.0.0/16":        {{Subnet: "10.20.0.0/24", IPRange: "10.20.0.0/16"}},
+		"aux address is not in subnet: fd00:20::1":       {{Subnet: "10.20.0.0/24", AuxAddresses: map[string]string{"host": "fd00:20::1"}}},
+	} {
+		createReq.IPAM = pools
+		_, err := networkIPAMConfigs(createReq)
+		assert.EqualError(t, err, want)
+	}
+
+	_, err = networkIPAMConfigs(dockerReq.NetworkCreateRequest{Subnet: "fd00:30::/64"})
+	assert.EqualError(t, err, "ipv6 subnet requires enableIPv6: fd00:30::/64")
+}
+
+func TestNetworkDriverOptions(t *testing.T) {
+	icc := false
+	options, err := networkDriverOptions(dockerReq.NetworkCreateRequest{Driver: "bridge", MTU: 1450, BridgeName: "br-app", ICC: &icc, Options: map[string]string{networkOptionMTU: "1500"}})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{networkOptionMTU: "1450", networkOptionBridgeName: "br-app", networkOptionICC: "false"}, options)
+
+	options, err = networkDriverOptions(dockerReq.NetworkCreateRequest{Driver: "ipvlan", Parent: "eth0.100", Mode: "l3"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{networkOptionParent: "eth0.100", networkOptionIPvlan: "l3"}, options)
+
+	_, err = networkDriverOptions(dockerReq.NetworkCreateRequest{Driver: "macvlan", ICC: &icc})
+	assert.EqualError(t, err, "bridge options require bridge driver")
+	_, err = networkDriverOptions(dockerReq.NetworkCreateRequest{Driver: "macvlan"})
+	assert.EqualError(t, err, "parent interface is required")
+	_, err = networkDriverOptions(dockerReq.NetworkCreateRequest{Driver: "macvlan", Parent: "eth0", Mode: "l2"})
+	assert.EqualError(t, err, "invalid macvlan mode: l2")
+
+	interfaces := parseNetDev("Inter-|   Receive\n face |bytes\n    lo: 0 0\n  eth0: 1 2\n")
+	assert.Equal(t, []string{"eth0", "lo"}, interfaces)
+	assert.NoError(t, validateParentInterface("eth0.100", interfaces))
+	assert.EqualError(t, validateParentInterface("eth0.5000", interfaces), "invalid vlan id: 5000")
+	assert.EqualError(t, validateParentInterface("ens3", interfaces), "parent interface not found on host: ens3")
+}
+
+func TestCheckSubnetConflicts(t *testing.T) {
+	existing := []types.NetworkResource{{Name: "bridge", IPAM: network.IPAM{Config: []network.IPAMConfig{{Subnet: "172.17.0.0/16"}}}}}
+	assert.NoError(t, checkSubnetConflicts([]network.IPAMConfig{{Subnet: "172.18.0.0/16"}}, existing))
+	assert.EqualError(t, checkSubnetConflicts([]network.IPAMConfig{{Subnet: "172.16.0.0/12"}}, existing),
+		"subnet 172.16.0.0/12 overlaps with network bridge (172.17.0.0/16)")
+}
@@ -0,0 +1,194 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/client"
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	dockerReq "github.com/flipped-aurora/gin-vue-admin/server/model/docker/request"
+	dockerRes "github.com/flipped-aurora/gin-vue-admin/server/model/docker/response"
+	"go.uber.org/zap"
+)
+
+// ConnectContainer 将容器连接到网络，可指定静态IP与别名；静态IP需在网络的子网内
+func (d *DockerNetworkService) ConnectContainer(networkID string, connectReq dockerReq.NetworkConnectRequest) error {
+	if d.cli() == nil {
+		return fmt.Errorf("Docker client is not available")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	resource, containerJSON, err := d.inspectMembership(ctx, networkID, connectReq.Container)
+	if err != nil {
+		return err
+	}
+	if containerJSON.NetworkSettings != nil {
+		if _, ok := containerJSON.NetworkSettings.Networks[resource.Name]; ok {
+			return fmt.Errorf("container is already connected to network")
+		}
+	}
+
+	settings := &network.EndpointSettings{Aliases: compactAliases(connectReq.Aliases)}
+	if connectReq.IPv4Address != "" || connectReq.IPv6Address != "" {
+		if err := validateStaticIPs(resource, connectReq.IPv4Address, connectReq.IPv6Address); err != nil {
+			return err
+		}
+		settings.IPAMConfig = &network.EndpointIPAMConfig{IPv4Address: connectReq.IPv4Address, IPv6Address: connectReq.IPv6Address}
+	}
+
+	if err := d.cli().NetworkConnect(ctx, resource.ID, containerJSON.ID, settings); err != nil {
+		global.GVA_LOG.Error("Failed to connect container to network", zap.String("network", resource.Name), zap.String("container", connectReq.Container), zap.Error(err))
+		return fmt.Errorf("failed to connect container: %v", err)
+	}
+
+	global.GVA_LOG.Info("Container connected to network", zap.String("network", resource.Name), zap.String("container", strings.TrimPrefix(containerJSON.Name, "/")))
+	return nil
+}
+
+// DisconnectContainer 将容器从网络断开
+func (d *DockerNetworkService) DisconnectContainer(networkID string, disconnectReq dockerReq.NetworkDisconnectRequest) error {
+	if d.cli() == nil {
+		return fmt.Errorf("Docker client is not available")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	resource, containerJSON, err := d.inspectMembership(ctx, networkID, disconnectReq.Container)
+	if err != nil {
+		return err
+	}
+	if containerJSON.NetworkSettings == nil || containerJSON.NetworkSettings.Networks[resource.Name] == nil {
+		return fmt.Errorf("container is not connected to network")
+	}
+
+	if err := d.cli().NetworkDisconnect(ctx, resource.ID, containerJSON.ID, disconnectReq.Force); err != nil {
+		global.GVA_LOG.Error("Failed to disconnect container from network", zap.String("network", resource.Name), zap.String("container", disconnectReq.Container), zap.Error(err))
+		return fmt.Errorf("failed to disconnect container: %v", err)
+	}
+
+	global.GVA_LOG.Info("Container disconnected from network", zap.String("network", resource.Name), zap.String("container", strings.TrimPrefix(containerJSON.Name, "/")))
+	return nil
+}
+
+// inspectMembership 查询网络与容器
+func (d *DockerNetworkService) inspectMembership(ctx context.Context, networkID, containerID string) (types.NetworkResource, types.ContainerJSON, error) {
+	resource, err := d.cli().NetworkInspect(ctx, networkID, types.NetworkInspectOptions{})
+	if err != nil {
+		if client.IsErrNotFound(err) {
+			return resource, types.ContainerJSON{}, fmt.Errorf("network not found")
+		}
+		return resource, types.ContainerJSON{}, fmt.Errorf("failed to inspect network: %v", err)
+	}
+	containerJSON, err := d.cli().ContainerInspect(ctx, containerID)
+	if err != nil {
+		if client.IsErrNotFound(err) {
+			return resource, containerJSON, fmt.Errorf("container not found")
+		}
+		return resource, containerJSON, fmt.Errorf("failed to inspect container: %v", err)
+	}
+	return resource, containerJSON, nil
+}
+
+// attachNetworkMembers 补充网络中容器的名称、别名、静态IP与编排归属，并加入已连接但未运行的容器
+func (d *DockerNetworkService) attachNetworkMembers(ctx context.Context, detail *dockerRes.NetworkDetail, resource types.NetworkResource) error {
+	containers, err := d.cli().ContainerList(ctx, types.ContainerListOptions{
+		All:     true,
+		Filters: filters.NewArgs(filters.Arg("network", resource.ID)),
+	})
+	if err != nil {
+		return err
+	}
+	for _, ctn := range containers {
+		member := detail.Containers[ctn.ID]
+		if member.Name == "" && len(ctn.Names) > 0 {
+			member.Name = strings.TrimPrefix(ctn.Names[0], "/")
+		}
+		member.State = ctn.State
+		member.Orchestration = containerOrchestrationName(ctn.Labels)
+		member.Service = ctn.Labels[composeServiceLabel]
+		if ctn.NetworkSettings != nil {
+			if endpoint := ctn.NetworkSettings.Networks[resource.Name]; endpoint != nil {
+				member.Aliases = endpoint.Aliases
+				if endpoint.IPAMConfig != nil {
+					member.StaticIPv4 = endpoint.IPAMConfig.IPv4Address
+					member.StaticIPv6 = endpoint.IPAMConfig.IPv6Address
+				}
+				if member.IPv4Address == "" && endpoint.IPAddress != "" {
+					member.IPv4Address = fmt.Sprintf("%s/%d", endpoint.IPAddress, endpoint.IPPrefixLen)
+				}
+				if member.MacAddress == "" {
+					member.MacAddress = endpoint.MacAddress
+				}
+			}
+		}
+		detail.Containers[ctn.ID] = member
+	}
+	return nil
+}
+
+// validateStaticIPs 校验静态IP的地址族，并要求位于网络已配置的子网内
+func validateStaticIPs(resource types.NetworkResource, ipv4, ipv6 string) error {
+	var subnets []*net.IPNet
+	for _, config := range resource.IPAM.Config {
+		if _, subnet, err := net.ParseCIDR(config.Subnet); err == nil {
+			subnets = append(subnets, subnet)
+		}
+	}
+	for _, addr := range []struct {
+		value string
+		v4    bool
+	}{{ipv4, true}, {ipv6, false}} {
+		if addr.value == "" {
+			continue
+		}
+		ip := net.ParseIP(addr.value)
+		if ip == nil || (ip.To4() != nil) != addr.v4 {
+			return fmt.Errorf("invalid ip address: %s", addr.value)
+		}
+		inSubnet := false
+		for _, subnet := range subnets {
+			if subnet.Contains(ip) {
+				inSubnet = true
+				break
+			}
+		}
+		if !inSubnet {
+			return fmt.Errorf("ip address is not in network subnet: %s", addr.value)
+		}
+	}
+	return nil
+}
+
+// compactAliases 去除空白与重复的别名
+func compactAliases(aliases []string) []string {
+	seen := make(map[string]bool, len(aliases))
+	result := make([]string, 0, len(aliases))
+	for _, alias := range aliases {
+		alias = strings.TrimSpace(alias)
+		if alias == "" || seen[alias] {
+			continue
+		}
+		seen[alias] = true
+		result = append(result, alias)
+	}
+	return result
+}
+
+// containerOrchestrationName 容器所属编排：自定义 orchestration 标签、Compose 项目或 1Panel 项目/应用标签
+func containerOrchestrationName(labels map[string]string) string {
+	for _, key := range []string{"orchestration", composeProjectLabel, "com.1panel.compose.project", "1panel.app"} {
+		if value := labels[key]; value != "" {
+			return value
+		}
+	}
+	return ""
+}
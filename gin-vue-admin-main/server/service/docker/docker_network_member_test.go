@@ -0,0 +1,30 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/network"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateStaticIPs(t *testing.T) {
+	resource := types.NetworkResource{IPAM: network.IPAM{Config: []network.IPAMConfig{
+		{Subnet: "172.30.0.0/16"},
+		{Subnet: "fd00:30::/64"},
+	}}}
+
+	assert.NoError(t, validateStaticIPs(resource, "172.30.1.10", "fd00:30::10"))
+	assert.EqualError(t, validateStaticIPs(resource, "172.31.0.10", ""), "ip address is not in network subnet: 172.31.0.10")
+	assert.EqualError(t, validateStaticIPs(resource, "fd00:30::10", ""), "invalid ip address: fd00:30::10")
+	assert.EqualError(t, validateStaticIPs(resource, "", "172.30.1.10"), "invalid ip address: 172.30.1.10")
+	// 未配置子网的网络不支持静态IP
+	assert.Error(t, validateStaticIPs(types.NetworkResource{}, "172.17.0.10", ""))
+}
+
+func TestContainerOrchestrationName(t *testing.T) {
+	assert.Equal(t, "shop", containerOrchestrationName(map[string]string{"orchestration": "shop", composeProjectLabel: "other"}))
+	assert.Equal(t, "blog", containerOrchestrationName(map[string]string{composeProjectLabel: "blog"}))
+	assert.Equal(t, "", containerOrchestrationName(nil))
+	assert.Equal(t, []string{"web", "api"}, compactAliases([]string{" web", "", "api", "web"}))
+}
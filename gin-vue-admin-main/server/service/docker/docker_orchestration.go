@@ -0,0 +1,553 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/client"
+	"github.com/docker/go-connections/nat"
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	dockerModel "github.com/flipped-aurora/gin-vue-admin/server/model/docker"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/docker/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/docker/response"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+	"gorm.io/gorm"
+)
+
+// orchestrationDeployTimeout 部署整个编排（含拉取镜像）的超时时间
+const orchestrationDeployTimeout = 10 * time.Minute
+
+// CreateOrchestration 创建编排并部署
+func (d *DockerContainerService) CreateOrchestration(req request.OrchestrationCreateRequest) (*response.OrchestrationDeployResult, error) {
+	if d.cli() == nil {
+		return nil, fmt.Errorf("Docker client is not available")
+	}
+	if err := validateComposeProjectName(req.Name); err != nil {
+		return nil, err
+	}
+
+	var count int64
+	if err := global.GVA_DB.Model(&dockerModel.DockerOrchestration{}).Where("host_id = ? AND name = ?", d.hostID(), req.Name).Count(&count).Error; err != nil {
+		return nil, fmt.Errorf("failed to query orchestration: %v", err)
+	}
+	if count > 0 {
+		return nil, fmt.Errorf("orchestration already exists")
+	}
+
+	content, project, err := resolveOrchestrationContent(req.ComposeContent, req.Services, req.WorkingDir, req.EnvFile)
+	if err != nil {
+		return nil, err
+	}
+
+	record := &dockerModel.DockerOrchestration{
+		HostID:         d.hostID(),
+		Name:           req.Name,
+		Description:    req.Description,
+		ComposeContent: content,
+		Source:         "manual",
+		WorkingDir:     req.WorkingDir,
+		EnvFile:        req.EnvFile,
+	}
+	return d.deployOrchestration(record, project)
+}
+
+// UpdateOrchestration 更新编排内容并重新部署，仅重建配置发生变化的服务
+// 对于尚未入库但已存在同名 compose 项目的编排，会以 imported 来源接管
+func (d *DockerContainerService) UpdateOrchestration(name string, req request.OrchestrationUpdateRequest) (*response.OrchestrationDeployResult, error) {
+	if d.cli() == nil {
+		return nil, fmt.Errorf("Docker client is not available")
+	}
+
+	var record dockerModel.DockerOrchestration
+	err := global.GVA_DB.Where("host_id = ? AND name = ?", d.hostID(), name).First(&record).Error
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("failed to query orchestration: %v", err)
+		}
+		existing, err := listProjectContainers(context.Background(), d.cli(), name)
+		if err != nil {
+			return nil, err
+		}
+		if len(existing) == 0 {
+			return nil, fmt.Errorf("orchestration not found")
+		}
+		if err := validateComposeProjectName(name); err != nil {
+			return nil, err
+		}
+		record = dockerModel.DockerOrchestration{HostID: d.hostID(), Name: name, Source: "imported"}
+	}
+
+	content, project, err := resolveOrchestrationContent(req.ComposeContent, req.Services, req.WorkingDir, req.EnvFile)
+	if err != nil {
+		return nil, err
+	}
+
+	record.Description = req.Description
+	record.ComposeContent = content
+	record.WorkingDir = req.WorkingDir
+	record.EnvFile = req.EnvFile
+	return d.deployOrchestration(&record, project)
+}
+
+// resolveOrchestrationContent 解析 compose 内容或结构化服务列表，返回最终保存的 compose 文本
+func resolveOrchestrationContent(content string, services []request.ServiceConfig, workingDir, envFile string) (string, *dockerModel.ComposeProject, error) {
+	if strings.TrimSpace(content) != "" {
+		project, err := loadComposeProject(content, workingDir, envFile)
+		return content, project, err
+	}
+	if len(services) == 0 {
+		return "", nil, fmt.Errorf("compose content or services is required")
+	}
+	project, err := buildComposeFromServices(services)
+	if err != nil {
+		return "", nil, err
+	}
+	data, err := yaml.Marshal(project)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to generate compose content: %v", err)
+	}
+	// 结构化配置生成的内容同样走一遍解析，保证与保存的 compose 文本一致
+	project, err = loadComposeProject(string(data), workingDir, envFile)
+	return string(data), project, err
+}
+
+// deployOrchestration 按依赖顺序创建网络、存储卷和容器，并保存编排记录
+func (d *DockerContainerService) deployOrchestration(record *dockerModel.DockerOrchestration, project *dockerModel.ComposeProject) (*response.OrchestrationDeployResult, error) {
+	order, err := sortComposeServices(project)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), orchestrationDeployTimeout)
+	defer cancel()
+
+	name := record.Name
+	result := &response.OrchestrationDeployResult{Name: name}
+
+	networkNames, err := ensureComposeNetworks(ctx, d.cli(), name, project, result)
+	if err != nil {
+		return result, err
+	}
+	volumeNames, err := ensureComposeVolumes(ctx, d.cli(), name, project, result)
+	if err != nil {
+		return result, err
+	}
+
+	existing, err := listProjectContainers(ctx, d.cli(), name)
+	if err != nil {
+		return result, err
+	}
+	byService := make(map[string][]types.Container)
+	for _, ctn := range existing {
+		byService[ctn.Labels[composeServiceLabel]] = append(byService[ctn.Labels[composeServiceLabel]], ctn)
+	}
+
+	containerIDs := make(map[string]string, len(order))
+	for _, serviceName := range order {
+		svc := project.Services[serviceName]
+		spec, err := buildComposeContainerSpec(name, record.WorkingDir, serviceName, svc, networkNames, volumeNames, containerIDs)
+		if err != nil {
+			return result, partialDeployError(result, err)
+		}
+
+		current := byService[serviceName]
+		delete(byService, serviceName)
+		if len(current) == 1 && current[0].Labels[composeConfigHashLabel] == spec.Hash {
+			containerIDs[serviceName] = current[0].ID
+			if current[0].State != "running" {
+				if err := d.cli().ContainerStart(ctx, current[0].ID, types.ContainerStartOptions{}); err != nil {
+					return result, partialDeployError(result, fmt.Errorf("failed to start service %s: %v", serviceName, err))
+				}
+			}
+			result.Unchanged = append(result.Unchanged, serviceName)
+			continue
+		}
+
+		for _, ctn := range current {
+			if err := d.cli().ContainerRemove(ctx, ctn.ID, types.ContainerRemoveOptions{Force: true}); err != nil && !client.IsErrNotFound(err) {
+				return result, partialDeployError(result, fmt.Errorf("failed to remove old container of service %s: %v", serviceName, err))
+			}
+		}
+
+		// 容器名固定，只能先删除旧容器再创建；创建失败时该服务已没有容器，在结果与错误中列出
+		id, err := createContainerFromSpec(ctx, d.streamCli(), spec, true)
+		if err != nil {
+			if len(current) > 0 {
+				result.Down = append(result.Down, serviceName)
+			}
+			return result, partialDeployError(result, fmt.Errorf("service %s: %v", serviceName, err))
+		}
+		containerIDs[serviceName] = id
+		if len(current) > 0 {
+			result.Recreated = append(result.Recreated, serviceName)
+		} else {
+			result.Created = append(result.Created, serviceName)
+		}
+	}
+
+	// 删除编排中已移除的服务
+	for serviceName, containers := range byService {
+		for _, ctn := range containers {
+			if err := d.cli().ContainerRemove(ctx, ctn.ID, types.ContainerRemoveOptions{Force: true}); err != nil && !client.IsErrNotFound(err) {
+				return result, partialDeployError(result, fmt.Errorf("failed to remove container of deleted service %s: %v", serviceName, err))
+			}
+		}
+		result.Removed = append(result.Removed, serviceName)
+	}
+	sort.Strings(result.Removed)
+	removeStaleComposeNetworks(ctx, d.cli(), name, networkNames)
+
+	if err := saveOrchestrationRecord(d.hostID(), record, project, order, containerIDs); err != nil {
+		return result, partialDeployError(result, err)
+	}
+
+	global.GVA_LOG.Info("Orchestration deployed successfully",
+		zap.String("name", name),
+		zap.Strings("created", result.Created),
+		zap.Strings("recreated", result.Recreated),
+		zap.Strings("removed", result.Removed))
+	return result, nil
+}
+
+// partialDeployError 部署中断时在错误中列出已生效的容器变更，便于判断各服务当前的状态
+func partialDeployError(result *response.OrchestrationDeployResult, err error) error {
+	var changes []string
+	for _, change := range []struct {
+		label    string
+		services []string
+	}{
+		{"created", result.Created},
+		{"recreated", result.Recreated},
+		{"removed", result.Removed},
+		{"old container removed without replacement", result.Down},
+	} {
+		if len(change.services) > 0 {
+			changes = append(changes, change.label+": "+strings.Join(change.services, ", "))
+		}
+	}
+	if len(changes) == 0 {
+		return err
+	}
+	return fmt.Errorf("%v; deployment stopped partway (%s)", err, strings.Join(changes, "; "))
+}
+
+// listProjectContainers 获取带有指定 compose 项目标签的所有容器
+func listProjectContainers(ctx context.Context, cli *client.Client, projectName string) ([]types.Container, error) {
+	containers, err := cli.ContainerList(ctx, types.ContainerListOptions{
+		All:     true,
+		Filters: filters.NewArgs(filters.Arg("label", composeProjectLabel+"="+projectName)),
+	})
+	if err != nil {
+		global.GVA_LOG.Error("Failed to list orchestration containers", zap.String("name", projectName), zap.Error(err))
+		return nil, fmt.Errorf("failed to get container list: %v", err)
+	}
+	return containers, nil
+}
+
+// ensureComposeNetworks 创建编排使用的网络，返回 compose 键到实际网络名的映射
+func ensureComposeNetworks(ctx context.Context, cli *client.Client, projectName string, project *dockerModel.ComposeProject, result *response.OrchestrationDeployResult) (map[string]string, error) {
+	used := map[string]bool{}
+	for _, svc := range project.Services {
+		for _, key := range composeServiceNetworks(svc) {
+			used[key] = true
+		}
+	}
+	for key := range project.Networks {
+		used[key] = true
+	}
+
+	keys := make([]string, 0, len(used))
+	for key := range used {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	names := make(map[string]string, len(keys))
+	for _, key := range keys {
+		cfg, declared := project.Networks[key]
+		if !declared && key != composeDefaultNetwork {
+			return nil, fmt.Errorf("network %s is not declared in top-level networks", key)
+		}
+		if cfg == nil {
+			cfg = &dockerModel.ComposeNetwork{}
+		}
+
+		if cfg.External {
+			actual := key
+			if cfg.Name != "" {
+				actual = cfg.Name
+			}
+			if _, err := cli.NetworkInspect(ctx, actual, types.NetworkInspectOptions{}); err != nil {
+				return nil, fmt.Errorf("external network %s not found: %v", actual, err)
+			}
+			names[key] = actual
+			continue
+		}
+
+		actual := composeResourceName(projectName, key, cfg.Name)
+		names[key] = actual
+		if _, err := cli.NetworkInspect(ctx, actual, types.NetworkInspectOptions{}); err == nil {
+			continue
+		} else if !client.IsErrNotFound(err) {
+			return nil, fmt.Errorf("failed to inspect network %s: %v", actual, err)
+		}
+
+		labels := map[string]string{}
+		for k, v := range cfg.Labels {
+			labels[k] = v
+		}
+		labels[composeProjectLabel] = projectName
+		labels[composeNetworkLabel] = key
+		driver := cfg.Driver
+		if driver == "" {
+			driver = "bridge"
+		}
+		if _, err := cli.NetworkCreate(ctx, actual, types.NetworkCreate{
+			CheckDuplicate: true,
+			Driver:         driver,
+			Options:        cfg.DriverOpts,
+			Internal:       cfg.Internal,
+			Attachable:     cfg.Attachable,
+			Labels:         labels,
+		}); err != nil {
+			return nil, fmt.Errorf("failed to create network %s: %v", actual, err)
+		}
+		result.Networks = append(result.Networks, actual)
+	}
+	return names, nil
+}
+
+// ensureComposeVolumes 创建编排声明的存储卷，返回 compose 键到实际卷名的映射
+func ensureComposeVolumes(ctx context.Context, cli *client.Client, projectName string, project *dockerModel.ComposeProject, result *response.OrchestrationDeployResult) (map[string]string, error) {
+	keys := make([]string, 0, len(project.Volumes))
+	for key := range project.Volumes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	names := make(map[string]string, len(keys))
+	for _, key := range keys {
+		cfg := project.Volumes[key]
+		if cfg == nil {
+			cfg = &dockerModel.ComposeVolume{}
+		}
+
+		if cfg.External {
+			actual := key
+			if cfg.Name != "" {
+				actual = cfg.Name
+			}
+			if _, err := cli.VolumeInspect(ctx, actual); err != nil {
+				return nil, fmt.Errorf("external volume %s not found: %v", actual, err)
+			}
+			names[key] = actual
+			continue
+		}
+
+		actual := composeResourceName(projectName, key, cfg.Name)
+		names[key] = actual
+		if _, err := cli.VolumeInspect(ctx, actual); err == nil {
+			continue
+		} else if !client.IsErrNotFound(err) {
+			return nil, fmt.Errorf("failed to inspect volume %s: %v", actual, err)
+		}
+
+		labels := map[string]string{}
+		for k, v := range cfg.Labels {
+			labels[k] = v
+		}
+		labels[composeProjectLabel] = projectName
+		labels[composeVolumeLabel] = key
+		if _, err := cli.VolumeCreate(ctx, volume.VolumeCreateBody{
+			Name:       actual,
+			Driver:     cfg.Driver,
+			DriverOpts: cfg.DriverOpts,
+			Labels:     labels,
+		}); err != nil {
+			return nil, fmt.Errorf("failed to create volume %s: %v", actual, err)
+		}
+		result.Volumes = append(result.Volumes, actual)
+	}
+	return names, nil
+}
+
+// removeStaleComposeNetworks 尽力删除编排不再使用的项目网络
+func removeStaleComposeNetworks(ctx context.Context, cli *client.Client, projectName string, inUse map[string]string) {
+	networks, err := cli.NetworkList(ctx, types.NetworkListOptions{
+		Filters: filters.NewArgs(filters.Arg("label", composeProjectLabel+"="+projectName)),
+	})
+	if err != nil {
+		return
+	}
+	keep := make(map[string]bool, len(inUse))
+	for _, actual := range inUse {
+		keep[actual] = true
+	}
+	for _, nw := range networks {
+		if keep[nw.Name] {
+			continue
+		}
+		if err := cli.NetworkRemove(ctx, nw.ID); err != nil {
+			global.GVA_LOG.Warn("Failed to remove stale orchestration network", zap.String("network", nw.Name), zap.Error(err))
+		}
+	}
+}
+
+// createContainerFromSpec 确保镜像存在，创建容器并连接附加网络，start 为 true 时启动容器
+func createContainerFromSpec(ctx context.Context, cli *client.Client, spec *containerCreateSpec, start bool) (string, error) {
+	if err := ensureImage(ctx, cli, spec.Config.Image); err != nil {
+		return "", err
+	}
+
+	created, err := cli.ContainerCreate(ctx, spec.Config, spec.HostConfig, spec.Networking, nil, spec.Name)
+	if err != nil {
+		return "", fmt.Errorf("failed to create container: %v", err)
+	}
+	for networkName, endpoint := range spec.ExtraNetworks {
+		if err := cli.NetworkConnect(ctx, networkName, created.ID, endpoint); err != nil {
+			return created.ID, fmt.Errorf("failed to connect network %s: %v", networkName, err)
+		}
+	}
+	if !start {
+		return created.ID, nil
+	}
+	if err := cli.ContainerStart(ctx, created.ID, types.ContainerStartOptions{}); err != nil {
+		return created.ID, fmt.Errorf("failed to start container: %v", err)
+	}
+	return created.ID, nil
+}
+
+// ensureImage 镜像不存在时拉取
+func ensureImage(ctx context.Context, cli *client.Client, image string) error {
+	if _, _, err := cli.ImageInspectWithRaw(ctx, image); err == nil {
+		return nil
+	} else if !client.IsErrNotFound(err) {
+		return fmt.Errorf("failed to inspect image %s: %v", image, err)
+	}
+	return pullImage(ctx, cli, image)
+}
+
+// pullImage 拉取镜像并等待完成
+func pullImage(ctx context.Context, cli *client.Client, image string) error {
+	registryAuth, err := registryAuthForImage(image)
+	if err != nil {
+		return err
+	}
+	reader, err := cli.ImagePull(ctx, image, types.ImagePullOptions{RegistryAuth: registryAuth})
+	if err != nil {
+		return fmt.Errorf("failed to pull image %s: %v", image, err)
+	}
+	defer reader.Close()
+	if _, err := readDockerJSONStream(reader); err != nil {
+		return fmt.Errorf("failed to pull image %s: %v", image, err)
+	}
+	return nil
+}
+
+// saveOrchestrationRecord 保存编排及其服务配置，编排记录属于 hostID 所在主机，名称在同一主机内唯一
+func saveOrchestrationRecord(hostID uint, record *dockerModel.DockerOrchestration, project *dockerModel.ComposeProject, order []string, containerIDs map[string]string) error {
+	now := time.Now()
+	record.HostID = hostID
+	record.Status = "running"
+	record.ContainerCount = len(containerIDs)
+	record.ApplicationCount = len(project.Services)
+	record.LastStartTime = &now
+
+	return global.GVA_DB.Transaction(func(tx *gorm.DB) error {
+		if record.ID == 0 {
+			// 部署期间同一主机上已创建同名编排
+			var count int64
+			if err := tx.Model(&dockerModel.DockerOrchestration{}).Where("host_id = ? AND name = ?", hostID, record.Name).Count(&count).Error; err != nil {
+				return fmt.Errorf("failed to query orchestration: %v", err)
+			}
+			if count > 0 {
+				return fmt.Errorf("orchestration already exists")
+			}
+		}
+		if err := tx.Save(record).Error; err != nil {
+			return fmt.Errorf("failed to save orchestration: %v", err)
+		}
+		if err := tx.Where("orchestration_id = ?", record.ID).Delete(&dockerModel.DockerOrchestrationService{}).Error; err != nil {
+			return fmt.Errorf("failed to clear orchestration services: %v", err)
+		}
+		for _, serviceName := range order {
+			svc := project.Services[serviceName]
+			row := dockerModel.DockerOrchestrationService{
+				OrchestrationID: record.ID,
+				ServiceName:     serviceName,
+				Image:           svc.Image,
+				ContainerName:   composeContainerName(record.Name, serviceName, svc),
+				Ports:           marshalJSONString(composePortMappings(svc.Ports)),
+				Volumes:         marshalJSONString(composeVolumeMounts(svc.Volumes)),
+				Environment:     marshalJSONString(svc.Environment),
+				RestartPolicy:   svc.Restart,
+				NetworkMode:     svc.NetworkMode,
+				DependsOn:       marshalJSONString(svc.DependsOn),
+				Command:         marshalJSONString(svc.Command),
+				Status:          "running",
+				ContainerID:     containerIDs[serviceName],
+			}
+			if row.RestartPolicy == "" {
+				row.RestartPolicy = "no"
+			}
+			if err := tx.Create(&row).Error; err != nil {
+				return fmt.Errorf("failed to save orchestration service %s: %v", serviceName, err)
+			}
+		}
+		return nil
+	})
+}
+
+// composePortMappings 将端口短语法转换为端口映射列表
+func composePortMappings(ports []dockerModel.ComposePort) []request.PortMapping {
+	var result []request.PortMapping
+	for _, port := range ports {
+		mappings, err := nat.ParsePortSpec(string(port))
+		if err != nil {
+			continue
+		}
+		for _, mapping := range mappings {
+			hostPort, _ := strconv.Atoi(mapping.Binding.HostPort)
+			result = append(result, request.PortMapping{
+				HostIP:        mapping.Binding.HostIP,
+				HostPort:      hostPort,
+				ContainerPort: mapping.Port.Int(),
+				Protocol:      mapping.Port.Proto(),
+			})
+		}
+	}
+	return result
+}
+
+// composeVolumeMounts 将卷挂载转换为存储格式
+func composeVolumeMounts(volumes []dockerModel.ComposeServiceVolume) []request.VolumeMount {
+	result := make([]request.VolumeMount, 0, len(volumes))
+	for _, vol := range volumes {
+		result = append(result, request.VolumeMount{
+			HostPath:      vol.Source,
+			ContainerPath: vol.Target,
+			ReadOnly:      vol.ReadOnly,
+			Type:          vol.Type,
+		})
+	}
+	return result
+}
+
+// marshalJSONString 序列化为 JSON 字符串，空值返回空串
+func marshalJSONString(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil || string(data) == "null" {
+		return ""
+	}
+	return string(data)
+}
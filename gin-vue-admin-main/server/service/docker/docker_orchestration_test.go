@@ -0,0 +1,56 @@
+package docker
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/client"
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	dockerModel "github.com/flipped-aurora/gin-vue-admin/server/model/docker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestDeployOrchestrationReportsPartialState(t *testing.T) {
+	var removed []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.Contains(r.URL.Path, "/networks/"):
+			fmt.Fprint(w, `{"Name":"demo_default"}`)
+		case strings.HasSuffix(r.URL.Path, "/containers/json"):
+			fmt.Fprintf(w, `[{"Id":"old","State":"running","Labels":{%q:"demo",%q:"app",%q:"stale"}}]`,
+				composeProjectLabel, composeServiceLabel, composeConfigHashLabel)
+		case r.Method == http.MethodDelete && strings.HasSuffix(r.URL.Path, "/containers/old"):
+			removed = append(removed, "old")
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			// 镜像检查失败，新容器无法创建
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, `{"message":"unavailable"}`)
+		}
+	}))
+	defer server.Close()
+
+	cli, err := client.NewClientWithOpts(client.WithHost("tcp://"+server.Listener.Addr().String()), client.WithVersion("1.41"))
+	require.NoError(t, err)
+	defer cli.Close()
+	oldDocker, oldStream := global.SetDocker(cli, cli)
+	defer global.SetDocker(oldDocker, oldStream)
+	oldLog := global.GVA_LOG
+	global.GVA_LOG = zap.NewNop()
+	defer func() { global.GVA_LOG = oldLog }()
+
+	project := &dockerModel.ComposeProject{Services: map[string]*dockerModel.ComposeService{"app": {Image: "app:1.0"}}}
+	result, err := (&DockerContainerService{}).deployOrchestration(&dockerModel.DockerOrchestration{Name: "demo"}, project)
+
+	require.Error(t, err)
+	assert.Equal(t, []string{"old"}, removed)
+	require.NotNil(t, result)
+	assert.Equal(t, []string{"app"}, result.Down)
+	assert.Contains(t, err.Error(), "deployment stopped partway (old container removed without replacement: app)")
+}
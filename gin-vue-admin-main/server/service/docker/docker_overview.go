@@ -14,12 +14,14 @@ import (
 	"go.uber.org/zap"
 )
 
-type DockerOverviewService struct{}
+type DockerOverviewService struct {
+	hostClient
+}
 
 // GetOverviewStats 获取Docker概览统计信息
 func (d *DockerOverviewService) GetOverviewStats() (*response.OverviewStats, error) {
 	// 检查Docker客户端是否可用
-	if global.GVA_DOCKER == nil {
+	if d.cli() == nil {
 		return nil, fmt.Errorf("Docker client is not available")
 	}
 
@@ -28,7 +30,7 @@ func (d *DockerOverviewService) GetOverviewStats() (*response.OverviewStats, err
 	defer cancel()
 
 	// 检查Docker连接
-	_, err := global.GVA_DOCKER.Ping(ctx)
+	_, err := d.cli().Ping(ctx)
 	if err != nil {
 		global.GVA_LOG.Error("Docker ping failed", zap.Error(err))
 		return nil, fmt.Errorf("Docker服务连接失败: %w", err)
@@ -89,7 +91,7 @@ func (d *DockerOverviewService) GetOverviewStats() (*response.OverviewStats, err
 // getContainerStats 获取容器统计信息
 func (d *DockerOverviewService) getContainerStats(ctx context.Context) (*response.ContainerStats, error) {
 	// 获取所有容器（包括停止的）
-	containers, err := global.GVA_DOCKER.ContainerList(ctx, types.ContainerListOptions{All: true})
+	containers, err := d.cli().ContainerList(ctx, types.ContainerListOptions{All: true})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list containers: %w", err)
 	}
@@ -132,7 +134,7 @@ func (d *DockerOverviewService) getContainerStats(ctx context.Context) (*respons
 // getImageStats 获取镜像统计信息
 func (d *DockerOverviewService) getImageStats(ctx context.Context) (*response.ImageStats, error) {
 	// 获取所有镜像（不包括中间层镜像）
-	images, err := global.GVA_DOCKER.ImageList(ctx, types.ImageListOptions{All: false})
+	images, err := d.cli().ImageList(ctx, types.ImageListOptions{All: false})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list images: %w", err)
 	}
@@ -161,7 +163,7 @@ func (d *DockerOverviewService) getImageStats(ctx context.Context) (*response.Im
 // getNetworkStats 获取网络统计信息
 func (d *DockerOverviewService) getNetworkStats(ctx context.Context) (*response.NetworkStats, error) {
 	// 获取所有网络
-	networks, err := global.GVA_DOCKER.NetworkList(ctx, types.NetworkListOptions{})
+	networks, err := d.cli().NetworkList(ctx, types.NetworkListOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list networks: %w", err)
 	}
@@ -197,7 +199,7 @@ func (d *DockerOverviewService) getNetworkStats(ctx context.Context) (*response.
 // getVolumeStats 获取存储卷统计信息
 func (d *DockerOverviewService) getVolumeStats(ctx context.Context) (*response.VolumeStats, error) {
 	// 获取所有存储卷
-	volumeResponse, err := global.GVA_DOCKER.VolumeList(ctx, filters.Args{})
+	volumeResponse, err := d.cli().VolumeList(ctx, filters.Args{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list volumes: %w", err)
 	}
@@ -237,13 +239,13 @@ func (d *DockerOverviewService) getVolumeStats(ctx context.Context) (*response.V
 // getSystemStats 获取系统统计信息
 func (d *DockerOverviewService) getSystemStats(ctx context.Context) (*response.SystemStats, error) {
 	// 获取Docker系统信息
-	info, err := global.GVA_DOCKER.Info(ctx)
+	info, err := d.cli().Info(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get docker info: %w", err)
 	}
 
 	// 获取Docker版本信息
-	version, err := global.GVA_DOCKER.ServerVersion(ctx)
+	version, err := d.cli().ServerVersion(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get docker version: %w", err)
 	}
@@ -272,7 +274,7 @@ func (d *DockerOverviewService) getSystemStats(ctx context.Context) (*response.S
 // GetConfigSummary 获取配置摘要信息
 func (d *DockerOverviewService) GetConfigSummary() (*response.ConfigSummary, error) {
 	// 检查Docker客户端是否可用
-	if global.GVA_DOCKER == nil {
+	if d.cli() == nil {
 		return nil, fmt.Errorf("Docker client is not available")
 	}
 
@@ -281,13 +283,13 @@ func (d *DockerOverviewService) GetConfigSummary() (*response.ConfigSummary, err
 	defer cancel()
 
 	// 获取Docker系统信息
-	info, err := global.GVA_DOCKER.Info(ctx)
+	info, err := d.cli().Info(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get docker info: %w", err)
 	}
 
 	// 获取Docker版本信息
-	version, err := global.GVA_DOCKER.ServerVersion(ctx)
+	version, err := d.cli().ServerVersion(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get docker version: %w", err)
 	}
@@ -358,7 +360,7 @@ func (d *DockerOverviewService) formatBytes(bytes int64) string {
 // GetDockerDiskUsage 获取Docker磁盘使用情况
 func (d *DockerOverviewService) GetDockerDiskUsage() (*response.DiskUsage, error) {
 	// 检查Docker客户端是否可用
-	if global.GVA_DOCKER == nil {
+	if d.cli() == nil {
 		return nil, fmt.Errorf("Docker client is not available")
 	}
 
@@ -367,7 +369,7 @@ func (d *DockerOverviewService) GetDockerDiskUsage() (*response.DiskUsage, error
 	defer cancel()
 
 	// 获取Docker磁盘使用情况
-	diskUsage, err := global.GVA_DOCKER.DiskUsage(ctx)
+	diskUsage, err := d.cli().DiskUsage(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get disk usage: %w", err)
 	}
@@ -2,338 +2,511 @@ package docker
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"regexp"
 	"strings"
 	"time"
 
-	"github.com/docker/docker/api/types"
 	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	dockerModel "github.com/flipped-aurora/gin-vue-admin/server/model/docker"
 	dockerReq "github.com/flipped-aurora/gin-vue-admin/server/model/docker/request"
 	dockerRes "github.com/flipped-aurora/gin-vue-admin/server/model/docker/response"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
+// registryTestTimeout 仓库连接测试超时时间
+const registryTestTimeout = 15 * time.Second
+
+// dockerHubHosts Docker Hub 的各种写法，镜像引用中统一为 docker.io
+var dockerHubHosts = map[string]bool{
+	"docker.io":            true,
+	"index.docker.io":      true,
+	"registry-1.docker.io": true,
+}
+
+var authChallengeParamPattern = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
 type DockerRegistryService struct{}
 
-// GetRegistryList 获取仓库列表 - 从Docker配置和实际使用情况获取
+// GetRegistryList 获取仓库列表，默认仓库排在最前
 func (d *DockerRegistryService) GetRegistryList(filter dockerReq.RegistryFilter) ([]dockerRes.RegistryInfo, int64, error) {
-	global.GVA_LOG.Info("开始从Docker获取仓库列表")
-	
-	// 检查Docker客户端是否可用
-	if global.GVA_DOCKER == nil {
-		global.GVA_LOG.Error("Docker客户端不可用")
-		return nil, 0, fmt.Errorf("Docker client is not available")
+	db := global.GVA_DB.Model(&dockerModel.DockerRegistry{})
+	if filter.Name != "" {
+		db = db.Where("name LIKE ?", "%"+filter.Name+"%")
 	}
-
-	// 获取Docker系统信息
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-
-	info, err := global.GVA_DOCKER.Info(ctx)
-	if err != nil {
-		global.GVA_LOG.Error("获取Docker系统信息失败", zap.Error(err))
-		return nil, 0, fmt.Errorf("获取Docker系统信息失败: %v", err)
-	}
-
-	// 构建仓库列表
-	var registries []dockerRes.RegistryInfo
-	registryMap := make(map[string]bool) // 用于去重
-	
-	// 添加默认的Docker Hub
-	dockerHub := dockerRes.RegistryInfo{
-		ID:          1,
-		Name:        "Docker Hub",
-		DownloadUrl: "https://registry-1.docker.io",
-		Protocol:    "https",
-		Status:      "active",
-		Description: "Docker官方仓库",
-		CreatedAt:   time.Now(),
-		UpdatedAt:   time.Now(),
-	}
-	registries = append(registries, dockerHub)
-	registryMap["https://registry-1.docker.io"] = true
-
-	// 从Docker信息中获取配置的仓库镜像
-	if info.RegistryConfig != nil && len(info.RegistryConfig.Mirrors) > 0 {
-		global.GVA_LOG.Info("找到Docker镜像仓库", zap.Int("count", len(info.RegistryConfig.Mirrors)))
-		for _, mirror := range info.RegistryConfig.Mirrors {
-			if !registryMap[mirror] {
-				registry := dockerRes.RegistryInfo{
-					ID:          uint(len(registries) + 1),
-					Name:        d.getRegistryName(mirror),
-					DownloadUrl: mirror,
-					Protocol:    d.getProtocol(mirror),
-					Status:      "active",
-					Description: "Docker仓库镜像",
-					CreatedAt:   time.Now(),
-					UpdatedAt:   time.Now(),
-				}
-				registries = append(registries, registry)
-				registryMap[mirror] = true
-			}
-		}
+	if filter.Protocol != "" {
+		db = db.Where("protocol = ?", filter.Protocol)
 	}
 
-	// 从Docker信息中获取不安全的仓库
-	if info.RegistryConfig != nil && len(info.RegistryConfig.InsecureRegistryCIDRs) > 0 {
-		global.GVA_LOG.Info("找到不安全的Docker仓库", zap.Int("count", len(info.RegistryConfig.InsecureRegistryCIDRs)))
-		for _, insecure := range info.RegistryConfig.InsecureRegistryCIDRs {
-			insecureUrl := insecure.String()
-			if !registryMap[insecureUrl] {
-				registry := dockerRes.RegistryInfo{
-					ID:          uint(len(registries) + 1),
-					Name:        d.getRegistryName(insecureUrl),
-					DownloadUrl: insecureUrl,
-					Protocol:    "http",
-					Status:      "active",
-					Description: "不安全的Docker仓库",
-					CreatedAt:   time.Now(),
-					UpdatedAt:   time.Now(),
-				}
-				registries = append(registries, registry)
-				registryMap[insecureUrl] = true
-			}
-		}
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		global.GVA_LOG.Error("查询仓库数量失败", zap.Error(err))
+		return nil, 0, fmt.Errorf("查询仓库列表失败: %v", err)
 	}
 
-	// 从现有镜像中推断使用的仓库
-	images, err := global.GVA_DOCKER.ImageList(ctx, types.ImageListOptions{})
-	if err == nil {
-		global.GVA_LOG.Info("分析现有镜像以推断仓库", zap.Int("image_count", len(images)))
-		for _, image := range images {
-			for _, repoTag := range image.RepoTags {
-				if repoTag != "<none>:<none>" {
-					registryUrl := d.extractRegistryFromImage(repoTag)
-					if registryUrl != "" && !registryMap[registryUrl] {
-						registry := dockerRes.RegistryInfo{
-							ID:          uint(len(registries) + 1),
-							Name:        d.getRegistryName(registryUrl),
-							DownloadUrl: registryUrl,
-							Protocol:    d.getProtocol(registryUrl),
-							Status:      "detected",
-							Description: "从镜像推断的仓库",
-							CreatedAt:   time.Now(),
-							UpdatedAt:   time.Now(),
-						}
-						registries = append(registries, registry)
-						registryMap[registryUrl] = true
-					}
-				}
-			}
-		}
+	if filter.Page > 0 && filter.PageSize > 0 {
+		db = db.Offset((filter.Page - 1) * filter.PageSize).Limit(filter.PageSize)
+	}
+	var registries []dockerModel.DockerRegistry
+	if err := db.Order("is_default desc, id asc").Find(&registries).Error; err != nil {
+		global.GVA_LOG.Error("查询仓库列表失败", zap.Error(err))
+		return nil, 0, fmt.Errorf("查询仓库列表失败: %v", err)
 	}
 
-	global.GVA_LOG.Info("从Docker获取到仓库数据", zap.Int("count", len(registries)))
-
-	// 应用过滤条件
-	var filteredRegistries []dockerRes.RegistryInfo
+	list := make([]dockerRes.RegistryInfo, 0, len(registries))
 	for _, registry := range registries {
-		// 名称过滤
-		if filter.Name != "" && !strings.Contains(registry.Name, filter.Name) {
-			continue
-		}
-		// 协议过滤
-		if filter.Protocol != "" && registry.Protocol != filter.Protocol {
-			continue
-		}
-		filteredRegistries = append(filteredRegistries, registry)
+		list = append(list, toRegistryInfo(registry))
 	}
+	return list, total, nil
+}
 
-	total := int64(len(filteredRegistries))
-
-	// 应用分页
-	if filter.Page > 0 && filter.PageSize > 0 {
-		start := (filter.Page - 1) * filter.PageSize
-		end := start + filter.PageSize
-		if start >= len(filteredRegistries) {
-			filteredRegistries = []dockerRes.RegistryInfo{}
-		} else {
-			if end > len(filteredRegistries) {
-				end = len(filteredRegistries)
-			}
-			filteredRegistries = filteredRegistries[start:end]
-		}
+// GetRegistryDetail 获取仓库详细信息
+func (d *DockerRegistryService) GetRegistryDetail(id uint) (*dockerRes.RegistryDetail, error) {
+	registry, err := getRegistryByID(id)
+	if err != nil {
+		return nil, err
 	}
 
-	global.GVA_LOG.Info("返回过滤后的仓库数据", zap.Int("filtered_count", len(filteredRegistries)), zap.Int64("total", total))
-	return filteredRegistries, total, nil
+	return &dockerRes.RegistryDetail{
+		RegistryInfo: toRegistryInfo(*registry),
+		LastTestTime: registry.LastTestTime,
+		TestResult:   registry.TestResult,
+	}, nil
 }
 
-// GetRegistryDetail 获取仓库详细信息
-func (d *DockerRegistryService) GetRegistryDetail(id uint) (*dockerRes.RegistryDetail, error) {
-	// 从Docker API获取仓库列表
-	registries, _, err := d.GetRegistryList(dockerReq.RegistryFilter{})
+// CreateRegistry 创建仓库，密码加密后保存
+func (d *DockerRegistryService) CreateRegistry(createReq dockerReq.RegistryCreateRequest) (*dockerRes.RegistryInfo, error) {
+	protocol, host, err := normalizeRegistryAddress(createReq.DownloadUrl, createReq.Protocol)
 	if err != nil {
 		return nil, err
 	}
+	if err := checkRegistryUnique(0, createReq.Name, host); err != nil {
+		return nil, err
+	}
 
-	// 查找指定ID的仓库
-	for _, registry := range registries {
-		if registry.ID == id {
-			return &dockerRes.RegistryDetail{
-				RegistryInfo: registry,
-				IsDefault:    registry.ID == 1, // Docker Hub为默认仓库
-				LastTestTime: nil,
-				TestResult:   "",
-			}, nil
+	registry := dockerModel.DockerRegistry{
+		Name:        createReq.Name,
+		DownloadUrl: protocol + "://" + host,
+		Protocol:    protocol,
+		Status:      "active",
+		Username:    createReq.Username,
+		Description: createReq.Description,
+	}
+	if createReq.Username != "" && createReq.Password != "" {
+		encrypted, err := utils.AesEncrypt(createReq.Password, registryCredentialKey())
+		if err != nil {
+			return nil, fmt.Errorf("加密仓库密码失败: %v", err)
 		}
+		registry.Password = encrypted
 	}
 
-	return nil, fmt.Errorf("仓库不存在")
-}
+	if err := global.GVA_DB.Create(&registry).Error; err != nil {
+		global.GVA_LOG.Error("创建仓库失败", zap.String("name", createReq.Name), zap.Error(err))
+		return nil, fmt.Errorf("创建仓库失败: %v", err)
+	}
 
-// CreateRegistry 创建仓库 - Docker仓库配置应该在Docker配置文件中管理
-func (d *DockerRegistryService) CreateRegistry(createReq dockerReq.RegistryCreateRequest) (*dockerRes.RegistryInfo, error) {
-	return nil, fmt.Errorf("Docker仓库配置应该在Docker daemon配置文件中管理")
+	info := toRegistryInfo(registry)
+	return &info, nil
 }
 
-// UpdateRegistry 更新仓库 - Docker仓库配置应该在Docker配置文件中管理
+// UpdateRegistry 更新仓库，密码为空时保持不变，用户名为空时清除凭据
 func (d *DockerRegistryService) UpdateRegistry(updateReq dockerReq.RegistryUpdateRequest) (*dockerRes.RegistryInfo, error) {
-	return nil, fmt.Errorf("Docker仓库配置应该在Docker daemon配置文件中管理")
+	registry, err := getRegistryByID(updateReq.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	protocol, host, err := normalizeRegistryAddress(updateReq.DownloadUrl, updateReq.Protocol)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkRegistryUnique(registry.ID, updateReq.Name, host); err != nil {
+		return nil, err
+	}
+
+	downloadUrl := protocol + "://" + host
+	credentialChanged := updateReq.Username != registry.Username || updateReq.Password != ""
+	if downloadUrl != registry.DownloadUrl || credentialChanged {
+		// 地址或凭据变化后之前的测试结果不再有效
+		registry.LastTestTime = nil
+		registry.TestResult = ""
+		registry.Status = "active"
+	}
+
+	registry.Name = updateReq.Name
+	registry.DownloadUrl = downloadUrl
+	registry.Protocol = protocol
+	registry.Username = updateReq.Username
+	registry.Description = updateReq.Description
+	switch {
+	case updateReq.Username == "":
+		registry.Password = ""
+	case updateReq.Password != "":
+		encrypted, err := utils.AesEncrypt(updateReq.Password, registryCredentialKey())
+		if err != nil {
+			return nil, fmt.Errorf("加密仓库密码失败: %v", err)
+		}
+		registry.Password = encrypted
+	}
+
+	if err := global.GVA_DB.Save(registry).Error; err != nil {
+		global.GVA_LOG.Error("更新仓库失败", zap.Uint("id", registry.ID), zap.Error(err))
+		return nil, fmt.Errorf("更新仓库失败: %v", err)
+	}
+
+	info := toRegistryInfo(*registry)
+	return &info, nil
 }
 
-// DeleteRegistry 删除仓库 - Docker仓库配置应该在Docker配置文件中管理
+// DeleteRegistry 删除仓库，直接删除记录以便名称可以复用
 func (d *DockerRegistryService) DeleteRegistry(id uint) error {
-	return fmt.Errorf("Docker仓库配置应该在Docker daemon配置文件中管理")
+	result := global.GVA_DB.Unscoped().Delete(&dockerModel.DockerRegistry{}, id)
+	if result.Error != nil {
+		global.GVA_LOG.Error("删除仓库失败", zap.Uint("id", id), zap.Error(result.Error))
+		return fmt.Errorf("删除仓库失败: %v", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("仓库不存在")
+	}
+	return nil
 }
 
-// TestRegistry 测试仓库连接
+// TestRegistry 使用保存的凭据访问仓库 /v2/ 接口验证连接与认证，并记录测试结果
 func (d *DockerRegistryService) TestRegistry(id uint) (*dockerRes.RegistryTestResponse, error) {
-	// 获取仓库详情
-	registryDetail, err := d.GetRegistryDetail(id)
+	registry, err := getRegistryByID(id)
 	if err != nil {
 		return nil, err
 	}
 
-	// 测试连接
-	success, message := d.testRegistryConnection(registryDetail.RegistryInfo)
+	password, err := decryptRegistryPassword(*registry)
+	if err != nil {
+		return nil, err
+	}
 
-	return &dockerRes.RegistryTestResponse{
-		Success: success,
-		Message: message,
-	}, nil
+	ctx, cancel := context.WithTimeout(context.Background(), registryTestTimeout)
+	defer cancel()
+
+	result := &dockerRes.RegistryTestResponse{Success: true, Message: "连接成功"}
+	if err := pingRegistry(ctx, registry.Protocol, registryHost(*registry), registry.Username, password); err != nil {
+		result.Success = false
+		result.Message = err.Error()
+	} else if registry.Username != "" {
+		result.Message = "连接成功，认证通过"
+	}
+
+	now := time.Now()
+	status := "active"
+	if !result.Success {
+		status = "error"
+	}
+	err = global.GVA_DB.Model(registry).Updates(map[string]interface{}{
+		"last_test_time": now,
+		"test_result":    result.Message,
+		"status":         status,
+	}).Error
+	if err != nil {
+		global.GVA_LOG.Error("保存仓库测试结果失败", zap.Uint("id", id), zap.Error(err))
+	}
+
+	return result, nil
 }
 
-// SetDefaultRegistry 设置默认仓库 - Docker仓库配置应该在Docker配置文件中管理
+// SetDefaultRegistry 设置默认仓库，拉取未指定仓库地址的镜像时使用
 func (d *DockerRegistryService) SetDefaultRegistry(id uint) error {
-	return fmt.Errorf("Docker仓库配置应该在Docker daemon配置文件中管理")
+	if _, err := getRegistryByID(id); err != nil {
+		return err
+	}
+
+	err := global.GVA_DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&dockerModel.DockerRegistry{}).Where("is_default = ?", true).Update("is_default", false).Error; err != nil {
+			return err
+		}
+		return tx.Model(&dockerModel.DockerRegistry{}).Where("id = ?", id).Update("is_default", true).Error
+	})
+	if err != nil {
+		global.GVA_LOG.Error("设置默认仓库失败", zap.Uint("id", id), zap.Error(err))
+		return fmt.Errorf("设置默认仓库失败: %v", err)
+	}
+	return nil
 }
 
-// testRegistryConnection 测试仓库连接
-func (d *DockerRegistryService) testRegistryConnection(registry dockerRes.RegistryInfo) (bool, string) {
-	// 构建测试URL
-	testUrl := registry.DownloadUrl
-	if !strings.HasSuffix(testUrl, "/") {
-		testUrl += "/"
+// ClearDefaultRegistry 取消默认仓库，恢复使用 Docker Hub
+func (d *DockerRegistryService) ClearDefaultRegistry() error {
+	err := global.GVA_DB.Model(&dockerModel.DockerRegistry{}).Where("is_default = ?", true).Update("is_default", false).Error
+	if err != nil {
+		return fmt.Errorf("取消默认仓库失败: %v", err)
 	}
-	testUrl += "v2/"
+	return nil
+}
 
-	// 创建HTTP客户端
-	client := &http.Client{
-		Timeout: 10 * time.Second,
+// getRegistryByID 按ID获取仓库记录
+func getRegistryByID(id uint) (*dockerModel.DockerRegistry, error) {
+	var registry dockerModel.DockerRegistry
+	if err := global.GVA_DB.First(&registry, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("仓库不存在")
+		}
+		return nil, fmt.Errorf("查询仓库失败: %v", err)
 	}
+	return &registry, nil
+}
 
-	// 创建请求
-	req, err := http.NewRequestWithContext(context.Background(), "GET", testUrl, nil)
+// checkRegistryUnique 名称与地址均不能重复，地址用于匹配镜像所属仓库
+func checkRegistryUnique(excludeID uint, name, host string) error {
+	var registries []dockerModel.DockerRegistry
+	if err := global.GVA_DB.Where("id <> ?", excludeID).Find(&registries).Error; err != nil {
+		return fmt.Errorf("查询仓库失败: %v", err)
+	}
+	for _, registry := range registries {
+		if registry.Name == name {
+			return fmt.Errorf("仓库名称已存在")
+		}
+		if registryHost(registry) == host {
+			return fmt.Errorf("仓库地址已存在: %s", registry.Name)
+		}
+	}
+	return nil
+}
+
+// toRegistryInfo 转换为接口返回的仓库信息，不包含密码
+func toRegistryInfo(registry dockerModel.DockerRegistry) dockerRes.RegistryInfo {
+	return dockerRes.RegistryInfo{
+		ID:          registry.ID,
+		Name:        registry.Name,
+		DownloadUrl: registry.DownloadUrl,
+		Protocol:    registry.Protocol,
+		Status:      registry.Status,
+		Username:    registry.Username,
+		HasPassword: registry.Password != "",
+		IsDefault:   registry.IsDefault,
+		Description: registry.Description,
+		CreatedAt:   registry.CreatedAt,
+		UpdatedAt:   registry.UpdatedAt,
+	}
+}
+
+// registryCredentialKey 仓库凭据的加密密钥
+func registryCredentialKey() string {
+	if key := global.GVA_CONFIG.Docker.CredentialKey; key != "" {
+		return key
+	}
+	return global.GVA_CONFIG.JWT.SigningKey
+}
+
+// decryptRegistryPassword 解密仓库密码
+func decryptRegistryPassword(registry dockerModel.DockerRegistry) (string, error) {
+	if registry.Password == "" {
+		return "", nil
+	}
+	password, err := utils.AesDecrypt(registry.Password, registryCredentialKey())
 	if err != nil {
-		return false, fmt.Sprintf("创建请求失败: %v", err)
+		return "", fmt.Errorf("仓库密码解密失败，加密密钥可能已变更，请重新填写密码")
 	}
+	return password, nil
+}
 
-	// 添加认证信息
-	if registry.Username != "" {
-		// 注意：这里没有密码信息，因为我们从Docker API获取的信息不包含密码
-		// 实际测试时可能需要用户手动输入认证信息
+// normalizeRegistryAddress 解析仓库地址，返回协议与 host[:port]
+// 地址中带协议时以地址为准，仓库按主机区分，不支持路径
+func normalizeRegistryAddress(address, protocol string) (string, string, error) {
+	address = strings.TrimSpace(address)
+	protocol = strings.ToLower(strings.TrimSpace(protocol))
+	if !strings.Contains(address, "://") {
+		if protocol == "" {
+			protocol = "https"
+		}
+		address = protocol + "://" + address
+	}
+
+	u, err := url.Parse(address)
+	if err != nil || u.Host == "" {
+		return "", "", fmt.Errorf("仓库地址格式不正确: %s", address)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", "", fmt.Errorf("仓库协议仅支持 http 或 https")
+	}
+	if strings.Trim(u.Path, "/") != "" {
+		return "", "", fmt.Errorf("仓库地址只需填写主机和端口，不能包含路径")
 	}
 
-	// 发送请求
-	resp, err := client.Do(req)
+	host := strings.ToLower(u.Host)
+	if dockerHubHosts[host] {
+		host = "docker.io"
+	}
+	return u.Scheme, host, nil
+}
+
+// registryHost 仓库的 host[:port]，与镜像引用中的仓库部分对应
+func registryHost(registry dockerModel.DockerRegistry) string {
+	_, host, err := normalizeRegistryAddress(registry.DownloadUrl, registry.Protocol)
 	if err != nil {
-		return false, fmt.Sprintf("连接失败: %v", err)
+		return registry.DownloadUrl
 	}
-	defer resp.Body.Close()
+	return host
+}
 
-	// 检查响应状态
-	if resp.StatusCode == 200 || resp.StatusCode == 401 {
-		// 200表示成功，401表示需要认证但仓库存在
-		return true, "连接成功"
+// imageRegistryHost 镜像引用中的仓库地址，未指定时为空
+func imageRegistryHost(image string) string {
+	i := strings.IndexRune(image, '/')
+	if i == -1 {
+		return ""
+	}
+	first := image[:i]
+	if strings.ContainsAny(first, ".:") || first == "localhost" {
+		return strings.ToLower(first)
 	}
+	return ""
+}
 
-	return false, fmt.Sprintf("连接失败，状态码: %d", resp.StatusCode)
+// applyDefaultRegistry 镜像未指定仓库地址且设置了默认仓库时，补全为默认仓库中的镜像
+func applyDefaultRegistry(image string) string {
+	if global.GVA_DB == nil || imageRegistryHost(image) != "" {
+		return image
+	}
+	var registry dockerModel.DockerRegistry
+	if err := global.GVA_DB.Where("is_default = ?", true).Limit(1).Find(&registry).Error; err != nil || registry.ID == 0 {
+		return image
+	}
+	host := registryHost(registry)
+	if host == "docker.io" {
+		return image
+	}
+	return host + "/" + image
 }
 
-// getRegistryName 根据URL生成仓库名称
-func (d *DockerRegistryService) getRegistryName(url string) string {
-	// 移除协议前缀
-	name := strings.TrimPrefix(url, "https://")
-	name = strings.TrimPrefix(name, "http://")
-	
-	// 移除路径
-	if idx := strings.Index(name, "/"); idx != -1 {
-		name = name[:idx]
-	}
-	
-	// 根据常见的仓库地址生成友好名称
-	switch {
-	case strings.Contains(name, "docker.io"):
-		return "Docker Hub"
-	case strings.Contains(name, "registry.cn-hangzhou.aliyuncs.com"):
-		return "阿里云容器镜像服务"
-	case strings.Contains(name, "ccr.ccs.tencentyun.com"):
-		return "腾讯云容器镜像服务"
-	case strings.Contains(name, "registry.cn-beijing.aliyuncs.com"):
-		return "阿里云容器镜像服务(北京)"
-	case strings.Contains(name, "registry.cn-shenzhen.aliyuncs.com"):
-		return "阿里云容器镜像服务(深圳)"
-	case strings.Contains(name, "dockerhub.azk8s.cn"):
-		return "Azure中国镜像"
-	case strings.Contains(name, "reg-mirror.qiniu.com"):
-		return "七牛云镜像"
-	case strings.Contains(name, "hub-mirror.c.163.com"):
-		return "网易云镜像"
-	case strings.Contains(name, "mirror.baidubce.com"):
-		return "百度云镜像"
+// pingRegistry 访问 /v2/ 接口，按 WWW-Authenticate 质询完成 Basic 或 Bearer 认证
+func pingRegistry(ctx context.Context, protocol, host, username, password string) error {
+	if host == "docker.io" {
+		host = "registry-1.docker.io"
+	}
+	endpoint := protocol + "://" + host + "/v2/"
+	client := &http.Client{Timeout: registryTestTimeout}
+
+	resp, err := registryGet(ctx, client, endpoint, func(req *http.Request) {})
+	if err != nil {
+		return fmt.Errorf("连接失败: %v", err)
+	}
+	resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusUnauthorized:
+	default:
+		return fmt.Errorf("连接失败，状态码: %d", resp.StatusCode)
+	}
+
+	if username == "" {
+		return fmt.Errorf("仓库需要认证，请填写用户名和密码")
+	}
+
+	scheme, params := parseAuthChallenge(resp.Header.Get("WWW-Authenticate"))
+	switch scheme {
+	case "basic":
+		resp, err := registryGet(ctx, client, endpoint, func(req *http.Request) {
+			req.SetBasicAuth(username, password)
+		})
+		if err != nil {
+			return fmt.Errorf("连接失败: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("认证失败，状态码: %d", resp.StatusCode)
+		}
+		return nil
+	case "bearer":
+		token, err := fetchRegistryToken(ctx, client, params, username, password)
+		if err != nil {
+			return err
+		}
+		resp, err := registryGet(ctx, client, endpoint, func(req *http.Request) {
+			req.Header.Set("Authorization", "Bearer "+token)
+		})
+		if err != nil {
+			return fmt.Errorf("连接失败: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("认证失败，状态码: %d", resp.StatusCode)
+		}
+		return nil
 	default:
-		return name
+		return fmt.Errorf("不支持的认证方式: %s", resp.Header.Get("WWW-Authenticate"))
+	}
+}
+
+// fetchRegistryToken 使用用户名密码向认证服务换取 Bearer Token
+func fetchRegistryToken(ctx context.Context, client *http.Client, params map[string]string, username, password string) (string, error) {
+	realm := params["realm"]
+	if realm == "" {
+		return "", fmt.Errorf("认证质询缺少 realm")
+	}
+	tokenUrl, err := url.Parse(realm)
+	if err != nil {
+		return "", fmt.Errorf("认证地址格式不正确: %s", realm)
+	}
+	query := tokenUrl.Query()
+	if service := params["service"]; service != "" {
+		query.Set("service", service)
+	}
+	if scope := params["scope"]; scope != "" {
+		query.Set("scope", scope)
+	}
+	tokenUrl.RawQuery = query.Encode()
+
+	resp, err := registryGet(ctx, client, tokenUrl.String(), func(req *http.Request) {
+		req.SetBasicAuth(username, password)
+	})
+	if err != nil {
+		return "", fmt.Errorf("获取认证令牌失败: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("认证失败，用户名或密码错误（状态码: %d）", resp.StatusCode)
+	}
+
+	var body struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("解析认证令牌失败: %v", err)
+	}
+	if body.Token != "" {
+		return body.Token, nil
 	}
+	if body.AccessToken != "" {
+		return body.AccessToken, nil
+	}
+	return "", fmt.Errorf("认证服务未返回令牌")
 }
 
-// getProtocol 从URL中提取协议
-func (d *DockerRegistryService) getProtocol(url string) string {
-	if strings.HasPrefix(url, "https://") {
-		return "https"
-	} else if strings.HasPrefix(url, "http://") {
-		return "http"
+// registryGet 发送GET请求，decorate 用于设置认证头
+func registryGet(ctx context.Context, client *http.Client, target string, decorate func(req *http.Request)) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
 	}
-	// 默认假设是https
-	return "https"
+	decorate(req)
+	return client.Do(req)
 }
 
-// extractRegistryFromImage 从镜像名称中提取仓库地址
-func (d *DockerRegistryService) extractRegistryFromImage(imageTag string) string {
-	// 分割镜像名称和标签
-	parts := strings.Split(imageTag, ":")
-	imageName := parts[0]
-	
-	// 如果镜像名称包含斜杠，可能包含仓库地址
-	if strings.Contains(imageName, "/") {
-		parts := strings.Split(imageName, "/")
-		
-		// 检查第一部分是否像仓库地址（包含点号或端口）
-		firstPart := parts[0]
-		if strings.Contains(firstPart, ".") || strings.Contains(firstPart, ":") {
-			// 构建完整的仓库URL
-			if strings.Contains(firstPart, ":") && !strings.Contains(firstPart, "://") {
-				// 包含端口，假设是http
-				return "http://" + firstPart
-			} else if !strings.Contains(firstPart, "://") {
-				// 不包含协议，假设是https
-				return "https://" + firstPart
-			}
-			return firstPart
-		}
+// parseAuthChallenge 解析 WWW-Authenticate 头，返回小写的认证方式与参数
+func parseAuthChallenge(header string) (string, map[string]string) {
+	header = strings.TrimSpace(header)
+	scheme := header
+	rest := ""
+	if i := strings.IndexByte(header, ' '); i != -1 {
+		scheme, rest = header[:i], header[i+1:]
 	}
-	
-	// 如果没有明确的仓库地址，返回空字符串（表示使用默认的Docker Hub）
-	return ""
+	params := make(map[string]string)
+	for _, match := range authChallengeParamPattern.FindAllStringSubmatch(rest, -1) {
+		params[strings.ToLower(match[1])] = match[2]
+	}
+	return strings.ToLower(scheme), params
 }
@@ -0,0 +1,95 @@
+package docker
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	dockerModel "github.com/flipped-aurora/gin-vue-admin/server/model/docker"
+)
+
+// dockerHubAuthAddress Docker Hub 认证时使用的 ServerAddress
+const dockerHubAuthAddress = "https://index.docker.io/v1/"
+
+// registryAuthForImage 按镜像引用中的仓库地址匹配已保存的仓库，生成 RegistryAuth 头
+// 未匹配到仓库或仓库未配置凭据时返回空串，按匿名方式访问
+func registryAuthForImage(image string) (string, error) {
+	if global.GVA_DB == nil {
+		return "", nil
+	}
+	host := imageRegistryHost(image)
+	if host == "" || dockerHubHosts[host] {
+		host = "docker.io"
+	}
+
+	var registries []dockerModel.DockerRegistry
+	if err := global.GVA_DB.Where("username <> ''").Find(&registries).Error; err != nil {
+		return "", fmt.Errorf("failed to query registries: %v", err)
+	}
+	for _, registry := range registries {
+		if registryHost(registry) != host {
+			continue
+		}
+		password, err := decryptRegistryPassword(registry)
+		if err != nil {
+			return "", err
+		}
+		return encodeRegistryAuth(registry.Username, password, host)
+	}
+	return "", nil
+}
+
+// encodeRegistryAuth 将凭据编码为 Docker API 所需的 X-Registry-Auth 格式
+func encodeRegistryAuth(username, password, host string) (string, error) {
+	serverAddress := host
+	if host == "docker.io" {
+		serverAddress = dockerHubAuthAddress
+	}
+	data, err := json.Marshal(types.AuthConfig{
+		Username:      username,
+		Password:      password,
+		ServerAddress: serverAddress,
+	})
+	if err != nil {
+		return "", err
+	}
+	return base64.URLEncoding.EncodeToString(data), nil
+}
+
+// readDockerJSONStream 读取拉取/推送返回的 JSON 消息流，返回完整日志
+// Docker 在 HTTP 200 后通过消息中的 error 字段报告失败，遇到时返回该错误
+func readDockerJSONStream(reader io.Reader) (string, error) {
+	var log strings.Builder
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		log.Write(line)
+		log.WriteByte('\n')
+
+		var message struct {
+			Error       string `json:"error"`
+			ErrorDetail *struct {
+				Message string `json:"message"`
+			} `json:"errorDetail"`
+		}
+		if json.Unmarshal(line, &message) != nil {
+			continue
+		}
+		if message.ErrorDetail != nil && message.ErrorDetail.Message != "" {
+			return log.String(), fmt.Errorf("%s", message.ErrorDetail.Message)
+		}
+		if message.Error != "" {
+			return log.String(), fmt.Errorf("%s", message.Error)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return log.String(), err
+	}
+	return log.String(), nil
+}
@@ -0,0 +1,87 @@
+package docker
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeRegistryAddress(t *testing.T) {
+	protocol, host, err := normalizeRegistryAddress("harbor.example.com:5000", "http")
+	assert.NoError(t, err)
+	assert.Equal(t, "http", protocol)
+	assert.Equal(t, "harbor.example.com:5000", host)
+
+	protocol, host, err = normalizeRegistryAddress("https://Registry-1.docker.io/", "http")
+	assert.NoError(t, err)
+	assert.Equal(t, "https", protocol)
+	assert.Equal(t, "docker.io", host)
+
+	_, _, err = normalizeRegistryAddress("https://harbor.example.com/library", "https")
+	assert.Error(t, err)
+}
+
+func TestImageRegistryHost(t *testing.T) {
+	assert.Equal(t, "", imageRegistryHost("nginx:latest"))
+	assert.Equal(t, "", imageRegistryHost("library/nginx"))
+	assert.Equal(t, "harbor.example.com", imageRegistryHost("harbor.example.com/app/web:1.0"))
+	assert.Equal(t, "localhost:5000", imageRegistryHost("localhost:5000/web"))
+}
+
+func TestPingRegistryBearer(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/":
+			if r.Header.Get("Authorization") == "Bearer secret-token" {
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+			w.Header().Set("WWW-Authenticate", `Bearer realm="`+server.URL+`/token",service="test-registry"`)
+			w.WriteHeader(http.StatusUnauthorized)
+		case "/token":
+			user, pass, ok := r.BasicAuth()
+			if !ok || user != "admin" || pass != "pass" || r.URL.Query().Get("service") != "test-registry" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]string{"token": "secret-token"})
+		}
+	}))
+	defer server.Close()
+
+	host := strings.TrimPrefix(server.URL, "http://")
+	assert.NoError(t, pingRegistry(context.Background(), "http", host, "admin", "pass"))
+	assert.Error(t, pingRegistry(context.Background(), "http", host, "admin", "wrong"))
+	assert.Error(t, pingRegistry(context.Background(), "http", host, "", ""))
+}
+
+func TestReadDockerJSONStream(t *testing.T) {
+	log, err := readDockerJSONStream(strings.NewReader(`{"status":"Pushing","id":"abc"}
+{"status":"latest: digest: sha256:1234 size: 528"}
+`))
+	assert.NoError(t, err)
+	assert.Contains(t, log, "digest")
+
+	_, err = readDockerJSONStream(strings.NewReader(`{"status":"Preparing","id":"abc"}
+{"errorDetail":{"message":"unauthorized: authentication required"},"error":"unauthorized: authentication required"}
+`))
+	assert.EqualError(t, err, "unauthorized: authentication required")
+}
+
+func TestEncodeRegistryAuth(t *testing.T) {
+	encoded, err := encodeRegistryAuth("admin", "secret", "docker.io")
+	assert.NoError(t, err)
+	data, err := base64.URLEncoding.DecodeString(encoded)
+	assert.NoError(t, err)
+	var auth map[string]string
+	assert.NoError(t, json.Unmarshal(data, &auth))
+	assert.Equal(t, "admin", auth["username"])
+	assert.Equal(t, dockerHubAuthAddress, auth["serveraddress"])
+}
@@ -110,6 +110,7 @@ func (c *DockerServiceController) StartService() error {
 
 // GetServiceStatus 获取Docker服务状态
 func (c *DockerServiceController) GetServiceStatus() (*dockerModel.ServiceStatusResponse, error) {
+	cli := global.GetDocker()
 	status := &dockerModel.ServiceStatusResponse{
 		Status:   "unknown",
 		Version:  "",
@@ -117,21 +118,21 @@ func (c *DockerServiceController) GetServiceStatus() (*dockerModel.ServiceStatus
 	}
 
 	// 检查Docker守护进程是否可访问
-	if global.GVA_DOCKER != nil {
+	if cli != nil {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 
 		// 尝试ping Docker守护进程
-		if _, err := global.GVA_DOCKER.Ping(ctx); err == nil {
+		if _, err := cli.Ping(ctx); err == nil {
 			status.Status = "running"
 			
 			// 获取Docker版本信息
-			if version, err := global.GVA_DOCKER.ServerVersion(ctx); err == nil {
+			if version, err := cli.ServerVersion(ctx); err == nil {
 				status.Version = version.Version
 			}
 
 			// 获取系统信息
-			if _, err := global.GVA_DOCKER.Info(ctx); err == nil {
+			if _, err := cli.Info(ctx); err == nil {
 				// 计算运行时间（如果可用）
 				// Docker Info中没有直接的启动时间，这里使用一个近似值
 				status.Uptime = "运行中"
@@ -161,7 +162,8 @@ func (c *DockerServiceController) GetServiceStatus() (*dockerModel.ServiceStatus
 
 // CheckServiceHealth 检查Docker服务健康状态
 func (c *DockerServiceController) CheckServiceHealth() error {
-	if global.GVA_DOCKER == nil {
+	cli := global.GetDocker()
+	if cli == nil {
 		return fmt.Errorf("Docker客户端未初始化")
 	}
 
@@ -169,17 +171,17 @@ func (c *DockerServiceController) CheckServiceHealth() error {
 	defer cancel()
 
 	// 检查Docker守护进程连接
-	if _, err := global.GVA_DOCKER.Ping(ctx); err != nil {
+	if _, err := cli.Ping(ctx); err != nil {
 		return fmt.Errorf("Docker守护进程不可访问: %v", err)
 	}
 
 	// 检查Docker版本
-	if _, err := global.GVA_DOCKER.ServerVersion(ctx); err != nil {
+	if _, err := cli.ServerVersion(ctx); err != nil {
 		return fmt.Errorf("无法获取Docker版本: %v", err)
 	}
 
 	// 检查Docker系统信息
-	if _, err := global.GVA_DOCKER.Info(ctx); err != nil {
+	if _, err := cli.Info(ctx); err != nil {
 		return fmt.Errorf("无法获取Docker系统信息: %v", err)
 	}
 
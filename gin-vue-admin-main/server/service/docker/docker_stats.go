@@ -0,0 +1,231 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/docker/response"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+)
+
+// statsConcurrency 同时采集容器统计的并发数
+const statsConcurrency = 8
+
+type DockerStatsService struct {
+	hostClient
+}
+
+// GetContainerStats 获取单个容器的资源使用快照
+func (d *DockerStatsService) GetContainerStats(containerID string) (*response.ContainerResourceStats, error) {
+	if d.cli() == nil {
+		return nil, fmt.Errorf("Docker client is not available")
+	}
+
+	if containerID == "" {
+		return nil, fmt.Errorf("container ID cannot be empty")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	stats, err := fetchContainerStats(ctx, d.cli(), containerID)
+	if err != nil {
+		if client.IsErrNotFound(err) {
+			return nil, fmt.Errorf("container not found")
+		}
+		global.GVA_LOG.Error("Failed to get container stats", zap.String("containerID", containerID), zap.Error(err))
+		return nil, fmt.Errorf("failed to get container stats: %v", err)
+	}
+	return stats, nil
+}
+
+// GetAllContainerStats 获取所有运行中容器的资源使用快照，按CPU使用率降序
+func (d *DockerStatsService) GetAllContainerStats() ([]response.ContainerResourceStats, error) {
+	if d.cli() == nil {
+		return nil, fmt.Errorf("Docker client is not available")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	return collectAllContainerStats(ctx, d.cli())
+}
+
+// StreamContainerStats 持续推送单个容器的资源使用情况，ctx 取消时返回；不受 docker.timeout 限制
+func (d *DockerStatsService) StreamContainerStats(ctx context.Context, containerID string, emit func(response.ContainerResourceStats) error) error {
+	if d.cli() == nil {
+		return fmt.Errorf("Docker client is not available")
+	}
+
+	if containerID == "" {
+		return fmt.Errorf("container ID cannot be empty")
+	}
+
+	streamCtx, connected, cancel := streamContext(ctx, dockerStreamConnectTimeout)
+	defer cancel()
+	reader, err := d.streamCli().ContainerStats(streamCtx, containerID, true)
+	connected()
+	if err != nil {
+		if client.IsErrNotFound(err) {
+			return fmt.Errorf("container not found")
+		}
+		return fmt.Errorf("failed to get container stats: %v", err)
+	}
+	defer reader.Body.Close()
+
+	decoder := json.NewDecoder(reader.Body)
+	for {
+		var raw types.StatsJSON
+		if err := decoder.Decode(&raw); err != nil {
+			if err == io.EOF || ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("failed to decode container stats: %v", err)
+		}
+		if err := emit(calculateContainerStats(raw)); err != nil {
+			return err
+		}
+	}
+}
+
+// StreamAllContainerStats 按间隔推送所有运行中容器的资源使用情况，ctx 取消时返回
+func (d *DockerStatsService) StreamAllContainerStats(ctx context.Context, interval time.Duration, emit func([]response.ContainerResourceStats) error) error {
+	if d.cli() == nil {
+		return fmt.Errorf("Docker client is not available")
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		stats, err := collectAllContainerStats(ctx, d.cli())
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		if err := emit(stats); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// collectAllContainerStats 并发采集所有运行中容器的统计信息
+func collectAllContainerStats(ctx context.Context, cli *client.Client) ([]response.ContainerResourceStats, error) {
+	containers, err := cli.ContainerList(ctx, types.ContainerListOptions{})
+	if err != nil {
+		global.GVA_LOG.Error("Failed to get container list for stats", zap.Error(err))
+		return nil, fmt.Errorf("failed to get container list: %v", err)
+	}
+
+	var (
+		mu     sync.Mutex
+		result = make([]response.ContainerResourceStats, 0, len(containers))
+	)
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(statsConcurrency)
+	for _, ctn := range containers {
+		containerID := ctn.ID
+		group.Go(func() error {
+			stats, err := fetchContainerStats(groupCtx, cli, containerID)
+			if err != nil {
+				// 采集期间容器被停止或删除，跳过即可
+				global.GVA_LOG.Debug("Skip container stats", zap.String("containerID", containerID), zap.Error(err))
+				return nil
+			}
+			mu.Lock()
+			result = append(result, *stats)
+			mu.Unlock()
+			return nil
+		})
+	}
+	_ = group.Wait()
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].CPUPercent > result[j].CPUPercent })
+	return result, nil
+}
+
+// fetchContainerStats 获取单次统计，非流式请求时 Docker 会采样两次以填充 precpu_stats
+func fetchContainerStats(ctx context.Context, cli *client.Client, containerID string) (*response.ContainerResourceStats, error) {
+	reader, err := cli.ContainerStats(ctx, containerID, false)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Body.Close()
+
+	var raw types.StatsJSON
+	if err := json.NewDecoder(reader.Body).Decode(&raw); err != nil {
+		return nil, err
+	}
+	stats := calculateContainerStats(raw)
+	return &stats, nil
+}
+
+// calculateContainerStats 按 docker stats 的算法计算各项指标
+func calculateContainerStats(raw types.StatsJSON) response.ContainerResourceStats {
+	stats := response.ContainerResourceStats{
+		ID:   raw.ID,
+		Name: strings.TrimPrefix(raw.Name, "/"),
+		PIDs: raw.PidsStats.Current,
+		Read: raw.Read,
+	}
+
+	onlineCPUs := raw.CPUStats.OnlineCPUs
+	if onlineCPUs == 0 {
+		onlineCPUs = uint32(len(raw.CPUStats.CPUUsage.PercpuUsage))
+	}
+	stats.OnlineCPUs = onlineCPUs
+	cpuDelta := float64(raw.CPUStats.CPUUsage.TotalUsage) - float64(raw.PreCPUStats.CPUUsage.TotalUsage)
+	systemDelta := float64(raw.CPUStats.SystemUsage) - float64(raw.PreCPUStats.SystemUsage)
+	if cpuDelta > 0 && systemDelta > 0 {
+		stats.CPUPercent = cpuDelta / systemDelta * float64(onlineCPUs) * 100
+	}
+
+	// 内存使用量扣除页缓存，cgroup v1 为 total_inactive_file，v2 为 inactive_file
+	usage := raw.MemoryStats.Usage
+	cache, ok := raw.MemoryStats.Stats["total_inactive_file"]
+	if !ok {
+		cache = raw.MemoryStats.Stats["inactive_file"]
+	}
+	if cache < usage {
+		usage -= cache
+	}
+	stats.MemoryUsage = usage
+	stats.MemoryLimit = raw.MemoryStats.Limit
+	if stats.MemoryLimit > 0 {
+		stats.MemoryPercent = float64(usage) / float64(stats.MemoryLimit) * 100
+	}
+
+	for _, nw := range raw.Networks {
+		stats.NetworkRx += nw.RxBytes
+		stats.NetworkTx += nw.TxBytes
+	}
+
+	for _, entry := range raw.BlkioStats.IoServiceBytesRecursive {
+		switch strings.ToLower(entry.Op) {
+		case "read":
+			stats.BlockRead += entry.Value
+		case "write":
+			stats.BlockWrite += entry.Value
+		}
+	}
+	return stats
+}
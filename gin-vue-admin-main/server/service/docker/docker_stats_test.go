@@ -0,0 +1,56 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/docker/response"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalculateContainerStats(t *testing.T) {
+	sample := func(total, preTotal, system, preSystem uint64, online uint32, percpu int) types.StatsJSON {
+		var raw types.StatsJSON
+		raw.ID, raw.Name = "c1", "/web"
+		raw.CPUStats.CPUUsage.TotalUsage, raw.PreCPUStats.CPUUsage.TotalUsage = total, preTotal
+		raw.CPUStats.SystemUsage, raw.PreCPUStats.SystemUsage = system, preSystem
+		raw.CPUStats.OnlineCPUs = online
+		raw.CPUStats.CPUUsage.PercpuUsage = make([]uint64, percpu)
+		return raw
+	}
+
+	cases := []struct {
+		name        string
+		raw         types.StatsJSON
+		memoryStats map[string]uint64
+		cpu         float64
+		onlineCPUs  uint32
+		memory      uint64
+		memPercent  float64
+	}{
+		{"cgroup v1 缓存", sample(400, 200, 2000, 1000, 2, 0), map[string]uint64{"total_inactive_file": 100, "inactive_file": 50}, 40, 2, 700, 70},
+		{"cgroup v2 缓存", sample(400, 200, 2000, 1000, 4, 0), map[string]uint64{"inactive_file": 300}, 80, 4, 500, 50},
+		{"按 percpu 推算核数", sample(300, 200, 2000, 1000, 0, 8), nil, 80, 8, 800, 80},
+		{"首次采样无差值", sample(300, 0, 2000, 0, 1, 0), nil, 15, 1, 800, 80},
+		{"CPU 计数回退", sample(100, 200, 2000, 1000, 2, 0), map[string]uint64{"inactive_file": 900}, 0, 2, 800, 80},
+	}
+	for _, c := range cases {
+		c.raw.MemoryStats.Usage = 800
+		c.raw.MemoryStats.Limit = 1000
+		c.raw.MemoryStats.Stats = c.memoryStats
+		stats := calculateContainerStats(c.raw)
+		assert.InDelta(t, c.cpu, stats.CPUPercent, 0.001, c.name)
+		assert.Equal(t, c.onlineCPUs, stats.OnlineCPUs, c.name)
+		assert.Equal(t, c.memory, stats.MemoryUsage, c.name)
+		assert.InDelta(t, c.memPercent, stats.MemoryPercent, 0.001, c.name)
+		assert.Equal(t, "web", stats.Name, c.name)
+	}
+
+	raw := sample(0, 0, 0, 0, 1, 0)
+	raw.Networks = map[string]types.NetworkStats{"eth0": {RxBytes: 10, TxBytes: 20}, "eth1": {RxBytes: 1, TxBytes: 2}}
+	raw.BlkioStats.IoServiceBytesRecursive = []types.BlkioStatEntry{
+		{Op: "Read", Value: 100}, {Op: "Write", Value: 50}, {Op: "read", Value: 5}, {Op: "Total", Value: 155},
+	}
+	stats := calculateContainerStats(raw)
+	assert.Equal(t, response.ContainerResourceStats{ID: "c1", Name: "web", OnlineCPUs: 1, NetworkRx: 11, NetworkTx: 22, BlockRead: 105, BlockWrite: 50}, stats)
+}
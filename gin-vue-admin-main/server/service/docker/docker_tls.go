@@ -0,0 +1,227 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	dockerRes "github.com/flipped-aurora/gin-vue-admin/server/model/docker/response"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultDockerCertPath   = "docker-certs"      // 未配置 docker.cert-path 时上传证书的保存目录
+	dockerCertMaxSize       = 1 << 20             // 单个证书文件大小上限
+	dockerCertExpiryWarning = 30 * 24 * time.Hour // 证书剩余有效期不足该时长时提示轮换
+)
+
+// dockerTLSLock 同一时间只允许一次证书轮换
+var dockerTLSLock sync.Mutex
+
+// GetTLSStatus 获取默认主机的 TLS 配置与证书有效期
+func (s *DockerConfigService) GetTLSStatus() *dockerRes.DockerTLSStatus {
+	cfg := global.GVA_CONFIG.Docker
+	status := &dockerRes.DockerTLSStatus{
+		Host:         cfg.Host,
+		Enabled:      strings.HasPrefix(cfg.Host, "tcp://") && cfg.CertPath != "",
+		TLSVerify:    cfg.TLSVerify,
+		CertPath:     cfg.CertPath,
+		Certificates: []dockerRes.DockerCertificateInfo{},
+	}
+	if cfg.CertPath == "" {
+		return status
+	}
+	certs, err := utils.LoadDockerCertificates(cfg.CertPath, cfg.TLSVerify)
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	status.Certificates = dockerCertificateInfos(certs, time.Now())
+	return status
+}
+
+// RotateTLSCertificates 上传并轮换默认主机的客户端证书：未上传的文件沿用当前证书，
+// 新证书通过校验且能连接守护进程后才替换，旧证书移入证书目录下的 backup-时间 目录，随后重建客户端
+func (s *DockerConfigService) RotateTLSCertificates(files map[string]*multipart.FileHeader, tlsVerify bool) (*dockerRes.DockerTLSStatus, error) {
+	cfg := global.GVA_CONFIG.Docker
+	if !strings.HasPrefix(cfg.Host, "tcp://") {
+		return nil, fmt.Errorf("tls certificates only apply to tcp endpoints")
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no certificate files uploaded")
+	}
+	if !dockerTLSLock.TryLock() {
+		return nil, fmt.Errorf("certificate rotation is already running")
+	}
+	defer dockerTLSLock.Unlock()
+
+	certPath := cfg.CertPath
+	if certPath == "" {
+		certPath = defaultDockerCertPath
+	}
+	if err := os.MkdirAll(certPath, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create certificate directory: %v", err)
+	}
+	staging, err := os.MkdirTemp(filepath.Dir(filepath.Clean(certPath)), ".docker-certs-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create staging directory: %v", err)
+	}
+	defer os.RemoveAll(staging)
+
+	for _, name := range []string{utils.DockerCAFile, utils.DockerCertFile, utils.DockerKeyFile} {
+		if header, ok := files[name]; ok {
+			err = saveDockerCertFile(header, filepath.Join(staging, name))
+		} else {
+			err = copyDockerCertFile(filepath.Join(certPath, name), filepath.Join(staging, name))
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := utils.LoadDockerCertificates(staging, tlsVerify); err != nil {
+		return nil, fmt.Errorf("invalid certificates: %v", err)
+	}
+	if err := pingWithDockerCerts(cfg.Host, cfg.Version, staging, tlsVerify); err != nil {
+		return nil, fmt.Errorf("docker daemon rejected the new certificates: %v", err)
+	}
+	if err := installDockerCerts(staging, certPath); err != nil {
+		return nil, err
+	}
+
+	global.GVA_CONFIG.Docker.CertPath = certPath
+	global.GVA_CONFIG.Docker.TLSVerify = tlsVerify
+	if global.GVA_VP != nil && (cfg.CertPath != certPath || cfg.TLSVerify != tlsVerify) {
+		global.GVA_VP.Set("docker.cert-path", certPath)
+		global.GVA_VP.Set("docker.tls-verify", tlsVerify)
+		if err := global.GVA_VP.WriteConfig(); err != nil {
+			global.GVA_LOG.Warn("Failed to persist docker tls config", zap.Error(err))
+		}
+	}
+	if err := reconnectDefaultDocker(); err != nil {
+		return nil, fmt.Errorf("certificates installed but reconnect failed: %v", err)
+	}
+	global.GVA_LOG.Info("Docker TLS certificates rotated", zap.String("certPath", certPath))
+	return s.GetTLSStatus(), nil
+}
+
+// dockerCertificateInfos 转换证书有效期信息
+func dockerCertificateInfos(certs []utils.DockerCertificate, now time.Time) []dockerRes.DockerCertificateInfo {
+	infos := make([]dockerRes.DockerCertificateInfo, 0, len(certs))
+	for _, cert := range certs {
+		remaining := cert.Cert.NotAfter.Sub(now)
+		infos = append(infos, dockerRes.DockerCertificateInfo{
+			File:         cert.File,
+			Subject:      cert.Cert.Subject.String(),
+			Issuer:       cert.Cert.Issuer.String(),
+			NotBefore:    cert.Cert.NotBefore,
+			NotAfter:     cert.Cert.NotAfter,
+			DaysLeft:     int(remaining.Hours() / 24),
+			Expired:      remaining <= 0,
+			ExpiringSoon: remaining > 0 && remaining < dockerCertExpiryWarning,
+		})
+	}
+	return infos
+}
+
+// saveDockerCertFile 保存上传的证书文件，私钥仅所有者可读
+func saveDockerCertFile(header *multipart.FileHeader, target string) error {
+	if header.Size > dockerCertMaxSize {
+		return fmt.Errorf("%s exceeds %d KB", header.Filename, dockerCertMaxSize>>10)
+	}
+	src, err := header.Open()
+	if err != nil {
+		return fmt.Errorf("failed to open uploaded file: %v", err)
+	}
+	defer src.Close()
+	data, err := io.ReadAll(io.LimitReader(src, dockerCertMaxSize))
+	if err != nil {
+		return fmt.Errorf("failed to read uploaded file: %v", err)
+	}
+	return os.WriteFile(target, data, 0o600)
+}
+
+// copyDockerCertFile 沿用当前证书目录中的文件，文件不存在时跳过
+func copyDockerCertFile(source, target string) error {
+	data, err := os.ReadFile(source)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read current certificate: %v", err)
+	}
+	return os.WriteFile(target, data, 0o600)
+}
+
+// pingWithDockerCerts 使用待安装的证书连接守护进程
+func pingWithDockerCerts(host, version, certPath string, verify bool) error {
+	conn, err := utils.NewDockerClient(utils.DockerClientOptions{
+		Host:      host,
+		Version:   version,
+		Timeout:   15 * time.Second,
+		TLSVerify: verify,
+		CertPath:  certPath,
+	})
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	_, err = conn.Ping(ctx)
+	return err
+}
+
+// installDockerCerts 将旧证书移入备份目录，再移入新证书
+func installDockerCerts(staging, certPath string) error {
+	backup := filepath.Join(certPath, "backup-"+time.Now().Format("20060102150405"))
+	for _, name := range []string{utils.DockerCAFile, utils.DockerCertFile, utils.DockerKeyFile} {
+		current := filepath.Join(certPath, name)
+		if _, err := os.Stat(current); err == nil {
+			if err := os.MkdirAll(backup, 0o700); err != nil {
+				return fmt.Errorf("failed to create certificate backup directory: %v", err)
+			}
+			if err := os.Rename(current, filepath.Join(backup, name)); err != nil {
+				return fmt.Errorf("failed to back up %s: %v", name, err)
+			}
+		}
+		staged := filepath.Join(staging, name)
+		if _, err := os.Stat(staged); err != nil {
+			continue
+		}
+		if err := os.Rename(staged, current); err != nil {
+			return fmt.Errorf("failed to install %s: %v", name, err)
+		}
+	}
+	return nil
+}
+
+// reconnectDefaultDocker 按当前配置重建默认主机客户端，旧客户端只关闭空闲连接，不影响进行中的请求
+func reconnectDefaultDocker() error {
+	cfg := global.GVA_CONFIG.Docker
+	opts, _, err := utils.DefaultDockerClientOptions(cfg)
+	if err != nil {
+		return err
+	}
+	conn, err := utils.NewDockerClient(opts)
+	if err != nil {
+		return err
+	}
+	old, oldStream := global.SetDocker(conn.Client, conn.Stream)
+	dockerConnection.setConfigKey(dockerConfigKey(cfg))
+	if old != nil {
+		old.Close()
+	}
+	if oldStream != nil {
+		oldStream.Close()
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package docker
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestCert 生成自签名证书与私钥并写入 PEM 文件
+func writeTestCert(t *testing.T, certFile, keyFile string, notAfter time.Time) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: filepath.Base(certFile)},
+		NotBefore:    notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	if keyFile != "" {
+		keyDER, err := x509.MarshalECPrivateKey(key)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	}
+}
+
+func TestLoadDockerCertificates(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	writeTestCert(t, filepath.Join(dir, utils.DockerCertFile), filepath.Join(dir, utils.DockerKeyFile), now.Add(10*24*time.Hour))
+
+	// 未校验服务端证书时 CA 可缺省
+	certs, err := utils.LoadDockerCertificates(dir, false)
+	require.NoError(t, err)
+	require.Len(t, certs, 1)
+	_, err = utils.LoadDockerCertificates(dir, true)
+	assert.Error(t, err)
+
+	writeTestCert(t, filepath.Join(dir, utils.DockerCAFile), "", now.Add(-time.Hour))
+	certs, err = utils.LoadDockerCertificates(dir, true)
+	require.NoError(t, err)
+	infos := dockerCertificateInfos(certs, now)
+	require.Len(t, infos, 2)
+	assert.Equal(t, utils.DockerCertFile, infos[0].File)
+	assert.True(t, infos[0].ExpiringSoon)
+	assert.False(t, infos[0].Expired)
+	assert.True(t, infos[1].Expired)
+
+	// 私钥与证书不匹配
+	other := t.TempDir()
+	writeTestCert(t, filepath.Join(other, utils.DockerCertFile), filepath.Join(dir, utils.DockerKeyFile), now.Add(time.Hour))
+	writeTestCert(t, filepath.Join(dir, utils.DockerCertFile), "", now.Add(time.Hour))
+	_, err = utils.LoadDockerCertificates(dir, false)
+	assert.Error(t, err)
+}
@@ -0,0 +1,222 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	dockerReq "github.com/flipped-aurora/gin-vue-admin/server/model/docker/request"
+	dockerRes "github.com/flipped-aurora/gin-vue-admin/server/model/docker/response"
+)
+
+// 拓扑图节点类型
+const (
+	TopologyNodeContainer     = "container"
+	TopologyNodeNetwork       = "network"
+	TopologyNodeVolume        = "volume"
+	TopologyNodePort          = "port"
+	TopologyNodeOrchestration = "orchestration"
+)
+
+// 拓扑图关系类型
+const (
+	TopologyEdgeAttachment = "attachment" // 容器连接网络
+	TopologyEdgeMount      = "mount"      // 容器挂载存储卷
+	TopologyEdgePort       = "port"       // 主机端口映射到容器
+	TopologyEdgeMember     = "member"     // 容器属于编排
+)
+
+const defaultTopologyMaxNetworks = 3 // 默认过度连接阈值
+
+type DockerTopologyService struct {
+	hostClient
+}
+
+// GetTopology 构建容器、网络、存储卷与主机端口的关系图；按编排过滤时只保留相关的网络与存储卷，
+// 不过滤时包含未被使用的网络与存储卷
+func (d *DockerTopologyService) GetTopology(filter dockerReq.TopologyFilter) (*dockerRes.TopologyGraph, error) {
+	if d.cli() == nil {
+		return nil, fmt.Errorf("Docker client is not available")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	containers, err := d.cli().ContainerList(ctx, types.ContainerListOptions{All: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list containers: %v", err)
+	}
+	networks, err := d.cli().NetworkList(ctx, types.NetworkListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list networks: %v", err)
+	}
+	volumes, err := d.cli().VolumeList(ctx, filters.NewArgs())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list volumes: %v", err)
+	}
+	return buildTopology(containers, networks, volumes.Volumes, filter), nil
+}
+
+// topologyBuilder 去重收集节点与边
+type topologyBuilder struct {
+	graph *dockerRes.TopologyGraph
+	nodes map[string]bool
+}
+
+func (b *topologyBuilder) node(node dockerRes.TopologyNode) {
+	if b.nodes[node.ID] {
+		return
+	}
+	b.nodes[node.ID] = true
+	b.graph.Nodes = append(b.graph.Nodes, node)
+}
+
+func (b *topologyBuilder) edge(source, target, edgeType, label string) {
+	b.graph.Edges = append(b.graph.Edges, dockerRes.TopologyEdge{Source: source, Target: target, Type: edgeType, Label: label})
+}
+
+// buildTopology 由容器、网络、存储卷列表构建关系图
+func buildTopology(containers []types.Container, networks []types.NetworkResource, volumes []*types.Volume, filter dockerReq.TopologyFilter) *dockerRes.TopologyGraph {
+	maxNetworks := filter.MaxNetworks
+	if maxNetworks <= 0 {
+		maxNetworks = defaultTopologyMaxNetworks
+	}
+	b := &topologyBuilder{
+		graph: &dockerRes.TopologyGraph{Nodes: []dockerRes.TopologyNode{}, Edges: []dockerRes.TopologyEdge{}, Isolated: []string{}, OverConnected: []string{}},
+		nodes: make(map[string]bool),
+	}
+
+	networkByName := make(map[string]types.NetworkResource, len(networks))
+	for _, resource := range networks {
+		networkByName[resource.Name] = resource
+	}
+	volumeByName := make(map[string]*types.Volume, len(volumes))
+	for _, vol := range volumes {
+		volumeByName[vol.Name] = vol
+	}
+
+	// 不过滤时先加入全部网络与存储卷，未被使用的资源显示为孤立节点
+	if filter.Orchestration == "" {
+		for _, resource := range networks {
+			b.node(networkNode(resource))
+		}
+		for _, vol := range volumes {
+			b.node(volumeNode(vol.Name, vol))
+		}
+	}
+
+	sort.Slice(containers, func(i, j int) bool {
+		return topologyContainerName(containers[i]) < topologyContainerName(containers[j])
+	})
+	for _, ctn := range containers {
+		orchestration := containerOrchestrationName(ctn.Labels)
+		if filter.Orchestration != "" && orchestration != filter.Orchestration {
+			continue
+		}
+		containerID := TopologyNodeContainer + ":" + ctn.ID
+		b.node(dockerRes.TopologyNode{
+			ID:            containerID,
+			Type:          TopologyNodeContainer,
+			Name:          topologyContainerName(ctn),
+			State:         ctn.State,
+			Orchestration: orchestration,
+			Meta:          map[string]string{"image": ctn.Image, "status": ctn.Status},
+		})
+
+		if orchestration != "" {
+			orchestrationID := TopologyNodeOrchestration + ":" + orchestration
+			b.node(dockerRes.TopologyNode{ID: orchestrationID, Type: TopologyNodeOrchestration, Name: orchestration})
+			b.edge(orchestrationID, containerID, TopologyEdgeMember, ctn.Labels[composeServiceLabel])
+		}
+
+		attached := 0
+		if ctn.NetworkSettings != nil {
+			names := make([]string, 0, len(ctn.NetworkSettings.Networks))
+			for name := range ctn.NetworkSettings.Networks {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				endpoint := ctn.NetworkSettings.Networks[name]
+				resource, ok := networkByName[name]
+				if !ok {
+					resource = types.NetworkResource{ID: endpoint.NetworkID, Name: name}
+				}
+				b.node(networkNode(resource))
+				if name != "none" {
+					attached++
+				}
+				label := endpoint.IPAddress
+				if endpoint.GlobalIPv6Address != "" {
+					label = strings.TrimSpace(label + " " + endpoint.GlobalIPv6Address)
+				}
+				b.edge(containerID, TopologyNodeNetwork+":"+resource.ID, TopologyEdgeAttachment, label)
+			}
+		}
+		if attached == 0 {
+			b.graph.Isolated = append(b.graph.Isolated, containerID)
+		}
+		if attached > maxNetworks {
+			b.graph.OverConnected = append(b.graph.OverConnected, containerID)
+		}
+
+		for _, m := range ctn.Mounts {
+			if m.Type != "volume" || m.Name == "" {
+				continue
+			}
+			b.node(volumeNode(m.Name, volumeByName[m.Name]))
+			label := m.Destination
+			if !m.RW {
+				label += " (ro)"
+			}
+			b.edge(containerID, TopologyNodeVolume+":"+m.Name, TopologyEdgeMount, label)
+		}
+
+		for _, port := range ctn.Ports {
+			if port.PublicPort == 0 {
+				continue
+			}
+			ip := port.IP
+			if ip == "" {
+				ip = "0.0.0.0"
+			}
+			name := fmt.Sprintf("%s:%d/%s", ip, port.PublicPort, port.Type)
+			if strings.Contains(ip, ":") {
+				name = fmt.Sprintf("[%s]:%d/%s", ip, port.PublicPort, port.Type)
+			}
+			portID := TopologyNodePort + ":" + name
+			b.node(dockerRes.TopologyNode{ID: portID, Type: TopologyNodePort, Name: name})
+			b.edge(portID, containerID, TopologyEdgePort, fmt.Sprintf("%d->%d/%s", port.PublicPort, port.PrivatePort, port.Type))
+		}
+	}
+	return b.graph
+}
+
+func networkNode(resource types.NetworkResource) dockerRes.TopologyNode {
+	return dockerRes.TopologyNode{
+		ID:   TopologyNodeNetwork + ":" + resource.ID,
+		Type: TopologyNodeNetwork,
+		Name: resource.Name,
+		Meta: map[string]string{"driver": resource.Driver, "scope": resource.Scope},
+	}
+}
+
+func volumeNode(name string, vol *types.Volume) dockerRes.TopologyNode {
+	node := dockerRes.TopologyNode{ID: TopologyNodeVolume + ":" + name, Type: TopologyNodeVolume, Name: name}
+	if vol != nil {
+		node.Meta = map[string]string{"driver": vol.Driver}
+	}
+	return node
+}
+
+// topologyContainerName 容器名称，去掉前导斜杠
+func topologyContainerName(ctn types.Container) string {
+	if len(ctn.Names) > 0 {
+		return strings.TrimPrefix(ctn.Names[0], "/")
+	}
+	return ctn.ID
+}
@@ -0,0 +1,62 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/network"
+	dockerReq "github.com/flipped-aurora/gin-vue-admin/server/model/docker/request"
+	dockerRes "github.com/flipped-aurora/gin-vue-admin/server/model/docker/response"
+	"github.com/stretchr/testify/assert"
+)
+
+func topologyContainer(id, name, project string, networks ...string) types.Container {
+	ctn := types.Container{
+		ID:              id,
+		Names:           []string{"/" + name},
+		State:           "running",
+		Labels:          map[string]string{},
+		NetworkSettings: &types.SummaryNetworkSettings{Networks: map[string]*network.EndpointSettings{}},
+	}
+	if project != "" {
+		ctn.Labels[composeProjectLabel] = project
+		ctn.Labels[composeServiceLabel] = name
+	}
+	for _, name := range networks {
+		ctn.NetworkSettings.Networks[name] = &network.EndpointSettings{NetworkID: "id-" + name, IPAddress: "172.18.0.2"}
+	}
+	return ctn
+}
+
+func TestBuildTopology(t *testing.T) {
+	web := topologyContainer("c1", "web", "shop", "shop_default")
+	web.Mounts = []types.MountPoint{
+		{Type: "volume", Name: "shop_data", Destination: "/data", RW: false},
+		{Type: "bind", Source: "/etc/hosts", Destination: "/etc/hosts"},
+	}
+	web.Ports = []types.Port{{IP: "0.0.0.0", PublicPort: 8080, PrivatePort: 80, Type: "tcp"}, {PrivatePort: 443, Type: "tcp"}}
+	worker := topologyContainer("c2", "worker", "", "none")
+	gateway := topologyContainer("c3", "gateway", "", "a", "b", "c", "d")
+	networks := []types.NetworkResource{{ID: "id-shop_default", Name: "shop_default", Driver: "bridge"}, {ID: "id-unused", Name: "unused"}}
+	volumes := []*types.Volume{{Name: "shop_data", Driver: "local"}, {Name: "orphan", Driver: "local"}}
+
+	graph := buildTopology([]types.Container{web, worker, gateway}, networks, volumes, dockerReq.TopologyFilter{})
+	ids := make(map[string]bool)
+	for _, node := range graph.Nodes {
+		ids[node.ID] = true
+	}
+	for _, id := range []string{"network:id-unused", "volume:orphan", "orchestration:shop", "port:0.0.0.0:8080/tcp", "network:id-d"} {
+		assert.True(t, ids[id], id)
+	}
+	assert.Contains(t, graph.Edges, dockerRes.TopologyEdge{Source: "container:c1", Target: "volume:shop_data", Type: TopologyEdgeMount, Label: "/data (ro)"})
+	assert.Contains(t, graph.Edges, dockerRes.TopologyEdge{Source: "port:0.0.0.0:8080/tcp", Target: "container:c1", Type: TopologyEdgePort, Label: "8080->80/tcp"})
+	assert.Contains(t, graph.Edges, dockerRes.TopologyEdge{Source: "orchestration:shop", Target: "container:c1", Type: TopologyEdgeMember, Label: "web"})
+	assert.Equal(t, []string{"container:c2"}, graph.Isolated)
+	assert.Equal(t, []string{"container:c3"}, graph.OverConnected)
+
+	// 按编排过滤时不包含其他容器及未使用的资源
+	graph = buildTopology([]types.Container{web, worker, gateway}, networks, volumes, dockerReq.TopologyFilter{Orchestration: "shop"})
+	assert.Len(t, graph.Nodes, 5)
+	assert.Empty(t, graph.Isolated)
+	assert.Empty(t, graph.OverConnected)
+}
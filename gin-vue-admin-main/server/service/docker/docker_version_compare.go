@@ -0,0 +1,301 @@
+package docker
+
+import (
+	"strconv"
+	"strings"
+
+	dockerModel "github.com/flipped-aurora/gin-vue-admin/server/model/docker"
+)
+
+// compareVersions 按生态的版本规则比较版本，a<b 返回负数，相等返回0，a>b 返回正数
+func compareVersions(ecosystem, a, b string) int {
+	switch ecosystem {
+	case dockerModel.PackageTypeDeb:
+		return compareDebVersions(a, b)
+	case dockerModel.PackageTypeRpm:
+		return compareRpmVersions(a, b)
+	}
+	return compareGenericVersions(a, b)
+}
+
+// splitEpoch 拆分 epoch:version，epoch 缺省为0
+func splitEpoch(version string) (int, string) {
+	if i := strings.IndexByte(version, ':'); i > 0 {
+		if epoch, err := strconv.Atoi(version[:i]); err == nil {
+			return epoch, version[i+1:]
+		}
+	}
+	return 0, version
+}
+
+// compareDebVersions 按 dpkg 规则比较 [epoch:]upstream[-revision]
+func compareDebVersions(a, b string) int {
+	epochA, restA := splitEpoch(strings.TrimSpace(a))
+	epochB, restB := splitEpoch(strings.TrimSpace(b))
+	if epochA != epochB {
+		return sign(epochA - epochB)
+	}
+	upA, revA := splitRevision(restA)
+	upB, revB := splitRevision(restB)
+	if c := debVerrevcmp(upA, upB); c != 0 {
+		return c
+	}
+	return debVerrevcmp(revA, revB)
+}
+
+// splitRevision 按最后一个 - 拆分版本与修订号
+func splitRevision(version string) (string, string) {
+	if i := strings.LastIndexByte(version, '-'); i >= 0 {
+		return version[:i], version[i+1:]
+	}
+	return version, ""
+}
+
+// debOrder dpkg 字符排序权重：~ 最小，其次为结束，字母小于其他符号
+func debOrder(s string) int {
+	if s == "" {
+		return 0
+	}
+	c := s[0]
+	switch {
+	case isDigitByte(c):
+		return 0
+	case isAlphaByte(c):
+		return int(c)
+	case c == '~':
+		return -1
+	}
+	return int(c) + 256
+}
+
+// debVerrevcmp dpkg 的 verrevcmp 实现，交替比较非数字段与数字段
+func debVerrevcmp(a, b string) int {
+	for a != "" || b != "" {
+		for (a != "" && !isDigitByte(a[0])) || (b != "" && !isDigitByte(b[0])) {
+			ac, bc := debOrder(a), debOrder(b)
+			if ac != bc {
+				return sign(ac - bc)
+			}
+			a, b = a[1:], b[1:]
+		}
+		a, b = strings.TrimLeft(a, "0"), strings.TrimLeft(b, "0")
+		firstDiff := 0
+		for a != "" && isDigitByte(a[0]) && b != "" && isDigitByte(b[0]) {
+			if firstDiff == 0 {
+				firstDiff = int(a[0]) - int(b[0])
+			}
+			a, b = a[1:], b[1:]
+		}
+		if a != "" && isDigitByte(a[0]) {
+			return 1
+		}
+		if b != "" && isDigitByte(b[0]) {
+			return -1
+		}
+		if firstDiff != 0 {
+			return sign(firstDiff)
+		}
+	}
+	return 0
+}
+
+// compareRpmVersions 按 rpm 规则比较 [epoch:]version[-release]
+func compareRpmVersions(a, b string) int {
+	epochA, restA := splitEpoch(strings.TrimSpace(a))
+	epochB, restB := splitEpoch(strings.TrimSpace(b))
+	if epochA != epochB {
+		return sign(epochA - epochB)
+	}
+	verA, relA := splitRevision(restA)
+	verB, relB := splitRevision(restB)
+	if c := rpmvercmp(verA, verB); c != 0 {
+		return c
+	}
+	// 一方未指定 release 时只比较版本
+	if relA == "" || relB == "" {
+		return 0
+	}
+	return rpmvercmp(relA, relB)
+}
+
+// rpmvercmp rpm 的版本段比较：数字段大于字母段，~ 小于任何内容，^ 大于结束
+func rpmvercmp(a, b string) int {
+	if a == b {
+		return 0
+	}
+	for a != "" || b != "" {
+		a = strings.TrimLeftFunc(a, isRpmSeparator)
+		b = strings.TrimLeftFunc(b, isRpmSeparator)
+
+		if strings.HasPrefix(a, "~") || strings.HasPrefix(b, "~") {
+			if !strings.HasPrefix(a, "~") {
+				return 1
+			}
+			if !strings.HasPrefix(b, "~") {
+				return -1
+			}
+			a, b = a[1:], b[1:]
+			continue
+		}
+		if strings.HasPrefix(a, "^") || strings.HasPrefix(b, "^") {
+			if a == "" {
+				return -1
+			}
+			if b == "" {
+				return 1
+			}
+			if !strings.HasPrefix(a, "^") {
+				return 1
+			}
+			if !strings.HasPrefix(b, "^") {
+				return -1
+			}
+			a, b = a[1:], b[1:]
+			continue
+		}
+		if a == "" || b == "" {
+			break
+		}
+
+		numeric := isDigitByte(a[0])
+		segA, segB := takeSegment(a, numeric), takeSegment(b, numeric)
+		a, b = a[len(segA):], b[len(segB):]
+		if segB == "" {
+			// 段类型不同：数字段更新
+			if numeric {
+				return 1
+			}
+			return -1
+		}
+		if numeric {
+			segA, segB = strings.TrimLeft(segA, "0"), strings.TrimLeft(segB, "0")
+			if len(segA) != len(segB) {
+				return sign(len(segA) - len(segB))
+			}
+		}
+		if c := strings.Compare(segA, segB); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case a == "" && b == "":
+		return 0
+	case a == "":
+		return -1
+	}
+	return 1
+}
+
+// isRpmSeparator rpm 比较时跳过的分隔字符
+func isRpmSeparator(r rune) bool {
+	if r >= 128 {
+		return true
+	}
+	return !isDigitByte(byte(r)) && !isAlphaByte(byte(r)) && r != '~' && r != '^'
+}
+
+// takeSegment 取开头连续的数字或字母段
+func takeSegment(s string, numeric bool) string {
+	i := 0
+	for i < len(s) && ((numeric && isDigitByte(s[i])) || (!numeric && isAlphaByte(s[i]))) {
+		i++
+	}
+	return s[:i]
+}
+
+// preReleaseMarkers 表示预发布版本的字母段，排在正式版之前
+var preReleaseMarkers = map[string]bool{
+	"alpha": true, "a": true, "beta": true, "b": true, "pre": true, "preview": true,
+	"rc": true, "dev": true, "snapshot": true,
+}
+
+// compareGenericVersions 通用版本比较，适用于 semver、PEP 440、apk 等：
+// 按数字段与字母段逐段比较，数字段大于字母段，预发布标记小于正式版
+func compareGenericVersions(a, b string) int {
+	segA := versionSegments(a)
+	segB := versionSegments(b)
+	for i := 0; i < len(segA) || i < len(segB); i++ {
+		if i >= len(segA) {
+			return -trailingSegmentOrder(segB[i])
+		}
+		if i >= len(segB) {
+			return trailingSegmentOrder(segA[i])
+		}
+		x, y := segA[i], segB[i]
+		xNum, yNum := isDigitByte(x[0]), isDigitByte(y[0])
+		switch {
+		case xNum && yNum:
+			x, y = strings.TrimLeft(x, "0"), strings.TrimLeft(y, "0")
+			if len(x) != len(y) {
+				return sign(len(x) - len(y))
+			}
+			if c := strings.Compare(x, y); c != 0 {
+				return c
+			}
+		case xNum:
+			return 1
+		case yNum:
+			return -1
+		default:
+			xPre, yPre := preReleaseMarkers[x], preReleaseMarkers[y]
+			if xPre != yPre {
+				if xPre {
+					return -1
+				}
+				return 1
+			}
+			if c := strings.Compare(x, y); c != 0 {
+				return c
+			}
+		}
+	}
+	return 0
+}
+
+// trailingSegmentOrder 一方已结束时另一方剩余段的影响：预发布标记使版本更旧，其余使版本更新
+func trailingSegmentOrder(segment string) int {
+	if preReleaseMarkers[segment] {
+		return -1
+	}
+	return 1
+}
+
+// versionSegments 将版本拆分为小写的数字段与字母段，忽略前缀 v 与 semver 构建元数据
+func versionSegments(version string) []string {
+	version = strings.ToLower(strings.TrimSpace(version))
+	version = strings.TrimPrefix(version, "v")
+	if i := strings.IndexByte(version, '+'); i >= 0 {
+		version = version[:i]
+	}
+	var segments []string
+	for i := 0; i < len(version); {
+		c := version[i]
+		if !isDigitByte(c) && !isAlphaByte(c) {
+			i++
+			continue
+		}
+		numeric := isDigitByte(c)
+		segment := takeSegment(version[i:], numeric)
+		segments = append(segments, segment)
+		i += len(segment)
+	}
+	return segments
+}
+
+func isDigitByte(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isAlphaByte(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
@@ -15,12 +15,14 @@ import (
 	"go.uber.org/zap"
 )
 
-type DockerVolumeService struct{}
+type DockerVolumeService struct {
+	hostClient
+}
 
 // GetVolumeList 获取存储卷列表
 func (d *DockerVolumeService) GetVolumeList(filter dockerReq.VolumeFilter) ([]dockerRes.VolumeInfo, int64, error) {
 	// 检查Docker客户端是否可用
-	if global.GVA_DOCKER == nil {
+	if d.cli() == nil {
 		global.GVA_LOG.Error("Docker client is not available")
 		return nil, 0, fmt.Errorf("Docker client is not available")
 	}
@@ -38,7 +40,7 @@ func (d *DockerVolumeService) GetVolumeList(filter dockerReq.VolumeFilter) ([]do
 	}
 
 	// 调用Docker API获取存储卷列表
-	volumeListResponse, err := global.GVA_DOCKER.VolumeList(ctx, filters.Args{})
+	volumeListResponse, err := d.cli().VolumeList(ctx, filters.Args{})
 	if err != nil {
 		global.GVA_LOG.Error("Failed to get volume list", zap.Error(err))
 		return nil, 0, fmt.Errorf("failed to get volume list: %v", err)
@@ -80,7 +82,7 @@ func (d *DockerVolumeService) GetVolumeList(filter dockerReq.VolumeFilter) ([]do
 // GetVolumeDetail 获取存储卷详细信息
 func (d *DockerVolumeService) GetVolumeDetail(volumeName string) (*dockerRes.VolumeDetail, error) {
 	// 检查Docker客户端是否可用
-	if global.GVA_DOCKER == nil {
+	if d.cli() == nil {
 		global.GVA_LOG.Error("Docker client is not available")
 		return nil, fmt.Errorf("Docker client is not available")
 	}
@@ -89,7 +91,7 @@ func (d *DockerVolumeService) GetVolumeDetail(volumeName string) (*dockerRes.Vol
 	defer cancel()
 
 	// 调用Docker API获取存储卷详细信息
-	dockerVolume, err := global.GVA_DOCKER.VolumeInspect(ctx, volumeName)
+	dockerVolume, err := d.cli().VolumeInspect(ctx, volumeName)
 	if err != nil {
 		global.GVA_LOG.Error("Failed to get volume detail", zap.String("volumeName", volumeName), zap.Error(err))
 		return nil, fmt.Errorf("failed to get volume detail: %v", err)
@@ -103,7 +105,7 @@ func (d *DockerVolumeService) GetVolumeDetail(volumeName string) (*dockerRes.Vol
 // CreateVolume 创建存储卷
 func (d *DockerVolumeService) CreateVolume(createReq dockerReq.VolumeCreateRequest) (string, error) {
 	// 检查Docker客户端是否可用
-	if global.GVA_DOCKER == nil {
+	if d.cli() == nil {
 		global.GVA_LOG.Error("Docker client is not available")
 		return "", fmt.Errorf("Docker client is not available")
 	}
@@ -125,7 +127,7 @@ func (d *DockerVolumeService) CreateVolume(createReq dockerReq.VolumeCreateReque
 	}
 
 	// 创建存储卷
-	dockerVolume, err := global.GVA_DOCKER.VolumeCreate(ctx, createOptions)
+	dockerVolume, err := d.cli().VolumeCreate(ctx, createOptions)
 	if err != nil {
 		global.GVA_LOG.Error("Failed to create volume", 
 			zap.String("name", createReq.Name), 
@@ -144,7 +146,7 @@ func (d *DockerVolumeService) CreateVolume(createReq dockerReq.VolumeCreateReque
 // RemoveVolume 删除存储卷
 func (d *DockerVolumeService) RemoveVolume(volumeName string, force bool) error {
 	// 检查Docker客户端是否可用
-	if global.GVA_DOCKER == nil {
+	if d.cli() == nil {
 		global.GVA_LOG.Error("Docker client is not available")
 		return fmt.Errorf("Docker client is not available")
 	}
@@ -153,7 +155,7 @@ func (d *DockerVolumeService) RemoveVolume(volumeName string, force bool) error
 	defer cancel()
 
 	// 删除存储卷
-	err := global.GVA_DOCKER.VolumeRemove(ctx, volumeName, force)
+	err := d.cli().VolumeRemove(ctx, volumeName, force)
 	if err != nil {
 		global.GVA_LOG.Error("Failed to remove volume", zap.String("volumeName", volumeName), zap.Error(err))
 		return fmt.Errorf("failed to remove volume: %v", err)
@@ -166,7 +168,7 @@ func (d *DockerVolumeService) RemoveVolume(volumeName string, force bool) error
 // PruneVolumes 清理未使用的存储卷
 func (d *DockerVolumeService) PruneVolumes() (int64, int64, error) {
 	// 检查Docker客户端是否可用
-	if global.GVA_DOCKER == nil {
+	if d.cli() == nil {
 		global.GVA_LOG.Error("Docker client is not available")
 		return 0, 0, fmt.Errorf("Docker client is not available")
 	}
@@ -175,7 +177,7 @@ func (d *DockerVolumeService) PruneVolumes() (int64, int64, error) {
 	defer cancel()
 
 	// 清理存储卷
-	pruneReport, err := global.GVA_DOCKER.VolumesPrune(ctx, filters.NewArgs())
+	pruneReport, err := d.cli().VolumesPrune(ctx, filters.NewArgs())
 	if err != nil {
 		global.GVA_LOG.Error("Failed to prune volumes", zap.Error(err))
 		return 0, 0, fmt.Errorf("failed to prune volumes: %v", err)
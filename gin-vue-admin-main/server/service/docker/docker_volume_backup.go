@@ -0,0 +1,614 @@
+package docker
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/client"
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	dockerModel "github.com/flipped-aurora/gin-vue-admin/server/model/docker"
+	dockerReq "github.com/flipped-aurora/gin-vue-admin/server/model/docker/request"
+	dockerRes "github.com/flipped-aurora/gin-vue-admin/server/model/docker/response"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils/upload"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultVolumeBackupPath  = "docker-backups"    // 未配置 docker.backup-path 时的本地备份目录
+	defaultVolumeBackupImage = "busybox:latest"    // 未配置 docker.backup-image 时的辅助容器镜像
+	volumeBackupTimeout      = 2 * time.Hour       // 单次备份或恢复的超时时间
+	volumeQuiesceTimeout     = 30 * time.Second    // 停止容器的等待时间
+	volumeHelperMount        = "/volume"           // 辅助容器中存储卷的挂载路径
+	volumeHelperLabel        = "gva.volume-helper" // 辅助容器标签，值为存储卷名称
+)
+
+// BackupVolume 将存储卷内容打包为 tar.gz 并保存到本地或对象存储，记录大小与 SHA-256 校验值
+func (d *DockerVolumeService) BackupVolume(volumeName string, backupReq dockerReq.VolumeBackupRequest, userID uint) (*dockerModel.DockerVolumeBackup, error) {
+	if d.cli() == nil {
+		return nil, fmt.Errorf("Docker client is not available")
+	}
+	if backupReq.Storage == "" {
+		backupReq.Storage = dockerModel.VolumeBackupStorageLocal
+	}
+	if backupReq.Storage != dockerModel.VolumeBackupStorageLocal && backupReq.Storage != dockerModel.VolumeBackupStorageOSS {
+		return nil, fmt.Errorf("unsupported backup storage: %s", backupReq.Storage)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), volumeBackupTimeout)
+	defer cancel()
+
+	vol, err := d.cli().VolumeInspect(ctx, volumeName)
+	if err != nil {
+		if client.IsErrNotFound(err) {
+			return nil, fmt.Errorf("volume not found")
+		}
+		return nil, fmt.Errorf("failed to inspect volume: %v", err)
+	}
+	method, err := d.volumeBackupMethod(vol, backupReq.Method)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	quiesced := []string{}
+	resume := func() {}
+	if backupReq.Quiesce {
+		if quiesced, resume, err = d.quiesceVolume(ctx, volumeName); err != nil {
+			return nil, err
+		}
+	}
+	defer resume()
+
+	dir := volumeBackupPath()
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create backup directory: %v", err)
+	}
+	fileName := volumeBackupFileName(volumeName, start)
+	target := filepath.Join(dir, fileName)
+	size, checksum, err := d.writeVolumeArchive(ctx, vol, method, target)
+	// 打包完成后立即恢复容器，上传对象存储期间无需停机
+	resume()
+	if err != nil {
+		_ = os.Remove(target)
+		global.GVA_LOG.Error("Failed to back up volume", zap.String("volume", volumeName), zap.String("method", method), zap.Error(err))
+		return nil, fmt.Errorf("failed to back up volume: %v", err)
+	}
+
+	backup := dockerModel.DockerVolumeBackup{
+		HostID:     d.hostID(),
+		VolumeName: volumeName,
+		Driver:     vol.Driver,
+		Labels:     vol.Labels,
+		Method:     method,
+		Storage:    backupReq.Storage,
+		FileName:   fileName,
+		Path:       target,
+		Size:       size,
+		Checksum:   checksum,
+		Quiesced:   quiesced,
+		Remark:     backupReq.Remark,
+		CreatedBy:  userID,
+	}
+	if backupReq.Storage == dockerModel.VolumeBackupStorageOSS {
+		if err := uploadVolumeBackup(&backup); err != nil {
+			return nil, err
+		}
+	}
+	backup.Duration = time.Since(start).Milliseconds()
+
+	if err := global.GVA_DB.Create(&backup).Error; err != nil {
+		removeVolumeBackupFile(backup)
+		return nil, fmt.Errorf("failed to save backup record: %v", err)
+	}
+
+	global.GVA_LOG.Info("Volume backed up", zap.String("volume", volumeName), zap.String("storage", backup.Storage), zap.Int64("size", size), zap.Strings("quiesced", quiesced))
+	return &backup, nil
+}
+
+// GetVolumeBackupList 分页获取备份记录，按时间倒序；备份可恢复到任意主机
+func (d *DockerVolumeService) GetVolumeBackupList(filter dockerReq.VolumeBackupFilter) ([]dockerModel.DockerVolumeBackup, int64, error) {
+	db := global.GVA_DB.Model(&dockerModel.DockerVolumeBackup{})
+	if filter.VolumeName != "" {
+		db = db.Where("volume_name = ?", filter.VolumeName)
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count volume backups: %v", err)
+	}
+	var list []dockerModel.DockerVolumeBackup
+	if err := db.Order("id desc").Offset(filter.PageSize * (filter.Page - 1)).Limit(filter.PageSize).Find(&list).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to query volume backups: %v", err)
+	}
+	return list, total, nil
+}
+
+// DeleteVolumeBackup 删除备份记录及备份文件
+func (d *DockerVolumeService) DeleteVolumeBackup(id uint) error {
+	backup, err := getVolumeBackup(id)
+	if err != nil {
+		return err
+	}
+	removeVolumeBackupFile(*backup)
+	if err := global.GVA_DB.Delete(&dockerModel.DockerVolumeBackup{}, id).Error; err != nil {
+		return fmt.Errorf("failed to delete backup record: %v", err)
+	}
+	return nil
+}
+
+// OpenVolumeBackup 打开备份文件用于下载，调用方读取完毕后必须关闭
+func (d *DockerVolumeService) OpenVolumeBackup(ctx context.Context, id uint) (io.ReadCloser, string, error) {
+	backup, err := getVolumeBackup(id)
+	if err != nil {
+		return nil, "", err
+	}
+	if path := localVolumeBackupPath(*backup); path != "" {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, "", fmt.Errorf("backup file not found")
+		}
+		return file, backup.FileName, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, backup.Url, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid backup url: %v", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to download backup: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, "", fmt.Errorf("failed to download backup: status %d", resp.StatusCode)
+	}
+	return resp.Body, backup.FileName, nil
+}
+
+// RestoreVolume 从备份恢复存储卷：目标不存在时按备份记录的驱动与标签创建，
+// 已存在时需开启覆盖，先清空再解压；恢复前校验备份文件的 SHA-256
+func (d *DockerVolumeService) RestoreVolume(restoreReq dockerReq.VolumeRestoreRequest) (*dockerRes.VolumeRestoreResult, error) {
+	if d.cli() == nil {
+		return nil, fmt.Errorf("Docker client is not available")
+	}
+	backup, err := getVolumeBackup(restoreReq.BackupID)
+	if err != nil {
+		return nil, err
+	}
+	target := restoreReq.Volume
+	if target == "" {
+		target = backup.VolumeName
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), volumeBackupTimeout)
+	defer cancel()
+
+	start := time.Now()
+	archive, cleanup, err := fetchVolumeBackup(ctx, *backup)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+	if checksum, err := fileSHA256(archive); err != nil {
+		return nil, fmt.Errorf("failed to read backup file: %v", err)
+	} else if checksum != backup.Checksum {
+		return nil, fmt.Errorf("backup checksum mismatch")
+	}
+
+	result := &dockerRes.VolumeRestoreResult{Volume: target, Quiesced: []string{}}
+	exists := true
+	if _, err := d.cli().VolumeInspect(ctx, target); err != nil {
+		if !client.IsErrNotFound(err) {
+			return nil, fmt.Errorf("failed to inspect volume: %v", err)
+		}
+		exists = false
+	}
+	if exists && !restoreReq.Overwrite {
+		return nil, fmt.Errorf("volume already exists")
+	}
+	if !exists {
+		if _, err := d.cli().VolumeCreate(ctx, volume.VolumeCreateBody{Name: target, Driver: backup.Driver, Labels: backup.Labels}); err != nil {
+			return nil, fmt.Errorf("failed to create volume: %v", err)
+		}
+		result.Created = true
+	}
+
+	if exists && restoreReq.Quiesce {
+		var resume func()
+		if result.Quiesced, resume, err = d.quiesceVolume(ctx, target); err != nil {
+			return nil, err
+		}
+		defer resume()
+	}
+
+	// 辅助容器运行时清空存储卷，退出后通过归档接口解压，守护进程自动识别 gzip
+	var cmd []string
+	if exists {
+		cmd = []string{"find", volumeHelperMount, "-mindepth", "1", "-delete"}
+	}
+	helperID, err := d.createVolumeHelper(ctx, target, false, cmd)
+	if err != nil {
+		return nil, err
+	}
+	defer d.removeVolumeHelper(helperID)
+	if exists {
+		if err := d.runVolumeHelper(ctx, helperID); err != nil {
+			return nil, fmt.Errorf("failed to clear volume: %v", err)
+		}
+	}
+	file, err := os.Open(archive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup file: %v", err)
+	}
+	defer file.Close()
+	if err := d.streamCli().CopyToContainer(ctx, helperID, volumeHelperMount, file, types.CopyToContainerOptions{}); err != nil {
+		global.GVA_LOG.Error("Failed to restore volume", zap.String("volume", target), zap.Uint("backupId", backup.ID), zap.Error(err))
+		return nil, fmt.Errorf("failed to restore volume: %v", err)
+	}
+
+	result.Duration = time.Since(start).Milliseconds()
+	global.GVA_LOG.Info("Volume restored", zap.String("volume", target), zap.Uint("backupId", backup.ID), zap.Bool("created", result.Created), zap.Strings("quiesced", result.Quiesced))
+	return result, nil
+}
+
+// volumeBackupMethod 确定读取方式，auto 在能直接读取挂载点时使用 mountpoint
+func (d *DockerVolumeService) volumeBackupMethod(vol types.Volume, method string) (string, error) {
+	switch method {
+	case "", "auto":
+		if d.mountpointReadable(vol) {
+			return dockerModel.VolumeBackupMethodMountpoint, nil
+		}
+		return dockerModel.VolumeBackupMethodContainer, nil
+	case dockerModel.VolumeBackupMethodContainer:
+		return method, nil
+	case dockerModel.VolumeBackupMethodMountpoint:
+		if !d.mountpointReadable(vol) {
+			return "", fmt.Errorf("volume mountpoint is not readable on this server")
+		}
+		return method, nil
+	default:
+		return "", fmt.Errorf("unsupported backup method: %s", method)
+	}
+}
+
+// mountpointReadable 默认主机通过本机 unix socket 连接且挂载点可读时，可直接读取存储卷目录
+func (d *DockerVolumeService) mountpointReadable(vol types.Volume) bool {
+	host := global.GVA_CONFIG.Docker.Host
+	if d.hostID() != 0 || vol.Driver != "local" || vol.Mountpoint == "" || (host != "" && !strings.HasPrefix(host, "unix://")) {
+		return false
+	}
+	dir, err := os.Open(vol.Mountpoint)
+	if err != nil {
+		return false
+	}
+	defer dir.Close()
+	_, err = dir.Readdirnames(1)
+	return err == nil || errors.Is(err, io.EOF)
+}
+
+// writeVolumeArchive 将存储卷内容写入 tar.gz 文件，返回文件大小与 SHA-256
+func (d *DockerVolumeService) writeVolumeArchive(ctx context.Context, vol types.Volume, method, target string) (int64, string, error) {
+	file, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return 0, "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	gz := gzip.NewWriter(io.MultiWriter(file, hash))
+	if method == dockerModel.VolumeBackupMethodMountpoint {
+		err = tarDirectory(vol.Mountpoint, gz)
+	} else {
+		err = d.copyVolumeFromHelper(ctx, vol.Name, gz)
+	}
+	if closeErr := gz.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return 0, "", err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return 0, "", err
+	}
+	return info.Size(), hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// copyVolumeFromHelper 创建挂载存储卷的只读辅助容器（不启动），通过归档接口读取存储卷内容
+func (d *DockerVolumeService) copyVolumeFromHelper(ctx context.Context, volumeName string, w io.Writer) error {
+	helperID, err := d.createVolumeHelper(ctx, volumeName, true, nil)
+	if err != nil {
+		return err
+	}
+	defer d.removeVolumeHelper(helperID)
+
+	reader, _, err := d.streamCli().CopyFromContainer(ctx, helperID, volumeHelperMount+"/.")
+	if err != nil {
+		return fmt.Errorf("failed to read volume: %v", err)
+	}
+	defer reader.Close()
+	_, err = io.Copy(w, reader)
+	return err
+}
+
+// createVolumeHelper 创建挂载存储卷的辅助容器，镜像不存在时先拉取
+func (d *DockerVolumeService) createVolumeHelper(ctx context.Context, volumeName string, readOnly bool, cmd []string) (string, error) {
+	image := global.GVA_CONFIG.Docker.BackupImage
+	if image == "" {
+		image = defaultVolumeBackupImage
+	}
+	if err := ensureImage(ctx, d.streamCli(), image); err != nil {
+		return "", err
+	}
+	created, err := d.cli().ContainerCreate(ctx, &container.Config{
+		Image:  image,
+		Cmd:    cmd,
+		Labels: map[string]string{volumeHelperLabel: volumeName},
+	}, &container.HostConfig{
+		Mounts: []mount.Mount{{Type: mount.TypeVolume, Source: volumeName, Target: volumeHelperMount, ReadOnly: readOnly}},
+	}, nil, nil, "")
+	if err != nil {
+		return "", fmt.Errorf("failed to create helper container: %v", err)
+	}
+	return created.ID, nil
+}
+
+// runVolumeHelper 启动辅助容器并等待退出
+func (d *DockerVolumeService) runVolumeHelper(ctx context.Context, helperID string) error {
+	if err := d.cli().ContainerStart(ctx, helperID, types.ContainerStartOptions{}); err != nil {
+		return err
+	}
+	statusCh, errCh := d.streamCli().ContainerWait(ctx, helperID, container.WaitConditionNotRunning)
+	select {
+	case err := <-errCh:
+		return err
+	case status := <-statusCh:
+		if status.Error != nil {
+			return fmt.Errorf("%s", status.Error.Message)
+		}
+		if status.StatusCode != 0 {
+			return fmt.Errorf("helper container exited with code %d", status.StatusCode)
+		}
+		return nil
+	}
+}
+
+// removeVolumeHelper 删除辅助容器，不受请求超时影响
+func (d *DockerVolumeService) removeVolumeHelper(helperID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := d.cli().ContainerRemove(ctx, helperID, types.ContainerRemoveOptions{Force: true}); err != nil && !client.IsErrNotFound(err) {
+		global.GVA_LOG.Warn("Failed to remove volume helper container", zap.String("id", helperID), zap.Error(err))
+	}
+}
+
+// quiesceVolume 停止挂载存储卷的运行中容器，返回停止的容器名称与重新启动函数（可重复调用，只执行一次）
+func (d *DockerVolumeService) quiesceVolume(ctx context.Context, volumeName string) ([]string, func(), error) {
+	containers, err := d.cli().ContainerList(ctx, types.ContainerListOptions{
+		Filters: filters.NewArgs(filters.Arg("volume", volumeName), filters.Arg("status", "running")),
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list containers using volume: %v", err)
+	}
+
+	var stopped []string
+	names := []string{}
+	var once sync.Once
+	resume := func() {
+		once.Do(func() {
+			startCtx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+			defer cancel()
+			for _, id := range stopped {
+				if err := d.cli().ContainerStart(startCtx, id, types.ContainerStartOptions{}); err != nil {
+					global.GVA_LOG.Error("Failed to restart quiesced container", zap.String("container", id), zap.Error(err))
+				}
+			}
+		})
+	}
+
+	timeout := volumeQuiesceTimeout
+	for _, ctn := range containers {
+		if _, ok := ctn.Labels[volumeHelperLabel]; ok {
+			continue
+		}
+		if err := d.cli().ContainerStop(ctx, ctn.ID, &timeout); err != nil {
+			resume()
+			return nil, nil, fmt.Errorf("failed to stop container %s: %v", containerDisplayName(ctn), err)
+		}
+		stopped = append(stopped, ctn.ID)
+		names = append(names, containerDisplayName(ctn))
+	}
+	return names, resume, nil
+}
+
+// containerDisplayName 容器名称，去掉前导斜杠
+func containerDisplayName(ctn types.Container) string {
+	if len(ctn.Names) > 0 {
+		return strings.TrimPrefix(ctn.Names[0], "/")
+	}
+	return ctn.ID[:12]
+}
+
+// tarDirectory 将目录内容写入 tar 流，路径相对于目录根，跳过 socket 文件
+func tarDirectory(root string, w io.Writer) error {
+	tw := tar.NewWriter(w)
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		if info.Mode()&os.ModeSocket != 0 {
+			return nil
+		}
+		link := ""
+		if info.Mode()&os.ModeSymlink != 0 {
+			if link, err = os.Readlink(path); err != nil {
+				return err
+			}
+		}
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(rel)
+		if info.IsDir() {
+			header.Name += "/"
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		// 按记录的大小读取，备份期间文件变长时截断，避免写入超出 tar 头记录的长度
+		_, err = io.CopyN(tw, file, header.Size)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return tw.Close()
+}
+
+// uploadVolumeBackup 将本地备份文件上传到对象存储，成功后删除本地文件
+func uploadVolumeBackup(backup *dockerModel.DockerVolumeBackup) error {
+	file, err := os.Open(backup.Path)
+	if err != nil {
+		return fmt.Errorf("failed to read backup file: %v", err)
+	}
+	header, cleanup, err := streamToFileHeader(file, backup.FileName)
+	file.Close()
+	if err != nil {
+		_ = os.Remove(backup.Path)
+		return fmt.Errorf("failed to upload backup: %v", err)
+	}
+	defer cleanup()
+
+	url, key, err := upload.NewOss().UploadFile(header)
+	_ = os.Remove(backup.Path)
+	if err != nil {
+		global.GVA_LOG.Error("Failed to upload volume backup", zap.String("file", backup.FileName), zap.Error(err))
+		return fmt.Errorf("failed to upload backup: %v", err)
+	}
+	backup.Path = ""
+	backup.Url = url
+	backup.Key = key
+	backup.OssType = global.GVA_CONFIG.System.OssType
+	return nil
+}
+
+// fetchVolumeBackup 获取备份文件的本地路径，远程对象存储中的备份先下载到临时文件
+func fetchVolumeBackup(ctx context.Context, backup dockerModel.DockerVolumeBackup) (string, func(), error) {
+	if path := localVolumeBackupPath(backup); path != "" {
+		if _, err := os.Stat(path); err != nil {
+			return "", nil, fmt.Errorf("backup file not found")
+		}
+		return path, func() {}, nil
+	}
+	path, err := downloadImportArchive(ctx, backup.Url)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to download backup: %v", err)
+	}
+	return path, func() { _ = os.Remove(path) }, nil
+}
+
+// localVolumeBackupPath 本地可直接读取的备份文件路径，包括 local 类型对象存储中的文件；远程对象存储返回空
+func localVolumeBackupPath(backup dockerModel.DockerVolumeBackup) string {
+	if backup.Storage == dockerModel.VolumeBackupStorageLocal {
+		return backup.Path
+	}
+	if backup.OssType == "local" {
+		return filepath.Join(global.GVA_CONFIG.Local.StorePath, backup.Key)
+	}
+	return ""
+}
+
+// removeVolumeBackupFile 删除备份文件，对象存储类型已变更时只记录日志
+func removeVolumeBackupFile(backup dockerModel.DockerVolumeBackup) {
+	var err error
+	switch {
+	case backup.Storage == dockerModel.VolumeBackupStorageLocal:
+		if err = os.Remove(backup.Path); os.IsNotExist(err) {
+			err = nil
+		}
+	case backup.OssType != global.GVA_CONFIG.System.OssType:
+		err = fmt.Errorf("object storage changed from %s to %s", backup.OssType, global.GVA_CONFIG.System.OssType)
+	default:
+		err = upload.NewOss().DeleteFile(backup.Key)
+	}
+	if err != nil {
+		global.GVA_LOG.Warn("Failed to remove volume backup file", zap.Uint("id", backup.ID), zap.String("file", backup.FileName), zap.Error(err))
+	}
+}
+
+// getVolumeBackup 查询备份记录
+func getVolumeBackup(id uint) (*dockerModel.DockerVolumeBackup, error) {
+	var backup dockerModel.DockerVolumeBackup
+	if err := global.GVA_DB.First(&backup, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("volume backup not found")
+		}
+		return nil, fmt.Errorf("failed to query volume backup: %v", err)
+	}
+	return &backup, nil
+}
+
+// volumeBackupPath 本地备份目录
+func volumeBackupPath() string {
+	if path := global.GVA_CONFIG.Docker.BackupPath; path != "" {
+		return path
+	}
+	return defaultVolumeBackupPath
+}
+
+// volumeBackupFileName 备份文件名：存储卷名称-时间.tar.gz
+func volumeBackupFileName(volumeName string, at time.Time) string {
+	name := strings.Trim(exportFileNamePattern.ReplaceAllString(volumeName, "_"), "._")
+	if len(name) > 100 {
+		name = name[:100]
+	}
+	if name == "" {
+		name = "volume"
+	}
+	return name + "-" + at.Format("20060102150405") + ".tar.gz"
+}
+
+// fileSHA256 计算文件的 SHA-256
+func fileSHA256(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
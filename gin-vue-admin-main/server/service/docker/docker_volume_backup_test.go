@@ -0,0 +1,52 @@
+package docker
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTarDirectory(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "data", "nested"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "data", "nested", "app.db"), []byte("content"), 0o600))
+	require.NoError(t, os.Symlink("nested/app.db", filepath.Join(root, "data", "current")))
+
+	var buf bytes.Buffer
+	require.NoError(t, tarDirectory(root, &buf))
+
+	entries := map[string]*tar.Header{}
+	contents := map[string]string{}
+	tr := tar.NewReader(&buf)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		entries[header.Name] = header
+		data, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		contents[header.Name] = string(data)
+	}
+
+	// 路径相对于存储卷根目录，不包含根目录本身
+	assert.Len(t, entries, 4)
+	assert.Contains(t, entries, "data/")
+	assert.Equal(t, "content", contents["data/nested/app.db"])
+	assert.Equal(t, int64(0o600), entries["data/nested/app.db"].Mode&0o777)
+	assert.Equal(t, "nested/app.db", entries["data/current"].Linkname)
+}
+
+func TestVolumeBackupFileName(t *testing.T) {
+	at := time.Date(2024, 3, 1, 3, 0, 0, 0, time.Local)
+	assert.Equal(t, "app_data-20240301030000.tar.gz", volumeBackupFileName("app/data", at))
+	assert.Equal(t, "volume-20240301030000.tar.gz", volumeBackupFileName("..", at))
+}
@@ -0,0 +1,761 @@
+package docker
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"mime/multipart"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	dockerModel "github.com/flipped-aurora/gin-vue-admin/server/model/docker"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/docker/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/docker/response"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	vulnDBMaxFileSize  = 1 << 30  // 漏洞库文件大小上限
+	vulnDBMaxEntrySize = 32 << 20 // 压缩包内单个JSON文件大小上限
+	vulnDBBatchSize    = 500      // 批量写入条数
+	vulnDBQueryBatch   = 500      // 按包名批量查询条数
+)
+
+var vulnDBSourcePattern = regexp.MustCompile(`[^A-Za-z0-9._:-]+`)
+
+// osvRecord OSV 格式漏洞记录（https://ossf.github.io/osv-schema/），只解析匹配需要的字段
+type osvRecord struct {
+	ID               string          `json:"id"`
+	Aliases          []string        `json:"aliases"`
+	Summary          string          `json:"summary"`
+	Details          string          `json:"details"`
+	Withdrawn        string          `json:"withdrawn"`
+	Severity         []osvSeverity   `json:"severity"`
+	Affected         []osvAffected   `json:"affected"`
+	DatabaseSpecific json.RawMessage `json:"database_specific"`
+}
+
+type osvSeverity struct {
+	Type  string `json:"type"`
+	Score string `json:"score"`
+}
+
+type osvAffected struct {
+	Package struct {
+		Ecosystem string `json:"ecosystem"`
+		Name      string `json:"name"`
+	} `json:"package"`
+	Ranges []struct {
+		Type   string              `json:"type"`
+		Events []map[string]string `json:"events"`
+	} `json:"ranges"`
+	Versions          []string        `json:"versions"`
+	Severity          []osvSeverity   `json:"severity"`
+	EcosystemSpecific json.RawMessage `json:"ecosystem_specific"`
+	DatabaseSpecific  json.RawMessage `json:"database_specific"`
+}
+
+// simpleVulnRecord 简化格式漏洞记录，每条对应一个软件包的一个受影响区间
+type simpleVulnRecord struct {
+	ID           string   `json:"id"`
+	Ecosystem    string   `json:"ecosystem"`
+	Distro       string   `json:"distro"`
+	Package      string   `json:"package"`
+	Introduced   string   `json:"introduced"`
+	Fixed        string   `json:"fixed"`
+	LastAffected string   `json:"lastAffected"`
+	Versions     []string `json:"versions"`
+	Severity     string   `json:"severity"`
+	Summary      string   `json:"summary"`
+}
+
+// vulnDBImporter 解析漏洞库文件并分批写入
+type vulnDBImporter struct {
+	tx      *gorm.DB
+	source  string
+	batch   []dockerModel.DockerVulnerability
+	result  response.VulnDBImportResult
+	created time.Time
+}
+
+// ImportVulnDB 导入离线漏洞库文件，同名来源的旧数据整体替换
+// 支持 OSV 格式的单条/数组 JSON、osv.dev 按生态导出的 zip 包，以及简化格式 {"vulnerabilities":[...]}
+func (s *DockerImageInspectService) ImportVulnDB(header *multipart.FileHeader, importReq request.VulnDBImportRequest) (*response.VulnDBImportResult, error) {
+	if header.Size > vulnDBMaxFileSize {
+		return nil, fmt.Errorf("vulnerability database file is too large")
+	}
+	source := importReq.Source
+	if source == "" {
+		source = strings.TrimSuffix(header.Filename, filepath.Ext(header.Filename))
+	}
+	source = strings.Trim(vulnDBSourcePattern.ReplaceAllString(source, "_"), "_")
+	if len(source) > 100 {
+		source = source[:100]
+	}
+	if source == "" {
+		return nil, fmt.Errorf("invalid vulnerability database source")
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open uploaded file: %v", err)
+	}
+	defer file.Close()
+
+	var result response.VulnDBImportResult
+	err = global.GVA_DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("source = ?", source).Delete(&dockerModel.DockerVulnerability{}).Error; err != nil {
+			return err
+		}
+		importer := &vulnDBImporter{tx: tx, source: source, created: time.Now()}
+		importer.result.Source = source
+		if strings.EqualFold(filepath.Ext(header.Filename), ".zip") {
+			err = importer.readZip(file, header.Size)
+		} else {
+			err = importer.readJSON(file)
+		}
+		if err != nil {
+			return err
+		}
+		if err := importer.flush(); err != nil {
+			return err
+		}
+		result = importer.result
+		return nil
+	})
+	if err != nil {
+		global.GVA_LOG.Error("Failed to import vulnerability database", zap.String("source", source), zap.Error(err))
+		return nil, err
+	}
+	if result.Entries == 0 {
+		return nil, fmt.Errorf("no supported vulnerabilities found in file")
+	}
+
+	global.GVA_LOG.Info("Vulnerability database imported", zap.String("source", source), zap.Int("vulnerabilities", result.Vulnerabilities), zap.Int("entries", result.Entries))
+	return &result, nil
+}
+
+// GetVulnDBStats 按数据来源统计离线漏洞库
+func (s *DockerImageInspectService) GetVulnDBStats() ([]response.VulnDBSourceStat, error) {
+	var rows []struct {
+		Source     string
+		Ecosystem  string
+		Count      int64
+		ImportedAt time.Time
+	}
+	err := global.GVA_DB.Model(&dockerModel.DockerVulnerability{}).
+		Select("source, ecosystem, COUNT(*) AS count, MAX(created_at) AS imported_at").
+		Group("source, ecosystem").Order("source").Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make([]response.VulnDBSourceStat, 0)
+	index := make(map[string]int)
+	for _, row := range rows {
+		i, ok := index[row.Source]
+		if !ok {
+			i = len(stats)
+			index[row.Source] = i
+			stats = append(stats, response.VulnDBSourceStat{Source: row.Source})
+		}
+		stats[i].Ecosystems = append(stats[i].Ecosystems, row.Ecosystem)
+		stats[i].Count += row.Count
+		if row.ImportedAt.After(stats[i].ImportedAt) {
+			stats[i].ImportedAt = row.ImportedAt
+		}
+	}
+	return stats, nil
+}
+
+// DeleteVulnDBSource 删除指定来源的漏洞库数据
+func (s *DockerImageInspectService) DeleteVulnDBSource(source string) error {
+	result := global.GVA_DB.Where("source = ?", source).Delete(&dockerModel.DockerVulnerability{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("vulnerability database source not found")
+	}
+	return nil
+}
+
+// readZip 读取压缩包中的所有 JSON 文件
+func (im *vulnDBImporter) readZip(file io.ReaderAt, size int64) error {
+	zr, err := zip.NewReader(file, size)
+	if err != nil {
+		return fmt.Errorf("invalid zip file: %v", err)
+	}
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() || !strings.EqualFold(filepath.Ext(f.Name), ".json") {
+			continue
+		}
+		if f.UncompressedSize64 > vulnDBMaxEntrySize {
+			im.result.Skipped++
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		err = im.readJSON(rc)
+		_ = rc.Close()
+		if err != nil {
+			// 单个文件格式错误不影响其余文件
+			im.result.Skipped++
+		}
+	}
+	return nil
+}
+
+// readJSON 解析单个 JSON 文件，自动识别 OSV 与简化格式
+func (im *vulnDBImporter) readJSON(r io.Reader) error {
+	data, err := io.ReadAll(io.LimitReader(r, vulnDBMaxFileSize))
+	if err != nil {
+		return err
+	}
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return fmt.Errorf("empty vulnerability database file")
+	}
+
+	var items []json.RawMessage
+	switch data[0] {
+	case '[':
+		if err := json.Unmarshal(data, &items); err != nil {
+			return fmt.Errorf("invalid vulnerability database file: %v", err)
+		}
+	case '{':
+		var wrapper struct {
+			Vulnerabilities []json.RawMessage `json:"vulnerabilities"`
+		}
+		if err := json.Unmarshal(data, &wrapper); err != nil {
+			return fmt.Errorf("invalid vulnerability database file: %v", err)
+		}
+		if wrapper.Vulnerabilities != nil {
+			items = wrapper.Vulnerabilities
+		} else {
+			items = []json.RawMessage{data}
+		}
+	default:
+		return fmt.Errorf("invalid vulnerability database file")
+	}
+
+	for _, item := range items {
+		if err := im.addRecord(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addRecord 解析一条记录，包含 affected 字段的视为 OSV 格式
+func (im *vulnDBImporter) addRecord(data json.RawMessage) error {
+	var probe struct {
+		Affected json.RawMessage `json:"affected"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		im.result.Skipped++
+		return nil
+	}
+
+	var entries []dockerModel.DockerVulnerability
+	if probe.Affected != nil {
+		var record osvRecord
+		if err := json.Unmarshal(data, &record); err != nil {
+			im.result.Skipped++
+			return nil
+		}
+		entries = osvToVulnerabilities(record)
+	} else {
+		var record simpleVulnRecord
+		if err := json.Unmarshal(data, &record); err != nil {
+			im.result.Skipped++
+			return nil
+		}
+		entries = simpleToVulnerabilities(record)
+	}
+	if len(entries) == 0 {
+		im.result.Skipped++
+		return nil
+	}
+
+	im.result.Vulnerabilities++
+	for i := range entries {
+		entries[i].Source = im.source
+		entries[i].CreatedAt = im.created
+		im.batch = append(im.batch, entries[i])
+	}
+	if len(im.batch) >= vulnDBBatchSize {
+		return im.flush()
+	}
+	return nil
+}
+
+// flush 写入缓存的条目
+func (im *vulnDBImporter) flush() error {
+	if len(im.batch) == 0 {
+		return nil
+	}
+	if err := im.tx.CreateInBatches(im.batch, vulnDBBatchSize).Error; err != nil {
+		return err
+	}
+	im.result.Entries += len(im.batch)
+	im.batch = im.batch[:0]
+	return nil
+}
+
+// osvToVulnerabilities 将 OSV 记录展开为按软件包和版本区间的匹配条目
+func osvToVulnerabilities(record osvRecord) []dockerModel.DockerVulnerability {
+	if record.ID == "" || record.Withdrawn != "" {
+		return nil
+	}
+	summary := record.Summary
+	if summary == "" {
+		summary = record.Details
+	}
+	summary = truncateRunes(strings.TrimSpace(summary), 1000)
+	recordSeverity := osvSeverityLevel(record.DatabaseSpecific, record.Severity)
+
+	var entries []dockerModel.DockerVulnerability
+	for _, affected := range record.Affected {
+		ecosystem, distro := mapOSVEcosystem(affected.Package.Ecosystem)
+		if ecosystem == "" || affected.Package.Name == "" {
+			continue
+		}
+		severity := osvSeverityLevel(affected.EcosystemSpecific, affected.Severity)
+		if severity == dockerModel.SeverityUnknown {
+			severity = osvSeverityLevel(affected.DatabaseSpecific, nil)
+		}
+		if severity == dockerModel.SeverityUnknown {
+			severity = recordSeverity
+		}
+		base := dockerModel.DockerVulnerability{
+			VulnID:    truncateRunes(record.ID, 100),
+			Ecosystem: ecosystem,
+			Distro:    distro,
+			Package:   normalizePackageName(ecosystem, affected.Package.Name),
+			Severity:  severity,
+			Summary:   summary,
+		}
+
+		ranged := false
+		for _, r := range affected.Ranges {
+			if r.Type == "GIT" {
+				continue
+			}
+			for _, interval := range osvIntervals(r.Events) {
+				entry := base
+				entry.Introduced, entry.Fixed, entry.LastAffected = interval[0], interval[1], interval[2]
+				entries = append(entries, entry)
+				ranged = true
+			}
+		}
+		if !ranged && len(affected.Versions) > 0 {
+			// 只列出版本时不能视为全版本受影响，用一个不可能的区间占位，仅按版本列表匹配
+			entry := base
+			entry.Versions = affected.Versions
+			entry.Fixed = "0"
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// osvIntervals 将 OSV 事件序列转换为 [引入, 修复, 最后受影响] 区间
+func osvIntervals(events []map[string]string) [][3]string {
+	var intervals [][3]string
+	var introduced string
+	open := false
+	for _, event := range events {
+		switch {
+		case event["introduced"] != "":
+			if open {
+				intervals = append(intervals, [3]string{introduced, "", ""})
+			}
+			introduced = event["introduced"]
+			open = true
+		case event["fixed"] != "":
+			intervals = append(intervals, [3]string{introduced, event["fixed"], ""})
+			open = false
+		case event["last_affected"] != "":
+			intervals = append(intervals, [3]string{introduced, "", event["last_affected"]})
+			open = false
+		}
+	}
+	if open {
+		intervals = append(intervals, [3]string{introduced, "", ""})
+	}
+	for i := range intervals {
+		if intervals[i][0] == "0" {
+			intervals[i][0] = ""
+		}
+	}
+	return intervals
+}
+
+// simpleToVulnerabilities 转换简化格式记录
+func simpleToVulnerabilities(record simpleVulnRecord) []dockerModel.DockerVulnerability {
+	ecosystem := strings.ToLower(record.Ecosystem)
+	if mapped, distro := mapOSVEcosystem(record.Ecosystem); mapped != "" {
+		ecosystem = mapped
+		if record.Distro == "" {
+			record.Distro = distro
+		}
+	}
+	if record.ID == "" || record.Package == "" || !supportedEcosystem(ecosystem) {
+		return nil
+	}
+	entry := dockerModel.DockerVulnerability{
+		VulnID:       truncateRunes(record.ID, 100),
+		Ecosystem:    ecosystem,
+		Distro:       strings.ToLower(record.Distro),
+		Package:      normalizePackageName(ecosystem, record.Package),
+		Introduced:   record.Introduced,
+		Fixed:        record.Fixed,
+		LastAffected: record.LastAffected,
+		Versions:     record.Versions,
+		Severity:     normalizeSeverity(record.Severity),
+		Summary:      truncateRunes(record.Summary, 1000),
+	}
+	if entry.Introduced == "0" {
+		entry.Introduced = ""
+	}
+	return []dockerModel.DockerVulnerability{entry}
+}
+
+// mapOSVEcosystem 将 OSV 生态名称映射为软件包类型与发行版标识，如 "Debian:12" -> ("deb", "debian:12")
+func mapOSVEcosystem(name string) (string, string) {
+	parts := strings.Split(name, ":")
+	vendor := strings.ToLower(strings.TrimSpace(parts[0]))
+	release := ""
+	for _, part := range parts[1:] {
+		part = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(part)), "v")
+		if part != "" && part[0] >= '0' && part[0] <= '9' {
+			release = part
+			break
+		}
+	}
+	distro := func(id string) string {
+		if release == "" {
+			return id
+		}
+		return id + ":" + release
+	}
+
+	switch vendor {
+	case "debian":
+		return dockerModel.PackageTypeDeb, distro("debian")
+	case "ubuntu":
+		return dockerModel.PackageTypeDeb, distro("ubuntu")
+	case "alpine":
+		return dockerModel.PackageTypeApk, distro("alpine")
+	case "red hat", "rhel":
+		return dockerModel.PackageTypeRpm, distro("rhel")
+	case "rocky linux", "rocky":
+		return dockerModel.PackageTypeRpm, distro("rocky")
+	case "almalinux":
+		return dockerModel.PackageTypeRpm, distro("almalinux")
+	case "opensuse":
+		return dockerModel.PackageTypeRpm, distro("opensuse")
+	case "suse":
+		return dockerModel.PackageTypeRpm, distro("sles")
+	case "mageia":
+		return dockerModel.PackageTypeRpm, distro("mageia")
+	case "npm":
+		return dockerModel.PackageTypeNpm, ""
+	case "pypi":
+		return dockerModel.PackageTypePypi, ""
+	case "go":
+		return dockerModel.PackageTypeGo, ""
+	case "crates.io", "cargo":
+		return dockerModel.PackageTypeCargo, ""
+	case "rubygems", "gem":
+		return dockerModel.PackageTypeGem, ""
+	case "packagist", "composer":
+		return dockerModel.PackageTypeComposer, ""
+	case "deb", "apk", "rpm":
+		return vendor, ""
+	}
+	return "", ""
+}
+
+// supportedEcosystem 是否为支持匹配的生态
+func supportedEcosystem(ecosystem string) bool {
+	switch ecosystem {
+	case dockerModel.PackageTypeDeb, dockerModel.PackageTypeApk, dockerModel.PackageTypeRpm,
+		dockerModel.PackageTypeNpm, dockerModel.PackageTypePypi, dockerModel.PackageTypeGo,
+		dockerModel.PackageTypeCargo, dockerModel.PackageTypeGem, dockerModel.PackageTypeComposer:
+		return true
+	}
+	return false
+}
+
+// normalizePackageName 统一包名写法，PyPI 包名不区分大小写且 -_. 等价
+func normalizePackageName(ecosystem, name string) string {
+	name = strings.TrimSpace(name)
+	switch ecosystem {
+	case dockerModel.PackageTypePypi:
+		return strings.NewReplacer("_", "-", ".", "-").Replace(strings.ToLower(name))
+	case dockerModel.PackageTypeComposer:
+		return strings.ToLower(name)
+	}
+	return name
+}
+
+// osvSeverityLevel 从 database_specific/ecosystem_specific 的 severity 字段或 CVSS 向量得出严重程度
+func osvSeverityLevel(specific json.RawMessage, scores []osvSeverity) string {
+	if len(specific) > 0 {
+		var fields struct {
+			Severity interface{} `json:"severity"`
+		}
+		if json.Unmarshal(specific, &fields) == nil {
+			if text, ok := fields.Severity.(string); ok {
+				if level := normalizeSeverity(text); level != dockerModel.SeverityUnknown {
+					return level
+				}
+			}
+		}
+	}
+	for _, score := range scores {
+		if score.Type != "CVSS_V3" {
+			continue
+		}
+		if value, ok := cvss3BaseScore(score.Score); ok {
+			return severityFromScore(value)
+		}
+	}
+	return dockerModel.SeverityUnknown
+}
+
+// normalizeSeverity 统一各数据源的严重程度写法
+func normalizeSeverity(severity string) string {
+	switch strings.ToLower(strings.TrimSpace(severity)) {
+	case "critical":
+		return dockerModel.SeverityCritical
+	case "high", "important":
+		return dockerModel.SeverityHigh
+	case "medium", "moderate":
+		return dockerModel.SeverityMedium
+	case "low", "negligible", "unimportant", "minor":
+		return dockerModel.SeverityLow
+	}
+	return dockerModel.SeverityUnknown
+}
+
+// severityFromScore CVSS 分值对应的严重程度
+func severityFromScore(score float64) string {
+	switch {
+	case score >= 9:
+		return dockerModel.SeverityCritical
+	case score >= 7:
+		return dockerModel.SeverityHigh
+	case score >= 4:
+		return dockerModel.SeverityMedium
+	case score > 0:
+		return dockerModel.SeverityLow
+	}
+	return dockerModel.SeverityUnknown
+}
+
+// cvss3BaseScore 按 CVSS v3.x 规范计算向量的基础分
+func cvss3BaseScore(vector string) (float64, bool) {
+	if !strings.HasPrefix(vector, "CVSS:3.") {
+		return 0, false
+	}
+	metrics := make(map[string]string)
+	for _, part := range strings.Split(vector, "/")[1:] {
+		if kv := strings.SplitN(part, ":", 2); len(kv) == 2 {
+			metrics[kv[0]] = kv[1]
+		}
+	}
+
+	weights := map[string]map[string]float64{
+		"AV": {"N": 0.85, "A": 0.62, "L": 0.55, "P": 0.2},
+		"AC": {"L": 0.77, "H": 0.44},
+		"UI": {"N": 0.85, "R": 0.62},
+		"C":  {"H": 0.56, "L": 0.22, "N": 0},
+		"I":  {"H": 0.56, "L": 0.22, "N": 0},
+		"A":  {"H": 0.56, "L": 0.22, "N": 0},
+	}
+	values := make(map[string]float64)
+	for metric, table := range weights {
+		value, ok := table[metrics[metric]]
+		if !ok {
+			return 0, false
+		}
+		values[metric] = value
+	}
+
+	changed := metrics["S"] == "C"
+	if !changed && metrics["S"] != "U" {
+		return 0, false
+	}
+	privileges := map[string]float64{"N": 0.85, "L": 0.62, "H": 0.27}
+	if changed {
+		privileges = map[string]float64{"N": 0.85, "L": 0.68, "H": 0.5}
+	}
+	pr, ok := privileges[metrics["PR"]]
+	if !ok {
+		return 0, false
+	}
+
+	iss := 1 - (1-values["C"])*(1-values["I"])*(1-values["A"])
+	impact := 6.42 * iss
+	if changed {
+		impact = 7.52*(iss-0.029) - 3.25*math.Pow(iss-0.02, 15)
+	}
+	if impact <= 0 {
+		return 0, true
+	}
+	exploitability := 8.22 * values["AV"] * values["AC"] * pr * values["UI"]
+	score := impact + exploitability
+	if changed {
+		score *= 1.08
+	}
+	return math.Ceil(math.Min(score, 10)*10-1e-9) / 10, true
+}
+
+// vulnerabilityAffects 判断版本是否落在漏洞条目的受影响范围内
+func vulnerabilityAffects(vuln dockerModel.DockerVulnerability, version string) bool {
+	for _, v := range vuln.Versions {
+		if v == version {
+			return true
+		}
+	}
+	if vuln.Introduced != "" && compareVersions(vuln.Ecosystem, version, vuln.Introduced) < 0 {
+		return false
+	}
+	if vuln.Fixed != "" {
+		return compareVersions(vuln.Ecosystem, version, vuln.Fixed) < 0
+	}
+	if vuln.LastAffected != "" {
+		return compareVersions(vuln.Ecosystem, version, vuln.LastAffected) <= 0
+	}
+	return true
+}
+
+// distroMatches 判断漏洞条目的发行版是否适用于镜像，如 alpine:3.18 适用于 VERSION_ID=3.18.4
+func distroMatches(distro, osID, osVersion string) bool {
+	if distro == "" {
+		return true
+	}
+	id, version, _ := strings.Cut(distro, ":")
+	if osID == "" || !strings.HasPrefix(strings.ToLower(osID), id) {
+		return false
+	}
+	if version == "" || osVersion == "" {
+		return true
+	}
+	return osVersion == version || strings.HasPrefix(osVersion, version+".")
+}
+
+// matchVulnerabilities 将软件包与离线漏洞库匹配，生成漏洞结果
+func matchVulnerabilities(packages []dockerModel.DockerImageReportPackage, osID, osVersion string) ([]dockerModel.DockerImageReportFinding, error) {
+	// 按生态收集需要查询的包名（系统包同时按源码包名匹配）
+	names := make(map[string]map[string]struct{})
+	for _, pkg := range packages {
+		if names[pkg.Type] == nil {
+			names[pkg.Type] = make(map[string]struct{})
+		}
+		names[pkg.Type][normalizePackageName(pkg.Type, pkg.Name)] = struct{}{}
+		if pkg.Source != "" {
+			names[pkg.Type][normalizePackageName(pkg.Type, pkg.Source)] = struct{}{}
+		}
+	}
+
+	vulns := make(map[string][]dockerModel.DockerVulnerability)
+	for ecosystem, set := range names {
+		list := make([]string, 0, len(set))
+		for name := range set {
+			list = append(list, name)
+		}
+		sort.Strings(list)
+		for start := 0; start < len(list); start += vulnDBQueryBatch {
+			end := start + vulnDBQueryBatch
+			if end > len(list) {
+				end = len(list)
+			}
+			var rows []dockerModel.DockerVulnerability
+			if err := global.GVA_DB.Where("ecosystem = ? AND package IN ?", ecosystem, list[start:end]).Find(&rows).Error; err != nil {
+				return nil, err
+			}
+			for _, row := range rows {
+				key := ecosystem + "/" + row.Package
+				vulns[key] = append(vulns[key], row)
+			}
+		}
+	}
+
+	findings := make([]dockerModel.DockerImageReportFinding, 0)
+	seen := make(map[string]struct{})
+	for _, pkg := range packages {
+		candidates := vulns[pkg.Type+"/"+normalizePackageName(pkg.Type, pkg.Name)]
+		if pkg.Source != "" && pkg.Source != pkg.Name {
+			candidates = append(candidates, vulns[pkg.Type+"/"+normalizePackageName(pkg.Type, pkg.Source)]...)
+		}
+		for _, vuln := range candidates {
+			if !distroMatches(vuln.Distro, osID, osVersion) || !vulnerabilityAffects(vuln, pkg.Version) {
+				continue
+			}
+			key := vuln.VulnID + "|" + pkg.Type + "|" + pkg.Name + "|" + pkg.Version + "|" + pkg.Path
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+			findings = append(findings, dockerModel.DockerImageReportFinding{
+				VulnID:       vuln.VulnID,
+				PackageType:  pkg.Type,
+				Package:      pkg.Name,
+				Version:      pkg.Version,
+				FixedVersion: vuln.Fixed,
+				Severity:     vuln.Severity,
+				Summary:      vuln.Summary,
+				Path:         pkg.Path,
+			})
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		if ri, rj := severityRank(findings[i].Severity), severityRank(findings[j].Severity); ri != rj {
+			return ri < rj
+		}
+		if findings[i].Package != findings[j].Package {
+			return findings[i].Package < findings[j].Package
+		}
+		return findings[i].VulnID < findings[j].VulnID
+	})
+	return findings, nil
+}
+
+// severityRank 严重程度排序，越严重越靠前
+func severityRank(severity string) int {
+	switch severity {
+	case dockerModel.SeverityCritical:
+		return 0
+	case dockerModel.SeverityHigh:
+		return 1
+	case dockerModel.SeverityMedium:
+		return 2
+	case dockerModel.SeverityLow:
+		return 3
+	}
+	return 4
+}
+
+// truncateRunes 按字符截断字符串，避免截断多字节字符
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
@@ -3,10 +3,21 @@ package docker
 type ServiceGroup struct {
 	DockerContainerService
 	DockerImageService
+	DockerImageJobService
+	DockerImageInspectService
+	DockerImageRetentionService
 	DockerNetworkService
 	DockerVolumeService
 	DockerRegistryService
 	DockerConfigService
 	DockerOverviewService
 	DockerDiagnosticService
+	DockerStatsService
+	DockerMetricsService
+	DockerAlertService
+	DockerHostService
+	DockerConnectionService
+	DockerEventService
+	DockerFileService
+	DockerTopologyService
 }
@@ -0,0 +1,368 @@
+package utils
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/client"
+	"github.com/flipped-aurora/gin-vue-admin/server/config"
+	"golang.org/x/crypto/ssh"
+)
+
+// defaultDockerSocket ssh 端点未指定路径时转发的远端 Docker 套接字
+const defaultDockerSocket = "/var/run/docker.sock"
+
+// Docker 证书目录中的文件名，与 docker CLI 的 DOCKER_CERT_PATH 约定一致
+const (
+	DockerCAFile   = "ca.pem"
+	DockerCertFile = "cert.pem"
+	DockerKeyFile  = "key.pem"
+)
+
+// DockerClientOptions 创建Docker客户端的参数
+type DockerClientOptions struct {
+	Host        string        // 端点：unix:///var/run/docker.sock、tcp://host:2376、ssh://user@host:22[/path/docker.sock]
+	Version     string        // API版本，为空时与守护进程协商
+	Timeout     time.Duration // 请求超时，0为不限；只作用于 Client，不作用于 Stream
+	TLSVerify   bool          // tcp 端点是否校验服务端证书
+	CertPath    string        // tcp 端点的证书目录，包含 ca.pem、cert.pem、key.pem
+	SSHKey      string        // ssh 私钥（PEM）
+	SSHPassword string        // ssh 密码，与私钥二选一
+	SSHHostKey  string        // ssh 主机公钥指纹（SHA256:...），为空时接受首次连接的主机并通过 OnHostKey 返回
+	OnHostKey   func(fingerprint string)
+}
+
+// DefaultDockerClientOptions 按配置文件中的 docker 段生成默认主机的客户端参数：补全端点、API版本与超时的默认值，
+// tcp 端点启用 TLS 时校验证书目录；返回加载的证书，用于检查有效期
+func DefaultDockerClientOptions(cfg config.Docker) (DockerClientOptions, []DockerCertificate, error) {
+	opts := DockerClientOptions{
+		Host:      cfg.Host,
+		Version:   cfg.Version,
+		Timeout:   time.Duration(cfg.Timeout) * time.Second,
+		TLSVerify: cfg.TLSVerify,
+		CertPath:  cfg.CertPath,
+	}
+	if opts.Host == "" {
+		opts.Host = "unix://" + defaultDockerSocket
+	}
+	if opts.Version == "" {
+		opts.Version = "1.41"
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 30 * time.Second
+	}
+
+	// tcp 端点配置了证书目录时使用双向 TLS
+	if !strings.HasPrefix(opts.Host, "tcp://") || (!opts.TLSVerify && opts.CertPath == "") {
+		return opts, nil, nil
+	}
+	if opts.CertPath == "" {
+		return opts, nil, fmt.Errorf("docker TLS verification is enabled but cert-path is not configured")
+	}
+	certs, err := LoadDockerCertificates(opts.CertPath, opts.TLSVerify)
+	if err != nil {
+		return opts, nil, fmt.Errorf("invalid docker TLS certificates in %s: %v", opts.CertPath, err)
+	}
+	return opts, certs, nil
+}
+
+// DockerConn Docker客户端及其底层 ssh 连接，不再使用时需要 Close。
+// Client 的 HTTP 超时会限制读取响应体的总时长，跟随日志、统计、事件等长连接与镜像导出等大文件传输
+// 需使用 Stream：两者共享连接池，Stream 不设置 HTTP 超时，由调用方的 context 控制
+type DockerConn struct {
+	*client.Client
+	Stream *client.Client
+	ssh    *sshSocketDialer
+}
+
+// Close 关闭客户端与 ssh 连接
+func (c *DockerConn) Close() error {
+	err := c.Client.Close()
+	c.Stream.Close()
+	if c.ssh != nil {
+		c.ssh.close()
+	}
+	return err
+}
+
+// NewDockerClient 按端点类型创建Docker客户端：unix 直接连接，tcp 可配置双向 TLS，ssh 通过 ssh 连接转发远端套接字
+func NewDockerClient(opts DockerClientOptions) (*DockerConn, error) {
+	endpoint, err := url.Parse(opts.Host)
+	if err != nil {
+		return nil, fmt.Errorf("invalid docker host: %v", err)
+	}
+
+	var httpClient *http.Client // 为空时使用 Docker 客户端默认的 Transport
+	var endpointOpts []client.Opt
+	conn := &DockerConn{}
+	switch endpoint.Scheme {
+	case "unix", "npipe":
+		endpointOpts = append(endpointOpts, client.WithHost(opts.Host))
+	case "tcp":
+		if opts.CertPath != "" {
+			tlsConfig, err := DockerTLSConfig(opts.CertPath, opts.TLSVerify)
+			if err != nil {
+				return nil, err
+			}
+			// 先替换 Transport 再设置端点，由 WithHost 为其配置拨号
+			httpClient = &http.Client{
+				Transport:     &http.Transport{TLSClientConfig: tlsConfig},
+				CheckRedirect: client.CheckRedirect,
+			}
+		}
+		endpointOpts = append(endpointOpts, client.WithHost(opts.Host))
+	case "ssh":
+		dialer, err := newSSHSocketDialer(endpoint, opts)
+		if err != nil {
+			return nil, err
+		}
+		conn.ssh = dialer
+		// 请求经 ssh 转发到远端套接字，这里的地址只用于构造 HTTP 请求
+		endpointOpts = append(endpointOpts, client.WithHost("tcp://docker"), client.WithDialContext(dialer.dialContext))
+	default:
+		return nil, fmt.Errorf("unsupported docker host scheme: %s", endpoint.Scheme)
+	}
+
+	if opts.Version != "" {
+		endpointOpts = append(endpointOpts, client.WithVersion(opts.Version))
+	} else {
+		endpointOpts = append(endpointOpts, client.WithAPIVersionNegotiation())
+	}
+
+	// 长连接客户端不设置 HTTP 超时
+	streamOpts := endpointOpts
+	if httpClient != nil {
+		streamOpts = append([]client.Opt{client.WithHTTPClient(httpClient)}, endpointOpts...)
+	}
+	stream, err := client.NewClientWithOpts(streamOpts...)
+	if err != nil {
+		if conn.ssh != nil {
+			conn.ssh.close()
+		}
+		return nil, err
+	}
+
+	// 普通请求客户端复用同一 Transport，另外设置请求超时
+	shared := stream.HTTPClient()
+	shared.Timeout = opts.Timeout
+	cli, err := client.NewClientWithOpts(append([]client.Opt{client.WithHTTPClient(shared)}, endpointOpts...)...)
+	if err != nil {
+		stream.Close()
+		if conn.ssh != nil {
+			conn.ssh.close()
+		}
+		return nil, err
+	}
+	conn.Client = cli
+	conn.Stream = stream
+	return conn, nil
+}
+
+// DockerTLSConfig 读取证书目录中的 ca.pem、cert.pem、key.pem 生成双向 TLS 配置；
+// verify 为 false 时仍发送客户端证书，但不校验服务端证书
+func DockerTLSConfig(certPath string, verify bool) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(filepath.Join(certPath, DockerCertFile), filepath.Join(certPath, DockerKeyFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load client certificate: %v", err)
+	}
+	config := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if !verify {
+		config.InsecureSkipVerify = true
+		return config, nil
+	}
+
+	caPEM, err := os.ReadFile(filepath.Join(certPath, DockerCAFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA certificate: %v", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("failed to parse CA certificate")
+	}
+	config.RootCAs = pool
+	return config, nil
+}
+
+// DockerCertificate 证书目录中的一个证书
+type DockerCertificate struct {
+	File string            // 文件名：ca.pem 或 cert.pem
+	Cert *x509.Certificate // 证书内容，ca.pem 包含多个证书时逐个返回
+}
+
+// LoadDockerCertificates 校验证书目录：客户端证书与私钥必须存在且匹配，verify 为 true 时 CA 证书必须存在；
+// 返回目录中的客户端证书与 CA 证书，用于检查有效期
+func LoadDockerCertificates(certPath string, verify bool) ([]DockerCertificate, error) {
+	if info, err := os.Stat(certPath); err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("certificate directory not found: %s", certPath)
+	}
+	pair, err := tls.LoadX509KeyPair(filepath.Join(certPath, DockerCertFile), filepath.Join(certPath, DockerKeyFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load client certificate: %v", err)
+	}
+	leaf, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse client certificate: %v", err)
+	}
+	certs := []DockerCertificate{{File: DockerCertFile, Cert: leaf}}
+
+	caPEM, err := os.ReadFile(filepath.Join(certPath, DockerCAFile))
+	if err != nil {
+		if verify || !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read CA certificate: %v", err)
+		}
+		return certs, nil
+	}
+	count := 0
+	for block, rest := pem.Decode(caPEM); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		ca, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CA certificate: %v", err)
+		}
+		certs = append(certs, DockerCertificate{File: DockerCAFile, Cert: ca})
+		count++
+	}
+	if count == 0 {
+		return nil, fmt.Errorf("failed to parse CA certificate")
+	}
+	return certs, nil
+}
+
+// sshSocketDialer 复用一条 ssh 连接，为每个 HTTP 连接打开一个到远端 Docker 套接字的转发通道，断开后自动重连
+type sshSocketDialer struct {
+	mu     sync.Mutex
+	addr   string
+	socket string
+	config *ssh.ClientConfig
+	conn   *ssh.Client
+}
+
+func newSSHSocketDialer(endpoint *url.URL, opts DockerClientOptions) (*sshSocketDialer, error) {
+	if endpoint.User == nil || endpoint.User.Username() == "" {
+		return nil, fmt.Errorf("ssh docker host requires a user")
+	}
+	var auth []ssh.AuthMethod
+	if opts.SSHKey != "" {
+		signer, err := ssh.ParsePrivateKey([]byte(opts.SSHKey))
+		if err != nil {
+			return nil, fmt.Errorf("invalid ssh private key: %v", err)
+		}
+		auth = append(auth, ssh.PublicKeys(signer))
+	}
+	if opts.SSHPassword != "" {
+		auth = append(auth, ssh.Password(opts.SSHPassword))
+	}
+	if len(auth) == 0 {
+		return nil, fmt.Errorf("ssh docker host requires a private key or password")
+	}
+
+	addr := endpoint.Host
+	if endpoint.Port() == "" {
+		addr = net.JoinHostPort(endpoint.Hostname(), "22")
+	}
+	socket := endpoint.Path
+	if socket == "" || socket == "/" {
+		socket = defaultDockerSocket
+	}
+	timeout := opts.Timeout
+	if timeout <= 0 || timeout > 30*time.Second {
+		timeout = 30 * time.Second
+	}
+
+	return &sshSocketDialer{
+		addr:   addr,
+		socket: socket,
+		config: &ssh.ClientConfig{
+			User:            endpoint.User.Username(),
+			Auth:            auth,
+			HostKeyCallback: sshHostKeyCallback(opts.SSHHostKey, opts.OnHostKey),
+			Timeout:         timeout,
+		},
+	}, nil
+}
+
+// sshHostKeyCallback 校验主机公钥指纹；未配置指纹时接受并回调，由调用方保存以便后续校验
+func sshHostKeyCallback(expected string, onHostKey func(string)) ssh.HostKeyCallback {
+	return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		fingerprint := ssh.FingerprintSHA256(key)
+		if expected == "" {
+			if onHostKey != nil {
+				onHostKey(fingerprint)
+			}
+			return nil
+		}
+		if fingerprint != expected {
+			return fmt.Errorf("ssh host key mismatch: got %s, want %s", fingerprint, expected)
+		}
+		return nil
+	}
+}
+
+// dialContext 打开一个到远端 Docker 套接字的通道
+func (d *sshSocketDialer) dialContext(ctx context.Context, _, _ string) (net.Conn, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	// 已有连接可能因远端重启或网络中断失效，打开通道失败时重新建立连接
+	if d.conn != nil {
+		if channel, err := d.conn.Dial("unix", d.socket); err == nil {
+			return channel, nil
+		}
+		d.conn.Close()
+		d.conn = nil
+	}
+	conn, err := d.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	d.conn = conn
+	channel, err := conn.Dial("unix", d.socket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open docker socket over ssh: %v", err)
+	}
+	return channel, nil
+}
+
+// connect 建立 ssh 连接，握手过程受 ctx 控制
+func (d *sshSocketDialer) connect(ctx context.Context) (*ssh.Client, error) {
+	var dialer net.Dialer
+	tcpConn, err := dialer.DialContext(ctx, "tcp", d.addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect ssh host: %v", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		tcpConn.SetDeadline(deadline)
+	}
+	sshConn, chans, reqs, err := ssh.NewClientConn(tcpConn, d.addr, d.config)
+	if err != nil {
+		tcpConn.Close()
+		return nil, fmt.Errorf("ssh handshake failed: %v", err)
+	}
+	tcpConn.SetDeadline(time.Time{})
+	return ssh.NewClient(sshConn, chans, reqs), nil
+}
+
+func (d *sshSocketDialer) close() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.conn != nil {
+		d.conn.Close()
+		d.conn = nil
+	}
+}